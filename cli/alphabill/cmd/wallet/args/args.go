@@ -37,9 +37,15 @@ const (
 	FcrIdCmdName               = "fcr-id"
 	PartitionIdentifierCmdName = "partition-identifier"
 	ReferenceNumber            = "reference-number"
-	proofOutputFlagName        = "proof-output"
+	ProofOutputFlagName        = "proof-output"
 	MaxFeeFlagName             = "max-fee"
 	TargetPubkeyFlagName       = "target-pubkey"
+	UnsignedOutFlagName        = "unsigned-out"
+	UnsignedTimeoutFlagName    = "unsigned-timeout"
+
+	// DefaultUnsignedTimeout is the number of rounds the unsigned transactions are valid
+	// for, leaves time to carry the transactions to the offline machine and back.
+	DefaultUnsignedTimeout = 10000
 )
 
 func BuildRpcUrl(url string) string {
@@ -60,9 +66,9 @@ func AddWaitForProofFlags(cmd *cobra.Command, flags *pflag.FlagSet) {
 	// use string instead of boolean as boolean requires equals sign between name and value e.g. w=[true|false]
 	flags.StringP(WaitForConfCmdName, "w", "true", "waits for transaction confirmation "+
 		"on the blockchain, otherwise just broadcasts the transaction")
-	flags.String(proofOutputFlagName, "", `save transaction proof to the file (if the file already exists `+
+	flags.String(ProofOutputFlagName, "", `save transaction proof to the file (if the file already exists `+
 		`it will be overwritten). This flag implicitly sets "`+WaitForConfCmdName+`" to "true"`)
-	cmd.MarkFlagsMutuallyExclusive(WaitForConfCmdName, proofOutputFlagName)
+	cmd.MarkFlagsMutuallyExclusive(WaitForConfCmdName, ProofOutputFlagName)
 }
 
 /*
@@ -81,19 +87,57 @@ func WaitForProofArg(cmd *cobra.Command) (wait bool, filename string, _ error) {
 		return false, "", fmt.Errorf("parsing %q flag: %w", WaitForConfCmdName, err)
 	}
 
-	if cmd.Flags().Changed(proofOutputFlagName) {
-		outputProof, err := cmd.Flags().GetString(proofOutputFlagName)
+	if cmd.Flags().Changed(ProofOutputFlagName) {
+		outputProof, err := cmd.Flags().GetString(ProofOutputFlagName)
 		if err != nil {
-			return false, "", fmt.Errorf("reading %q flag: %w", proofOutputFlagName, err)
+			return false, "", fmt.Errorf("reading %q flag: %w", ProofOutputFlagName, err)
 		}
 		if filename, err = filepath.Abs(outputProof); err != nil {
-			return false, "", fmt.Errorf("parsing %q flag value as file name: %w", proofOutputFlagName, err)
+			return false, "", fmt.Errorf("parsing %q flag value as file name: %w", ProofOutputFlagName, err)
 		}
 	}
 
 	return wait || filename != "", filename, nil
 }
 
+/*
+AddUnsignedOutFlags adds "unsigned-out" and "unsigned-timeout" flags to the flagset.
+*/
+func AddUnsignedOutFlags(cmd *cobra.Command, flags *pflag.FlagSet) {
+	flags.String(UnsignedOutFlagName, "", "do not sign nor send the transaction(s), save the unsigned transaction(s) "+
+		"to the file instead, the file can be signed offline with the \"tx sign\" command")
+	flags.Uint64(UnsignedTimeoutFlagName, DefaultUnsignedTimeout, "number of rounds the transaction(s) saved "+
+		"by the \""+UnsignedOutFlagName+"\" flag are valid for, the transaction(s) must be signed and submitted before the timeout")
+}
+
+/*
+UnsignedOutArg returns values of the "unsigned-out" and "unsigned-timeout" flags.
+Returns:
+  - filename: the file into which the unsigned transactions must be saved, empty if the
+    transactions must be signed and sent as usual;
+  - timeout: the number of rounds the unsigned transactions are valid for;
+*/
+func UnsignedOutArg(cmd *cobra.Command) (filename string, timeout uint64, _ error) {
+	filename, err := cmd.Flags().GetString(UnsignedOutFlagName)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read '%s' parameter: %w", UnsignedOutFlagName, err)
+	}
+	if cmd.Flags().Changed(UnsignedTimeoutFlagName) && filename == "" {
+		return "", 0, fmt.Errorf("'%s' parameter can only be used together with '%s' parameter", UnsignedTimeoutFlagName, UnsignedOutFlagName)
+	}
+	if filename == "" {
+		return "", 0, nil
+	}
+	timeout, err = cmd.Flags().GetUint64(UnsignedTimeoutFlagName)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read '%s' parameter: %w", UnsignedTimeoutFlagName, err)
+	}
+	if timeout == 0 {
+		return "", 0, fmt.Errorf("invalid parameter for flag %q: timeout must be greater than zero", UnsignedTimeoutFlagName)
+	}
+	return filename, timeout, nil
+}
+
 func AddMaxFeeFlag(cmd *cobra.Command, flags *pflag.FlagSet) {
 	flags.String(MaxFeeFlagName, "10", "maximum fee per transaction (in tema)")
 }
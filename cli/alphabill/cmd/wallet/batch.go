@@ -0,0 +1,220 @@
+package wallet
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	sdkmoney "github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	sdktypes "github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	"github.com/alphabill-org/alphabill-wallet/wallet/money"
+	"github.com/alphabill-org/alphabill-wallet/wallet/money/txbuilder"
+)
+
+const (
+	batchCmdName       = "batch"
+	batchReportCmdName = "batch-report"
+
+	batchFileHelp = "The batch file is a CSV file with a row per payment: the public key of the receiver, the amount " +
+		"and optionally the reference number of the payment (see the \"reference-number\" flag), the first row is " +
+		"skipped when it is a header starting with \"pubkey\"."
+)
+
+type (
+	// batchReport is the JSON report of the batch payments.
+	batchReport struct {
+		Success  bool                  `json:"success"`
+		Payments []*batchPaymentReport `json:"payments"`
+	}
+
+	batchPaymentReport struct {
+		// Line is the line of the payment in the batch file.
+		Line            int    `json:"line"`
+		PubKey          string `json:"pubKey"`
+		Amount          string `json:"amount"`
+		ReferenceNumber string `json:"referenceNumber,omitempty"`
+		TxHash          string `json:"txHash,omitempty"`
+		TxType          string `json:"txType,omitempty"`
+		// Status is one of "confirmed", "failed", "sent" or "unconfirmed".
+		Status string `json:"status"`
+		Fee    string `json:"fee,omitempty"`
+		// Proof is the hex encoded CBOR of the transaction proof.
+		Proof string `json:"proof,omitempty"`
+	}
+
+	// batchRow is a payment of the batch file.
+	batchRow struct {
+		line            int
+		referenceNumber string
+		payment         *txbuilder.Payment
+	}
+)
+
+// execSendBatchCmd pays the payments of the batch file and saves the report of the payments.
+func execSendBatchCmd(ctx context.Context, cmd *cobra.Command, config *types.WalletConfig, w *money.Wallet, filename string, sendCmd money.SendBatchCmd) error {
+	reportFile, err := cmd.Flags().GetString(batchReportCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", batchReportCmdName, err)
+	}
+	if reportFile == "" {
+		reportFile = strings.TrimSuffix(filename, filepath.Ext(filename)) + ".report.json"
+	}
+	rows, err := readBatchPayments(filename)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		sendCmd.Payments = append(sendCmd.Payments, row.payment)
+	}
+
+	results, sendErr := w.SendBatch(ctx, sendCmd)
+	if results == nil {
+		return sendErr
+	}
+	report := &batchReport{Success: sendErr == nil}
+	txs := map[string]bool{}
+	for i, row := range rows {
+		r := results[i]
+		paymentReport := &batchPaymentReport{
+			Line:            row.line,
+			PubKey:          hexutil.Encode(row.payment.PubKey),
+			Amount:          util.AmountToString(row.payment.Amount, 8),
+			ReferenceNumber: row.referenceNumber,
+			TxHash:          hexutil.Encode(r.TxHash),
+			TxType:          batchTxTypeName(r.TxType),
+			Status:          batchPaymentStatus(r, sendCmd.WaitForConfirmation, sendErr),
+		}
+		if r.Proof != nil {
+			paymentReport.Fee = util.AmountToString(r.Proof.TxRecord.ServerMetadata.GetActualFee(), 8)
+			proofBytes, err := sdktypes.Cbor.Marshal(r.Proof)
+			if err != nil {
+				return fmt.Errorf("encoding transaction proof: %w", err)
+			}
+			paymentReport.Proof = hexutil.Encode(proofBytes)
+		}
+		report.Success = report.Success && paymentReport.Status != "failed"
+		report.Payments = append(report.Payments, paymentReport)
+		txs[paymentReport.TxHash] = true
+	}
+	if err := saveBatchReport(reportFile, report); err != nil {
+		return err
+	}
+	switch {
+	case sendErr != nil:
+	case sendCmd.WaitForConfirmation:
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Successfully confirmed %d payment(s) in %d transaction(s)", len(rows), len(txs)))
+	default:
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Successfully sent %d payment(s) in %d transaction(s)", len(rows), len(txs)))
+	}
+	config.Base.ConsoleWriter.Println("Batch report saved to file: " + reportFile)
+	return sendErr
+}
+
+// readBatchPayments reads the payments of the batch file.
+func readBatchPayments(filename string) ([]*batchRow, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+	var rows []*batchRow
+	for first := true; ; first = false {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch file: %w", err)
+		}
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "pubkey") {
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		payment, err := parseBatchPayment(record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch file: line %d: %w", line, err)
+		}
+		row := &batchRow{line: line, payment: payment}
+		if len(record) == 3 {
+			row.referenceNumber = record[2]
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("invalid batch file: no payments")
+	}
+	return rows, nil
+}
+
+func parseBatchPayment(record []string) (*txbuilder.Payment, error) {
+	if len(record) < 2 || len(record) > 3 {
+		return nil, fmt.Errorf("expected 2 or 3 fields (pubkey, amount, reference number), got %d", len(record))
+	}
+	pubKey, err := hexutil.Decode(strings.TrimSpace(record[0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid address format: %s", record[0])
+	}
+	amount, err := util.StringToAmount(strings.TrimSpace(record[1]), 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+	payment := &txbuilder.Payment{PubKey: pubKey, Amount: amount}
+	if len(record) == 3 {
+		if payment.ReferenceNumber, err = parseReferenceNumber(record[2]); err != nil {
+			return nil, fmt.Errorf("invalid reference number: %w", err)
+		}
+	}
+	return payment, nil
+}
+
+func batchTxTypeName(txType uint16) string {
+	switch txType {
+	case sdkmoney.TransactionTypeTransfer:
+		return "transfer"
+	case sdkmoney.TransactionTypeSplit:
+		return "split"
+	default:
+		return fmt.Sprintf("%d", txType)
+	}
+}
+
+func batchPaymentStatus(r *money.BatchPaymentResult, waitForConf bool, sendErr error) string {
+	switch {
+	case r.Proof != nil && r.Proof.TxRecord.ServerMetadata.SuccessIndicator == sdktypes.TxStatusSuccessful:
+		return "confirmed"
+	case r.Proof != nil:
+		return "failed"
+	case waitForConf || sendErr != nil:
+		return "unconfirmed"
+	default:
+		return "sent"
+	}
+}
+
+func saveBatchReport(filename string, report *batchReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding batch report: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("saving batch report: %w", err)
+	}
+	return nil
+}
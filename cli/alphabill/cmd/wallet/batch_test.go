@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestSendCmd_batch(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	stateMock := mocksrv.NewStateServiceMock(
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID:      moneyid.NewBillID(t),
+				Data:        money.BillData{Value: 10 * 1e8},
+			}),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID: func() abtypes.UnitID {
+					id, err := money.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, abtypes.ShardID{}, testutils.TestPubKey0Hash(t), 1000)
+					require.NoError(t, err)
+					return id
+				}(),
+				Data: fc.FeeCreditRecord{Balance: 1e8},
+			}),
+	)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, stateMock)
+	walletCmd := newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir)
+
+	batchFile := filepath.Join(t.TempDir(), "payments.csv")
+	require.NoError(t, os.WriteFile(batchFile, []byte(
+		"pubkey,amount,reference number\n"+
+			"# salaries\n"+
+			"0x"+testutils.TestPubKey1Hex+",1,salary\n"+
+			"0x"+testutils.TestPubKey1Hex+",2.5,salary\n"), 0600))
+
+	stdout := walletCmd.Exec(t, "send", "--batch", batchFile)
+	reportFile := filepath.Join(filepath.Dir(batchFile), "payments.report.json")
+	testutils.VerifyStdout(t, stdout,
+		"Successfully confirmed 2 payment(s) in 1 transaction(s)",
+		"Batch report saved to file: "+reportFile)
+	require.Len(t, stateMock.SentTxs, 1)
+
+	data, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+	report := &batchReport{}
+	require.NoError(t, json.Unmarshal(data, report))
+	require.True(t, report.Success)
+	require.Len(t, report.Payments, 2)
+	require.Equal(t, 3, report.Payments[0].Line)
+	require.Equal(t, "1.000'000'00", report.Payments[0].Amount)
+	require.Equal(t, "salary", report.Payments[0].ReferenceNumber)
+	require.Equal(t, 4, report.Payments[1].Line)
+	require.Equal(t, "2.500'000'00", report.Payments[1].Amount)
+	for _, p := range report.Payments {
+		require.Equal(t, "split", p.TxType)
+		require.Equal(t, "confirmed", p.Status)
+		require.Equal(t, report.Payments[0].TxHash, p.TxHash)
+		require.NotEmpty(t, p.Proof)
+	}
+
+	// batch can not be combined with a single payment
+	walletCmd.ExecWithError(t, "are set none of the others can be",
+		"send", "--batch", batchFile, "--address", "0x"+testutils.TestPubKey1Hex, "--amount", "1")
+	// bill selection and proof output apply only to a single payment, the batch saves the proofs into the report
+	walletCmd.ExecWithError(t, "[batch selection] were all set",
+		"send", "--batch", batchFile, "--selection", "smallest-first")
+	walletCmd.ExecWithError(t, "[batch proof-output] were all set",
+		"send", "--batch", batchFile, "--proof-output", filepath.Join(t.TempDir(), "proof.json"))
+}
+
+func TestReadBatchPayments(t *testing.T) {
+	pubKey := "0x" + testutils.TestPubKey1Hex
+	tests := []struct {
+		name        string
+		content     string
+		payments    int
+		expectedErr string
+	}{
+		{
+			name:     "without header",
+			content:  pubKey + ",1\n" + pubKey + ", 2, ref\n",
+			payments: 2,
+		},
+		{
+			name:        "empty",
+			content:     "pubkey,amount\n# no payments\n",
+			expectedErr: "invalid batch file: no payments",
+		},
+		{
+			name:        "invalid pubkey",
+			content:     pubKey + ",1\n0x12z,1\n",
+			expectedErr: "invalid batch file: line 2: invalid address format: 0x12z",
+		},
+		{
+			name:        "invalid amount",
+			content:     pubKey + ",1x\n",
+			expectedErr: "invalid batch file: line 1: invalid amount:",
+		},
+		{
+			name:        "too many fields",
+			content:     pubKey + ",1,ref,x\n",
+			expectedErr: "invalid batch file: line 1: expected 2 or 3 fields (pubkey, amount, reference number), got 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "batch.csv")
+			require.NoError(t, os.WriteFile(filename, []byte(tt.content), 0600))
+			rows, err := readBatchPayments(filename)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rows, tt.payments)
+		})
+	}
+}
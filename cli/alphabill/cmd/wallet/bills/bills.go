@@ -130,7 +130,7 @@ func execLockCmd(cmd *cobra.Command, config *clitypes.BillsConfig) error {
 		return fmt.Errorf("failed to load account manager: %w", err)
 	}
 	defer am.Close()
-	accountKey, err := am.GetAccountKey(accountNumber - 1)
+	accountKey, err := am.GetSigningKey(accountNumber - 1)
 	if err != nil {
 		return fmt.Errorf("failed to load account key: %w", err)
 	}
@@ -221,7 +221,7 @@ func execUnlockCmd(cmd *cobra.Command, config *clitypes.BillsConfig) error {
 		return fmt.Errorf("failed to load account manager: %w", err)
 	}
 	defer am.Close()
-	accountKey, err := am.GetAccountKey(accountNumber - 1)
+	accountKey, err := am.GetSigningKey(accountNumber - 1)
 	if err != nil {
 		return fmt.Errorf("failed to load account key: %w", err)
 	}
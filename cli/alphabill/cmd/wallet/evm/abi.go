@@ -0,0 +1,81 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/spf13/cobra"
+
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const abiArgsHelp = "Method arguments are given in the order of the ABI method inputs: integers in decimal " +
+	"(or hex with 0x prefix), addresses and bytes as 0x prefixed hex, booleans as true/false and " +
+	"arrays and tuples as JSON arrays, eg '[\"0x01\",2]'."
+
+// addAbiFlags adds "abi" and "method" flags to the command, "method" is mutually exclusive
+// with "data" flag and one of them (or one of the "alternatives" flags) must be set.
+func addAbiFlags(cmd *cobra.Command, alternatives ...string) {
+	cmd.Flags().String(AbiCmdName, "", "contract ABI file (plain ABI JSON or compiler artifact), used to encode "+
+		"the method call and to decode the return data and logs")
+	cmd.Flags().String(MethodCmdName, "", "name or signature of the contract method to call, requires ABI")
+	cmd.MarkFlagsMutuallyExclusive(DataCmdName, MethodCmdName)
+	cmd.MarkFlagsOneRequired(append([]string{DataCmdName, MethodCmdName}, alternatives...)...)
+}
+
+// readAbiFlag returns contract ABI loaded from the file given by "abi" flag, nil if flag is not set.
+func readAbiFlag(cmd *cobra.Command) (*abi.ABI, error) {
+	filename, err := cmd.Flags().GetString(AbiCmdName)
+	if err != nil {
+		return nil, err
+	}
+	if filename == "" {
+		return nil, nil
+	}
+	contractABI, err := evmwallet.LoadABI(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", AbiCmdName, err)
+	}
+	return contractABI, nil
+}
+
+/*
+readCallData returns smart contract call data either from "data" flag or ABI encoded
+from the "method" flag and positional arguments. Also returns the contract ABI (nil when
+not given) and method name (empty when "data" flag was used) to decode the result with.
+The ABI is read from "abiPath" (ABI of the named contract) when the "abi" flag is not set.
+*/
+func readCallData(cmd *cobra.Command, methodArgs []string, abiPath string) ([]byte, *abi.ABI, string, error) {
+	contractABI, err := readAbiFlag(cmd)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if contractABI == nil && abiPath != "" {
+		if contractABI, err = evmwallet.LoadABI(abiPath); err != nil {
+			return nil, nil, "", fmt.Errorf("failed to read contract ABI: %w", err)
+		}
+	}
+	method, err := cmd.Flags().GetString(MethodCmdName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if method == "" {
+		if len(methodArgs) > 0 {
+			return nil, nil, "", fmt.Errorf("positional arguments are only allowed together with '%s' parameter", MethodCmdName)
+		}
+		data, err := readHexFlag(cmd, DataCmdName)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to read '%s' parameter: %w", DataCmdName, err)
+		}
+		return data, contractABI, "", nil
+	}
+	if contractABI == nil {
+		return nil, nil, "", errors.New("contract ABI is required to call a method, use '" + AbiCmdName + "' parameter")
+	}
+	data, err := evmwallet.PackMethodCall(contractABI, method, methodArgs)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to encode method call: %w", err)
+	}
+	return data, contractABI, method, nil
+}
@@ -0,0 +1,227 @@
+package evm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	basetypes "github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	BatchCmdName = "batch"
+
+	batchFileHelp = "The batch file is a JSON array of transactions, each with the fields \"to\" (contract address), " +
+		"either \"data\" (hex call data) or \"method\" and \"args\" (encoded with the ABI given by \"" + AbiCmdName + "\" flag), " +
+		"optional \"value\" (in ALPHA or with \"wei\" suffix) and optional \"gas\" (amount of gas or \"" + autoMaxGas + "\", " +
+		"the \"" + MaxGasCmdName + "\" flag is used when not set, the flag is only required when some transaction does not set it), eg " +
+		`[{"to":"0x..","method":"transfer","args":["0x..","10"],"gas":"auto"},{"to":"0x..","data":"0x..","value":"1.5"}]. ` +
+		"The nonces are assigned in the order of the transactions, when a transaction is not executed the " +
+		"transactions after it are not executed either. All the transactions of the batch are simulated against the " +
+		"current state before any of them is sent, use \"" + SkipSimulationCmdName + "\" flag when the transactions " +
+		"depend on the previous transactions of the batch."
+)
+
+// batchTx is a transaction in the batch file.
+type batchTx struct {
+	To     string       `json:"to"`
+	Data   string       `json:"data"`
+	Method string       `json:"method"`
+	Args   []string     `json:"args"`
+	Value  string       `json:"value"`
+	Gas    *maxGasValue `json:"gas"`
+}
+
+// UnmarshalJSON accepts the amount of gas either as number or string, "auto" to estimate it.
+func (v *maxGasValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	return v.Set(s)
+}
+
+func execEvmCmdExecuteBatch(cmd *cobra.Command, methodArgs []string, config *types.EvmConfig) error {
+	if len(methodArgs) > 0 {
+		return fmt.Errorf("positional arguments are not allowed together with '%s' parameter", BatchCmdName)
+	}
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	noWait, err := cmd.Flags().GetBool(NoWaitCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", NoWaitCmdName, err)
+	}
+	contractABI, err := readAbiFlag(cmd)
+	if err != nil {
+		return err
+	}
+	txs, err := readBatchFile(cmd, contractABI)
+	if err != nil {
+		return err
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	batch := w.NewTxBatch(accountNumber, config.WalletConfig.Base.Logger)
+	for i, tx := range txs {
+		attrs, err := tx.attributes(contractABI)
+		if err != nil {
+			return fmt.Errorf("invalid transaction #%d in the batch: %w", i+1, err)
+		}
+		var estimate *evmwallet.GasEstimate
+		if attrs.Gas, estimate, err = batchTxGas(cmd, w, accountNumber, tx, attrs); err != nil {
+			return fmt.Errorf("invalid transaction #%d in the batch: %w", i+1, err)
+		}
+		batch.Add(attrs).GasEstimate = estimate
+	}
+	var opts []evmwallet.SendOption
+	if skip, err := cmd.Flags().GetBool(SkipSimulationCmdName); err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", SkipSimulationCmdName, err)
+	} else if !skip {
+		opts = append(opts, evmwallet.WithSimulation(nil))
+	}
+	// transactions which were not executed are reported below, any other error aborts
+	sendErr := batch.SendTx(cmd.Context(), !noWait, opts...)
+	if sendErr != nil && !batchNotExecuted(batch) {
+		return txSubmitError(simulationError(sendErr, contractABI), accountNumber, "batch execution")
+	}
+
+	var proofs []*basetypes.TxRecordProof
+	for i, tx := range batch.Transactions() {
+		prefix := fmt.Sprintf("Transaction #%d (nonce %d)", i+1, tx.Attrs.Nonce)
+		switch {
+		case tx.Err != nil:
+			consoleWriter.Println(fmt.Sprintf("%s was not executed: %v", prefix, tx.Err))
+		case !tx.Confirmed():
+			consoleWriter.Println(fmt.Sprintf("%s submitted, tx hash: 0x%x", prefix, tx.TxHash))
+		default:
+			consoleWriter.Println(prefix + ":")
+			printResult(consoleWriter, tx.Result, contractABI, txs[i].Method)
+			proofs = append(proofs, tx.Result.Proof)
+		}
+	}
+	if noWait {
+		consoleWriter.Println("Use \"evm tx-status <tx hash>\" to check the status of the transactions")
+	}
+	if err := saveTxProof(cmd, consoleWriter, proofs...); err != nil {
+		return err
+	}
+	if sendErr != nil {
+		return fmt.Errorf("batch execution failed, %w", sendErr)
+	}
+	return nil
+}
+
+// readBatchFile reads the transactions from the file given with "batch" flag.
+func readBatchFile(cmd *cobra.Command, contractABI *abi.ABI) ([]*batchTx, error) {
+	filename, err := cmd.Flags().GetString(BatchCmdName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", BatchCmdName, err)
+	}
+	var txs []*batchTx
+	if err := json.Unmarshal(data, &txs); err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: invalid batch file: %w", BatchCmdName, err)
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("failed to read '%s' parameter: batch file does not contain any transactions", BatchCmdName)
+	}
+	return txs, nil
+}
+
+// attributes returns the transaction attributes, gas is not set.
+func (tx *batchTx) attributes(contractABI *abi.ABI) (*evm.TxAttributes, error) {
+	to, err := decodeHex(tx.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	if len(to) != DefaultEvmAddrLen {
+		return nil, fmt.Errorf("invalid address %q, address must be 20 bytes", tx.To)
+	}
+	attrs := &evm.TxAttributes{To: to}
+	switch {
+	case tx.Method != "" && tx.Data != "":
+		return nil, errors.New("only one of 'data' and 'method' can be set")
+	case tx.Method != "":
+		if contractABI == nil {
+			return nil, errors.New("contract ABI is required to call a method, use '" + AbiCmdName + "' parameter")
+		}
+		if attrs.Data, err = evmwallet.PackMethodCall(contractABI, tx.Method, tx.Args); err != nil {
+			return nil, fmt.Errorf("failed to encode method call: %w", err)
+		}
+	case len(tx.Args) > 0:
+		return nil, errors.New("'args' are only allowed together with 'method'")
+	case tx.Data != "":
+		if attrs.Data, err = decodeHex(tx.Data); err != nil {
+			return nil, fmt.Errorf("invalid data: %w", err)
+		}
+	default:
+		return nil, errors.New("either 'data' or 'method' must be set")
+	}
+	if tx.Value != "" {
+		if attrs.Value, err = parseValue(tx.Value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+	}
+	return attrs, nil
+}
+
+// batchTxGas returns the gas of the transaction, "max-gas" flag is used when the transaction does
+// not set it. In case of "auto" the gas is estimated.
+func batchTxGas(cmd *cobra.Command, w *evmwallet.Wallet, accountNumber uint64, tx *batchTx, attrs *evm.TxAttributes) (uint64, *evmwallet.GasEstimate, error) {
+	gas := tx.Gas
+	if gas == nil {
+		if !cmd.Flags().Changed(MaxGasCmdName) {
+			return 0, nil, fmt.Errorf("'gas' is not set and '%s' parameter is not given", MaxGasCmdName)
+		}
+		var ok bool
+		if gas, ok = cmd.Flags().Lookup(MaxGasCmdName).Value.(*maxGasValue); !ok {
+			return 0, nil, fmt.Errorf("failed to read '%s' parameter", MaxGasCmdName)
+		}
+	}
+	if !gas.auto {
+		return gas.gas, nil, nil
+	}
+	estimate, err := estimateGas(cmd, w, accountNumber, &evm.CallEVMRequest{To: attrs.To, Data: attrs.Data, Value: attrs.Value})
+	if err != nil {
+		return 0, nil, err
+	}
+	return estimate.GasLimit, estimate, nil
+}
+
+// batchNotExecuted returns true when the batch was sent but some of the transactions were not executed.
+func batchNotExecuted(batch *evmwallet.TxBatch) bool {
+	for _, tx := range batch.Transactions() {
+		if tx.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeHex decodes hex string with optional 0x prefix.
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+	return hexutil.Decode(s)
+}
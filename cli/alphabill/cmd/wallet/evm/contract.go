@@ -0,0 +1,251 @@
+package evm
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	NameCmdName    = "name"
+	ReplaceCmdName = "replace"
+)
+
+func evmCmdContract(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "contract",
+		Short: "manages the named smart contracts of the wallet",
+		Long: "Manages the named smart contracts of the wallet. The name of the contract can be used instead of the " +
+			"address with the \"" + args.AddressCmdName + "\" flag of the execute, call and estimate-gas commands, " +
+			"the ABI of the contract (when registered) is used when the \"" + AbiCmdName + "\" flag is not set.",
+	}
+	cmd.AddCommand(evmCmdContractAdd(config))
+	cmd.AddCommand(evmCmdContractList(config))
+	cmd.AddCommand(evmCmdContractRemove(config))
+	return cmd
+}
+
+func evmCmdContractAdd(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "adds a named smart contract to the wallet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdContractAdd(cmd, config)
+		},
+	}
+	cmd.Flags().String(NameCmdName, "", "name of the contract, must start with a letter or underscore and "+
+		"contain only letters, digits, '_', '.' and '-'")
+	cmd.Flags().String(args.AddressCmdName, "", "smart contract address in hexadecimal format")
+	cmd.Flags().String(AbiCmdName, "", "(optional) contract ABI file (plain ABI JSON or compiler artifact)")
+	cmd.Flags().Bool(ReplaceCmdName, false, "replace the existing contract with the same name")
+	if err := cmd.MarkFlagRequired(NameCmdName); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkFlagRequired(args.AddressCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdContractAdd(cmd *cobra.Command, config *types.EvmConfig) error {
+	name, err := cmd.Flags().GetString(NameCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", NameCmdName, err)
+	}
+	addr, err := cmd.Flags().GetString(args.AddressCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
+	}
+	if !common.IsHexAddress(addr) {
+		return fmt.Errorf("invalid address %q, address must be 20 bytes in hex", addr)
+	}
+	replace, err := cmd.Flags().GetBool(ReplaceCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ReplaceCmdName, err)
+	}
+	abiPath, err := readContractAbiPath(cmd, AbiCmdName)
+	if err != nil {
+		return err
+	}
+	c := &evmwallet.Contract{Name: name, Address: common.HexToAddress(addr), ABIPath: abiPath}
+	if err := addContract(config, c, replace); err != nil {
+		return err
+	}
+	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Contract %q added: 0x%x", c.Name, c.Address))
+	return nil
+}
+
+func evmCmdContractList(config *types.EvmConfig) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "lists the named smart contracts of the wallet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdContractList(config)
+		},
+	}
+}
+
+func execEvmCmdContractList(config *types.EvmConfig) error {
+	book, err := openContractBook(config)
+	if err != nil {
+		return err
+	}
+	defer book.Close()
+	contracts, err := book.List()
+	if err != nil {
+		return fmt.Errorf("failed to read contracts: %w", err)
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	if len(contracts) == 0 {
+		consoleWriter.Println("No contracts")
+		return nil
+	}
+	for _, c := range contracts {
+		line := fmt.Sprintf("%s 0x%x", c.Name, c.Address)
+		if c.ABIPath != "" {
+			line += " " + c.ABIPath
+		}
+		consoleWriter.Println(line)
+	}
+	return nil
+}
+
+func evmCmdContractRemove(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "removes a named smart contract from the wallet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdContractRemove(cmd, config)
+		},
+	}
+	cmd.Flags().String(NameCmdName, "", "name of the contract")
+	if err := cmd.MarkFlagRequired(NameCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdContractRemove(cmd *cobra.Command, config *types.EvmConfig) error {
+	name, err := cmd.Flags().GetString(NameCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", NameCmdName, err)
+	}
+	book, err := openContractBook(config)
+	if err != nil {
+		return err
+	}
+	defer book.Close()
+	if err := book.Remove(name); err != nil {
+		return fmt.Errorf("failed to remove contract: %w", err)
+	}
+	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Contract %q removed", name))
+	return nil
+}
+
+func openContractBook(config *types.EvmConfig) (*evmwallet.ContractBook, error) {
+	book, err := evmwallet.NewContractBook(config.WalletConfig.WalletHomeDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open contract book: %w", err)
+	}
+	return book, nil
+}
+
+func addContract(config *types.EvmConfig, c *evmwallet.Contract, replace bool) error {
+	book, err := openContractBook(config)
+	if err != nil {
+		return err
+	}
+	defer book.Close()
+	if err := book.Add(c, replace); err != nil {
+		return fmt.Errorf("failed to add contract: %w", err)
+	}
+	return nil
+}
+
+// verifyContractNameAvailable returns error when the name is invalid or already used.
+func verifyContractNameAvailable(config *types.EvmConfig, name string) error {
+	if err := evmwallet.ValidateContractName(name); err != nil {
+		return err
+	}
+	book, err := openContractBook(config)
+	if err != nil {
+		return err
+	}
+	defer book.Close()
+	if _, err := book.Get(name); !errors.Is(err, evmwallet.ErrContractNotFound) {
+		if err == nil {
+			return fmt.Errorf("%w: %s", evmwallet.ErrContractExists, name)
+		}
+		return err
+	}
+	return nil
+}
+
+// readContractAbiPath returns the absolute path of the ABI file given by the flag, the file is
+// verified to contain the ABI. Returns empty string when the flag is not set.
+func readContractAbiPath(cmd *cobra.Command, flag string) (string, error) {
+	filename, err := cmd.Flags().GetString(flag)
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s' parameter: %w", flag, err)
+	}
+	if filename == "" {
+		return "", nil
+	}
+	if _, err := evmwallet.LoadABI(filename); err != nil {
+		return "", fmt.Errorf("failed to read '%s' parameter: %w", flag, err)
+	}
+	abiPath, err := filepath.Abs(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s' parameter: %w", flag, err)
+	}
+	return abiPath, nil
+}
+
+/*
+readContractAddress returns the smart contract address given with "address" flag either in hex or
+as the name of the contract in the contract book. In the latter case the ABI file path of the
+contract is returned too (empty when not registered).
+*/
+func readContractAddress(cmd *cobra.Command, config *types.EvmConfig) ([]byte, string, error) {
+	addr, err := cmd.Flags().GetString(args.AddressCmdName)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
+	}
+	if common.IsHexAddress(addr) {
+		return common.HexToAddress(addr).Bytes(), "", nil
+	}
+	if evmwallet.ValidateContractName(addr) == nil {
+		book, err := openContractBook(config)
+		if err != nil {
+			return nil, "", err
+		}
+		defer book.Close()
+		c, err := book.Get(addr)
+		if err == nil {
+			return c.Address.Bytes(), c.ABIPath, nil
+		}
+		if !errors.Is(err, evmwallet.ErrContractNotFound) {
+			return nil, "", fmt.Errorf("failed to read contract: %w", err)
+		}
+		if _, err := hex.DecodeString(strings.TrimPrefix(addr, "0x")); err != nil {
+			return nil, "", fmt.Errorf("unknown contract %q, use 'evm contract add' to register it", addr)
+		}
+	}
+	toAddr, err := readHexFlag(cmd, args.AddressCmdName)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
+	}
+	if len(toAddr) != DefaultEvmAddrLen {
+		return nil, "", fmt.Errorf("invalid address %x, address must be 20 bytes", toAddr)
+	}
+	return toAddr, "", nil
+}
@@ -0,0 +1,128 @@
+package evm
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+func Test_evmCmdContract(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	abiFile := filepath.Join(t.TempDir(), "token.abi")
+	require.NoError(t, os.WriteFile(abiFile, []byte(testTokenABI), 0600))
+
+	stdout, err := execEvmCmd(t, homedir, "evm contract list")
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "No contracts")
+
+	stdout, err = execEvmCmd(t, homedir, "evm contract add --name usdc --address 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, `Contract "usdc" added: 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521`)
+	_, err = execEvmCmd(t, homedir, "evm contract add --name plain --address 1111111111111111111111111111111111111111")
+	require.NoError(t, err)
+
+	_, err = execEvmCmd(t, homedir, "evm contract add --name usdc --address 0x2222222222222222222222222222222222222222")
+	require.EqualError(t, err, "failed to add contract: contract already exists: usdc")
+	_, err = execEvmCmd(t, homedir, "evm contract add --name 1usdc --address 0x2222222222222222222222222222222222222222")
+	require.ErrorContains(t, err, `invalid contract name "1usdc"`)
+	_, err = execEvmCmd(t, homedir, "evm contract add --name other --address 0x1234")
+	require.EqualError(t, err, `invalid address "0x1234", address must be 20 bytes in hex`)
+	_, err = execEvmCmd(t, homedir, "evm contract add --name other --address 0x2222222222222222222222222222222222222222 --abi "+filepath.Join(t.TempDir(), "missing.abi"))
+	require.ErrorContains(t, err, "failed to read 'abi' parameter: reading ABI file")
+
+	stdout, err = execEvmCmd(t, homedir, "evm contract list")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"plain 0x1111111111111111111111111111111111111111",
+		"usdc 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521 " + abiFile,
+	}, stdout.Lines)
+
+	stdout, err = execEvmCmd(t, homedir, "evm contract remove --name plain")
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, `Contract "plain" removed`)
+	_, err = execEvmCmd(t, homedir, "evm contract remove --name plain")
+	require.EqualError(t, err, "failed to remove contract: contract not found: plain")
+}
+
+func Test_evmCmdCall_contractName(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	abiFile := filepath.Join(t.TempDir(), "token.abi")
+	require.NoError(t, os.WriteFile(abiFile, []byte(testTokenABI), 0600))
+	contractABI, err := evmwallet.ParseABI([]byte(testTokenABI))
+	require.NoError(t, err)
+	returnData, err := contractABI.Methods["balanceOf"].Outputs.Pack(big.NewInt(100))
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		callResp: &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ReturnData: returnData}},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	_, err = execEvmCmd(t, homedir, "evm contract add --name usdc --address 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile)
+	require.NoError(t, err)
+
+	// ABI of the contract is used
+	stdout, err := execEvmCmd(t, homedir, "evm call --address usdc --method balanceOf 0x2222222222222222222222222222222222222222 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Evm execution returned: balance (uint256): 100")
+	require.EqualValues(t, common.HexToAddress("0x3443919fcbc4476b4f332fd5df6a82fe88dbf521").Bytes(), mockConf.callReq.To)
+	data, err := contractABI.Pack("balanceOf", common.HexToAddress("0x2222222222222222222222222222222222222222"))
+	require.NoError(t, err)
+	require.EqualValues(t, data, mockConf.callReq.Data)
+
+	// 0x prefixed address
+	_, err = execEvmCmd(t, homedir, "evm call --address 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data 9021ACFE --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	require.EqualValues(t, common.HexToAddress("0x3443919fcbc4476b4f332fd5df6a82fe88dbf521").Bytes(), mockConf.callReq.To)
+
+	_, err = execEvmCmd(t, homedir, "evm call --address dai --method balanceOf 0x2222222222222222222222222222222222222222 --alphabill-api-uri "+addr.Host)
+	require.EqualError(t, err, `unknown contract "dai", use 'evm contract add' to register it`)
+}
+
+func Test_evmCmdDeploy_name(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	contractAddr := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{ContractAddr: contractAddr})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000",
+		nonce:    1,
+		gasPrice: "10000",
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	artifactFile := filepath.Join(t.TempDir(), "Token.json")
+	require.NoError(t, os.WriteFile(artifactFile, []byte(`{"contractName":"Token","abi":`+testTokenABI+`,"bytecode":"0x6080"}`), 0600))
+
+	stdout, err := execEvmCmd(t, homedir, "evm deploy --name token --max-gas 10000 --artifact "+artifactFile+" --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Deployed smart contract address: 5555555555555555555555555555555555555555",
+		`Contract added as "token"`)
+	stdout, err = execEvmCmd(t, homedir, "evm contract list")
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "token 0x5555555555555555555555555555555555555555 "+artifactFile)
+
+	// the name is verified before the deployment
+	mockConf.receivedTx = nil
+	_, err = execEvmCmd(t, homedir, "evm deploy --name token --max-gas 10000 --artifact "+artifactFile+" --alphabill-api-uri "+addr.Host)
+	require.EqualError(t, err, "contract already exists: token")
+	require.Nil(t, mockConf.receivedTx)
+	_, err = execEvmCmd(t, homedir, "evm deploy --name token2 --no-wait --max-gas 10000 --artifact "+artifactFile+" --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "if any flags in the group [name no-wait] are set none of the others can be")
+}
@@ -0,0 +1,108 @@
+package evm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+// addDeployFlags adds flags for reading the contract code either from the "data" flag or
+// from the compiler artifact.
+func addDeployFlags(cmd *cobra.Command) {
+	addDeployCodeFlags(cmd)
+	cmd.MarkFlagsOneRequired(DataCmdName, ArtifactCmdName)
+}
+
+// addDeployCodeFlags adds the flags of addDeployFlags without requiring the contract code.
+func addDeployCodeFlags(cmd *cobra.Command) {
+	cmd.Flags().String(DataCmdName, "", "contract code as hex string")
+	cmd.Flags().String(ArtifactCmdName, "", "compiler artifact file (Hardhat, Truffle or Foundry JSON or solc --combined-json output) "+
+		"containing the contract bytecode and ABI")
+	cmd.Flags().String(ContractCmdName, "", "name of the contract in the artifact, required when the artifact contains multiple contracts")
+	cmd.Flags().String(AbiCmdName, "", "contract ABI file used to encode constructor arguments when code is given with 'data' parameter")
+	cmd.Flags().StringArray(LinkCmdName, nil, "library address to link into the bytecode in form name=0x<address>, "+
+		"name is either library name or fully qualified name (path/File.sol:Name), can be repeated")
+	cmd.MarkFlagsMutuallyExclusive(DataCmdName, ArtifactCmdName)
+	cmd.MarkFlagsMutuallyExclusive(AbiCmdName, ArtifactCmdName)
+}
+
+/*
+readDeployCode returns contract creation code with linked libraries and ABI encoded constructor
+arguments appended. Also returns contract ABI (nil when not known) to decode the result with.
+*/
+func readDeployCode(cmd *cobra.Command, constructorArgs []string) ([]byte, *abi.ABI, error) {
+	libraries, err := readLinkFlag(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	artifactFile, err := cmd.Flags().GetString(ArtifactCmdName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var code []byte
+	var contractABI *abi.ABI
+	if artifactFile != "" {
+		contractName, err := cmd.Flags().GetString(ContractCmdName)
+		if err != nil {
+			return nil, nil, err
+		}
+		artifact, err := evmwallet.LoadArtifact(artifactFile, contractName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read '%s' parameter: %w", ArtifactCmdName, err)
+		}
+		if code, err = evmwallet.LinkBytecode(artifact.Bytecode, artifact.LinkReferences, libraries); err != nil {
+			return nil, nil, fmt.Errorf("failed to link contract code: %w", err)
+		}
+		contractABI = artifact.ABI
+	} else {
+		data, err := cmd.Flags().GetString(DataCmdName)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(data) == 0 {
+			return nil, nil, fmt.Errorf("failed to read '%s' parameter: argument is empty", DataCmdName)
+		}
+		if code, err = evmwallet.LinkBytecode(data, nil, libraries); err != nil {
+			return nil, nil, fmt.Errorf("failed to read '%s' parameter: %w", DataCmdName, err)
+		}
+		if contractABI, err = readAbiFlag(cmd); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(code) > ScSizeLimit24Kb {
+		return nil, nil, fmt.Errorf("contract code too big, maximum size is 24Kb")
+	}
+	encodedArgs, err := evmwallet.PackConstructorArgs(contractABI, constructorArgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode constructor arguments: %w", err)
+	}
+	return append(code, encodedArgs...), contractABI, nil
+}
+
+// readLinkFlag returns library addresses given with "link" flags, keyed by library name.
+func readLinkFlag(cmd *cobra.Command) (map[string]common.Address, error) {
+	links, err := cmd.Flags().GetStringArray(LinkCmdName)
+	if err != nil {
+		return nil, err
+	}
+	libraries := make(map[string]common.Address, len(links))
+	for _, link := range links {
+		name, addr, found := strings.Cut(link, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid '%s' parameter %q, expected name=0x<address>", LinkCmdName, link)
+		}
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("invalid '%s' parameter %q, invalid library address %q", LinkCmdName, link, addr)
+		}
+		if _, ok := libraries[name]; ok {
+			return nil, fmt.Errorf("invalid '%s' parameter, library %q is linked more than once", LinkCmdName, name)
+		}
+		libraries[name] = common.HexToAddress(addr)
+	}
+	return libraries, nil
+}
@@ -0,0 +1,343 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	TokenCmdName   = "token"
+	OwnerCmdName   = "owner"
+	SpenderCmdName = "spender"
+	FromCmdName    = "from"
+)
+
+const tokenAmountHelp = "given in token units, decimals of the token are taken into account (eg 1.5)"
+
+func evmCmdERC20(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "erc20",
+		Short: "interact with ERC-20 token contracts",
+		Long: "Reads and transfers ERC-20 tokens using the standard token interface. Token amounts are given " +
+			"and displayed in token units according to the decimals of the token.",
+	}
+	cmd.AddCommand(erc20CmdInfo(config))
+	cmd.AddCommand(erc20CmdBalance(config))
+	cmd.AddCommand(erc20CmdAllowance(config))
+	cmd.AddCommand(erc20CmdTransfer(config))
+	cmd.AddCommand(erc20CmdApprove(config))
+	cmd.AddCommand(erc20CmdTransferFrom(config))
+	return cmd
+}
+
+func erc20CmdInfo(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "shows token name, symbol, decimals and total supply",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execERC20CmdInfo(cmd, config)
+		},
+	}
+	addERC20Flags(cmd, "which key to use for from address in evm call")
+	return cmd
+}
+
+func erc20CmdBalance(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "shows token balance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execERC20CmdBalance(cmd, config)
+		},
+	}
+	addERC20Flags(cmd, "which key to use for from address in evm call, balance of the key is shown when owner is not given")
+	cmd.Flags().String(OwnerCmdName, "", "(optional) token owner address in hexadecimal format")
+	return cmd
+}
+
+func erc20CmdAllowance(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "allowance",
+		Short: "shows amount of tokens the spender is allowed to transfer on behalf of the owner",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execERC20CmdAllowance(cmd, config)
+		},
+	}
+	addERC20Flags(cmd, "which key to use for from address in evm call, the key is the owner when owner is not given")
+	cmd.Flags().String(OwnerCmdName, "", "(optional) token owner address in hexadecimal format")
+	cmd.Flags().String(SpenderCmdName, "", "spender address in hexadecimal format")
+	if err := cmd.MarkFlagRequired(SpenderCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func erc20CmdTransfer(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "transfers tokens to another address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execERC20CmdTransfer(cmd, config)
+		},
+	}
+	addERC20Flags(cmd, "which key to use for sending the transaction")
+	addERC20ReceiverFlags(cmd)
+	addERC20AmountFlag(cmd, "amount to transfer, "+tokenAmountHelp)
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	return cmd
+}
+
+func erc20CmdApprove(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "allows the spender to transfer tokens on behalf of the account",
+		Long: "Sets the amount of tokens the spender is allowed to transfer on behalf of the account, " +
+			"replaces the current allowance. Amount 0 revokes the allowance.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execERC20CmdApprove(cmd, config)
+		},
+	}
+	addERC20Flags(cmd, "which key to use for sending the transaction")
+	cmd.Flags().String(SpenderCmdName, "", "spender address in hexadecimal format")
+	addERC20AmountFlag(cmd, "allowed amount, "+tokenAmountHelp)
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	if err := cmd.MarkFlagRequired(SpenderCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func erc20CmdTransferFrom(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-from",
+		Short: "transfers tokens on behalf of the owner using the allowance",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execERC20CmdTransferFrom(cmd, config)
+		},
+	}
+	addERC20Flags(cmd, "which key to use for sending the transaction, the key must have allowance from the owner")
+	cmd.Flags().String(FromCmdName, "", "token owner address in hexadecimal format")
+	addERC20ReceiverFlags(cmd)
+	addERC20AmountFlag(cmd, "amount to transfer, "+tokenAmountHelp)
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	if err := cmd.MarkFlagRequired(FromCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func addERC20Flags(cmd *cobra.Command, keyUsage string) {
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, keyUsage)
+	cmd.Flags().String(TokenCmdName, "", "token contract address in hexadecimal format")
+	if err := cmd.MarkFlagRequired(TokenCmdName); err != nil {
+		panic(err)
+	}
+}
+
+func addERC20ReceiverFlags(cmd *cobra.Command) {
+	cmd.Flags().String(ToCmdName, "", "receiver address in hexadecimal format")
+	cmd.Flags().Uint64(ToKeyCmdName, 0, "send to the EVM address of the given key of the wallet")
+	cmd.MarkFlagsMutuallyExclusive(ToCmdName, ToKeyCmdName)
+	cmd.MarkFlagsOneRequired(ToCmdName, ToKeyCmdName)
+}
+
+func addERC20AmountFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().String(AmountCmdName, "", usage)
+	if err := cmd.MarkFlagRequired(AmountCmdName); err != nil {
+		panic(err)
+	}
+}
+
+func execERC20CmdInfo(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withERC20Token(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error {
+		consoleWriter := config.WalletConfig.Base.ConsoleWriter
+		consoleWriter.Println(fmt.Sprintf("Token: %s", info.Address))
+		consoleWriter.Println(fmt.Sprintf("Name: %s", info.Name))
+		consoleWriter.Println(fmt.Sprintf("Symbol: %s", info.Symbol))
+		consoleWriter.Println(fmt.Sprintf("Decimals: %d", info.Decimals))
+		consoleWriter.Println(fmt.Sprintf("Total supply: %s", formatTokenAmount(info, info.TotalSupply)))
+		return nil
+	})
+}
+
+func execERC20CmdBalance(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withERC20Token(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error {
+		owner, err := readOwnerFlag(cmd, w, accountNumber)
+		if err != nil {
+			return err
+		}
+		balance, err := w.ERC20BalanceOf(cmd.Context(), accountNumber, info.Address, owner)
+		if err != nil {
+			return fmt.Errorf("failed to read balance: %w", err)
+		}
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Balance of %s: %s", owner, formatTokenAmount(info, balance)))
+		return nil
+	})
+}
+
+func execERC20CmdAllowance(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withERC20Token(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error {
+		owner, err := readOwnerFlag(cmd, w, accountNumber)
+		if err != nil {
+			return err
+		}
+		spender, err := readAddressFlag(cmd, SpenderCmdName)
+		if err != nil {
+			return err
+		}
+		allowance, err := w.ERC20Allowance(cmd.Context(), accountNumber, info.Address, owner, spender)
+		if err != nil {
+			return fmt.Errorf("failed to read allowance: %w", err)
+		}
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Allowance of %s for %s: %s", spender, owner, formatTokenAmount(info, allowance)))
+		return nil
+	})
+}
+
+func execERC20CmdTransfer(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withERC20Token(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error {
+		amount, err := readTokenAmount(cmd, info, false)
+		if err != nil {
+			return err
+		}
+		to, err := readReceiver(cmd, w)
+		if err != nil {
+			return err
+		}
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Transferring %s to %s", formatTokenAmount(info, amount), to))
+		return sendContractTx(cmd, config, w, accountNumber, info.Address, evmwallet.ERC20ABI, "transfer", to, amount)
+	})
+}
+
+func execERC20CmdApprove(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withERC20Token(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error {
+		amount, err := readTokenAmount(cmd, info, true)
+		if err != nil {
+			return err
+		}
+		spender, err := readAddressFlag(cmd, SpenderCmdName)
+		if err != nil {
+			return err
+		}
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Approving %s to spend %s", spender, formatTokenAmount(info, amount)))
+		return sendContractTx(cmd, config, w, accountNumber, info.Address, evmwallet.ERC20ABI, "approve", spender, amount)
+	})
+}
+
+func execERC20CmdTransferFrom(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withERC20Token(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error {
+		amount, err := readTokenAmount(cmd, info, false)
+		if err != nil {
+			return err
+		}
+		from, err := readAddressFlag(cmd, FromCmdName)
+		if err != nil {
+			return err
+		}
+		to, err := readReceiver(cmd, w)
+		if err != nil {
+			return err
+		}
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Transferring %s from %s to %s", formatTokenAmount(info, amount), from, to))
+		return sendContractTx(cmd, config, w, accountNumber, info.Address, evmwallet.ERC20ABI, "transferFrom", from, to, amount)
+	})
+}
+
+// withERC20Token initializes the wallet, reads the token metadata and calls "fn".
+func withERC20Token(cmd *cobra.Command, config *types.EvmConfig, fn func(w *evmwallet.Wallet, accountNumber uint64, info *evmwallet.ERC20Info) error) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	token, err := readAddressFlag(cmd, TokenCmdName)
+	if err != nil {
+		return err
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	info, err := w.ERC20Info(cmd.Context(), accountNumber, token)
+	if err != nil {
+		return fmt.Errorf("failed to read token info: %w", err)
+	}
+	return fn(w, accountNumber, info)
+}
+
+// sendContractTx sends transaction which calls the contract method and prints the result.
+func sendContractTx(cmd *cobra.Command, config *types.EvmConfig, w *evmwallet.Wallet, accountNumber uint64, contract common.Address, contractABI *abi.ABI, method string, methodArgs ...any) error {
+	data, err := contractABI.Pack(method, methodArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s call: %w", method, err)
+	}
+	maxGas, estimate, err := readMaxGas(cmd, w, accountNumber, &evm.CallEVMRequest{To: contract.Bytes(), Data: data, Value: big.NewInt(0)}, config)
+	if err != nil {
+		return err
+	}
+	attrs := &evm.TxAttributes{
+		To:   contract.Bytes(),
+		Data: data,
+		Gas:  maxGas,
+	}
+	_, err = submitEvmTx(cmd, config, w, accountNumber, attrs, estimate, contractABI, method, method)
+	return err
+}
+
+// readOwnerFlag returns the address given with "owner" flag or the address of the account.
+func readOwnerFlag(cmd *cobra.Command, w *evmwallet.Wallet, accountNumber uint64) (common.Address, error) {
+	if owner, _ := cmd.Flags().GetString(OwnerCmdName); owner != "" {
+		return readAddressFlag(cmd, OwnerCmdName)
+	}
+	return w.GetAccountAddress(accountNumber)
+}
+
+// readAddressFlag returns the EVM address given in hexadecimal format with the flag.
+func readAddressFlag(cmd *cobra.Command, flag string) (common.Address, error) {
+	addr, err := cmd.Flags().GetString(flag)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, fmt.Errorf("failed to read '%s' parameter: invalid address %q", flag, addr)
+	}
+	return common.HexToAddress(addr), nil
+}
+
+// readTokenAmount returns the "amount" flag value in the smallest token units.
+func readTokenAmount(cmd *cobra.Command, info *evmwallet.ERC20Info, allowZero bool) (*big.Int, error) {
+	amountStr, err := cmd.Flags().GetString(AmountCmdName)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := util.StringToBigAmount(amountStr, uint32(info.Decimals))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", AmountCmdName, err)
+	}
+	if !allowZero && amount.Sign() == 0 {
+		return nil, fmt.Errorf("failed to read '%s' parameter: amount must be positive", AmountCmdName)
+	}
+	return amount, nil
+}
+
+// formatTokenAmount returns the amount in token units followed by the token symbol.
+func formatTokenAmount(info *evmwallet.ERC20Info, amount *big.Int) string {
+	s := util.BigAmountToString(amount, uint32(info.Decimals))
+	if info.Symbol != "" {
+		s += " " + info.Symbol
+	}
+	return s
+}
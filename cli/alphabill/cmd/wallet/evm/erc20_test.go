@@ -0,0 +1,152 @@
+package evm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const testERC20Address = "0x3443919fcbc4476b4f332fd5df6a82fe88dbf521"
+
+// testERC20Calls simulates ERC-20 token with 6 decimals
+func testERC20Calls(t *testing.T) func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+	outputs := map[string][]any{
+		"name":         {"Test Token"},
+		"symbol":       {"TT"},
+		"decimals":     {uint8(6)},
+		"totalSupply":  {big.NewInt(1_000_000_000_000)},
+		"balanceOf":    {big.NewInt(1_500_000)},
+		"allowance":    {big.NewInt(250_000)},
+		"transfer":     {true},
+		"approve":      {true},
+		"transferFrom": {true},
+	}
+	return testContractCalls(t, evmwallet.ERC20ABI, outputs)
+}
+
+// testContractCalls returns the packed outputs of the called contract method, unknown methods revert
+func testContractCalls(t *testing.T, contractABI *abi.ABI, outputs map[string][]any) func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+	return func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+		for name, m := range contractABI.Methods {
+			if len(req.Data) >= 4 && bytes.Equal(req.Data[:4], m.ID) {
+				data, err := m.Outputs.Pack(outputs[name]...)
+				require.NoError(t, err)
+				return &evm.ProcessingDetails{ReturnData: data}
+			}
+		}
+		return &evm.ProcessingDetails{ErrorDetails: "evm runtime error: execution reverted"}
+	}
+}
+
+func Test_evmCmdERC20_read(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	mockConf := &clientMockConf{round: 3, callFn: testERC20Calls(t)}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	spender := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	stdout, err := execEvmCmd(t, homedir, "evm erc20 info --token "+testERC20Address+" --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Token: "+common.HexToAddress(testERC20Address).Hex(),
+		"Name: Test Token",
+		"Symbol: TT",
+		"Decimals: 6",
+		"Total supply: 1'000'000.000'000 TT")
+
+	stdout, err = execEvmCmd(t, homedir, "evm erc20 balance --token "+testERC20Address+" --owner "+owner.Hex()+" --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Balance of "+owner.Hex()+": 1.500'000 TT")
+	data, err := evmwallet.ERC20ABI.Pack("balanceOf", owner)
+	require.NoError(t, err)
+	require.Equal(t, data, mockConf.callReq.Data)
+	// balance of the account key by default
+	stdout, err = execEvmCmd(t, homedir, "evm erc20 balance --token "+testERC20Address+" --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	require.Len(t, stdout.Lines, 1)
+	require.Contains(t, stdout.Lines[0], ": 1.500'000 TT")
+	require.Equal(t, mockConf.callReq.From, mockConf.callReq.Data[16:36])
+
+	stdout, err = execEvmCmd(t, homedir, "evm erc20 allowance --token "+testERC20Address+" --owner "+owner.Hex()+" --spender "+spender.Hex()+" --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Allowance of "+spender.Hex()+" for "+owner.Hex()+": 0.250'000 TT")
+
+	// errors
+	_, err = execEvmCmd(t, homedir, "evm erc20 info --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `required flag(s) "token" not set`)
+	_, err = execEvmCmd(t, homedir, "evm erc20 info --token 0x12 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `failed to read 'token' parameter: invalid address "0x12"`)
+	_, err = execEvmCmd(t, homedir, "evm erc20 allowance --token "+testERC20Address+" --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `required flag(s) "spender" not set`)
+	mockConf.callFn = func(*evm.CallEVMRequest) *evm.ProcessingDetails { return &evm.ProcessingDetails{} }
+	_, err = execEvmCmd(t, homedir, "evm erc20 info --token "+testERC20Address+" --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read token info: decimals call returned no data")
+}
+
+func Test_evmCmdERC20_send(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000",
+		gasPrice: "10000",
+		callFn:   testERC20Calls(t),
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         1,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	token := common.HexToAddress(testERC20Address)
+
+	verifyTx := func(method string, args ...any) {
+		t.Helper()
+		attrs := &evm.TxAttributes{}
+		require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(attrs))
+		require.Equal(t, token.Bytes(), attrs.To)
+		require.EqualValues(t, 100000, attrs.Gas)
+		data, err := evmwallet.ERC20ABI.Pack(method, args...)
+		require.NoError(t, err)
+		require.Equal(t, data, attrs.Data)
+	}
+
+	stdout, err := execEvmCmd(t, homedir, "evm erc20 transfer --token "+testERC20Address+" --to "+to.Hex()+" --amount 1.5 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Transferring 1.500'000 TT to "+to.Hex(), "Evm transaction succeeded")
+	verifyTx("transfer", to, big.NewInt(1_500_000))
+
+	stdout, err = execEvmCmd(t, homedir, "evm erc20 approve --token "+testERC20Address+" --spender "+to.Hex()+" --amount 0 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Approving "+to.Hex()+" to spend 0.000'000 TT")
+	verifyTx("approve", to, big.NewInt(0))
+
+	stdout, err = execEvmCmd(t, homedir, "evm erc20 transfer-from --token "+testERC20Address+" --from "+from.Hex()+" --to "+to.Hex()+" --amount 0.000001 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Transferring 0.000'001 TT from "+from.Hex()+" to "+to.Hex())
+	verifyTx("transferFrom", from, to, big.NewInt(1))
+
+	// errors
+	_, err = execEvmCmd(t, homedir, "evm erc20 transfer --token "+testERC20Address+" --to "+to.Hex()+" --amount 1.0000001 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'amount' parameter: invalid precision")
+	_, err = execEvmCmd(t, homedir, "evm erc20 transfer --token "+testERC20Address+" --to "+to.Hex()+" --amount 0 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'amount' parameter: amount must be positive")
+	_, err = execEvmCmd(t, homedir, "evm erc20 transfer --token "+testERC20Address+" --amount 1 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "at least one of the flags in the group [to to-key] is required")
+	_, err = execEvmCmd(t, homedir, "evm erc20 transfer-from --token "+testERC20Address+" --to "+to.Hex()+" --amount 1 --max-gas 100000 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `required flag(s) "from" not set`)
+}
@@ -2,17 +2,18 @@ package evm
 
 import (
 	"encoding/hex"
-	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/client"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/util"
 	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
 	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
@@ -22,12 +23,16 @@ const (
 	DataCmdName       = "data"
 	MaxGasCmdName     = "max-gas"
 	ValueCmdName      = "value"
+	AbiCmdName        = "abi"
+	MethodCmdName     = "method"
+	ArtifactCmdName   = "artifact"
+	ContractCmdName   = "contract"
+	LinkCmdName       = "link"
 	ScSizeLimit24Kb   = 24 * 1024
 	DefaultEvmAddrLen = 20
 	DefaultCallMaxGas = 50000000
 
 	AlphabillApiURLCmdName = "alphabill-api-uri"
-	DefaultEvmNodeRestURL  = "localhost:29866"
 )
 
 func NewEvmCmd(config *types.WalletConfig) *cobra.Command {
@@ -37,95 +42,118 @@ func NewEvmCmd(config *types.WalletConfig) *cobra.Command {
 		Short: "interact with alphabill EVM partition",
 	}
 	cmd.AddCommand(evmCmdDeploy(evmConfig))
+	cmd.AddCommand(evmCmdPredictAddress(evmConfig))
+	cmd.AddCommand(evmCmdVerify(evmConfig))
+	cmd.AddCommand(evmCmdContract(evmConfig))
 	cmd.AddCommand(evmCmdExecute(evmConfig))
+	cmd.AddCommand(evmCmdRun(evmConfig))
 	cmd.AddCommand(evmCmdCall(evmConfig))
+	cmd.AddCommand(evmCmdEstimateGas(evmConfig))
+	cmd.AddCommand(evmCmdSend(evmConfig))
+	cmd.AddCommand(evmCmdTxStatus(evmConfig))
+	cmd.AddCommand(evmCmdLogs(evmConfig))
+	cmd.AddCommand(evmCmdERC20(evmConfig))
+	cmd.AddCommand(evmCmdNFT(evmConfig))
+	cmd.AddCommand(evmCmdServeJsonRpc(evmConfig))
 	cmd.AddCommand(evmCmdBalance(evmConfig))
-	cmd.PersistentFlags().StringVarP(&evmConfig.NodeURL, AlphabillApiURLCmdName, "r", DefaultEvmNodeRestURL, "alphabill EVM partition node REST URI to connect to")
+	cmd.AddCommand(evmCmdSignMessage(evmConfig))
+	cmd.AddCommand(evmCmdSignTypedData(evmConfig))
+	cmd.AddCommand(evmCmdVerifySignature(evmConfig))
+	cmd.PersistentFlags().StringVarP(&evmConfig.NodeURL, AlphabillApiURLCmdName, "r", args.DefaultEvmRpcUrl, "alphabill EVM partition node RPC URI to connect to")
 	return cmd
 }
 
 func evmCmdDeploy(config *types.EvmConfig) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "deploy",
+		Use:   "deploy [constructor arguments...]",
 		Short: "deploys a new smart contract on evm partition by sending a transaction on the block chain",
 		Long: "Executes smart contract deployment by sending a transaction on the block chain." +
-			"On success the new smart contract address is printed as result and it can be used to execute/call smart contract functions",
+			"On success the new smart contract address is printed as result and it can be used to execute/call smart contract functions.\n" +
+			"Contract code is given either as raw hex with the \"" + DataCmdName + "\" flag or it is read from the compiler " +
+			"artifact given with the \"" + ArtifactCmdName + "\" flag. Constructor arguments are ABI encoded and appended to the code. " +
+			abiArgsHelp + "\n" +
+			"With the \"" + Create2CmdName + "\" flag the contract is deployed by calling the CREATE2 factory contract, " +
+			"the address of the contract depends only on the factory address, salt and contract code and it can be " +
+			"calculated in advance with the \"predict-address\" command.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return execEvmCmdDeploy(cmd, config)
+			return execEvmCmdDeploy(cmd, args, config)
 		},
 	}
 	// account from which to call - pay for the transaction
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for sending the transaction")
-	// data - smart contract code
-	cmd.Flags().String(DataCmdName, "", "contract code as hex string")
+	// data or artifact - smart contract code
+	addDeployFlags(cmd)
+	addCreate2Flags(cmd)
+	cmd.Flags().String(NameCmdName, "", "add the deployed contract to the wallet with the name, "+
+		"the name can be used instead of the contract address (see \"contract\" command)")
 	// max-gas
-	cmd.Flags().Uint64(MaxGasCmdName, 0, "maximum amount of gas user is willing to spend")
-	if err := cmd.MarkFlagRequired(DataCmdName); err != nil {
-		panic(err)
-	}
-	if err := cmd.MarkFlagRequired(MaxGasCmdName); err != nil {
-		panic(err)
-	}
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive(NameCmdName, NoWaitCmdName)
 	return cmd
 }
 
 func evmCmdExecute(config *types.EvmConfig) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "execute",
+		Use:   "execute [method arguments...]",
 		Short: "executes smart contract call by sending a transaction on the block chain",
 		Long: "Executes smart contract call by sending a transaction on the block chain." +
-			"State changes are persisted and result is stored in block chain",
+			"State changes are persisted and result is stored in block chain.\n" +
+			"Call data is given either as raw hex with the \"" + DataCmdName + "\" flag or it is ABI encoded " +
+			"from the \"" + MethodCmdName + "\" flag and positional method arguments. " + abiArgsHelp + "\n" +
+			"Many transactions can be sent at once with the \"" + BatchCmdName + "\" flag. " + batchFileHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return execEvmCmdExecute(cmd, config)
+			return execEvmCmdExecute(cmd, args, config)
 		},
 	}
 	// account from which to call - pay for the transaction
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for sending the transaction")
 	// to address - smart contract to call
-	cmd.Flags().String(args.AddressCmdName, "", "smart contract address in hexadecimal format, must start with 0x and be 20 characters in length")
+	cmd.Flags().String(args.AddressCmdName, "", "smart contract address in hexadecimal format or name of the contract added with \"contract add\" command")
 	// data - function ID + parameter
 	cmd.Flags().String(DataCmdName, "", "4 byte function ID and optionally argument in hex")
-	// max amount of gas user is willing to spend
-	cmd.Flags().Uint64(MaxGasCmdName, 0, "maximum amount of gas user is willing to spend")
-	if err := cmd.MarkFlagRequired(args.AddressCmdName); err != nil {
-		panic(err)
-	}
-	if err := cmd.MarkFlagRequired(DataCmdName); err != nil {
-		panic(err)
-	}
-	if err := cmd.MarkFlagRequired(MaxGasCmdName); err != nil {
-		panic(err)
-	}
+	cmd.Flags().String(BatchCmdName, "", "JSON file with the transactions to send, the transactions are sent without "+
+		"waiting for the previous one to be confirmed")
+	addAbiFlags(cmd, BatchCmdName)
+	// value, default 0
+	addValueFlag(cmd)
+	// max amount of gas user is willing to spend, required with the address, the default of the batch transactions
+	addOptionalMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	cmd.MarkFlagsOneRequired(args.AddressCmdName, BatchCmdName)
+	cmd.MarkFlagsMutuallyExclusive(BatchCmdName, args.AddressCmdName)
+	cmd.MarkFlagsMutuallyExclusive(BatchCmdName, DataCmdName)
+	cmd.MarkFlagsMutuallyExclusive(BatchCmdName, MethodCmdName)
+	cmd.MarkFlagsMutuallyExclusive(BatchCmdName, ValueCmdName)
 	return cmd
 }
 
 func evmCmdCall(config *types.EvmConfig) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "call",
+		Use:   "call [method arguments...]",
 		Short: "executes a smart contract call immediately without creating a transaction on the block chain",
 		Long: "Executes a smart contract call immediately without creating a transaction on the block chain." +
-			"State changes are not persisted and nothing is added to the block. Often used for executing read-only smart contract functions.",
+			"State changes are not persisted and nothing is added to the block. Often used for executing read-only smart contract functions.\n" +
+			"Call data is given either as raw hex with the \"" + DataCmdName + "\" flag or it is ABI encoded " +
+			"from the \"" + MethodCmdName + "\" flag and positional method arguments. " + abiArgsHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return execEvmCmdCall(cmd, config)
+			return execEvmCmdCall(cmd, args, config)
 		},
 	}
 	// account from which to call - pay for the transaction
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for from address in evm call")
 	// to address - smart contract to call
-	cmd.Flags().String(args.AddressCmdName, "", "to address in hexadecimal format, must be 20 characters in length")
+	cmd.Flags().String(args.AddressCmdName, "", "to address in hexadecimal format or name of the contract added with \"contract add\" command")
 	// data
 	cmd.Flags().String(DataCmdName, "", "data as hex string")
+	addAbiFlags(cmd)
 	// max amount of gas user is willing to spend
 	cmd.Flags().Uint64(MaxGasCmdName, DefaultCallMaxGas, "(optional) maximum amount of gas user is willing to spend")
 	// value, default 0
-	cmd.Flags().Uint64(ValueCmdName, 0, "(optional) value to transfer")
-	_ = cmd.Flags().MarkHidden(ValueCmdName)
+	addValueFlag(cmd)
 	if err := cmd.MarkFlagRequired(args.AddressCmdName); err != nil {
 		panic(err)
 	}
-	if err := cmd.MarkFlagRequired(DataCmdName); err != nil {
-		panic(err)
-	}
 	return cmd
 }
 
@@ -144,21 +172,37 @@ func evmCmdBalance(config *types.EvmConfig) *cobra.Command {
 }
 
 func initEvmWallet(cobraCmd *cobra.Command, config *types.EvmConfig) (*evmwallet.Wallet, error) {
-	uri, err := cobraCmd.Flags().GetString(AlphabillApiURLCmdName)
+	am, err := account.LoadExistingAccountManager(config.WalletConfig)
 	if err != nil {
 		return nil, err
 	}
-	am, err := account.LoadExistingAccountManager(config.WalletConfig)
+	evmClient, err := newEvmClient(cobraCmd)
 	if err != nil {
+		am.Close()
 		return nil, err
 	}
-	wallet, err := evmwallet.New(evm.DefaultPartitionID, uri, am)
+	wallet, err := evmwallet.New(cobraCmd.Context(), am, evmClient)
 	if err != nil {
+		am.Close()
+		evmClient.Close()
 		return nil, err
 	}
 	return wallet, nil
 }
 
+// newEvmClient creates the evm partition client for the node given with "alphabill-api-uri" flag.
+func newEvmClient(cobraCmd *cobra.Command) (sdktypes.EvmPartitionClient, error) {
+	uri, err := cobraCmd.Flags().GetString(AlphabillApiURLCmdName)
+	if err != nil {
+		return nil, err
+	}
+	evmClient, err := client.NewEvmPartitionClient(cobraCmd.Context(), args.BuildRpcUrl(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial evm node rpc: %w", err)
+	}
+	return evmClient, nil
+}
+
 // readHexFlag returns nil in case array is empty (weird behaviour by cobra)
 func readHexFlag(cmd *cobra.Command, flag string) ([]byte, error) {
 	str, err := cmd.Flags().GetString(flag)
@@ -175,7 +219,7 @@ func readHexFlag(cmd *cobra.Command, flag string) ([]byte, error) {
 	return res, err
 }
 
-func execEvmCmdDeploy(cmd *cobra.Command, config *types.EvmConfig) error {
+func execEvmCmdDeploy(cmd *cobra.Command, constructorArgs []string, config *types.EvmConfig) error {
 	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
 	if err != nil {
 		return fmt.Errorf("key parameter read failed: %w", err)
@@ -185,33 +229,95 @@ func execEvmCmdDeploy(cmd *cobra.Command, config *types.EvmConfig) error {
 		return fmt.Errorf("evm wallet init failed: %w", err)
 	}
 	defer w.Shutdown()
-	code, err := readHexFlag(cmd, DataCmdName)
+	code, contractABI, err := readDeployCode(cmd, constructorArgs)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", DataCmdName, err)
+		return err
 	}
-	if len(code) > ScSizeLimit24Kb {
-		return fmt.Errorf("contract code too big, maximum size is 24Kb")
+	create2, factory, salt, err := readCreate2Flags(cmd)
+	if err != nil {
+		return err
 	}
-	maxGas, err := cmd.Flags().GetUint64(MaxGasCmdName)
+	name, err := cmd.Flags().GetString(NameCmdName)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", MaxGasCmdName, err)
+		return fmt.Errorf("failed to read '%s' parameter: %w", NameCmdName, err)
+	}
+	if name != "" {
+		if err := verifyContractNameAvailable(config, name); err != nil {
+			return err
+		}
+	}
+	callReq := &evm.CallEVMRequest{Data: code}
+	var contractAddr common.Address
+	if create2 {
+		callReq.To = factory.Bytes()
+		callReq.Data = evmwallet.Create2FactoryCallData(salt, code)
+		contractAddr = evmwallet.Create2Address(factory, salt, evmwallet.InitCodeHash(code))
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Smart contract address: 0x%x (CREATE2 factory 0x%x, salt %s)",
+			contractAddr, factory, salt))
+		// call to an address without code succeeds without deploying anything
+		factoryCode, err := w.GetCode(cmd.Context(), accountNumber, factory)
+		if err != nil {
+			return fmt.Errorf("failed to read CREATE2 factory code: %w", err)
+		}
+		if len(factoryCode) == 0 {
+			return fmt.Errorf("there is no CREATE2 factory at the address 0x%x, deploy the factory first or use '%s' parameter", factory, FactoryCmdName)
+		}
+	}
+	maxGas, estimate, err := readMaxGas(cmd, w, accountNumber, callReq, config)
+	if err != nil {
+		return err
 	}
 	attributes := &evm.TxAttributes{
-		Data: code,
+		To:   callReq.To,
+		Data: callReq.Data,
 		Gas:  maxGas,
 	}
-	result, err := w.SendEvmTx(cmd.Context(), accountNumber, attributes)
-	if err != nil {
-		if errors.Is(err, evmclient.ErrNotFound) {
-			return fmt.Errorf("no evm fee credit for account %d, please add", accountNumber)
+	result, err := submitEvmTx(cmd, config, w, accountNumber, attributes, estimate, contractABI, "", "deploy")
+	if err != nil || result == nil || !result.Success {
+		return err
+	}
+	if create2 {
+		// the factory call may succeed without creating the contract, eg when the address is already taken
+		code, err := w.GetCode(cmd.Context(), accountNumber, contractAddr)
+		if err != nil {
+			return fmt.Errorf("failed to read deployed contract code: %w", err)
+		}
+		if len(code) == 0 {
+			return fmt.Errorf("deploy failed, there is no contract code at the address 0x%x after the CREATE2 deployment", contractAddr)
+		}
+	} else {
+		contractAddr = result.Details.ContractAddr
+	}
+	if name == "" {
+		return nil
+	}
+	return registerDeployedContract(cmd, config, name, contractAddr)
+}
+
+// registerDeployedContract adds the deployed contract to the contract book, the ABI is registered
+// when it can be read from the "abi" or "artifact" file.
+func registerDeployedContract(cmd *cobra.Command, config *types.EvmConfig, name string, addr common.Address) error {
+	c := &evmwallet.Contract{Name: name, Address: addr}
+	for _, flag := range []string{AbiCmdName, ArtifactCmdName} {
+		if abiPath, err := readContractAbiPath(cmd, flag); err == nil && abiPath != "" {
+			c.ABIPath = abiPath
+			break
 		}
-		return fmt.Errorf("deploy failed, %w", err)
 	}
-	printResult(config.WalletConfig.Base.ConsoleWriter, result)
+	if err := addContract(config, c, false); err != nil {
+		return err
+	}
+	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Contract added as %q", name))
 	return nil
 }
 
-func execEvmCmdExecute(cmd *cobra.Command, config *types.EvmConfig) error {
+func execEvmCmdExecute(cmd *cobra.Command, methodArgs []string, config *types.EvmConfig) error {
+	if cmd.Flags().Changed(BatchCmdName) {
+		return execEvmCmdExecuteBatch(cmd, methodArgs, config)
+	}
+	if !cmd.Flags().Changed(MaxGasCmdName) {
+		return fmt.Errorf("required flag(s) %q not set", MaxGasCmdName)
+	}
 	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
 	if err != nil {
 		return fmt.Errorf("key parameter read failed: %w", err)
@@ -222,39 +328,34 @@ func execEvmCmdExecute(cmd *cobra.Command, config *types.EvmConfig) error {
 	}
 	defer w.Shutdown()
 	// get to address
-	toAddr, err := readHexFlag(cmd, args.AddressCmdName)
+	toAddr, abiPath, err := readContractAddress(cmd, config)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
+		return err
 	}
-	if len(toAddr) != DefaultEvmAddrLen {
-		return fmt.Errorf("invalid address %x, address must be 20 bytes", toAddr)
+	// read function ID and arguments
+	fnIDAndArg, contractABI, method, err := readCallData(cmd, methodArgs, abiPath)
+	if err != nil {
+		return err
 	}
-	// read binary contract file
-	fnIDAndArg, err := readHexFlag(cmd, DataCmdName)
+	value, err := readValueFlag(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", DataCmdName, err)
+		return err
 	}
-	maxGas, err := cmd.Flags().GetUint64(MaxGasCmdName)
+	maxGas, estimate, err := readMaxGas(cmd, w, accountNumber, &evm.CallEVMRequest{To: toAddr, Data: fnIDAndArg, Value: value}, config)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", MaxGasCmdName, err)
+		return err
 	}
 	attributes := &evm.TxAttributes{
-		To:   toAddr,
-		Data: fnIDAndArg,
-		Gas:  maxGas,
-	}
-	result, err := w.SendEvmTx(cmd.Context(), accountNumber, attributes)
-	if err != nil {
-		if errors.Is(err, evmclient.ErrNotFound) {
-			return fmt.Errorf("no evm fee credit for account %d, please add", accountNumber)
-		}
-		return fmt.Errorf("excution failed, %w", err)
+		To:    toAddr,
+		Data:  fnIDAndArg,
+		Value: value,
+		Gas:   maxGas,
 	}
-	printResult(config.WalletConfig.Base.ConsoleWriter, result)
-	return nil
+	_, err = submitEvmTx(cmd, config, w, accountNumber, attributes, estimate, contractABI, method, "execution")
+	return err
 }
 
-func execEvmCmdCall(cmd *cobra.Command, config *types.EvmConfig) error {
+func execEvmCmdCall(cmd *cobra.Command, methodArgs []string, config *types.EvmConfig) error {
 	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
 	if err != nil {
 		return fmt.Errorf("key parameter read failed: %w", err)
@@ -265,17 +366,14 @@ func execEvmCmdCall(cmd *cobra.Command, config *types.EvmConfig) error {
 	}
 	defer w.Shutdown()
 	// get to address
-	toAddr, err := readHexFlag(cmd, args.AddressCmdName)
+	toAddr, abiPath, err := readContractAddress(cmd, config)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
-	}
-	if len(toAddr) != DefaultEvmAddrLen {
-		return fmt.Errorf("invalid address %x, address must be 20 bytes", toAddr)
+		return err
 	}
 	// data
-	data, err := readHexFlag(cmd, DataCmdName)
+	data, contractABI, method, err := readCallData(cmd, methodArgs, abiPath)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", DataCmdName, err)
+		return err
 	}
 	if len(data) > ScSizeLimit24Kb {
 		return fmt.Errorf("")
@@ -284,21 +382,21 @@ func execEvmCmdCall(cmd *cobra.Command, config *types.EvmConfig) error {
 	if err != nil {
 		return fmt.Errorf("failed to read '%s' parameter: %w", MaxGasCmdName, err)
 	}
-	value, err := cmd.Flags().GetUint64(ValueCmdName)
+	value, err := readValueFlag(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to read '%s' parameter: %w", ValueCmdName, err)
+		return err
 	}
 	attributes := &evm.CallEVMRequest{
 		To:    toAddr,
 		Data:  data,
-		Value: new(big.Int).SetUint64(value),
+		Value: value,
 		Gas:   maxGas,
 	}
 	result, err := w.EvmCall(cmd.Context(), accountNumber, attributes)
 	if err != nil {
 		return fmt.Errorf("call failed, %w", err)
 	}
-	printResult(config.WalletConfig.Base.ConsoleWriter, result)
+	printResult(config.WalletConfig.Base.ConsoleWriter, result, contractABI, method)
 	return nil
 }
 
@@ -316,16 +414,30 @@ func execEvmCmdBalance(cmd *cobra.Command, config *types.EvmConfig) error {
 	if err != nil {
 		return fmt.Errorf("get balance failed, %w", err)
 	}
-	inAlpha := evmwallet.ConvertBalanceToAlpha(balance)
-	balanceStr := util.AmountToString(inAlpha, 8)
-	balanceEthStr := util.AmountToString(balance.Uint64(), 18)
-	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s (eth: %s)", accountNumber, balanceStr, balanceEthStr))
+	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s (eth: %s)", accountNumber, util.WeiToAlphaString(balance), util.WeiToString(balance)))
 	return nil
 }
 
-func printResult(consoleWriter types.ConsoleWrapper, result *evmclient.Result) {
+// printResult prints the evm execution result. When contract ABI is given it is used to decode the logs
+// and custom errors, when also the method name is given the return data is decoded as the output of the method.
+func printResult(consoleWriter types.ConsoleWrapper, result *evmclient.Result, contractABI *abi.ABI, method string) {
+	if len(result.TxHash) > 0 {
+		consoleWriter.Println(fmt.Sprintf("Evm transaction hash: 0x%x", result.TxHash))
+	}
 	if !result.Success {
 		consoleWriter.Println(fmt.Sprintf("Evm transaction failed: %s", result.Details.ErrorDetails))
+		revert := result.Revert
+		if contractABI != nil {
+			// ABI is needed to decode custom errors
+			if r := evmwallet.DecodeRevertReason(contractABI, result.Details.ReturnData); r != nil {
+				revert = r
+			}
+		}
+		if revert != nil {
+			consoleWriter.Println(fmt.Sprintf("Revert reason: %s", evmwallet.FormatRevertReason(revert)))
+		} else if len(result.Details.ReturnData) > 0 {
+			consoleWriter.Println(fmt.Sprintf("Evm execution returned: %X", result.Details.ReturnData))
+		}
 		consoleWriter.Println(fmt.Sprintf("Evm transaction processing fee: %v", util.AmountToString(result.ActualFee, 8)))
 		return
 	}
@@ -336,9 +448,28 @@ func printResult(consoleWriter types.ConsoleWrapper, result *evmclient.Result) {
 		consoleWriter.Println(fmt.Sprintf("Deployed smart contract address: %x", result.Details.ContractAddr))
 	}
 	for i, l := range result.Details.Logs {
+		if contractABI != nil {
+			if decoded, err := evmwallet.DecodeLog(contractABI, l); err == nil {
+				consoleWriter.Println(fmt.Sprintf("Evm log %v : %s", i, decoded))
+				continue
+			}
+		}
 		consoleWriter.Println(fmt.Sprintf("Evm log %v : %v", i, l))
 	}
 	if len(result.Details.ReturnData) > 0 {
+		if method != "" {
+			values, err := evmwallet.DecodeMethodOutput(contractABI, method, result.Details.ReturnData)
+			if err == nil {
+				for i, v := range values {
+					if v.Name == "" {
+						v.Name = fmt.Sprintf("#%d", i)
+					}
+					consoleWriter.Println(fmt.Sprintf("Evm execution returned: %s", v))
+				}
+				return
+			}
+			consoleWriter.Println(fmt.Sprintf("Failed to decode return data: %v", err))
+		}
 		consoleWriter.Println(fmt.Sprintf("Evm execution returned: %X", result.Details.ReturnData))
 	}
 }
@@ -1,27 +1,36 @@
 package evm
 
 import (
-	"bytes"
+	"context"
 	"crypto"
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
 
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
 	"github.com/alphabill-org/alphabill-go-base/types"
+	abhex "github.com/alphabill-org/alphabill-go-base/types/hex"
 
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
 	cmdtypes "github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	othertestutils "github.com/alphabill-org/alphabill-wallet/internal/testutils"
 	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
 )
 
 func Test_evmCmdDeploy_error_cases(t *testing.T) {
@@ -30,9 +39,19 @@ func Test_evmCmdDeploy_error_cases(t *testing.T) {
 	mockServer, addr := mockClientCalls(t, &clientMockConf{balance: "15000000000000000000", counter: 0})
 	defer mockServer.Close()
 	_, err := execEvmCmd(t, homedir, "evm deploy --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"data\", \"max-gas\" not set")
+	require.ErrorContains(t, err, "required flag(s) \"max-gas\" not set")
 	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"data\" not set")
+	require.ErrorContains(t, err, "at least one of the flags in the group [data artifact] is required")
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --data accbdeef --artifact a.json --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "if any flags in the group [data artifact] are set none of the others can be")
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --data accbdeef 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to encode constructor arguments: contract ABI is required to encode constructor arguments")
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --data accbdeef --link Lib --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `invalid 'link' parameter "Lib", expected name=0x<address>`)
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --data accbdeef --link Lib=0x12 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `invalid 'link' parameter "Lib=0x12", invalid library address "0x12"`)
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --data accb__$b40e1ea0a5d1adc1ab8e5e4a1e8a2ac28c$__deef --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'data' parameter: unresolved library link placeholder(s): __$b40e1ea0a5d1adc1ab8e5e4a1e8a2ac28c$__")
 	_, err = execEvmCmd(t, homedir, "evm deploy --data accbdeef --alphabill-api-uri "+addr.Host)
 	require.ErrorContains(t, err, "required flag(s) \"max-gas\" not set")
 	// smart contract code too big
@@ -89,17 +108,74 @@ func Test_evmCmdDeploy_ok(t *testing.T) {
 	require.EqualValues(t, 1, evmAttributes.Nonce)
 }
 
+func Test_evmCmdDeploy_artifact(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{ContractAddr: common.HexToAddress("0x5555555555555555555555555555555555555555")})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000", // balance is returned by EVM in wei 10^-18
+		nonce:    1,
+		gasPrice: "10000",
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	// Hardhat artifact of a contract using a library
+	libAddr := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	artifactFile := filepath.Join(t.TempDir(), "Token.json")
+	require.NoError(t, os.WriteFile(artifactFile, []byte(`{
+		"contractName": "Token",
+		"abi": [{"type":"constructor","inputs":[{"name":"owner","type":"address"},{"name":"supply","type":"uint256"}]}],
+		"bytecode": "0x6080__$b40e1ea0a5d1adc1ab8e5e4a1e8a2ac28c$__6040",
+		"linkReferences": {"contracts/Math.sol": {"Math": [{"start": 2, "length": 20}]}}
+	}`), 0600))
+
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --artifact "+artifactFile+" 0x1111111111111111111111111111111111111111 1000 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to link contract code: unresolved library link placeholder(s): contracts/Math.sol:Math")
+	_, err = execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --artifact "+artifactFile+" --link Math="+libAddr.Hex()+" --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to encode constructor arguments: constructor: expected 2 argument(s), got 0")
+
+	stdout, err := execEvmCmd(t, homedir, "evm deploy --max-gas 10000 --artifact "+artifactFile+" --link Math="+libAddr.Hex()+" 0x1111111111111111111111111111111111111111 1000 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Evm transaction succeeded",
+		"Deployed smart contract address: 5555555555555555555555555555555555555555")
+	evmAttributes := &evm.TxAttributes{}
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+	contractABI, err := evmwallet.LoadABI(artifactFile)
+	require.NoError(t, err)
+	encodedArgs, err := contractABI.Pack("", common.HexToAddress("0x1111111111111111111111111111111111111111"), big.NewInt(1000))
+	require.NoError(t, err)
+	code := append([]byte{0x60, 0x80}, libAddr.Bytes()...)
+	code = append(code, 0x60, 0x40)
+	require.EqualValues(t, append(code, encodedArgs...), evmAttributes.Data)
+	require.Nil(t, evmAttributes.To)
+}
+
 func Test_evmCmdExecute_error_cases(t *testing.T) {
 	homedir := testutils.CreateNewTestWallet(t)
 	// balance is returned by EVM in wei 10^-18
 	mockServer, addr := mockClientCalls(t, &clientMockConf{balance: "15000000000000000000", counter: 0, gasPrice: "20000000000000000000"})
 	defer mockServer.Close()
 	_, err := execEvmCmd(t, homedir, "evm execute --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"address\", \"data\", \"max-gas\" not set")
+	require.ErrorContains(t, err, "at least one of the flags in the group [address batch] is required")
 	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"address\", \"data\" not set")
-	_, err = execEvmCmd(t, homedir, "evm execute --data accbdeee --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"address\", \"max-gas\" not set")
+	require.ErrorContains(t, err, "at least one of the flags in the group [address batch] is required")
+	_, err = execEvmCmd(t, homedir, "evm execute --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data accbdeee --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "required flag(s) \"max-gas\" not set")
+	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 1000 --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "at least one of the flags in the group [data method batch] is required")
+	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 1000 --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data aabbccdd --method get --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "if any flags in the group [data method] are set none of the others can be; [data method] were all set")
+	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 1000 --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --method get --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "contract ABI is required to call a method, use 'abi' parameter")
+	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 1000 --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data aabbccdd 1 2 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "positional arguments are only allowed together with 'method' parameter")
 	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 1000 --address aabbccddeeff --data aabbccdd --alphabill-api-uri "+addr.Host)
 	require.ErrorContains(t, err, "invalid address aabbccddeeff, address must be 20 bytes")
 	_, err = execEvmCmd(t, homedir, "evm execute --max-gas 1000 --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data aabbkccdd --alphabill-api-uri "+addr.Host)
@@ -156,15 +232,87 @@ func Test_evmCmdExecute_ok(t *testing.T) {
 	require.EqualValues(t, 1, evmAttributes.Nonce)
 }
 
+func Test_evmCmdExecute_simulation(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:      3,
+		balance:    "15000000000000000000",
+		gasPrice:   "10000",
+		callMinGas: 54321,
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	cmdArgs := "evm execute --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --max-gas 100000 --value 5 --data 9021ACFE --alphabill-api-uri " + addr.Host
+
+	stdout, err := execEvmCmd(t, homedir, cmdArgs)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Transaction simulation succeeded, gas used 54321 (max gas 100000)",
+		"Send the transaction? [y/N]: ",
+		"Evm transaction succeeded")
+	require.NotNil(t, mockConf.receivedTx)
+	// the transaction is simulated as a call from the sender
+	attrs := &evm.TxAttributes{}
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(attrs))
+	require.Equal(t, attrs.From, mockConf.callReq.From)
+	require.Equal(t, attrs.To, mockConf.callReq.To)
+	require.Equal(t, attrs.Data, mockConf.callReq.Data)
+	require.Equal(t, attrs.Value, mockConf.callReq.Value)
+
+	// the transaction is not confirmed
+	mockConf.receivedTx = nil
+	stdout, err = execEvmCmdWithInput(t, homedir, "n\n", cmdArgs)
+	require.EqualError(t, err, "execution failed, transaction was not confirmed")
+	testutils.VerifyStdout(t, stdout, "Send the transaction? [y/N]: ")
+	require.Nil(t, mockConf.receivedTx)
+	_, err = execEvmCmdWithInput(t, homedir, "", cmdArgs)
+	require.EqualError(t, err, `execution failed, failed to read the confirmation (use "yes" flag to send without confirmation): EOF`)
+	require.Nil(t, mockConf.receivedTx)
+
+	// the confirmation is not asked
+	stdout, err = execEvmCmdWithInput(t, homedir, "", cmdArgs+" --yes")
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Transaction simulation succeeded, gas used 54321 (max gas 100000)", "Evm transaction succeeded")
+	testutils.VerifyStdoutNotExists(t, stdout, "Send the transaction? [y/N]: ")
+	require.NotNil(t, mockConf.receivedTx)
+
+	// Error(string) with reason "not owner"
+	reasonData, err := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000009" +
+		"6e6f74206f776e65720000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	mockConf.callMinGas = 0
+	mockConf.callResp = &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ErrorDetails: "evm runtime error: execution reverted", ReturnData: reasonData}}
+	mockConf.receivedTx = nil
+	stdout, err = execEvmCmd(t, homedir, cmdArgs)
+	require.EqualError(t, err, `execution failed, transaction simulation failed: evm runtime error: execution reverted, revert reason: Error("not owner")`)
+	testutils.VerifyStdoutNotExists(t, stdout, "Evm transaction succeeded")
+	require.Nil(t, mockConf.receivedTx)
+
+	// simulation is turned off
+	stdout, err = execEvmCmd(t, homedir, cmdArgs+" --skip-simulation")
+	require.NoError(t, err)
+	testutils.VerifyStdoutNotExists(t, stdout, "Transaction simulation succeeded, gas used 54321 (max gas 100000)")
+	require.NotNil(t, mockConf.receivedTx)
+}
+
 func Test_evmCmdCall_error_cases(t *testing.T) {
 	homedir := testutils.CreateNewTestWallet(t)
 	// balance is returned by EVM in wei 10^-18
 	mockServer, addr := mockClientCalls(t, &clientMockConf{balance: "15000000000000000000", counter: 0})
 	defer mockServer.Close()
 	_, err := execEvmCmd(t, homedir, "evm call --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"address\", \"data\" not set")
-	_, err = execEvmCmd(t, homedir, "evm call --alphabill-api-uri "+addr.Host)
-	require.ErrorContains(t, err, "required flag(s) \"address\", \"data\" not set")
+	require.ErrorContains(t, err, "required flag(s) \"address\" not set")
+	_, err = execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "at least one of the flags in the group [data method] is required")
 	_, err = execEvmCmd(t, homedir, "evm call --data accbdeee --alphabill-api-uri "+addr.Host)
 	require.ErrorContains(t, err, "required flag(s) \"address\" not set")
 	_, err = execEvmCmd(t, homedir, "evm call --max-gas 1000 --address aabbccddeeff --data aabbccdd --alphabill-api-uri "+addr.Host)
@@ -243,18 +391,458 @@ func Test_evmCmdCall_ok_defaultGas(t *testing.T) {
 	require.EqualValues(t, data, mockConf.callReq.Data)
 }
 
+func Test_evmCmdCall_abi(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	abiFile := filepath.Join(t.TempDir(), "token.abi")
+	require.NoError(t, os.WriteFile(abiFile, []byte(testTokenABI), 0600))
+	contractABI, err := evmwallet.ParseABI([]byte(testTokenABI))
+	require.NoError(t, err)
+	returnData, err := contractABI.Methods["balanceOf"].Outputs.Pack(big.NewInt(100))
+	require.NoError(t, err)
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	logData, err := contractABI.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(5))
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round: 3,
+		callResp: &evm.CallEVMResponse{
+			ProcessingDetails: &evm.ProcessingDetails{
+				ReturnData: returnData,
+				Logs: []*evm.LogEntry{{
+					Topics: []common.Hash{contractABI.Events["Transfer"].ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+					Data:   logData,
+				}},
+			},
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	stdout, err := execEvmCmd(t, homedir, "evm call --value 0.5 --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile+" --method balanceOf 0x2222222222222222222222222222222222222222 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Evm transaction succeeded",
+		"Evm log 0 : Transfer(from: 0x1111111111111111111111111111111111111111, to: 0x2222222222222222222222222222222222222222, value: 5)",
+		"Evm execution returned: balance (uint256): 100")
+	require.Equal(t, "500000000000000000", mockConf.callReq.Value.String())
+	// verify call data is ABI encoded
+	data, err := contractABI.Pack("balanceOf", to)
+	require.NoError(t, err)
+	require.EqualValues(t, data, mockConf.callReq.Data)
+	// invalid arguments
+	_, err = execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile+" --method balanceOf --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to encode method call: method balanceOf(address): expected 1 argument(s), got 0")
+	_, err = execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile+" --method balanceOf 0x12 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `argument 0 (address account): invalid address "0x12"`)
+	_, err = execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile+" --method mint 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `method "mint" not found in ABI`)
+}
+
+func Test_evmCmdEstimateGas(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	mockConf := &clientMockConf{
+		gasPrice:   "20000000000",
+		callMinGas: 43210,
+		callResp:   &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{}},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	_, err := execEvmCmd(t, homedir, "evm estimate-gas --data aabbccdd --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "required flag(s) \"address\" not set")
+	stdout, err := execEvmCmd(t, homedir, "evm estimate-gas --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data aabbccdd --gas-margin 10 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Estimated gas: 43210",
+		"Max gas with 10% safety margin: 47531",
+		"Gas price: 20000000000 wei",
+		"Estimated max cost: 0.000'950'62")
+	require.EqualValues(t, []byte{0xaa, 0xbb, 0xcc, 0xdd}, mockConf.callReq.Data)
+	// execution fails regardless of gas
+	mockConf.callResp = &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ErrorDetails: "execution reverted"}}
+	_, err = execEvmCmd(t, homedir, "evm estimate-gas --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data aabbccdd --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "gas estimation failed, execution fails with gas limit 50000000: execution reverted")
+}
+
+func Test_evmCmdExecute_autoMaxGas(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:      3,
+		balance:    "15000000000000000000", // balance is returned by EVM in wei 10^-18
+		nonce:      1,
+		gasPrice:   "10000",
+		callMinGas: 43210,
+		callResp:   &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{}},
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	_, err = execEvmCmd(t, homedir, "evm execute --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --max-gas some --data 9021ACFE --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `invalid argument "some" for "--max-gas" flag: expected unsigned integer or "auto"`)
+	stdout, err := execEvmCmd(t, homedir, "evm execute --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --max-gas auto --data 9021ACFE --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Estimated gas: 43210, using max gas: 51852",
+		"Evm transaction succeeded")
+	evmAttributes := &evm.TxAttributes{}
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+	require.EqualValues(t, 51852, evmAttributes.Gas)
+	// deploy
+	stdout, err = execEvmCmd(t, homedir, "evm deploy --max-gas auto --gas-margin 0 --data 9021ACFE --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Estimated gas: 43210, using max gas: 43210")
+	require.Nil(t, mockConf.callReq.To)
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+	require.EqualValues(t, 43210, evmAttributes.Gas)
+}
+
+func Test_evmCmdExecute_batch(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:      3,
+		balance:    "15000000000000000000", // balance is returned by EVM in wei 10^-18
+		nonce:      4,
+		gasPrice:   "10000",
+		callMinGas: 43210,
+		callResp:   &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{}},
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	writeBatch := func(t *testing.T, content string) string {
+		filename := filepath.Join(t.TempDir(), "batch.json")
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+		return filename
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		filename := writeBatch(t, `[{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021ACFE"}]`)
+		_, err := execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+filename+" --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "if any flags in the group [batch address] are set none of the others can be")
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+filename+" 1 2 --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "positional arguments are not allowed together with 'batch' parameter")
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+writeBatch(t, `[]`)+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "failed to read 'batch' parameter: batch file does not contain any transactions")
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+writeBatch(t, `[{"to":"aabbccddeeff","data":"9021ACFE"}]`)+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `invalid transaction #1 in the batch: invalid address "aabbccddeeff", address must be 20 bytes`)
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+writeBatch(t, `[{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521"}]`)+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "invalid transaction #1 in the batch: either 'data' or 'method' must be set")
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+writeBatch(t, `[{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","method":"get"}]`)+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "invalid transaction #1 in the batch: contract ABI is required to call a method, use 'abi' parameter")
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 10000 --batch "+writeBatch(t, `[{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"aa","gas":"some"}]`)+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `failed to read 'batch' parameter: invalid batch file: expected unsigned integer or "auto"`)
+		_, err = execEvmCmd(t, homedir, "evm execute --batch "+filename+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "invalid transaction #1 in the batch: 'gas' is not set and 'max-gas' parameter is not given")
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		filename := writeBatch(t, `[
+			{"to":"0x3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"0x9021ACFE","value":"0.5"},
+			{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021ACFF","gas":"auto"},
+			{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021AC00","gas":50000}
+		]`)
+		stdout, err := execEvmCmd(t, homedir, "evm execute --max-gas 45000 --batch "+filename+" --alphabill-api-uri "+addr.Host)
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout,
+			"Transaction #1 (nonce 4):",
+			"Transaction #2 (nonce 5):",
+			"Transaction #3 (nonce 6):",
+			"Evm transaction succeeded")
+		// the last sent transaction
+		evmAttributes := &evm.TxAttributes{}
+		require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+		require.EqualValues(t, 6, evmAttributes.Nonce)
+		require.EqualValues(t, 50000, evmAttributes.Gas)
+		require.EqualValues(t, []byte{0x90, 0x21, 0xAC, 0x00}, evmAttributes.Data)
+	})
+
+	t.Run("gas of all the transactions is set", func(t *testing.T) {
+		filename := writeBatch(t, `[{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021ACFE","gas":50000}]`)
+		_, err := execEvmCmd(t, homedir, "evm execute --batch "+filename+" --alphabill-api-uri "+addr.Host)
+		require.NoError(t, err)
+		evmAttributes := &evm.TxAttributes{}
+		require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+		require.EqualValues(t, 50000, evmAttributes.Gas)
+	})
+
+	t.Run("simulation fails", func(t *testing.T) {
+		filename := writeBatch(t, `[
+			{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021ACFE","gas":50000},
+			{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021ACFF","gas":10000}
+		]`)
+		mockConf.receivedTx = nil
+		_, err := execEvmCmd(t, homedir, "evm execute --max-gas 45000 --batch "+filename+" --alphabill-api-uri "+addr.Host)
+		require.EqualError(t, err, "batch execution failed, transaction #2: transaction simulation failed: out of gas")
+		require.Nil(t, mockConf.receivedTx)
+
+		// simulation is turned off
+		_, err = execEvmCmd(t, homedir, "evm execute --max-gas 45000 --skip-simulation --batch "+filename+" --alphabill-api-uri "+addr.Host)
+		require.NoError(t, err)
+		require.NotNil(t, mockConf.receivedTx)
+	})
+
+	t.Run("no wait", func(t *testing.T) {
+		filename := writeBatch(t, `[{"to":"3443919fcbc4476b4f332fd5df6a82fe88dbf521","data":"9021ACFE"}]`)
+		stdout, err := execEvmCmd(t, homedir, "evm execute --max-gas 45000 --no-wait --batch "+filename+" --alphabill-api-uri "+addr.Host)
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout, `Use "evm tx-status <tx hash>" to check the status of the transactions`)
+		require.Contains(t, stdout.Lines[0], "Transaction #1 (nonce 4) submitted, tx hash: 0x")
+	})
+}
+
+func Test_evmCmdSend(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000", // balance is returned by EVM in wei 10^-18
+		nonce:    1,
+		gasPrice: "20000000000",
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         4200,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	_, err = execEvmCmd(t, homedir, "evm send --to 0x1111111111111111111111111111111111111111 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `required flag(s) "amount" not set`)
+	_, err = execEvmCmd(t, homedir, "evm send --amount 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "at least one of the flags in the group [to to-key] is required")
+	_, err = execEvmCmd(t, homedir, "evm send --amount 1 --to 0x12 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `failed to read 'to' parameter: invalid address "0x12"`)
+	_, err = execEvmCmd(t, homedir, "evm send --amount 0 --to-key 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'amount' parameter: amount must be positive")
+	_, err = execEvmCmd(t, homedir, "evm send --amount -1 --to-key 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'amount' parameter: invalid amount string \"-1\": not a positive decimal number")
+	_, err = execEvmCmd(t, homedir, "evm send --amount 0.0000000000000000001 --to-key 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'amount' parameter: invalid precision")
+	_, err = execEvmCmd(t, homedir, "evm send --amount 1 --to-key 2 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "failed to read 'to-key' parameter: account key read failed: account does not exist")
+	_, err = execEvmCmd(t, homedir, "evm send --amount 15 --to-key 1 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "insufficient fee credit balance for transaction")
+
+	stdout, err := execEvmCmd(t, homedir, "evm send --amount 1.5 --to 0x1111111111111111111111111111111111111111 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Sending 1.500'000'000'000'000'000 ALPHA (1500000000000000000 wei) to 0x1111111111111111111111111111111111111111",
+		"Max fee: 0.000'420'00 (max gas 21000, gas price 20000000000 wei)",
+		"Evm transaction succeeded",
+		"Evm transaction processing fee: 0.000'042'00")
+	evmAttributes := &evm.TxAttributes{}
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+	require.EqualValues(t, common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes(), evmAttributes.To)
+	require.Equal(t, "1500000000000000000", evmAttributes.Value.String())
+	require.EqualValues(t, 21000, evmAttributes.Gas)
+	require.Empty(t, evmAttributes.Data)
+	// amount in wei to own account
+	_, err = execEvmCmd(t, homedir, "evm send --amount 1000wei --to-key 1 --max-gas 30000 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+	require.Equal(t, evmAttributes.From, evmAttributes.To)
+	require.Equal(t, "1000", evmAttributes.Value.String())
+	require.EqualValues(t, 30000, evmAttributes.Gas)
+}
+
+func Test_evmCmdLogs(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	contractABI, err := evmwallet.ParseABI([]byte(testTokenABI))
+	require.NoError(t, err)
+	abiFile := filepath.Join(t.TempDir(), "token.abi")
+	require.NoError(t, os.WriteFile(abiFile, []byte(testTokenABI), 0600))
+	contract := common.HexToAddress("0x3443919fcbc4476b4f332fd5df6a82fe88dbf521")
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	transferEvent := contractABI.Events["Transfer"]
+	logData, err := transferEvent.Inputs.NonIndexed().Pack(big.NewInt(5))
+	require.NoError(t, err)
+	transferLog := &evm.LogEntry{
+		Address: contract,
+		Topics:  []common.Hash{transferEvent.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    logData,
+	}
+	otherLog := &evm.LogEntry{Address: from, Topics: []common.Hash{common.HexToHash("0x0a")}, Data: []byte{1, 2}}
+	mockConf := &clientMockConf{
+		round: 7,
+		blocks: map[uint64]*types.Block{
+			3: {Transactions: []*types.TransactionRecord{newTestEvmTxRecord(t, otherLog)}},
+			5: {Transactions: []*types.TransactionRecord{newTestEvmTxRecord(t, transferLog)}},
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+
+	_, err = execEvmCmd(t, homedir, "evm logs --from-round 5 --to-round 4 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "'from-round' must not be greater than 'to-round'")
+	_, err = execEvmCmd(t, homedir, "evm logs --event Transfer --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, "contract ABI is required to filter by event, use 'abi' parameter")
+	_, err = execEvmCmd(t, homedir, "evm logs --address 0x12 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `failed to read 'address' parameter: invalid address "0x12"`)
+	_, err = execEvmCmd(t, homedir, "evm logs --topic 0xzz --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `failed to read 'topic' parameter: invalid topic "0xzz"`)
+
+	// all logs of the latest rounds, raw output
+	stdout, err := execEvmCmd(t, homedir, "evm logs --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	require.Len(t, stdout.Lines, 2)
+	require.Contains(t, stdout.Lines[0], "Round 3 tx 0x")
+	require.Contains(t, stdout.Lines[0], "log 0 address 0x1111111111111111111111111111111111111111: topics [0x000000000000000000000000000000000000000000000000000000000000000a] data 0x0102")
+	require.Contains(t, stdout.Lines[1], "Round 5 tx 0x")
+	// decoded with ABI, filtered by event and indexed argument
+	stdout, err = execEvmCmd(t, homedir, "evm logs --abi "+abiFile+" --event Transfer --topic * --topic "+to.Hex()+" --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	require.Len(t, stdout.Lines, 1)
+	require.Contains(t, stdout.Lines[0], "Round 5 tx 0x")
+	require.Contains(t, stdout.Lines[0], "log 0 address "+contract.Hex()+": Transfer(from: 0x1111111111111111111111111111111111111111, to: 0x2222222222222222222222222222222222222222, value: 5)")
+	// filtered by address and round range
+	stdout, err = execEvmCmd(t, homedir, "evm logs --address "+contract.Hex()+" --from-round 1 --to-round 4 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "No logs found in rounds 1 - 4")
+}
+
+func newTestEvmTxRecord(t *testing.T, logs ...*evm.LogEntry) *types.TransactionRecord {
+	txBytes, err := (&types.TransactionOrder{Version: 1, Payload: types.Payload{Type: evm.TransactionTypeEVMCall}}).MarshalCBOR()
+	require.NoError(t, err)
+	details, err := types.Cbor.Marshal(evm.ProcessingDetails{Logs: logs})
+	require.NoError(t, err)
+	return &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: txBytes,
+		ServerMetadata:   &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful, ProcessingDetails: details},
+	}
+}
+
+func Test_evmCmdCall_revert(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	errorsABI := `[
+		{"type":"function","name":"withdraw","inputs":[{"name":"amount","type":"uint256"}],"outputs":[]},
+		{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+	]`
+	abiFile := filepath.Join(t.TempDir(), "bank.abi")
+	require.NoError(t, os.WriteFile(abiFile, []byte(errorsABI), 0600))
+	contractABI, err := evmwallet.ParseABI([]byte(errorsABI))
+	require.NoError(t, err)
+	customErr := contractABI.Errors["InsufficientBalance"]
+	customData, err := customErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err)
+	customData = append(customErr.ID[:4:4], customData...)
+	// Error(string) with reason "not owner"
+	reasonData, err := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000009" +
+		"6e6f74206f776e65720000000000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		callResp: &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ErrorDetails: "evm runtime error: execution reverted", ReturnData: reasonData}},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+
+	stdout, err := execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data 01 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Evm transaction failed: evm runtime error: execution reverted",
+		`Revert reason: Error("not owner")`)
+	// custom error is decoded only with ABI
+	mockConf.callResp.ProcessingDetails.ReturnData = customData
+	stdout, err = execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --data 01 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, fmt.Sprintf("Evm execution returned: %X", customData))
+	stdout, err = execEvmCmd(t, homedir, "evm call --address 3443919fcbc4476b4f332fd5df6a82fe88dbf521 --abi "+abiFile+" --method withdraw 5 --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Revert reason: InsufficientBalance(available: 1, required: 2)")
+}
+
+func Test_evmCmdServeJsonRpc(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	mockServer, addr := mockClientCalls(t, &clientMockConf{balance: "15000000000000000000", round: 3, gasPrice: "210000000"})
+	defer mockServer.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listenAddr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	outputWriter := &testutils.TestConsoleWriter{}
+	ccmd := NewEvmCmd(&cmdtypes.WalletConfig{
+		Base:          &cmdtypes.BaseConfiguration{HomeDir: homedir, ConsoleWriter: outputWriter, Logger: logger.New(t)},
+		WalletHomeDir: filepath.Join(homedir, "wallet")})
+	ccmd.SetArgs([]string{"serve-jsonrpc", "--listen", listenAddr, "--alphabill-api-uri", addr.Host})
+	done := make(chan error, 1)
+	go func() { done <- ccmd.ExecuteContext(ctx) }()
+
+	var client *ethrpc.Client
+	require.Eventually(t, func() bool {
+		client, err = ethrpc.Dial("http://" + listenAddr)
+		if err != nil {
+			return false
+		}
+		var chainID string
+		if err = client.Call(&chainID, "eth_chainId"); err != nil {
+			client.Close()
+			return false
+		}
+		return chainID == "0x3"
+	}, 5*time.Second, 50*time.Millisecond)
+	defer client.Close()
+
+	var accounts []common.Address
+	require.NoError(t, client.Call(&accounts, "eth_accounts"))
+	require.Len(t, accounts, 1)
+	var balance string
+	require.NoError(t, client.Call(&balance, "eth_getBalance", accounts[0], "latest"))
+	require.Equal(t, "0xd02ab486cedc0000", balance)
+	var blockNumber string
+	require.NoError(t, client.Call(&blockNumber, "eth_blockNumber"))
+	require.Equal(t, "0x3", blockNumber)
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+	testutils.VerifyStdout(t, outputWriter, "Serving Ethereum JSON-RPC at http://"+listenAddr+" (chain ID 3)")
+}
+
 func Test_evmCmdBalance(t *testing.T) {
 	homedir := testutils.CreateNewTestWallet(t)
 	// balance is returned by EVM in wei 10^-18
-	mockServer, addr := mockClientCalls(t, &clientMockConf{balance: "15000000000000000000", counter: 0})
+	mockConf := &clientMockConf{balance: "15000000000000000000", counter: 0}
+	mockServer, addr := mockClientCalls(t, mockConf)
 	defer mockServer.Close()
 	stdout, _ := execEvmCmd(t, homedir, "evm balance --alphabill-api-uri "+addr.Host)
 	testutils.VerifyStdout(t, stdout, "#1 15.000'000'00 (eth: 15.000'000'000'000'000'000)")
+	// balance in wei does not fit into uint64
+	mockConf.balance = "1234567890123456789012345678"
+	stdout, err := execEvmCmd(t, homedir, "evm balance --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "#1 1'234'567'890.123'456'78 (eth: 1'234'567'890.123'456'789'012'345'678)")
 	// -k 2 -> no such account
-	_, err := execEvmCmd(t, homedir, "evm balance -k 2 --alphabill-api-uri "+addr.Host)
+	_, err = execEvmCmd(t, homedir, "evm balance -k 2 --alphabill-api-uri "+addr.Host)
 	require.ErrorContains(t, err, "get balance failed, account key read failed: account does not exist")
 }
 
+const testTokenABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
 type clientMockConf struct {
 	balance    string
 	counter    uint64
@@ -265,11 +853,74 @@ type clientMockConf struct {
 	serverMeta *types.ServerMetadata
 	callReq    *evm.CallEVMRequest
 	callResp   *evm.CallEVMResponse
+	// callMinGas - calls with lower gas limit fail with "out of gas" error
+	callMinGas uint64
+	// callFn - when set, handles the calls instead of returning callResp
+	callFn func(req *evm.CallEVMRequest) *evm.ProcessingDetails
+	// blocks served by the state RPC API
+	blocks map[uint64]*types.Block
+}
+
+// stateServiceMock serves the subset of the state RPC API used by the evm commands
+type stateServiceMock struct {
+	br *clientMockConf
+}
+
+func (s *stateServiceMock) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
+	return &sdktypes.RoundInfo{RoundNumber: s.br.round}, nil
+}
+
+func (s *stateServiceMock) SendTransaction(ctx context.Context, tx abhex.Bytes) (abhex.Bytes, error) {
+	txo := &types.TransactionOrder{}
+	if err := types.Cbor.Unmarshal(tx, txo); err != nil {
+		return nil, fmt.Errorf("unable to decode transaction: %w", err)
+	}
+	s.br.receivedTx = txo
+	return txo.Hash(crypto.SHA256)
+}
+
+func (s *stateServiceMock) GetTransactionProof(ctx context.Context, txHash abhex.Bytes) (*sdktypes.TransactionRecordAndProof, error) {
+	if s.br.receivedTx == nil {
+		return nil, nil
+	}
+	txBytes, err := types.Cbor.Marshal(s.br.receivedTx)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := types.Cbor.Marshal(&types.TxRecordProof{
+		TxRecord: &types.TransactionRecord{
+			TransactionOrder: txBytes,
+			ServerMetadata:   s.br.serverMeta,
+		},
+		TxProof: &types.TxProof{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sdktypes.TransactionRecordAndProof{TxRecordProof: proof}, nil
+}
+
+func (s *stateServiceMock) GetBlock(ctx context.Context, roundNumber abhex.Uint64) (abhex.Bytes, error) {
+	b, ok := s.br.blocks[uint64(roundNumber)]
+	if !ok {
+		return nil, nil
+	}
+	return types.Cbor.Marshal(b)
 }
 
 func mockClientCalls(t *testing.T, br *clientMockConf) (*httptest.Server, *url.URL) {
+	rpcServer := ethrpc.NewServer()
+	t.Cleanup(rpcServer.Stop)
+	require.NoError(t, rpcServer.RegisterName("state", &stateServiceMock{br: br}))
+	require.NoError(t, rpcServer.RegisterName("admin", mocksrv.NewAdminServiceMock(mocksrv.WithInfoResponse(&sdktypes.NodeInfoResponse{
+		NetworkID:       3,
+		PartitionID:     evm.DefaultPartitionID,
+		PartitionTypeID: evm.PartitionTypeID,
+	}))))
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch {
+		case r.URL.Path == "/rpc":
+			rpcServer.ServeHTTP(w, r)
 		case strings.Contains(r.URL.Path, "/api/v1/evm/balance/"):
 			writeCBORResponse(t, w, &struct {
 				_       struct{} `cbor:",toarray"`
@@ -292,6 +943,19 @@ func mockClientCalls(t *testing.T, br *clientMockConf) (*httptest.Server, *url.U
 				writeCBORError(t, w, fmt.Errorf("unable to decode request body: %w", err), http.StatusBadRequest)
 				return
 			}
+			if br.callReq.Gas < br.callMinGas {
+				writeCBORResponse(t, w, &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ErrorDetails: "out of gas"}}, http.StatusOK)
+				return
+			}
+			if br.callFn != nil {
+				writeCBORResponse(t, w, &evm.CallEVMResponse{ProcessingDetails: br.callFn(br.callReq)}, http.StatusOK)
+				return
+			}
+			if br.callResp == nil {
+				// successful execution without return data
+				writeCBORResponse(t, w, &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{}}, http.StatusOK)
+				return
+			}
 			writeCBORResponse(t, w, br.callResp, http.StatusOK)
 		case strings.Contains(r.URL.Path, "/api/v1/evm/gasPrice"):
 			writeCBORResponse(t, w, &struct {
@@ -300,41 +964,6 @@ func mockClientCalls(t *testing.T, br *clientMockConf) (*httptest.Server, *url.U
 			}{
 				GasPrice: br.gasPrice,
 			}, http.StatusOK)
-		case strings.Contains(r.URL.Path, "/api/v1/rounds/latest"):
-			writeCBORResponse(t, w, br.round, http.StatusOK)
-		case strings.Contains(r.URL.Path, "/api/v1/transactions"):
-			if r.Method == "POST" {
-				buf := new(bytes.Buffer)
-				if _, err := buf.ReadFrom(r.Body); err != nil {
-					writeCBORError(t, w, fmt.Errorf("reading request body failed: %w", err), http.StatusBadRequest)
-					return
-				}
-				tx := &types.TransactionOrder{}
-				if err := types.Cbor.Unmarshal(buf.Bytes(), tx); err != nil {
-					writeCBORError(t, w, fmt.Errorf("unable to decode request body as transaction: %w", err), http.StatusBadRequest)
-					return
-				}
-				br.receivedTx = tx
-				txHash, err := tx.Hash(crypto.SHA256)
-				require.NoError(t, err)
-				writeCBORResponse(t, w, txHash, http.StatusAccepted)
-				return
-			}
-			txBytes, err := types.Cbor.Marshal(br.receivedTx)
-			require.NoError(t, err)
-			// GET
-			writeCBORResponse(t, w, struct {
-				_        struct{} `cbor:",toarray"`
-				TxRecord *types.TransactionRecord
-				TxProof  *types.TxProof
-			}{
-				TxRecord: &types.TransactionRecord{
-					TransactionOrder: txBytes,
-					ServerMetadata:   br.serverMeta,
-				},
-				TxProof: &types.TxProof{},
-			}, http.StatusOK)
-
 		default:
 			w.WriteHeader(http.StatusNotFound)
 		}
@@ -343,17 +972,23 @@ func mockClientCalls(t *testing.T, br *clientMockConf) (*httptest.Server, *url.U
 	return server, serverAddress
 }
 
+// execEvmCmd executes the command, the transaction is confirmed when asked.
 func execEvmCmd(t *testing.T, homeDir, command string) (*testutils.TestConsoleWriter, error) {
+	return execEvmCmdWithInput(t, homeDir, "y\n", command)
+}
+
+// execEvmCmdWithInput executes the command with the given input of the user.
+func execEvmCmdWithInput(t *testing.T, homeDir, input, command string) (*testutils.TestConsoleWriter, error) {
 	outputWriter := &testutils.TestConsoleWriter{}
 	command = strings.TrimPrefix(command, "evm ")
 	ccmd := NewEvmCmd(&cmdtypes.WalletConfig{
 		Base:          &cmdtypes.BaseConfiguration{HomeDir: homeDir, ConsoleWriter: outputWriter, Logger: logger.New(t)},
 		WalletHomeDir: filepath.Join(homeDir, "wallet")})
 	ccmd.SetArgs(strings.Split(command, " "))
+	ccmd.SetIn(strings.NewReader(input))
 	return outputWriter, ccmd.Execute()
 }
 
-// TODO: duplicated in rest_client_test.go
 // writeCBORResponse replies to the request with the given response and HTTP code.
 func writeCBORResponse(t *testing.T, w http.ResponseWriter, response any, statusCode int) {
 	w.Header().Set("Content-Type", "application/cbor")
@@ -0,0 +1,174 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	GasMarginCmdName = "gas-margin"
+	autoMaxGas       = "auto"
+	defaultGasMargin = 20
+)
+
+// maxGasValue is the value of "max-gas" flag, either amount of gas or "auto" to estimate it.
+type maxGasValue struct {
+	gas  uint64
+	auto bool
+}
+
+func (v *maxGasValue) String() string {
+	if v.auto {
+		return autoMaxGas
+	}
+	return strconv.FormatUint(v.gas, 10)
+}
+
+func (v *maxGasValue) Set(s string) error {
+	if s == autoMaxGas {
+		v.gas, v.auto = 0, true
+		return nil
+	}
+	gas, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return errors.New(`expected unsigned integer or "auto"`)
+	}
+	v.gas, v.auto = gas, false
+	return nil
+}
+
+func (v *maxGasValue) Type() string {
+	return "uint|auto"
+}
+
+// addMaxGasFlag adds required "max-gas" flag which accepts either amount of gas or "auto"
+// and "gas-margin" flag for the latter.
+func addMaxGasFlag(cmd *cobra.Command) {
+	addOptionalMaxGasFlag(cmd)
+	if err := cmd.MarkFlagRequired(MaxGasCmdName); err != nil {
+		panic(err)
+	}
+}
+
+// addOptionalMaxGasFlag adds "max-gas" and "gas-margin" flags like addMaxGasFlag, the command
+// checks itself whether "max-gas" is required.
+func addOptionalMaxGasFlag(cmd *cobra.Command) {
+	cmd.Flags().Var(&maxGasValue{}, MaxGasCmdName, "maximum amount of gas user is willing to spend, "+
+		"\""+autoMaxGas+"\" estimates the gas by simulating the transaction")
+	addGasMarginFlag(cmd)
+}
+
+func addGasMarginFlag(cmd *cobra.Command) {
+	cmd.Flags().Uint64(GasMarginCmdName, defaultGasMargin, "safety margin in percent added to the estimated gas")
+}
+
+/*
+readMaxGas returns the value of "max-gas" flag. In case of "auto" the gas is estimated by
+simulating the transaction and the result is printed, the estimate is returned too so that
+the simulation before sending can reuse it (nil when the gas is given).
+*/
+func readMaxGas(cmd *cobra.Command, w *evmwallet.Wallet, accountNumber uint64, callReq *evm.CallEVMRequest, config *types.EvmConfig) (uint64, *evmwallet.GasEstimate, error) {
+	maxGas, ok := cmd.Flags().Lookup(MaxGasCmdName).Value.(*maxGasValue)
+	if !ok {
+		return 0, nil, fmt.Errorf("failed to read '%s' parameter", MaxGasCmdName)
+	}
+	if !maxGas.auto {
+		return maxGas.gas, nil, nil
+	}
+	estimate, err := estimateGas(cmd, w, accountNumber, callReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Estimated gas: %d, using max gas: %d", estimate.GasUsed, estimate.GasLimit))
+	return estimate.GasLimit, estimate, nil
+}
+
+func estimateGas(cmd *cobra.Command, w *evmwallet.Wallet, accountNumber uint64, callReq *evm.CallEVMRequest) (*evmwallet.GasEstimate, error) {
+	margin, err := cmd.Flags().GetUint64(GasMarginCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", GasMarginCmdName, err)
+	}
+	if callReq.Value == nil {
+		callReq.Value = big.NewInt(0)
+	}
+	estimate, err := w.EstimateGas(cmd.Context(), accountNumber, callReq, margin)
+	if err != nil {
+		return nil, fmt.Errorf("gas estimation failed, %w", err)
+	}
+	return estimate, nil
+}
+
+func evmCmdEstimateGas(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate-gas [method arguments...]",
+		Short: "estimates the gas needed to execute a smart contract call",
+		Long: "Estimates the gas needed to execute a smart contract call by simulating it with different gas limits. " +
+			"Prints the estimated gas, the gas with safety margin added and the maximum cost of the transaction.\n" +
+			"Call data is given either as raw hex with the \"" + DataCmdName + "\" flag or it is ABI encoded " +
+			"from the \"" + MethodCmdName + "\" flag and positional method arguments. " + abiArgsHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdEstimateGas(cmd, args, config)
+		},
+	}
+	// account from which to call - pay for the transaction
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for from address in evm call")
+	// to address - smart contract to call
+	cmd.Flags().String(args.AddressCmdName, "", "smart contract address in hexadecimal format or name of the contract added with \"contract add\" command")
+	// data - function ID + parameter
+	cmd.Flags().String(DataCmdName, "", "4 byte function ID and optionally argument in hex")
+	addAbiFlags(cmd)
+	addValueFlag(cmd)
+	addGasMarginFlag(cmd)
+	if err := cmd.MarkFlagRequired(args.AddressCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdEstimateGas(cmd *cobra.Command, methodArgs []string, config *types.EvmConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	toAddr, abiPath, err := readContractAddress(cmd, config)
+	if err != nil {
+		return err
+	}
+	data, _, _, err := readCallData(cmd, methodArgs, abiPath)
+	if err != nil {
+		return err
+	}
+	value, err := readValueFlag(cmd)
+	if err != nil {
+		return err
+	}
+	estimate, err := estimateGas(cmd, w, accountNumber, &evm.CallEVMRequest{To: toAddr, Data: data, Value: value})
+	if err != nil {
+		return err
+	}
+	margin, err := cmd.Flags().GetUint64(GasMarginCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", GasMarginCmdName, err)
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	consoleWriter.Println(fmt.Sprintf("Estimated gas: %d", estimate.GasUsed))
+	consoleWriter.Println(fmt.Sprintf("Max gas with %d%% safety margin: %d", margin, estimate.GasLimit))
+	consoleWriter.Println(fmt.Sprintf("Gas price: %s wei", estimate.GasPrice))
+	consoleWriter.Println(fmt.Sprintf("Estimated max cost: %s", util.WeiToAlphaString(estimate.Cost())))
+	return nil
+}
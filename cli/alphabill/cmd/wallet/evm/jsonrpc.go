@@ -0,0 +1,106 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+	"github.com/alphabill-org/alphabill-wallet/wallet/evm/ethapi"
+)
+
+const (
+	ListenCmdName  = "listen"
+	ChainIDCmdName = "chain-id"
+
+	defaultJsonRpcListenAddr = "localhost:8545"
+)
+
+func evmCmdServeJsonRpc(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve-jsonrpc",
+		Short: "serves Ethereum JSON-RPC API backed by the wallet",
+		Long: "Starts local Ethereum JSON-RPC server which allows to use Ethereum tooling with the alphabill EVM partition. " +
+			"Common eth_* methods are translated to the EVM partition node API calls, transactions of eth_sendTransaction are " +
+			"signed with the keys of the wallet accounts WITHOUT asking for confirmation, the server should only be exposed " +
+			"to trusted clients. Alphabill rounds are presented as blocks and only the latest state can be queried.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdServeJsonRpc(cmd, config)
+		},
+	}
+	cmd.Flags().String(ListenCmdName, defaultJsonRpcListenAddr, "address to listen for JSON-RPC requests")
+	cmd.Flags().Uint64(ChainIDCmdName, uint64(evm.DefaultPartitionID), "chain ID of the EVM, the EVM partition uses partition ID as chain ID")
+	addGasMarginFlag(cmd)
+	return cmd
+}
+
+func execEvmCmdServeJsonRpc(cmd *cobra.Command, config *types.EvmConfig) error {
+	listenAddr, err := cmd.Flags().GetString(ListenCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ListenCmdName, err)
+	}
+	chainID, err := cmd.Flags().GetUint64(ChainIDCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ChainIDCmdName, err)
+	}
+	gasMargin, err := cmd.Flags().GetUint64(GasMarginCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", GasMarginCmdName, err)
+	}
+	am, err := account.LoadExistingAccountManager(config.WalletConfig)
+	if err != nil {
+		return err
+	}
+	client, err := newEvmClient(cmd)
+	if err != nil {
+		am.Close()
+		return fmt.Errorf("evm client init failed: %w", err)
+	}
+	w, err := evmwallet.New(cmd.Context(), am, client)
+	if err != nil {
+		am.Close()
+		client.Close()
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	rpcServer, err := ethapi.NewServer(client, w, chainID, gasMargin)
+	if err != nil {
+		return err
+	}
+	defer rpcServer.Stop()
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen %s: %w", listenAddr, err)
+	}
+	httpServer := &http.Server{
+		Handler:           rpcServer,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(listener) }()
+
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	consoleWriter.Println(fmt.Sprintf("Serving Ethereum JSON-RPC at http://%s (chain ID %d)", listener.Addr(), chainID))
+	consoleWriter.Println("WARNING: transactions are signed with the wallet keys without confirmation, do not expose the server to untrusted clients")
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("JSON-RPC server failed: %w", err)
+	case <-cmd.Context().Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("JSON-RPC server shutdown failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package evm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	FromRoundCmdName = "from-round"
+	ToRoundCmdName   = "to-round"
+	TopicCmdName     = "topic"
+	EventCmdName     = "event"
+	// defaultLogRounds is the number of latest rounds scanned when start round is not given
+	defaultLogRounds = 100
+	anyTopic         = "*"
+)
+
+func evmCmdLogs(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "lists EVM event logs",
+		Long: "Scans the blocks of the given round range and lists EVM logs emitted by successful transactions, " +
+			"optionally filtered by contract address and topics. When contract ABI is given the logs are decoded.\n" +
+			"Every \"" + TopicCmdName + "\" flag filters the topic at the next position: value is a comma separated " +
+			"list of accepted 32 byte hex topics or \"" + anyTopic + "\" to accept any topic. When \"" + EventCmdName +
+			"\" is given it filters the first topic and \"" + TopicCmdName + "\" flags filter the indexed event arguments.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdLogs(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64(FromRoundCmdName, 0, fmt.Sprintf("first round to scan (default %d rounds before the last round)", defaultLogRounds-1))
+	cmd.Flags().Uint64(ToRoundCmdName, 0, "last round to scan (default latest round)")
+	cmd.Flags().StringSlice(args.AddressCmdName, nil, "contract address(es) which emitted the logs, any address when not set")
+	cmd.Flags().StringArray(TopicCmdName, nil, "topic filter of the next position, can be repeated")
+	cmd.Flags().String(AbiCmdName, "", "contract ABI file (plain ABI JSON or compiler artifact), used to decode the logs")
+	cmd.Flags().String(EventCmdName, "", "name or signature of the event to list, requires ABI")
+	return cmd
+}
+
+func execEvmCmdLogs(cmd *cobra.Command, config *types.EvmConfig) error {
+	filter, contractABI, err := readLogFilter(cmd)
+	if err != nil {
+		return err
+	}
+	uri, err := cmd.Flags().GetString(AlphabillApiURLCmdName)
+	if err != nil {
+		return err
+	}
+	rpcClient, err := rpc.NewClient(cmd.Context(), args.BuildRpcUrl(uri))
+	if err != nil {
+		return fmt.Errorf("failed to dial evm node rpc: %w", err)
+	}
+	defer rpcClient.Close()
+	stateAPI, err := rpc.NewStateAPIClient(cmd.Context(), rpcClient)
+	if err != nil {
+		return err
+	}
+	if filter.ToRound == 0 {
+		roundInfo, err := stateAPI.GetRoundInfo(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to read latest round number: %w", err)
+		}
+		filter.ToRound = roundInfo.RoundNumber
+	}
+	if !cmd.Flags().Changed(FromRoundCmdName) && filter.ToRound >= defaultLogRounds {
+		filter.FromRound = filter.ToRound - defaultLogRounds + 1
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	count := 0
+	err = evmwallet.ScanLogs(cmd.Context(), stateAPI, filter, func(l *evmwallet.Log) error {
+		count++
+		prefix := fmt.Sprintf("Round %d tx 0x%x log %d address %s:", l.RoundNumber, l.TxHash, l.LogIndex, l.Address)
+		if contractABI != nil {
+			if decoded, err := evmwallet.DecodeLog(contractABI, l.LogEntry); err == nil {
+				consoleWriter.Println(fmt.Sprintf("%s %s", prefix, decoded))
+				return nil
+			}
+		}
+		topics := make([]string, len(l.Topics))
+		for i, t := range l.Topics {
+			topics[i] = t.Hex()
+		}
+		consoleWriter.Println(fmt.Sprintf("%s topics [%s] data 0x%x", prefix, strings.Join(topics, ", "), l.Data))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan logs: %w", err)
+	}
+	if count == 0 {
+		consoleWriter.Println(fmt.Sprintf("No logs found in rounds %d - %d", filter.FromRound, filter.ToRound))
+	}
+	return nil
+}
+
+// readLogFilter returns log filter and contract ABI (nil when not given) from the command flags.
+func readLogFilter(cmd *cobra.Command) (*evmwallet.LogFilter, *abi.ABI, error) {
+	filter := &evmwallet.LogFilter{}
+	var err error
+	if filter.FromRound, err = cmd.Flags().GetUint64(FromRoundCmdName); err != nil {
+		return nil, nil, err
+	}
+	if filter.ToRound, err = cmd.Flags().GetUint64(ToRoundCmdName); err != nil {
+		return nil, nil, err
+	}
+	if filter.ToRound > 0 && filter.FromRound > filter.ToRound {
+		return nil, nil, fmt.Errorf("'%s' must not be greater than '%s'", FromRoundCmdName, ToRoundCmdName)
+	}
+	addresses, err := cmd.Flags().GetStringSlice(args.AddressCmdName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, addr := range addresses {
+		if !common.IsHexAddress(addr) {
+			return nil, nil, fmt.Errorf("failed to read '%s' parameter: invalid address %q", args.AddressCmdName, addr)
+		}
+		filter.Addresses = append(filter.Addresses, common.HexToAddress(addr))
+	}
+	contractABI, err := readAbiFlag(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	event, err := cmd.Flags().GetString(EventCmdName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event != "" {
+		if contractABI == nil {
+			return nil, nil, fmt.Errorf("contract ABI is required to filter by event, use '%s' parameter", AbiCmdName)
+		}
+		e, err := evmwallet.FindEvent(contractABI, event)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read '%s' parameter: %w", EventCmdName, err)
+		}
+		filter.Topics = append(filter.Topics, []common.Hash{e.ID})
+	}
+	topicFlags, err := cmd.Flags().GetStringArray(TopicCmdName)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, tf := range topicFlags {
+		var topics []common.Hash
+		if tf != anyTopic {
+			for _, t := range strings.Split(tf, ",") {
+				h, err := parseTopic(t)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to read '%s' parameter: %w", TopicCmdName, err)
+				}
+				topics = append(topics, h)
+			}
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+	return filter, contractABI, nil
+}
+
+// parseTopic parses 32 byte hex topic, shorter values (eg addresses) are left padded with zeroes.
+func parseTopic(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(strings.TrimSpace(s))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid topic %q: %w", s, err)
+	}
+	if len(b) > common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid topic %q: longer than 32 bytes", s)
+	}
+	return common.BytesToHash(b), nil
+}
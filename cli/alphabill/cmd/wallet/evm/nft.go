@@ -0,0 +1,391 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	TokenIDCmdName  = "token-id"
+	StandardCmdName = "standard"
+	OperatorCmdName = "operator"
+	ApprovedCmdName = "approved"
+
+	standardERC721  = "erc721"
+	standardERC1155 = "erc1155"
+)
+
+func evmCmdNFT(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nft",
+		Short: "interact with ERC-721 and ERC-1155 token contracts",
+		Long: "Reads and transfers non-fungible ERC-721 tokens and ERC-1155 multi tokens using the standard token interfaces. " +
+			"Token ID is given either as decimal number or as hexadecimal number with 0x prefix.",
+	}
+	cmd.AddCommand(nftCmdOwnerOf(config))
+	cmd.AddCommand(nftCmdTokenURI(config))
+	cmd.AddCommand(nftCmdBalance(config))
+	cmd.AddCommand(nftCmdTransfer(config))
+	cmd.AddCommand(nftCmdApprove(config))
+	cmd.AddCommand(nftCmdSetApprovalForAll(config))
+	return cmd
+}
+
+func nftCmdOwnerOf(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner-of",
+		Short: "shows owner of the ERC-721 token",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execNFTCmdOwnerOf(cmd, config)
+		},
+	}
+	addNFTFlags(cmd, "which key to use for from address in evm call")
+	addTokenIDFlag(cmd, true)
+	return cmd
+}
+
+func nftCmdTokenURI(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token-uri",
+		Short: "shows metadata URI of the token",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execNFTCmdTokenURI(cmd, config)
+		},
+	}
+	addNFTFlags(cmd, "which key to use for from address in evm call")
+	addTokenIDFlag(cmd, true)
+	addStandardFlag(cmd)
+	return cmd
+}
+
+func nftCmdBalance(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "shows number of tokens of the owner",
+		Long: "Shows number of ERC-721 tokens of the owner in the contract or amount of ERC-1155 tokens " +
+			"with the given ID (\"" + TokenIDCmdName + "\" flag is required for ERC-1155 tokens).",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execNFTCmdBalance(cmd, config)
+		},
+	}
+	addNFTFlags(cmd, "which key to use for from address in evm call, balance of the key is shown when owner is not given")
+	cmd.Flags().String(OwnerCmdName, "", "(optional) token owner address in hexadecimal format")
+	addTokenIDFlag(cmd, false)
+	addStandardFlag(cmd)
+	return cmd
+}
+
+func nftCmdTransfer(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "transfers token to another address",
+		Long: "Transfers the token using safeTransferFrom method, the transfer fails when the receiver is a contract " +
+			"which does not accept the tokens.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execNFTCmdTransfer(cmd, config)
+		},
+	}
+	addNFTFlags(cmd, "which key to use for sending the transaction")
+	addTokenIDFlag(cmd, true)
+	addStandardFlag(cmd)
+	addERC20ReceiverFlags(cmd)
+	cmd.Flags().String(AmountCmdName, "1", "amount of ERC-1155 tokens to transfer")
+	cmd.Flags().String(DataCmdName, "", "(optional) data as hex string passed to the receiver of ERC-1155 tokens")
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	return cmd
+}
+
+func nftCmdApprove(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve",
+		Short: "allows the spender to transfer the ERC-721 token on behalf of the account",
+		Long:  "Allows the spender to transfer the ERC-721 token, zero address revokes the approval.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execNFTCmdApprove(cmd, config)
+		},
+	}
+	addNFTFlags(cmd, "which key to use for sending the transaction")
+	addTokenIDFlag(cmd, true)
+	cmd.Flags().String(SpenderCmdName, "", "spender address in hexadecimal format")
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	if err := cmd.MarkFlagRequired(SpenderCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func nftCmdSetApprovalForAll(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-approval-for-all",
+		Short: "allows or disallows the operator to transfer all tokens of the account",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execNFTCmdSetApprovalForAll(cmd, config)
+		},
+	}
+	addNFTFlags(cmd, "which key to use for sending the transaction")
+	addStandardFlag(cmd)
+	cmd.Flags().String(OperatorCmdName, "", "operator address in hexadecimal format")
+	cmd.Flags().Bool(ApprovedCmdName, true, "approve the operator, use --approved=false to revoke the approval")
+	addMaxGasFlag(cmd)
+	addTxSubmitFlags(cmd)
+	if err := cmd.MarkFlagRequired(OperatorCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func addNFTFlags(cmd *cobra.Command, keyUsage string) {
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, keyUsage)
+	cmd.Flags().String(ContractCmdName, "", "token contract address in hexadecimal format")
+	if err := cmd.MarkFlagRequired(ContractCmdName); err != nil {
+		panic(err)
+	}
+}
+
+func addTokenIDFlag(cmd *cobra.Command, required bool) {
+	cmd.Flags().String(TokenIDCmdName, "", "token ID as decimal or 0x prefixed hexadecimal number")
+	if required {
+		if err := cmd.MarkFlagRequired(TokenIDCmdName); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func addStandardFlag(cmd *cobra.Command) {
+	cmd.Flags().String(StandardCmdName, standardERC721, fmt.Sprintf("token standard of the contract, %s or %s", standardERC721, standardERC1155))
+}
+
+func execNFTCmdOwnerOf(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withNFTContract(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error {
+		tokenID, err := readTokenID(cmd)
+		if err != nil {
+			return err
+		}
+		owner, err := w.ERC721OwnerOf(cmd.Context(), accountNumber, contract, tokenID)
+		if err != nil {
+			return fmt.Errorf("failed to read owner: %w", err)
+		}
+		c := w.ERC721Collection(cmd.Context(), accountNumber, contract)
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("ID='%s', symbol='%s', name='%s', owner='%s' (%s)",
+			tokenID, c.Symbol, c.Name, owner, standardERC721))
+		return nil
+	})
+}
+
+func execNFTCmdTokenURI(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withNFTContract(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error {
+		tokenID, err := readTokenID(cmd)
+		if err != nil {
+			return err
+		}
+		standard, err := readStandard(cmd)
+		if err != nil {
+			return err
+		}
+		consoleWriter := config.WalletConfig.Base.ConsoleWriter
+		if standard == standardERC1155 {
+			uri, err := w.ERC1155URI(cmd.Context(), accountNumber, contract, tokenID)
+			if err != nil {
+				return fmt.Errorf("failed to read token URI: %w", err)
+			}
+			consoleWriter.Println(fmt.Sprintf("ID='%s', URI='%s' (%s)", tokenID, uri, standard))
+			return nil
+		}
+		uri, err := w.ERC721TokenURI(cmd.Context(), accountNumber, contract, tokenID)
+		if err != nil {
+			return fmt.Errorf("failed to read token URI: %w", err)
+		}
+		c := w.ERC721Collection(cmd.Context(), accountNumber, contract)
+		consoleWriter.Println(fmt.Sprintf("ID='%s', symbol='%s', name='%s', URI='%s' (%s)", tokenID, c.Symbol, c.Name, uri, standard))
+		return nil
+	})
+}
+
+func execNFTCmdBalance(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withNFTContract(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error {
+		standard, err := readStandard(cmd)
+		if err != nil {
+			return err
+		}
+		owner, err := readOwnerFlag(cmd, w, accountNumber)
+		if err != nil {
+			return err
+		}
+		consoleWriter := config.WalletConfig.Base.ConsoleWriter
+		if standard == standardERC1155 {
+			tokenID, err := readTokenID(cmd)
+			if err != nil {
+				return err
+			}
+			balance, err := w.ERC1155BalanceOf(cmd.Context(), accountNumber, contract, owner, tokenID)
+			if err != nil {
+				return fmt.Errorf("failed to read balance: %w", err)
+			}
+			consoleWriter.Println(fmt.Sprintf("ID='%s', contract='%s', owner='%s', amount='%s' (%s)", tokenID, contract, owner, balance, standard))
+			return nil
+		}
+		balance, err := w.ERC721BalanceOf(cmd.Context(), accountNumber, contract, owner)
+		if err != nil {
+			return fmt.Errorf("failed to read balance: %w", err)
+		}
+		c := w.ERC721Collection(cmd.Context(), accountNumber, contract)
+		consoleWriter.Println(fmt.Sprintf("contract='%s', symbol='%s', name='%s', owner='%s', amount='%s' (%s)",
+			contract, c.Symbol, c.Name, owner, balance, standard))
+		return nil
+	})
+}
+
+func execNFTCmdTransfer(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withNFTContract(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error {
+		tokenID, err := readTokenID(cmd)
+		if err != nil {
+			return err
+		}
+		standard, err := readStandard(cmd)
+		if err != nil {
+			return err
+		}
+		to, err := readReceiver(cmd, w)
+		if err != nil {
+			return err
+		}
+		from, err := w.GetAccountAddress(accountNumber)
+		if err != nil {
+			return err
+		}
+		consoleWriter := config.WalletConfig.Base.ConsoleWriter
+		if standard == standardERC1155 {
+			amount, err := readNFTAmount(cmd)
+			if err != nil {
+				return err
+			}
+			var data []byte
+			if cmd.Flags().Changed(DataCmdName) {
+				if data, err = readHexFlag(cmd, DataCmdName); err != nil {
+					return fmt.Errorf("failed to read '%s' parameter: %w", DataCmdName, err)
+				}
+			}
+			consoleWriter.Println(fmt.Sprintf("Transferring ID='%s', amount='%s' to %s (%s)", tokenID, amount, to, standard))
+			return sendContractTx(cmd, config, w, accountNumber, contract, evmwallet.ERC1155ABI, "safeTransferFrom", from, to, tokenID, amount, data)
+		}
+		if cmd.Flags().Changed(AmountCmdName) || cmd.Flags().Changed(DataCmdName) {
+			return fmt.Errorf("'%s' and '%s' parameters are only supported for %s tokens", AmountCmdName, DataCmdName, standardERC1155)
+		}
+		consoleWriter.Println(fmt.Sprintf("Transferring ID='%s' to %s (%s)", tokenID, to, standard))
+		return sendContractTx(cmd, config, w, accountNumber, contract, evmwallet.ERC721ABI, "safeTransferFrom", from, to, tokenID)
+	})
+}
+
+func execNFTCmdApprove(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withNFTContract(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error {
+		tokenID, err := readTokenID(cmd)
+		if err != nil {
+			return err
+		}
+		spender, err := readAddressFlag(cmd, SpenderCmdName)
+		if err != nil {
+			return err
+		}
+		config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Approving %s to transfer ID='%s' (%s)", spender, tokenID, standardERC721))
+		return sendContractTx(cmd, config, w, accountNumber, contract, evmwallet.ERC721ABI, "approve", spender, tokenID)
+	})
+}
+
+func execNFTCmdSetApprovalForAll(cmd *cobra.Command, config *types.EvmConfig) error {
+	return withNFTContract(cmd, config, func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error {
+		standard, err := readStandard(cmd)
+		if err != nil {
+			return err
+		}
+		operator, err := readAddressFlag(cmd, OperatorCmdName)
+		if err != nil {
+			return err
+		}
+		approved, err := cmd.Flags().GetBool(ApprovedCmdName)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s' parameter: %w", ApprovedCmdName, err)
+		}
+		consoleWriter := config.WalletConfig.Base.ConsoleWriter
+		if approved {
+			consoleWriter.Println(fmt.Sprintf("Approving operator %s for all tokens of %s (%s)", operator, contract, standard))
+		} else {
+			consoleWriter.Println(fmt.Sprintf("Revoking approval of operator %s for all tokens of %s (%s)", operator, contract, standard))
+		}
+		contractABI := evmwallet.ERC721ABI
+		if standard == standardERC1155 {
+			contractABI = evmwallet.ERC1155ABI
+		}
+		return sendContractTx(cmd, config, w, accountNumber, contract, contractABI, "setApprovalForAll", operator, approved)
+	})
+}
+
+// withNFTContract initializes the wallet and calls "fn" with the token contract address.
+func withNFTContract(cmd *cobra.Command, config *types.EvmConfig, fn func(w *evmwallet.Wallet, accountNumber uint64, contract common.Address) error) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	contract, err := readAddressFlag(cmd, ContractCmdName)
+	if err != nil {
+		return err
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	return fn(w, accountNumber, contract)
+}
+
+// readTokenID returns the "token-id" flag value, given either as decimal or 0x prefixed hexadecimal number.
+func readTokenID(cmd *cobra.Command) (*big.Int, error) {
+	str, err := cmd.Flags().GetString(TokenIDCmdName)
+	if err != nil {
+		return nil, err
+	}
+	if str == "" {
+		return nil, fmt.Errorf("'%s' parameter is required", TokenIDCmdName)
+	}
+	id, ok := new(big.Int).SetString(str, 0)
+	if !ok || id.Sign() < 0 {
+		return nil, fmt.Errorf("failed to read '%s' parameter: invalid token ID %q", TokenIDCmdName, str)
+	}
+	return id, nil
+}
+
+// readStandard returns the "standard" flag value, either "erc721" or "erc1155".
+func readStandard(cmd *cobra.Command) (string, error) {
+	standard, err := cmd.Flags().GetString(StandardCmdName)
+	if err != nil {
+		return "", err
+	}
+	switch standard {
+	case standardERC721, standardERC1155:
+		return standard, nil
+	default:
+		return "", fmt.Errorf("failed to read '%s' parameter: unsupported token standard %q, expected %s or %s",
+			StandardCmdName, standard, standardERC721, standardERC1155)
+	}
+}
+
+// readNFTAmount returns the "amount" flag value as positive integer.
+func readNFTAmount(cmd *cobra.Command) (*big.Int, error) {
+	str, err := cmd.Flags().GetString(AmountCmdName)
+	if err != nil {
+		return nil, err
+	}
+	amount, ok := new(big.Int).SetString(str, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("failed to read '%s' parameter: amount must be positive integer", AmountCmdName)
+	}
+	return amount, nil
+}
@@ -0,0 +1,150 @@
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const testNFTAddress = "0x5fbdb2315678afecb367f032d93f642f64180aa3"
+
+var testNFTOwner = common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+func testERC721Calls(t *testing.T) func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+	return testContractCalls(t, evmwallet.ERC721ABI, map[string][]any{
+		"name":      {"Test NFT"},
+		"symbol":    {"TNFT"},
+		"tokenURI":  {"ipfs://nft/42"},
+		"ownerOf":   {testNFTOwner},
+		"balanceOf": {big.NewInt(3)},
+	})
+}
+
+func testERC1155Calls(t *testing.T) func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+	return testContractCalls(t, evmwallet.ERC1155ABI, map[string][]any{
+		"uri":       {"https://example.com/{id}.json"},
+		"balanceOf": {big.NewInt(25)},
+	})
+}
+
+func Test_evmCmdNFT_read(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	mockConf := &clientMockConf{round: 3, callFn: testERC721Calls(t)}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	contract := common.HexToAddress(testNFTAddress)
+	apiFlag := " --alphabill-api-uri " + addr.Host
+
+	stdout, err := execEvmCmd(t, homedir, "evm nft owner-of --contract "+testNFTAddress+" --token-id 0x2a"+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "ID='42', symbol='TNFT', name='Test NFT', owner='"+testNFTOwner.Hex()+"' (erc721)")
+
+	stdout, err = execEvmCmd(t, homedir, "evm nft token-uri --contract "+testNFTAddress+" --token-id 42"+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "ID='42', symbol='TNFT', name='Test NFT', URI='ipfs://nft/42' (erc721)")
+
+	stdout, err = execEvmCmd(t, homedir, "evm nft balance --contract "+testNFTAddress+" --owner "+testNFTOwner.Hex()+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "contract='"+contract.Hex()+"', symbol='TNFT', name='Test NFT', owner='"+testNFTOwner.Hex()+"', amount='3' (erc721)")
+
+	mockConf.callFn = testERC1155Calls(t)
+	stdout, err = execEvmCmd(t, homedir, "evm nft balance --standard erc1155 --contract "+testNFTAddress+" --token-id 7 --owner "+testNFTOwner.Hex()+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "ID='7', contract='"+contract.Hex()+"', owner='"+testNFTOwner.Hex()+"', amount='25' (erc1155)")
+	data, err := evmwallet.ERC1155ABI.Pack("balanceOf", testNFTOwner, big.NewInt(7))
+	require.NoError(t, err)
+	require.Equal(t, data, mockConf.callReq.Data)
+
+	stdout, err = execEvmCmd(t, homedir, "evm nft token-uri --standard erc1155 --contract "+testNFTAddress+" --token-id 7"+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "ID='7', URI='https://example.com/{id}.json' (erc1155)")
+
+	// errors
+	_, err = execEvmCmd(t, homedir, "evm nft owner-of --token-id 1"+apiFlag)
+	require.ErrorContains(t, err, `required flag(s) "contract" not set`)
+	_, err = execEvmCmd(t, homedir, "evm nft owner-of --contract "+testNFTAddress+" --token-id abc"+apiFlag)
+	require.ErrorContains(t, err, `failed to read 'token-id' parameter: invalid token ID "abc"`)
+	_, err = execEvmCmd(t, homedir, "evm nft balance --standard erc1155 --contract "+testNFTAddress+apiFlag)
+	require.ErrorContains(t, err, "'token-id' parameter is required")
+	_, err = execEvmCmd(t, homedir, "evm nft balance --standard erc20 --contract "+testNFTAddress+apiFlag)
+	require.ErrorContains(t, err, `failed to read 'standard' parameter: unsupported token standard "erc20"`)
+	_, err = execEvmCmd(t, homedir, "evm nft owner-of --contract "+testNFTAddress+" --token-id 1"+apiFlag)
+	require.ErrorContains(t, err, "failed to read owner: ownerOf call failed: evm runtime error: execution reverted")
+}
+
+func Test_evmCmdNFT_send(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000",
+		gasPrice: "10000",
+		callFn:   testERC721Calls(t),
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         1,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	contract := common.HexToAddress(testNFTAddress)
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	apiFlag := " --max-gas 100000 --alphabill-api-uri " + addr.Host
+
+	verifyTx := func(contractABI *abi.ABI, method string, args ...any) {
+		t.Helper()
+		attrs := &evm.TxAttributes{}
+		require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(attrs))
+		require.Equal(t, contract.Bytes(), attrs.To)
+		data, err := contractABI.Pack(method, args...)
+		require.NoError(t, err)
+		require.Equal(t, data, attrs.Data)
+	}
+	senderAddr := func() common.Address {
+		attrs := &evm.TxAttributes{}
+		require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(attrs))
+		return common.BytesToAddress(attrs.From)
+	}
+
+	stdout, err := execEvmCmd(t, homedir, "evm nft transfer --contract "+testNFTAddress+" --token-id 42 --to "+to.Hex()+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Transferring ID='42' to "+to.Hex()+" (erc721)", "Evm transaction succeeded")
+	verifyTx(evmwallet.ERC721ABI, "safeTransferFrom", senderAddr(), to, big.NewInt(42))
+
+	mockConf.callFn = testERC1155Calls(t)
+	stdout, err = execEvmCmd(t, homedir, "evm nft transfer --standard erc1155 --contract "+testNFTAddress+" --token-id 7 --amount 5 --data 0102 --to "+to.Hex()+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Transferring ID='7', amount='5' to "+to.Hex()+" (erc1155)")
+	verifyTx(evmwallet.ERC1155ABI, "safeTransferFrom", senderAddr(), to, big.NewInt(7), big.NewInt(5), []byte{1, 2})
+	mockConf.callFn = testERC721Calls(t)
+
+	stdout, err = execEvmCmd(t, homedir, "evm nft approve --contract "+testNFTAddress+" --token-id 42 --spender "+to.Hex()+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Approving "+to.Hex()+" to transfer ID='42' (erc721)")
+	verifyTx(evmwallet.ERC721ABI, "approve", to, big.NewInt(42))
+
+	stdout, err = execEvmCmd(t, homedir, "evm nft set-approval-for-all --contract "+testNFTAddress+" --operator "+to.Hex()+" --approved=false"+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Revoking approval of operator "+to.Hex()+" for all tokens of "+contract.Hex()+" (erc721)")
+	verifyTx(evmwallet.ERC721ABI, "setApprovalForAll", to, false)
+
+	// errors
+	_, err = execEvmCmd(t, homedir, "evm nft transfer --contract "+testNFTAddress+" --token-id 42 --amount 2 --to "+to.Hex()+apiFlag)
+	require.ErrorContains(t, err, "'amount' and 'data' parameters are only supported for erc1155 tokens")
+	_, err = execEvmCmd(t, homedir, "evm nft transfer --standard erc1155 --contract "+testNFTAddress+" --token-id 7 --amount 0 --to "+to.Hex()+apiFlag)
+	require.ErrorContains(t, err, "failed to read 'amount' parameter: amount must be positive integer")
+	_, err = execEvmCmd(t, homedir, "evm nft transfer --contract "+testNFTAddress+" --token-id 42"+apiFlag)
+	require.ErrorContains(t, err, "at least one of the flags in the group [to to-key] is required")
+	_, err = execEvmCmd(t, homedir, "evm nft set-approval-for-all --contract "+testNFTAddress+apiFlag)
+	require.ErrorContains(t, err, `required flag(s) "operator" not set`)
+}
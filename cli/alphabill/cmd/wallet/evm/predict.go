@@ -0,0 +1,188 @@
+package evm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	Create2CmdName      = "create2"
+	FactoryCmdName      = "factory"
+	SaltCmdName         = "salt"
+	NonceCmdName        = "nonce"
+	InitCodeHashCmdName = "init-code-hash"
+)
+
+func evmCmdPredictAddress(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "predict-address [constructor arguments...]",
+		Short: "calculates the address of a smart contract before it is deployed",
+		Long: "Calculates the address of a smart contract before it is deployed.\n" +
+			"By default the address of the contract deployed by the next transaction of the account (CREATE) is calculated, " +
+			"the nonce of the account is read from the node unless it is given with the \"" + NonceCmdName + "\" flag.\n" +
+			"With the \"" + Create2CmdName + "\" flag the address of the contract deployed by the CREATE2 factory is " +
+			"calculated from the factory address, salt and hash of the contract creation code. The hash is either given with " +
+			"the \"" + InitCodeHashCmdName + "\" flag or calculated from the contract code given the same way as for the deploy " +
+			"command, including the constructor arguments.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdPredictAddress(cmd, args, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key is used to deploy the contract")
+	cmd.Flags().Uint64(NonceCmdName, 0, "nonce of the deploy transaction, by default the current nonce of the account")
+	addCreate2Flags(cmd)
+	cmd.Flags().String(InitCodeHashCmdName, "", "keccak256 hash of the contract creation code (including the constructor arguments) in hex")
+	addDeployCodeFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive(InitCodeHashCmdName, DataCmdName)
+	cmd.MarkFlagsMutuallyExclusive(InitCodeHashCmdName, ArtifactCmdName)
+	cmd.MarkFlagsMutuallyExclusive(NonceCmdName, Create2CmdName)
+	return cmd
+}
+
+func execEvmCmdPredictAddress(cmd *cobra.Command, constructorArgs []string, config *types.EvmConfig) error {
+	create2, factory, salt, err := readCreate2Flags(cmd)
+	if err != nil {
+		return err
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	if create2 {
+		initCodeHash, err := readInitCodeHash(cmd, constructorArgs)
+		if err != nil {
+			return err
+		}
+		consoleWriter.Println(fmt.Sprintf("Factory address: 0x%x", factory))
+		consoleWriter.Println(fmt.Sprintf("Salt: %s", salt))
+		consoleWriter.Println(fmt.Sprintf("Init code hash: %s", initCodeHash))
+		consoleWriter.Println(fmt.Sprintf("Contract address: 0x%x", evmwallet.Create2Address(factory, salt, initCodeHash)))
+		return nil
+	}
+	for _, flag := range []string{InitCodeHashCmdName, DataCmdName, ArtifactCmdName} {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("'%s' parameter is only allowed together with '%s' parameter", flag, Create2CmdName)
+		}
+	}
+	if len(constructorArgs) > 0 {
+		return fmt.Errorf("constructor arguments are only allowed together with '%s' parameter", Create2CmdName)
+	}
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	var addr, from common.Address
+	var nonce uint64
+	if from, err = w.GetAccountAddress(accountNumber); err != nil {
+		return fmt.Errorf("failed to read account address: %w", err)
+	}
+	if cmd.Flags().Changed(NonceCmdName) {
+		if nonce, err = cmd.Flags().GetUint64(NonceCmdName); err != nil {
+			return fmt.Errorf("failed to read '%s' parameter: %w", NonceCmdName, err)
+		}
+		addr = evmwallet.CreateAddress(from, nonce)
+	} else if addr, nonce, err = w.PredictContractAddress(cmd.Context(), accountNumber); err != nil {
+		return fmt.Errorf("address prediction failed: %w", err)
+	}
+	consoleWriter.Println(fmt.Sprintf("Deployer address: 0x%x", from))
+	consoleWriter.Println(fmt.Sprintf("Nonce: %d", nonce))
+	consoleWriter.Println(fmt.Sprintf("Contract address: 0x%x", addr))
+	return nil
+}
+
+// addCreate2Flags adds the flags to deploy the contract with the CREATE2 factory.
+func addCreate2Flags(cmd *cobra.Command) {
+	cmd.Flags().Bool(Create2CmdName, false, "deploy the contract with the CREATE2 factory, the address of the contract "+
+		"depends only on the factory address, salt and contract code")
+	cmd.Flags().String(FactoryCmdName, evmwallet.DefaultCreate2Factory.Hex(), "address of the CREATE2 factory contract, "+
+		"the factory is called with the salt followed by the contract code")
+	cmd.Flags().String(SaltCmdName, "", "CREATE2 salt in hex, up to 32 bytes, shorter values are left padded with zeros")
+}
+
+// readCreate2Flags returns the factory address and the salt when the "create2" flag is set.
+func readCreate2Flags(cmd *cobra.Command) (bool, common.Address, common.Hash, error) {
+	create2, err := cmd.Flags().GetBool(Create2CmdName)
+	if err != nil {
+		return false, common.Address{}, common.Hash{}, fmt.Errorf("failed to read '%s' parameter: %w", Create2CmdName, err)
+	}
+	if !create2 {
+		for _, flag := range []string{FactoryCmdName, SaltCmdName} {
+			if cmd.Flags().Changed(flag) {
+				return false, common.Address{}, common.Hash{}, fmt.Errorf("'%s' parameter is only allowed together with '%s' parameter", flag, Create2CmdName)
+			}
+		}
+		return false, common.Address{}, common.Hash{}, nil
+	}
+	factory, err := cmd.Flags().GetString(FactoryCmdName)
+	if err != nil {
+		return false, common.Address{}, common.Hash{}, fmt.Errorf("failed to read '%s' parameter: %w", FactoryCmdName, err)
+	}
+	if !common.IsHexAddress(factory) {
+		return false, common.Address{}, common.Hash{}, fmt.Errorf("invalid '%s' parameter %q, expected 20 byte address in hex", FactoryCmdName, factory)
+	}
+	if !cmd.Flags().Changed(SaltCmdName) {
+		return false, common.Address{}, common.Hash{}, fmt.Errorf("'%s' parameter is required together with '%s' parameter", SaltCmdName, Create2CmdName)
+	}
+	saltStr, err := cmd.Flags().GetString(SaltCmdName)
+	if err != nil {
+		return false, common.Address{}, common.Hash{}, fmt.Errorf("failed to read '%s' parameter: %w", SaltCmdName, err)
+	}
+	salt, err := parseSalt(saltStr)
+	if err != nil {
+		return false, common.Address{}, common.Hash{}, fmt.Errorf("invalid '%s' parameter: %w", SaltCmdName, err)
+	}
+	return true, common.HexToAddress(factory), salt, nil
+}
+
+// parseSalt parses the hex salt with optional 0x prefix, shorter than 32 byte values are left padded with zeros.
+func parseSalt(s string) (common.Hash, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s) == 0 || len(s) > 2*common.HashLength {
+		return common.Hash{}, fmt.Errorf("expected 1 to %d bytes in hex", common.HashLength)
+	}
+	b, err := hex.DecodeString(strings.Repeat("0", 2*common.HashLength-len(s)) + s)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("hex decode error: %w", err)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// readInitCodeHash returns the hash of the contract creation code either from the "init-code-hash"
+// flag or calculated from the contract code.
+func readInitCodeHash(cmd *cobra.Command, constructorArgs []string) (common.Hash, error) {
+	if !cmd.Flags().Changed(InitCodeHashCmdName) {
+		if !cmd.Flags().Changed(DataCmdName) && !cmd.Flags().Changed(ArtifactCmdName) {
+			return common.Hash{}, fmt.Errorf("either '%s', '%s' or '%s' parameter is required", InitCodeHashCmdName, DataCmdName, ArtifactCmdName)
+		}
+		code, _, err := readDeployCode(cmd, constructorArgs)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return evmwallet.InitCodeHash(code), nil
+	}
+	if len(constructorArgs) > 0 {
+		return common.Hash{}, fmt.Errorf("constructor arguments are not allowed together with '%s' parameter", InitCodeHashCmdName)
+	}
+	hashStr, err := cmd.Flags().GetString(InitCodeHashCmdName)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to read '%s' parameter: %w", InitCodeHashCmdName, err)
+	}
+	hash, err := hex.DecodeString(strings.TrimPrefix(hashStr, "0x"))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to read '%s' parameter: hex decode error: %w", InitCodeHashCmdName, err)
+	}
+	if len(hash) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid '%s' parameter, hash must be %d bytes", InitCodeHashCmdName, common.HashLength)
+	}
+	return common.BytesToHash(hash), nil
+}
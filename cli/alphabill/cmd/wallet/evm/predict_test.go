@@ -0,0 +1,136 @@
+package evm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+func Test_evmCmdPredictAddress(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	mockConf := &clientMockConf{round: 3, balance: "15000000000000000000", nonce: 7, gasPrice: "10000"}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	apiFlag := " --alphabill-api-uri " + addr.Host
+
+	t.Run("create", func(t *testing.T) {
+		stdout, err := execEvmCmd(t, homedir, "evm predict-address"+apiFlag)
+		require.NoError(t, err)
+		require.Len(t, stdout.Lines, 3)
+		require.Equal(t, "Nonce: 7", stdout.Lines[1])
+		from := common.HexToAddress(strings.TrimPrefix(stdout.Lines[0], "Deployer address: "))
+		require.Equal(t, "Contract address: "+strings.ToLower(evmwallet.CreateAddress(from, 7).Hex()), stdout.Lines[2])
+
+		stdout, err = execEvmCmd(t, homedir, "evm predict-address --nonce 0"+apiFlag)
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout,
+			"Nonce: 0",
+			"Contract address: "+strings.ToLower(evmwallet.CreateAddress(from, 0).Hex()))
+	})
+
+	t.Run("create2", func(t *testing.T) {
+		// example from EIP-1014
+		stdout, err := execEvmCmd(t, homedir, "evm predict-address --create2 --factory 0xdeadbeef00000000000000000000000000000000 --salt 0x000000000000000000000000feed000000000000000000000000000000000000 --data 00")
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout,
+			"Factory address: 0xdeadbeef00000000000000000000000000000000",
+			"Init code hash: 0xbc36789e7a1e281436464229828f817d6612f7b477d66591ff96a9e064bcc98a",
+			"Contract address: 0xd04116cdd17bebe565eb2422f2497e06cc1c9833")
+		stdout, err = execEvmCmd(t, homedir, "evm predict-address --create2 --factory 0xdeadbeef00000000000000000000000000000000 --salt feed000000000000000000000000000000000000 --init-code-hash bc36789e7a1e281436464229828f817d6612f7b477d66591ff96a9e064bcc98a")
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout, "Contract address: 0xd04116cdd17bebe565eb2422f2497e06cc1c9833")
+		// default factory
+		stdout, err = execEvmCmd(t, homedir, "evm predict-address --create2 --salt 1 --data 00")
+		require.NoError(t, err)
+		contractAddr := evmwallet.Create2Address(evmwallet.DefaultCreate2Factory, common.HexToHash("0x01"), evmwallet.InitCodeHash([]byte{0}))
+		testutils.VerifyStdout(t, stdout, "Contract address: "+strings.ToLower(contractAddr.Hex()))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := execEvmCmd(t, homedir, "evm predict-address --salt 01"+apiFlag)
+		require.EqualError(t, err, "'salt' parameter is only allowed together with 'create2' parameter")
+		_, err = execEvmCmd(t, homedir, "evm predict-address --data 00"+apiFlag)
+		require.EqualError(t, err, "'data' parameter is only allowed together with 'create2' parameter")
+		_, err = execEvmCmd(t, homedir, "evm predict-address --create2 --data 00")
+		require.EqualError(t, err, "'salt' parameter is required together with 'create2' parameter")
+		_, err = execEvmCmd(t, homedir, "evm predict-address --create2 --salt 0xkk --data 00")
+		require.ErrorContains(t, err, "invalid 'salt' parameter: hex decode error")
+		_, err = execEvmCmd(t, homedir, "evm predict-address --create2 --salt 0x"+strings.Repeat("00", 33)+" --data 00")
+		require.EqualError(t, err, "invalid 'salt' parameter: expected 1 to 32 bytes in hex")
+		_, err = execEvmCmd(t, homedir, "evm predict-address --create2 --salt 01 --factory 0x1234 --data 00")
+		require.EqualError(t, err, `invalid 'factory' parameter "0x1234", expected 20 byte address in hex`)
+		_, err = execEvmCmd(t, homedir, "evm predict-address --create2 --salt 01")
+		require.EqualError(t, err, "either 'init-code-hash', 'data' or 'artifact' parameter is required")
+		_, err = execEvmCmd(t, homedir, "evm predict-address --create2 --salt 01 --init-code-hash aabb")
+		require.EqualError(t, err, "invalid 'init-code-hash' parameter, hash must be 32 bytes")
+	})
+}
+
+func Test_evmCmdDeploy_create2(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{ReturnData: make([]byte, 20)})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000",
+		nonce:    1,
+		gasPrice: "10000",
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	salt := common.HexToHash("0xfeed")
+	contractAddr := evmwallet.Create2Address(evmwallet.DefaultCreate2Factory, salt, evmwallet.InitCodeHash([]byte{0x90, 0x21, 0xAC, 0xFE}))
+	// addresses with code, the code is read by a call without receiver which loads the code of the address
+	deployed := map[common.Address]bool{evmwallet.DefaultCreate2Factory: true, contractAddr: true}
+	mockConf.callFn = func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+		if req.To == nil && len(req.Data) > 21 && deployed[common.BytesToAddress(req.Data[1:21])] {
+			return &evm.ProcessingDetails{ReturnData: []byte{0x60, 0x80}}
+		}
+		return &evm.ProcessingDetails{}
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+
+	stdout, err := execEvmCmd(t, homedir, "evm deploy --create2 --salt 0xfeed --max-gas 100000 --data 9021ACFE --alphabill-api-uri "+addr.Host)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Smart contract address: "+strings.ToLower(contractAddr.Hex())+" (CREATE2 factory "+strings.ToLower(evmwallet.DefaultCreate2Factory.Hex())+", salt "+salt.Hex()+")",
+		"Evm transaction succeeded")
+	evmAttributes := &evm.TxAttributes{}
+	require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+	require.EqualValues(t, evmwallet.DefaultCreate2Factory.Bytes(), evmAttributes.To)
+	require.EqualValues(t, append(salt.Bytes(), 0x90, 0x21, 0xAC, 0xFE), evmAttributes.Data)
+	require.EqualValues(t, 100000, evmAttributes.Gas)
+
+	t.Run("no code at the contract address after deploy", func(t *testing.T) {
+		deployed[contractAddr] = false
+		defer func() { deployed[contractAddr] = true }()
+		_, err := execEvmCmd(t, homedir, "evm deploy --create2 --salt 0xfeed --name feed --max-gas 100000 --data 9021ACFE --alphabill-api-uri "+addr.Host)
+		require.EqualError(t, err, "deploy failed, there is no contract code at the address "+strings.ToLower(contractAddr.Hex())+" after the CREATE2 deployment")
+		stdout, err := execEvmCmd(t, homedir, "evm contract list")
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout, "No contracts")
+	})
+
+	t.Run("no factory", func(t *testing.T) {
+		deployed[evmwallet.DefaultCreate2Factory] = false
+		defer func() { deployed[evmwallet.DefaultCreate2Factory] = true }()
+		mockConf.receivedTx = nil
+		_, err := execEvmCmd(t, homedir, "evm deploy --create2 --salt 0xfeed --max-gas 100000 --data 9021ACFE --alphabill-api-uri "+addr.Host)
+		require.EqualError(t, err, "there is no CREATE2 factory at the address "+strings.ToLower(evmwallet.DefaultCreate2Factory.Hex())+", deploy the factory first or use 'factory' parameter")
+		require.Nil(t, mockConf.receivedTx)
+	})
+
+	_, err = execEvmCmd(t, homedir, "evm deploy --salt 0xfeed --max-gas 100000 --data 9021ACFE --alphabill-api-uri "+addr.Host)
+	require.EqualError(t, err, "'salt' parameter is only allowed together with 'create2' parameter")
+}
@@ -0,0 +1,658 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
+)
+
+const (
+	ReportCmdName = "report"
+
+	stepDeploy  = "deploy"
+	stepExecute = "execute"
+	stepCall    = "call"
+
+	scenarioFileHelp = "The script is a YAML document with the list of \"steps\", optional \"vars\" (map of " +
+		"variables) and optional \"gas\" (amount of gas or \"" + autoMaxGas + "\" used by the steps which do not set it, " +
+		"\"" + autoMaxGas + "\" by default). Each step has an optional \"name\", the \"action\" (\"" + stepDeploy + "\", " +
+		"\"" + stepExecute + "\" or \"" + stepCall + "\") and optional \"key\" (the \"" + args.KeyCmdName + "\" flag is used when not set). " +
+		"Deploy step takes the contract code either from \"artifact\" (and \"contract\") or from \"data\" (and " +
+		"optional \"abi\"), constructor arguments from \"args\" and library addresses from \"link\" map. Execute and " +
+		"call steps take the contract \"to\" (address, name of an earlier deploy step or name of the contract added " +
+		"with \"contract add\" command), either \"data\" or \"method\" and \"args\" and optional \"abi\" file. " +
+		"All steps take optional \"value\" (in ALPHA or with \"wei\" suffix) and \"gas\". Relative file names are " +
+		"relative to the directory of the script.\n" +
+		"Variables are referenced as ${name} in \"to\", \"data\", \"args\", \"value\" and \"link\": ${vars.<name>} is a " +
+		"variable of the script, ${key.<n>} is the address of the key n, ${<step>.address} is the address of the " +
+		"contract deployed by the step, ${<step>.txHash} is the hash of the transaction of the step, ${<step>.output} " +
+		"is the first value returned by the step and ${<step>.output.<name or index>} is the named or n-th " +
+		"(starting from 0) returned value."
+)
+
+var (
+	scenarioVarRegexp      = regexp.MustCompile(`\$\{([^{}]*)\}`)
+	scenarioStepNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+)
+
+type (
+	// scenario is the script of the "run" command.
+	scenario struct {
+		Gas   *maxGasValue      `yaml:"gas"`
+		Vars  map[string]string `yaml:"vars"`
+		Steps []*scenarioStep   `yaml:"steps"`
+	}
+
+	// scenarioStep is a single step of the scenario, the fields which are not used by the
+	// action of the step must not be set.
+	scenarioStep struct {
+		Name     string            `yaml:"name"`
+		Action   string            `yaml:"action"`
+		Key      uint64            `yaml:"key"`
+		Artifact string            `yaml:"artifact"`
+		Contract string            `yaml:"contract"`
+		ABI      string            `yaml:"abi"`
+		Link     map[string]string `yaml:"link"`
+		To       string            `yaml:"to"`
+		Data     string            `yaml:"data"`
+		Method   string            `yaml:"method"`
+		Args     []string          `yaml:"args"`
+		Value    string            `yaml:"value"`
+		Gas      *maxGasValue      `yaml:"gas"`
+	}
+
+	// scenarioReport is the JSON report of the scenario run.
+	scenarioReport struct {
+		Success bool                  `json:"success"`
+		Steps   []*scenarioStepReport `json:"steps"`
+	}
+
+	// scenarioStepReport is the result of a single step, the steps after the failed step are not reported.
+	scenarioStepReport struct {
+		Name            string                `json:"name,omitempty"`
+		Action          string                `json:"action"`
+		Success         bool                  `json:"success"`
+		Error           string                `json:"error,omitempty"`
+		TxHash          string                `json:"txHash,omitempty"`
+		ContractAddress string                `json:"contractAddress,omitempty"`
+		Gas             uint64                `json:"gas,omitempty"`
+		Fee             string                `json:"fee,omitempty"`
+		ReturnData      string                `json:"returnData,omitempty"`
+		Outputs         []*scenarioStepOutput `json:"outputs,omitempty"`
+	}
+
+	// scenarioStepOutput is a value returned by the step, decoded with the contract ABI.
+	scenarioStepOutput struct {
+		Name  string `json:"name,omitempty"`
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+
+	// scenarioRunner executes the steps of the scenario and keeps the values of the variables.
+	scenarioRunner struct {
+		cmd        *cobra.Command
+		config     *types.EvmConfig
+		w          *evmwallet.Wallet
+		dir        string
+		key        uint64
+		gas        *maxGasValue
+		vars       map[string]string
+		deployABIs map[string]*abi.ABI
+	}
+)
+
+// UnmarshalYAML accepts the amount of gas or "auto" to estimate it.
+func (v *maxGasValue) UnmarshalYAML(node *yaml.Node) error {
+	return v.Set(node.Value)
+}
+
+func evmCmdRun(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <script.yaml>",
+		Short: "runs a scenario of deploy, execute and call steps from a YAML script",
+		Long: "Runs the steps of the YAML script in order, a step is run only after the transaction of the previous " +
+			"step is confirmed and the run stops at the first failed step. Later steps can use the contract addresses " +
+			"and the return values of earlier steps through variables. The results of the steps are saved to a JSON " +
+			"report file.\n" + scenarioFileHelp + "\n" +
+			"Example:\n" +
+			"  steps:\n" +
+			"    - name: token\n" +
+			"      action: deploy\n" +
+			"      artifact: Token.json\n" +
+			"      args: [\"${key.1}\", 1000000]\n" +
+			"    - action: execute\n" +
+			"      to: token\n" +
+			"      method: transfer\n" +
+			"      args: [\"${key.2}\", 100]\n" +
+			"    - name: balance\n" +
+			"      action: call\n" +
+			"      to: token\n" +
+			"      method: balanceOf\n" +
+			"      args: [\"${key.2}\"]",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdRun(cmd, args[0], config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for the steps which do not set it")
+	cmd.Flags().String(ReportCmdName, "", "file to save the JSON report to (if the file already exists it will be "+
+		"overwritten), by default the report is saved next to the script with \".report.json\" extension")
+	cmd.Flags().Bool(SkipSimulationCmdName, false, "do not simulate the transactions before sending them, by default "+
+		"each transaction is executed as a call first and the run stops when the execution fails")
+	addGasMarginFlag(cmd)
+	return cmd
+}
+
+func execEvmCmdRun(cmd *cobra.Command, filename string, config *types.EvmConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	reportFile, err := cmd.Flags().GetString(ReportCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ReportCmdName, err)
+	}
+	if reportFile == "" {
+		reportFile = strings.TrimSuffix(filename, filepath.Ext(filename)) + ".report.json"
+	}
+	script, err := readScenario(filename)
+	if err != nil {
+		return err
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+
+	runner := &scenarioRunner{
+		cmd:        cmd,
+		config:     config,
+		w:          w,
+		dir:        filepath.Dir(filename),
+		key:        accountNumber,
+		gas:        script.Gas,
+		vars:       make(map[string]string),
+		deployABIs: make(map[string]*abi.ABI),
+	}
+	for name, value := range script.Vars {
+		runner.vars["vars."+name] = value
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	report := &scenarioReport{Success: true}
+	var runErr error
+	for i, step := range script.Steps {
+		title := stepTitle(i, step)
+		consoleWriter.Println(title + ":")
+		stepReport, err := runner.run(step)
+		report.Steps = append(report.Steps, stepReport)
+		if err != nil {
+			stepReport.Error = err.Error()
+			report.Success = false
+			runErr = fmt.Errorf("%s failed, %w", title, err)
+			break
+		}
+	}
+	if err := saveScenarioReport(reportFile, report); err != nil {
+		return errors.Join(runErr, err)
+	}
+	consoleWriter.Println("Scenario report saved to file: " + reportFile)
+	return runErr
+}
+
+// readScenario reads and validates the script, the variables in the steps are not resolved.
+func readScenario(filename string) (*scenario, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	script := &scenario{}
+	if err := dec.Decode(script); err != nil {
+		return nil, fmt.Errorf("invalid script: %w", err)
+	}
+	if len(script.Steps) == 0 {
+		return nil, errors.New("invalid script: script does not contain any steps")
+	}
+	names := make(map[string]struct{})
+	for i, step := range script.Steps {
+		if step.Name != "" {
+			if !scenarioStepNameRegexp.MatchString(step.Name) || step.Name == "vars" || step.Name == "key" {
+				return nil, fmt.Errorf("invalid script: invalid name of step #%d %q", i+1, step.Name)
+			}
+			if _, ok := names[step.Name]; ok {
+				return nil, fmt.Errorf("invalid script: step name %q is used more than once", step.Name)
+			}
+			names[step.Name] = struct{}{}
+		}
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("invalid script: %s: %w", stepTitle(i, step), err)
+		}
+	}
+	return script, nil
+}
+
+// validate verifies that the fields required by the action of the step are set and others are not.
+func (s *scenarioStep) validate() error {
+	switch s.Action {
+	case stepDeploy:
+		switch {
+		case s.To != "" || s.Method != "":
+			return errors.New("'to' and 'method' are not allowed in deploy step")
+		case s.Artifact != "" && s.Data != "":
+			return errors.New("only one of 'artifact' and 'data' can be set")
+		case s.Artifact != "" && s.ABI != "":
+			return errors.New("only one of 'artifact' and 'abi' can be set")
+		case s.Artifact == "" && s.Data == "":
+			return errors.New("either 'artifact' or 'data' must be set")
+		case s.Artifact == "" && s.Contract != "":
+			return errors.New("'contract' is only allowed together with 'artifact'")
+		}
+	case stepExecute, stepCall:
+		switch {
+		case s.Artifact != "" || s.Contract != "" || len(s.Link) > 0:
+			return fmt.Errorf("'artifact', 'contract' and 'link' are not allowed in %s step", s.Action)
+		case s.To == "":
+			return errors.New("'to' must be set")
+		case s.Method != "" && s.Data != "":
+			return errors.New("only one of 'data' and 'method' can be set")
+		case s.Method == "" && len(s.Args) > 0:
+			return errors.New("'args' are only allowed together with 'method'")
+		case s.Method == "" && s.Data == "":
+			return errors.New("either 'data' or 'method' must be set")
+		}
+	case "":
+		return errors.New("action is not set")
+	default:
+		return fmt.Errorf("unknown action %q, expected one of %s, %s, %s", s.Action, stepDeploy, stepExecute, stepCall)
+	}
+	return nil
+}
+
+// run runs the step and returns the report of it, the error is not added to the report.
+func (r *scenarioRunner) run(step *scenarioStep) (*scenarioStepReport, error) {
+	report := &scenarioStepReport{Name: step.Name, Action: step.Action}
+	accountNumber := r.key
+	if step.Key != 0 {
+		accountNumber = step.Key
+	}
+	value, err := r.stepValue(step)
+	if err != nil {
+		return report, err
+	}
+	var to, data []byte
+	var contractABI *abi.ABI
+	if step.Action == stepDeploy {
+		data, contractABI, err = r.deployCode(step)
+	} else {
+		to, data, contractABI, err = r.callData(step)
+	}
+	if err != nil {
+		return report, err
+	}
+
+	var result *evmclient.Result
+	if step.Action == stepCall {
+		report.Gas = DefaultCallMaxGas
+		if step.Gas != nil && !step.Gas.auto {
+			report.Gas = step.Gas.gas
+		}
+		result, err = r.w.EvmCall(r.cmd.Context(), accountNumber, &evm.CallEVMRequest{To: to, Data: data, Value: value, Gas: report.Gas})
+		if err != nil {
+			return report, fmt.Errorf("call failed, %w", err)
+		}
+	} else {
+		attrs := &evm.TxAttributes{To: to, Data: data, Value: value}
+		var estimate *evmwallet.GasEstimate
+		if attrs.Gas, estimate, err = r.stepGas(step, accountNumber, attrs); err != nil {
+			return report, err
+		}
+		report.Gas = attrs.Gas
+		// the steps of the script are sent without asking for confirmation
+		opts, err := txSendOptions(r.cmd, r.config.WalletConfig.Base.ConsoleWriter, attrs, estimate, false)
+		if err != nil {
+			return report, err
+		}
+		if result, err = r.w.SendEvmTx(r.cmd.Context(), accountNumber, attrs, opts...); err != nil {
+			return report, txSubmitError(simulationError(err, contractABI), accountNumber, step.Action)
+		}
+		report.TxHash = hexutil.Encode(result.TxHash)
+		report.Fee = util.AmountToString(result.ActualFee, 8)
+	}
+	printResult(r.config.WalletConfig.Base.ConsoleWriter, result, contractABI, step.Method)
+	if !result.Success {
+		return report, executionError(result, contractABI)
+	}
+	report.Success = true
+	if step.Action == stepDeploy {
+		report.ContractAddress = result.Details.ContractAddr.Hex()
+	}
+	if len(result.Details.ReturnData) > 0 {
+		report.ReturnData = hexutil.Encode(result.Details.ReturnData)
+	}
+	if step.Method != "" && len(result.Details.ReturnData) > 0 {
+		values, err := evmwallet.DecodeMethodOutput(contractABI, step.Method, result.Details.ReturnData)
+		if err != nil {
+			return report, fmt.Errorf("failed to decode return data: %w", err)
+		}
+		for _, v := range values {
+			report.Outputs = append(report.Outputs, &scenarioStepOutput{Name: v.Name, Type: v.Type, Value: scenarioValue(v.Value)})
+		}
+	}
+	r.setStepVars(step, report, contractABI)
+	return report, nil
+}
+
+// deployCode returns the contract creation code of the deploy step with linked libraries and ABI encoded
+// constructor arguments appended. Also returns the contract ABI, nil when not known.
+func (r *scenarioRunner) deployCode(step *scenarioStep) ([]byte, *abi.ABI, error) {
+	libraries := make(map[string]common.Address, len(step.Link))
+	for name, addr := range step.Link {
+		addr, err := r.resolve(addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid link of library %q: %w", name, err)
+		}
+		if !common.IsHexAddress(addr) {
+			return nil, nil, fmt.Errorf("invalid link of library %q, invalid library address %q", name, addr)
+		}
+		libraries[name] = common.HexToAddress(addr)
+	}
+	var code []byte
+	var contractABI *abi.ABI
+	if step.Artifact != "" {
+		artifact, err := evmwallet.LoadArtifact(r.path(step.Artifact), step.Contract)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read artifact: %w", err)
+		}
+		if code, err = evmwallet.LinkBytecode(artifact.Bytecode, artifact.LinkReferences, libraries); err != nil {
+			return nil, nil, fmt.Errorf("failed to link contract code: %w", err)
+		}
+		contractABI = artifact.ABI
+	} else {
+		data, err := r.resolve(step.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid data: %w", err)
+		}
+		if code, err = evmwallet.LinkBytecode(data, nil, libraries); err != nil {
+			return nil, nil, fmt.Errorf("invalid data: %w", err)
+		}
+		if contractABI, err = r.loadABI(step.ABI); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(code) > ScSizeLimit24Kb {
+		return nil, nil, fmt.Errorf("contract code too big, maximum size is 24Kb")
+	}
+	constructorArgs, err := r.resolveArgs(step.Args)
+	if err != nil {
+		return nil, nil, err
+	}
+	encodedArgs, err := evmwallet.PackConstructorArgs(contractABI, constructorArgs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode constructor arguments: %w", err)
+	}
+	return append(code, encodedArgs...), contractABI, nil
+}
+
+/*
+callData returns the contract address and the call data of the execute or call step. Also returns the
+contract ABI, which is read from the "abi" file of the step or it is the ABI of the contract deployed by
+the earlier step or the ABI of the contract in the contract book, nil when not known.
+*/
+func (r *scenarioRunner) callData(step *scenarioStep) ([]byte, []byte, *abi.ABI, error) {
+	to, contractABI, err := r.contractAddress(step.To)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if step.ABI != "" {
+		if contractABI, err = r.loadABI(step.ABI); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if step.Method == "" {
+		data, err := r.resolve(step.Data)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid data: %w", err)
+		}
+		callData, err := decodeHex(data)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid data: %w", err)
+		}
+		return to, callData, contractABI, nil
+	}
+	if contractABI == nil {
+		return nil, nil, nil, errors.New("contract ABI is required to call a method, use 'abi' in the step")
+	}
+	methodArgs, err := r.resolveArgs(step.Args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	callData, err := evmwallet.PackMethodCall(contractABI, step.Method, methodArgs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to encode method call: %w", err)
+	}
+	return to, callData, contractABI, nil
+}
+
+// contractAddress resolves the "to" of the step, it is either address, name of an earlier deploy step
+// or name of the contract in the contract book. Also returns the ABI of the contract, nil when not known.
+func (r *scenarioRunner) contractAddress(s string) ([]byte, *abi.ABI, error) {
+	to, err := r.resolve(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid 'to': %w", err)
+	}
+	if common.IsHexAddress(to) {
+		return common.HexToAddress(to).Bytes(), nil, nil
+	}
+	if addr, ok := r.vars[to+".address"]; ok {
+		return common.HexToAddress(addr).Bytes(), r.deployABIs[to], nil
+	}
+	if evmwallet.ValidateContractName(to) != nil {
+		return nil, nil, fmt.Errorf("invalid 'to' %q, expected address, name of an earlier deploy step or contract name", to)
+	}
+	book, err := openContractBook(r.config)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer book.Close()
+	c, err := book.Get(to)
+	if err != nil {
+		if errors.Is(err, evmwallet.ErrContractNotFound) {
+			return nil, nil, fmt.Errorf("unknown contract %q, use 'evm contract add' to register it", to)
+		}
+		return nil, nil, fmt.Errorf("failed to read contract: %w", err)
+	}
+	if c.ABIPath == "" {
+		return c.Address.Bytes(), nil, nil
+	}
+	contractABI, err := evmwallet.LoadABI(c.ABIPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read contract ABI: %w", err)
+	}
+	return c.Address.Bytes(), contractABI, nil
+}
+
+// stepValue returns the value of the step in wei, nil when not set.
+func (r *scenarioRunner) stepValue(step *scenarioStep) (*big.Int, error) {
+	if step.Value == "" {
+		return nil, nil
+	}
+	s, err := r.resolve(step.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	value, err := parseValue(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %w", err)
+	}
+	return value, nil
+}
+
+// stepGas returns the gas of the transaction, the gas of the script is used when the step does not
+// set it. In case of "auto" (the default) the gas is estimated and the estimate is returned too.
+func (r *scenarioRunner) stepGas(step *scenarioStep, accountNumber uint64, attrs *evm.TxAttributes) (uint64, *evmwallet.GasEstimate, error) {
+	gas := step.Gas
+	if gas == nil {
+		gas = r.gas
+	}
+	if gas != nil && !gas.auto {
+		return gas.gas, nil, nil
+	}
+	estimate, err := estimateGas(r.cmd, r.w, accountNumber, &evm.CallEVMRequest{To: attrs.To, Data: attrs.Data, Value: attrs.Value})
+	if err != nil {
+		return 0, nil, err
+	}
+	r.config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Estimated gas: %d, using max gas: %d", estimate.GasUsed, estimate.GasLimit))
+	return estimate.GasLimit, estimate, nil
+}
+
+// setStepVars sets the variables of the successful step, unnamed steps can not be referenced.
+func (r *scenarioRunner) setStepVars(step *scenarioStep, report *scenarioStepReport, contractABI *abi.ABI) {
+	if step.Name == "" {
+		return
+	}
+	if report.TxHash != "" {
+		r.vars[step.Name+".txHash"] = report.TxHash
+	}
+	if step.Action == stepDeploy {
+		r.vars[step.Name+".address"] = report.ContractAddress
+		r.deployABIs[step.Name] = contractABI
+	}
+	switch {
+	case len(report.Outputs) > 0:
+		r.vars[step.Name+".output"] = report.Outputs[0].Value
+		for i, o := range report.Outputs {
+			r.vars[step.Name+".output."+strconv.Itoa(i)] = o.Value
+			if o.Name != "" {
+				r.vars[step.Name+".output."+o.Name] = o.Value
+			}
+		}
+	case report.ReturnData != "" && step.Action != stepDeploy:
+		r.vars[step.Name+".output"] = report.ReturnData
+	}
+}
+
+// resolve replaces the variable references in the string with the values of the variables.
+func (r *scenarioRunner) resolve(s string) (string, error) {
+	var err error
+	res := scenarioVarRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		name := strings.TrimSpace(ref[2 : len(ref)-1])
+		value, e := r.variable(name)
+		if e != nil && err == nil {
+			err = e
+		}
+		return value
+	})
+	return res, err
+}
+
+func (r *scenarioRunner) resolveArgs(list []string) ([]string, error) {
+	res := make([]string, len(list))
+	for i, arg := range list {
+		var err error
+		if res[i], err = r.resolve(arg); err != nil {
+			return nil, fmt.Errorf("invalid argument #%d: %w", i+1, err)
+		}
+	}
+	return res, nil
+}
+
+func (r *scenarioRunner) variable(name string) (string, error) {
+	if value, ok := r.vars[name]; ok {
+		return value, nil
+	}
+	if n, found := strings.CutPrefix(name, "key."); found {
+		accountNumber, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid key number in variable %q", name)
+		}
+		addr, err := r.w.GetAccountAddress(accountNumber)
+		if err != nil {
+			return "", fmt.Errorf("variable %q: %w", name, err)
+		}
+		return addr.Hex(), nil
+	}
+	return "", fmt.Errorf("unknown variable %q", name)
+}
+
+func (r *scenarioRunner) loadABI(filename string) (*abi.ABI, error) {
+	if filename == "" {
+		return nil, nil
+	}
+	contractABI, err := evmwallet.LoadABI(r.path(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ABI: %w", err)
+	}
+	return contractABI, nil
+}
+
+// path returns the file name relative to the directory of the script.
+func (r *scenarioRunner) path(filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	return filepath.Join(r.dir, filename)
+}
+
+// executionError returns the error of the failed execution, the revert reason is decoded with the
+// contract ABI when it is given.
+func executionError(result *evmclient.Result, contractABI *abi.ABI) error {
+	revert := result.Revert
+	if contractABI != nil {
+		if r := evmwallet.DecodeRevertReason(contractABI, result.Details.ReturnData); r != nil {
+			revert = r
+		}
+	}
+	if revert != nil {
+		return fmt.Errorf("execution failed: %s, revert reason: %s", result.Details.ErrorDetails, evmwallet.FormatRevertReason(revert))
+	}
+	return fmt.Errorf("execution failed: %s", result.Details.ErrorDetails)
+}
+
+// scenarioValue returns the ABI decoded value as the variable value, strings are not quoted
+// so that these can be used as method arguments.
+func scenarioValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return evmwallet.FormatABIValue(value)
+}
+
+func stepTitle(i int, step *scenarioStep) string {
+	if step.Name != "" {
+		return fmt.Sprintf("Step #%d %q (%s)", i+1, step.Name, step.Action)
+	}
+	return fmt.Sprintf("Step #%d (%s)", i+1, step.Action)
+}
+
+func saveScenarioReport(filename string, report *scenarioReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding scenario report: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("saving scenario report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package evm
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const testScenarioABI = `[
+	{"type":"constructor","inputs":[{"name":"owner","type":"address"}]},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+func Test_evmCmdRun(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	contractAddr := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{ContractAddr: contractAddr})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000", // balance is returned by EVM in wei 10^-18
+		nonce:    1,
+		gasPrice: "10000",
+		callFn: func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+			// balanceOf returns 42
+			return &evm.ProcessingDetails{ReturnData: common.LeftPadBytes([]byte{42}, 32)}
+		},
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         21000,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token.abi"), []byte(testScenarioABI), 0600))
+	writeScript := func(t *testing.T, content string) string {
+		filename := filepath.Join(dir, "script.yaml")
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0600))
+		return filename
+	}
+	receiver := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	t.Run("invalid script", func(t *testing.T) {
+		_, err := execEvmCmd(t, homedir, "evm run "+filepath.Join(dir, "missing.yaml")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "failed to read script:")
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps: []")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "invalid script: script does not contain any steps")
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps:\n  - action: deploy\n    bytecode: 6080")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, "invalid script: yaml: unmarshal errors:\n  line 3: field bytecode not found")
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps:\n  - action: transfer")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `invalid script: Step #1 (transfer): unknown action "transfer", expected one of deploy, execute, call`)
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps:\n  - name: a\n    action: deploy\n    data: 6080\n  - name: a\n    action: deploy\n    data: 6080")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `invalid script: step name "a" is used more than once`)
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps:\n  - name: key\n    action: deploy\n    data: 6080")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `invalid script: invalid name of step #1 "key"`)
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps:\n  - action: call\n    to: 0x1111111111111111111111111111111111111111\n    args: [1]")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `invalid script: Step #1 (call): 'args' are only allowed together with 'method'`)
+		_, err = execEvmCmd(t, homedir, "evm run "+writeScript(t, "steps:\n  - action: deploy\n    data: 6080\n    gas: some")+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `invalid script: expected unsigned integer or "auto"`)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		filename := writeScript(t, `
+gas: 100000
+vars:
+  receiver: "`+receiver.Hex()+`"
+steps:
+  - name: token
+    action: deploy
+    data: "6080"
+    abi: token.abi
+    args: ["${key.1}"]
+  - action: execute
+    to: token
+    method: transfer
+    args: ["${vars.receiver}", 10]
+  - name: balance
+    action: call
+    to: token
+    method: balanceOf
+    args: ["${vars.receiver}"]
+  - name: refund
+    action: execute
+    to: ${token.address}
+    abi: token.abi
+    method: transfer
+    args: ["${vars.receiver}", "${balance.output.balance}"]
+    value: 100wei
+    gas: auto
+`)
+		reportFile := filepath.Join(dir, "report.json")
+		stdout, err := execEvmCmd(t, homedir, "evm run "+filename+" --report "+reportFile+" --alphabill-api-uri "+addr.Host)
+		require.NoError(t, err)
+		testutils.VerifyStdout(t, stdout,
+			`Step #1 "token" (deploy):`,
+			`Step #2 (execute):`,
+			`Step #3 "balance" (call):`,
+			`Evm execution returned: balance (uint256): 42`,
+			`Step #4 "refund" (execute):`,
+			"Scenario report saved to file: "+reportFile)
+		// the last transaction uses the contract address and the value returned by the call
+		evmAttributes := &evm.TxAttributes{}
+		require.NoError(t, mockConf.receivedTx.UnmarshalAttributes(evmAttributes))
+		require.EqualValues(t, contractAddr.Bytes(), evmAttributes.To)
+		require.EqualValues(t, big.NewInt(100), evmAttributes.Value)
+		contractABI, err := evmwallet.ParseABI([]byte(testScenarioABI))
+		require.NoError(t, err)
+		data, err := contractABI.Pack("transfer", receiver, big.NewInt(42))
+		require.NoError(t, err)
+		require.EqualValues(t, data, evmAttributes.Data)
+
+		var report scenarioReport
+		reportBytes, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(reportBytes, &report))
+		require.True(t, report.Success)
+		require.Len(t, report.Steps, 4)
+		require.Equal(t, contractAddr.Hex(), report.Steps[0].ContractAddress)
+		require.EqualValues(t, 100000, report.Steps[0].Gas)
+		require.Equal(t, "0.000'210'00", report.Steps[0].Fee)
+		require.NotEmpty(t, report.Steps[1].TxHash)
+		require.Equal(t, []*scenarioStepOutput{{Name: "balance", Type: "uint256", Value: "42"}}, report.Steps[2].Outputs)
+		require.True(t, report.Steps[3].Success)
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		filename := writeScript(t, `
+gas: 100000
+steps:
+  - name: token
+    action: deploy
+    data: "6080"
+    abi: token.abi
+    args: ["${key.1}"]
+  - action: execute
+    to: token
+    method: transfer
+    args: ["${vars.receiver}", 10]
+  - action: execute
+    to: token
+    method: transfer
+    args: ["0x1111111111111111111111111111111111111111", 10]
+`)
+		stdout, err := execEvmCmd(t, homedir, "evm run "+filename+" --alphabill-api-uri "+addr.Host)
+		require.ErrorContains(t, err, `Step #2 (execute) failed, invalid argument #1: unknown variable "vars.receiver"`)
+		reportFile := filepath.Join(dir, "script.report.json")
+		testutils.VerifyStdout(t, stdout, "Scenario report saved to file: "+reportFile)
+		var report scenarioReport
+		reportBytes, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(reportBytes, &report))
+		require.False(t, report.Success)
+		require.Len(t, report.Steps, 2)
+		require.True(t, report.Steps[0].Success)
+		require.False(t, report.Steps[1].Success)
+		require.Equal(t, `invalid argument #1: unknown variable "vars.receiver"`, report.Steps[1].Error)
+	})
+}
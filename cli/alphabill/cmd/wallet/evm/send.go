@@ -0,0 +1,153 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	ToCmdName     = "to"
+	ToKeyCmdName  = "to-key"
+	AmountCmdName = "amount"
+	weiSuffix     = "wei"
+)
+
+const valueHelp = "given in ALPHA (eg 1.5) or in wei with \"" + weiSuffix + "\" suffix (eg 1500" + weiSuffix + ")"
+
+func evmCmdSend(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "transfers value to an EVM address",
+		Long: "Transfers value from the account's EVM address to another EVM address, either given directly or " +
+			"as the address of another account of the wallet.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdSend(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for sending the transaction")
+	cmd.Flags().String(ToCmdName, "", "receiver EVM address in hexadecimal format")
+	cmd.Flags().Uint64(ToKeyCmdName, 0, "send to the EVM address of the given key of the wallet")
+	cmd.Flags().String(AmountCmdName, "", "amount to send, "+valueHelp)
+	cmd.Flags().Var(&maxGasValue{gas: evmwallet.TransferGas}, MaxGasCmdName, "maximum amount of gas user is willing to spend, "+
+		"\""+autoMaxGas+"\" estimates the gas by simulating the transaction")
+	addGasMarginFlag(cmd)
+	addTxSubmitFlags(cmd)
+	cmd.MarkFlagsMutuallyExclusive(ToCmdName, ToKeyCmdName)
+	cmd.MarkFlagsOneRequired(ToCmdName, ToKeyCmdName)
+	if err := cmd.MarkFlagRequired(AmountCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdSend(cmd *cobra.Command, config *types.EvmConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	amountStr, err := cmd.Flags().GetString(AmountCmdName)
+	if err != nil {
+		return err
+	}
+	amount, err := parseValue(amountStr)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", AmountCmdName, err)
+	}
+	if amount.Sign() == 0 {
+		return fmt.Errorf("failed to read '%s' parameter: amount must be positive", AmountCmdName)
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	to, err := readReceiver(cmd, w)
+	if err != nil {
+		return err
+	}
+	maxGas, estimate, err := readMaxGas(cmd, w, accountNumber, &evm.CallEVMRequest{To: to.Bytes(), Value: amount}, config)
+	if err != nil {
+		return err
+	}
+	gasPrice, err := w.GetGasPrice(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to read gas price: %w", err)
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	consoleWriter.Println(fmt.Sprintf("Sending %s ALPHA (%s wei) to %s", formatValue(amount), amount, to))
+	maxFee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(maxGas))
+	consoleWriter.Println(fmt.Sprintf("Max fee: %s (max gas %d, gas price %s wei)",
+		util.WeiToAlphaString(maxFee), maxGas, gasPrice))
+	attrs := &evm.TxAttributes{
+		To:    to.Bytes(),
+		Value: amount,
+		Gas:   maxGas,
+	}
+	_, err = submitEvmTx(cmd, config, w, accountNumber, attrs, estimate, nil, "", "transfer")
+	return err
+}
+
+// readReceiver returns the receiver address given either with "to" or "to-key" flag.
+func readReceiver(cmd *cobra.Command, w *evmwallet.Wallet) (common.Address, error) {
+	toKey, err := cmd.Flags().GetUint64(ToKeyCmdName)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if toKey > 0 {
+		addr, err := w.GetAccountAddress(toKey)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("failed to read '%s' parameter: %w", ToKeyCmdName, err)
+		}
+		return addr, nil
+	}
+	to, err := cmd.Flags().GetString(ToCmdName)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(to) {
+		return common.Address{}, fmt.Errorf("failed to read '%s' parameter: invalid address %q", ToCmdName, to)
+	}
+	return common.HexToAddress(to), nil
+}
+
+// addValueFlag adds optional "value" flag for transferring value with the smart contract call.
+func addValueFlag(cmd *cobra.Command) {
+	cmd.Flags().String(ValueCmdName, "0", "(optional) value to transfer, "+valueHelp)
+}
+
+// readValueFlag returns the value of "value" flag in wei.
+func readValueFlag(cmd *cobra.Command) (*big.Int, error) {
+	valueStr, err := cmd.Flags().GetString(ValueCmdName)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseValue(valueStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", ValueCmdName, err)
+	}
+	return value, nil
+}
+
+// parseValue returns the amount in wei, the amount is given either in ALPHA (eg "1.5")
+// or in wei with "wei" suffix (eg "1500wei").
+func parseValue(s string) (*big.Int, error) {
+	if amount, found := strings.CutSuffix(s, weiSuffix); found {
+		return util.StringToBigAmount(amount, 0)
+	}
+	return util.AlphaStringToWei(s)
+}
+
+// formatValue returns the amount in wei as ALPHA.
+func formatValue(wei *big.Int) string {
+	return util.WeiToString(wei)
+}
@@ -0,0 +1,240 @@
+package evm
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	walletaccount "github.com/alphabill-org/alphabill-wallet/wallet/account"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	MessageCmdName    = "message"
+	HexCmdName        = "hex"
+	TypedDataCmdName  = "typed-data"
+	SignatureCmdName  = "signature"
+	typedDataFileHelp = "JSON file with EIP-712 typed data in the eth_signTypedData_v4 format " +
+		"(object with \"types\", \"primaryType\", \"domain\" and \"message\" fields)"
+)
+
+func evmCmdSignMessage(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-message",
+		Short: "signs a message with the account key (EIP-191, personal_sign)",
+		Long: "Signs a message with the account key the same way as Ethereum wallets sign with the personal_sign " +
+			"JSON-RPC method i.e. the message is prefixed with \"\\x19Ethereum Signed Message:\\n\" and the length " +
+			"of the message (EIP-191). The signature is 65 bytes in [R || S || V] format.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdSignMessage(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for signing")
+	addMessageFlags(cmd)
+	if err := cmd.MarkFlagRequired(MessageCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdSignMessage(cmd *cobra.Command, config *types.EvmConfig) error {
+	message, err := readMessageFlags(cmd)
+	if err != nil {
+		return err
+	}
+	accountKey, err := readSigningKey(cmd, config)
+	if err != nil {
+		return err
+	}
+	signature, err := evmwallet.SignMessage(accountKey, message)
+	if err != nil {
+		return err
+	}
+	return printSignature(config, accountKey.PubKey, evmwallet.MessageHash(message), signature)
+}
+
+func evmCmdSignTypedData(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-typed-data",
+		Short: "signs EIP-712 typed structured data with the account key",
+		Long: "Signs EIP-712 typed structured data with the account key the same way as Ethereum wallets sign with " +
+			"the eth_signTypedData_v4 JSON-RPC method. The signature is 65 bytes in [R || S || V] format.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdSignTypedData(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for signing")
+	cmd.Flags().String(TypedDataCmdName, "", typedDataFileHelp)
+	if err := cmd.MarkFlagRequired(TypedDataCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdSignTypedData(cmd *cobra.Command, config *types.EvmConfig) error {
+	typedData, err := readTypedDataFlag(cmd)
+	if err != nil {
+		return err
+	}
+	accountKey, err := readSigningKey(cmd, config)
+	if err != nil {
+		return err
+	}
+	signature, err := evmwallet.SignTypedData(accountKey, typedData)
+	if err != nil {
+		return err
+	}
+	hash, err := evmwallet.TypedDataHash(typedData)
+	if err != nil {
+		return err
+	}
+	return printSignature(config, accountKey.PubKey, hash, signature)
+}
+
+func evmCmdVerifySignature(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-signature",
+		Short: "recovers the signer of a message or EIP-712 typed data signature",
+		Long: "Recovers the address of the account which signed the message (EIP-191, personal_sign) or the EIP-712 " +
+			"typed data. When the \"" + args.AddressCmdName + "\" flag is set the command fails unless the signer " +
+			"is the given address.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdVerifySignature(cmd, config)
+		},
+	}
+	cmd.Flags().String(SignatureCmdName, "", "65 byte signature in hex")
+	addMessageFlags(cmd)
+	cmd.Flags().String(TypedDataCmdName, "", typedDataFileHelp)
+	cmd.Flags().String(args.AddressCmdName, "", "(optional) expected signer address in hexadecimal format")
+	if err := cmd.MarkFlagRequired(SignatureCmdName); err != nil {
+		panic(err)
+	}
+	cmd.MarkFlagsOneRequired(MessageCmdName, TypedDataCmdName)
+	cmd.MarkFlagsMutuallyExclusive(MessageCmdName, TypedDataCmdName)
+	cmd.MarkFlagsMutuallyExclusive(HexCmdName, TypedDataCmdName)
+	return cmd
+}
+
+func execEvmCmdVerifySignature(cmd *cobra.Command, config *types.EvmConfig) error {
+	sigHex, err := cmd.Flags().GetString(SignatureCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", SignatureCmdName, err)
+	}
+	signature, err := decodeHex(sigHex)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", SignatureCmdName, err)
+	}
+	var expected *common.Address
+	addr, err := cmd.Flags().GetString(args.AddressCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
+	}
+	if addr != "" {
+		if !common.IsHexAddress(addr) {
+			return fmt.Errorf("invalid address %q, address must be 20 bytes in hex", addr)
+		}
+		a := common.HexToAddress(addr)
+		expected = &a
+	}
+	var signer common.Address
+	if cmd.Flags().Changed(TypedDataCmdName) {
+		typedData, err := readTypedDataFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if signer, err = evmwallet.RecoverTypedDataSigner(typedData, signature); err != nil {
+			return err
+		}
+	} else {
+		message, err := readMessageFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if signer, err = evmwallet.RecoverMessageSigner(message, signature); err != nil {
+			return err
+		}
+	}
+	config.WalletConfig.Base.ConsoleWriter.Println(fmt.Sprintf("Signer address: %s", signer))
+	if expected != nil && *expected != signer {
+		return fmt.Errorf("signature verification failed, message is signed by %s, expected %s", signer, expected)
+	}
+	return nil
+}
+
+func addMessageFlags(cmd *cobra.Command) {
+	cmd.Flags().String(MessageCmdName, "", "message to sign, used as UTF-8 text unless the \""+HexCmdName+"\" flag is set")
+	cmd.Flags().Bool(HexCmdName, false, "message is hex encoded binary data")
+}
+
+func readMessageFlags(cmd *cobra.Command) ([]byte, error) {
+	message, err := cmd.Flags().GetString(MessageCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", MessageCmdName, err)
+	}
+	isHex, err := cmd.Flags().GetBool(HexCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", HexCmdName, err)
+	}
+	if !isHex {
+		return []byte(message), nil
+	}
+	data, err := decodeHex(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", MessageCmdName, err)
+	}
+	return data, nil
+}
+
+func readTypedDataFlag(cmd *cobra.Command) (*evmwallet.TypedData, error) {
+	filename, err := cmd.Flags().GetString(TypedDataCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", TypedDataCmdName, err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", TypedDataCmdName, err)
+	}
+	typedData, err := evmwallet.ParseTypedData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", TypedDataCmdName, err)
+	}
+	return typedData, nil
+}
+
+func readSigningKey(cmd *cobra.Command, config *types.EvmConfig) (*walletaccount.AccountKey, error) {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("key parameter read failed: %w", err)
+	}
+	if accountNumber < 1 {
+		return nil, fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	am, err := account.LoadExistingAccountManager(config.WalletConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer am.Close()
+	key, err := am.GetSigningKey(accountNumber - 1)
+	if err != nil {
+		return nil, fmt.Errorf("account key read failed: %w", err)
+	}
+	return key, nil
+}
+
+func printSignature(config *types.EvmConfig, pubKey []byte, hash common.Hash, signature []byte) error {
+	signer, err := evmwallet.AddressFromPublicKey(pubKey)
+	if err != nil {
+		return err
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	consoleWriter.Println(fmt.Sprintf("Signer address: %s", signer))
+	consoleWriter.Println(fmt.Sprintf("Signed hash: %s", hash))
+	consoleWriter.Println(fmt.Sprintf("Signature: %s", hexutil.Encode(signature)))
+	return nil
+}
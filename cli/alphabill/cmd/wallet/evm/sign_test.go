@@ -0,0 +1,77 @@
+package evm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+)
+
+const testTypedData = `{
+	"types": {
+		"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+		"Login": [{"name": "user", "type": "address"}, {"name": "nonce", "type": "uint256"}]
+	},
+	"primaryType": "Login",
+	"domain": {"name": "dApp", "chainId": 3},
+	"message": {"user": "0x2222222222222222222222222222222222222222", "nonce": 7}
+}`
+
+func Test_evmCmdSignMessage(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	stdout, err := execEvmCmd(t, homedir, "evm sign-message --message hello")
+	require.NoError(t, err)
+	require.Len(t, stdout.Lines, 3)
+	signer := strings.TrimPrefix(stdout.Lines[0], "Signer address: ")
+	require.Equal(t, "Signed hash: 0x50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750", stdout.Lines[1])
+	signature := strings.TrimPrefix(stdout.Lines[2], "Signature: ")
+	require.Len(t, signature, 2+2*65)
+
+	stdout, err = execEvmCmd(t, homedir, "evm verify-signature --message hello --signature "+signature+" --address "+signer)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Signer address: "+signer)
+	// the same message in hex
+	_, err = execEvmCmd(t, homedir, "evm verify-signature --hex --message 68656c6c6f --signature "+signature+" --address "+signer)
+	require.NoError(t, err)
+
+	_, err = execEvmCmd(t, homedir, "evm verify-signature --message hello! --signature "+signature+" --address "+signer)
+	require.ErrorContains(t, err, "signature verification failed, message is signed by ")
+	_, err = execEvmCmd(t, homedir, "evm verify-signature --message hello --signature 0x1234")
+	require.EqualError(t, err, "invalid signature length 2, expected 65 bytes")
+	_, err = execEvmCmd(t, homedir, "evm sign-message --hex --message hello")
+	require.ErrorContains(t, err, "failed to read 'message' parameter")
+	_, err = execEvmCmd(t, homedir, "evm sign-message --key 2 --message hello")
+	require.ErrorContains(t, err, "account key read failed")
+}
+
+func Test_evmCmdSignTypedData(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	typedDataFile := filepath.Join(t.TempDir(), "login.json")
+	require.NoError(t, os.WriteFile(typedDataFile, []byte(testTypedData), 0600))
+
+	stdout, err := execEvmCmd(t, homedir, "evm sign-typed-data --typed-data "+typedDataFile)
+	require.NoError(t, err)
+	require.Len(t, stdout.Lines, 3)
+	signer := strings.TrimPrefix(stdout.Lines[0], "Signer address: ")
+	signature := strings.TrimPrefix(stdout.Lines[2], "Signature: ")
+
+	stdout, err = execEvmCmd(t, homedir, "evm verify-signature --typed-data "+typedDataFile+" --signature "+signature+" --address "+signer)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Signer address: "+signer)
+	// signature of the typed data is not valid for the message
+	_, err = execEvmCmd(t, homedir, "evm verify-signature --message hello --signature "+signature+" --address "+signer)
+	require.ErrorContains(t, err, "signature verification failed")
+
+	_, err = execEvmCmd(t, homedir, "evm verify-signature --message hello --typed-data "+typedDataFile+" --signature "+signature)
+	require.ErrorContains(t, err, "if any flags in the group [message typed-data] are set none of the others can be")
+	_, err = execEvmCmd(t, homedir, "evm verify-signature --signature "+signature)
+	require.ErrorContains(t, err, "at least one of the flags in the group [message typed-data] is required")
+	invalidFile := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalidFile, []byte(`{"types": {}, "primaryType": "Login", "domain": {}, "message": {}}`), 0600))
+	_, err = execEvmCmd(t, homedir, "evm sign-typed-data --typed-data "+invalidFile)
+	require.ErrorContains(t, err, "failed to read 'typed-data' parameter: invalid typed data")
+}
@@ -0,0 +1,225 @@
+package evm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	basetypes "github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
+)
+
+const (
+	NoWaitCmdName         = "no-wait"
+	ProofOutputCmdName    = "proof-output"
+	SkipSimulationCmdName = "skip-simulation"
+	YesCmdName            = "yes"
+
+	txHashLength = 32
+)
+
+func evmCmdTxStatus(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx-status <tx hash>",
+		Short: "shows status of the transaction",
+		Long: "Looks up the transaction by its hash and shows the execution status, the fee and the processing " +
+			"details of the confirmed transaction. The hash of the transaction is printed by the commands " +
+			"sending the transaction (use \"" + NoWaitCmdName + "\" flag to not wait for the confirmation).",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdTxStatus(cmd, args[0], config)
+		},
+	}
+	cmd.Flags().String(ProofOutputCmdName, "", "save transaction proof to the file (if the file already exists it will be overwritten)")
+	return cmd
+}
+
+func execEvmCmdTxStatus(cmd *cobra.Command, hashStr string, config *types.EvmConfig) error {
+	txHash, err := parseTxHash(hashStr)
+	if err != nil {
+		return err
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	result, err := w.GetTxStatus(cmd.Context(), txHash)
+	if err != nil {
+		return err
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	if result == nil {
+		consoleWriter.Println(fmt.Sprintf("Transaction 0x%x is not confirmed, it is either pending, timed out or unknown", txHash))
+		return nil
+	}
+	if uc, err := result.Proof.TxProof.GetUC(); err == nil {
+		consoleWriter.Println(fmt.Sprintf("Transaction 0x%x confirmed in round %d", txHash, uc.GetRoundNumber()))
+	} else {
+		consoleWriter.Println(fmt.Sprintf("Transaction 0x%x confirmed", txHash))
+	}
+	printResult(consoleWriter, result, nil, "")
+	return saveTxProof(cmd, consoleWriter, result.Proof)
+}
+
+// addTxSubmitFlags adds "no-wait" and "proof-output" flags to the command sending transaction.
+func addTxSubmitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(NoWaitCmdName, false, "do not wait for the transaction confirmation, only the hash of the transaction "+
+		"is printed, use \"tx-status\" command to check the status of the transaction")
+	cmd.Flags().String(ProofOutputCmdName, "", "save transaction proof to the file (if the file already exists it will be overwritten)")
+	cmd.Flags().Bool(SkipSimulationCmdName, false, "do not simulate the transaction before sending it, by default the "+
+		"transaction is executed as a call first and it is not sent when the execution fails")
+	cmd.Flags().Bool(YesCmdName, false, "send the simulated transaction without asking for confirmation")
+	cmd.MarkFlagsMutuallyExclusive(NoWaitCmdName, ProofOutputCmdName)
+}
+
+/*
+submitEvmTx sends the transaction and prints the result. Unless "skip-simulation" flag is set the
+transaction is simulated first and it is not sent when the simulation fails, the simulation reuses
+the gas estimate of "max-gas auto" (nil otherwise). The simulated transaction is sent only after the
+user confirms it, unless "yes" flag is set. When "no-wait" flag is set only the hash of the
+transaction is printed, otherwise the confirmation is waited for and the proof is saved to the file
+given with "proof-output" flag. The "action" is used in the error message. Returns the result of the
+confirmed transaction, nil in case of "no-wait".
+*/
+func submitEvmTx(cmd *cobra.Command, config *types.EvmConfig, w *evmwallet.Wallet, accountNumber uint64, attrs *evm.TxAttributes, estimate *evmwallet.GasEstimate, contractABI *abi.ABI, method, action string) (*evmclient.Result, error) {
+	noWait, err := cmd.Flags().GetBool(NoWaitCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", NoWaitCmdName, err)
+	}
+	yes, err := cmd.Flags().GetBool(YesCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", YesCmdName, err)
+	}
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	opts, err := txSendOptions(cmd, consoleWriter, attrs, estimate, !yes)
+	if err != nil {
+		return nil, err
+	}
+	if noWait {
+		txHash, err := w.PostEvmTx(cmd.Context(), accountNumber, attrs, opts...)
+		if err != nil {
+			return nil, txSubmitError(simulationError(err, contractABI), accountNumber, action)
+		}
+		consoleWriter.Println(fmt.Sprintf("Evm transaction submitted, tx hash: 0x%x", txHash))
+		consoleWriter.Println(fmt.Sprintf("Use \"evm tx-status 0x%x\" to check the status of the transaction", txHash))
+		return nil, nil
+	}
+	result, err := w.SendEvmTx(cmd.Context(), accountNumber, attrs, opts...)
+	if err != nil {
+		return nil, txSubmitError(simulationError(err, contractABI), accountNumber, action)
+	}
+	printResult(consoleWriter, result, contractABI, method)
+	return result, saveTxProof(cmd, consoleWriter, result.Proof)
+}
+
+// txSendOptions returns the simulation options of the transaction unless "skip-simulation" flag
+// is set, the gas used of the successful simulation is printed and when "confirm" is true the
+// user is asked whether to send the transaction.
+func txSendOptions(cmd *cobra.Command, consoleWriter types.ConsoleWrapper, attrs *evm.TxAttributes, estimate *evmwallet.GasEstimate, confirm bool) ([]evmwallet.SendOption, error) {
+	skip, err := cmd.Flags().GetBool(SkipSimulationCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", SkipSimulationCmdName, err)
+	}
+	if skip {
+		return nil, nil
+	}
+	return []evmwallet.SendOption{
+		evmwallet.WithSimulation(func(sim *evmwallet.TxSimulation) error {
+			consoleWriter.Println(fmt.Sprintf("Transaction simulation succeeded, gas used %d (max gas %d)", sim.GasUsed, attrs.Gas))
+			if !confirm {
+				return nil
+			}
+			return confirmTx(cmd, consoleWriter)
+		}),
+		evmwallet.WithGasEstimate(estimate),
+	}, nil
+}
+
+// confirmTx asks the user whether to send the transaction, error is returned unless the answer is yes.
+func confirmTx(cmd *cobra.Command, consoleWriter types.ConsoleWrapper) error {
+	consoleWriter.Print("Send the transaction? [y/N]: ")
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || answer == "") {
+		return fmt.Errorf("failed to read the confirmation (use %q flag to send without confirmation): %w", YesCmdName, err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	default:
+		return errors.New("transaction was not confirmed")
+	}
+}
+
+// simulationError decodes the custom error of the failed simulation with the contract ABI.
+func simulationError(err error, contractABI *abi.ABI) error {
+	var simErr *evmwallet.SimulationError
+	if errors.As(err, &simErr) && contractABI != nil {
+		if r := evmwallet.DecodeRevertReason(contractABI, simErr.Result.Details.ReturnData); r != nil {
+			simErr.Result.Revert = r
+		}
+	}
+	return err
+}
+
+func txSubmitError(err error, accountNumber uint64, action string) error {
+	if errors.Is(err, sdktypes.ErrNotFound) {
+		return fmt.Errorf("no evm fee credit for account %d, please add", accountNumber)
+	}
+	return fmt.Errorf("%s failed, %w", action, err)
+}
+
+// saveTxProof saves the transaction proof(s) into file when the "proof-output" flag is set.
+func saveTxProof(cmd *cobra.Command, consoleWriter types.ConsoleWrapper, proofs ...*basetypes.TxRecordProof) error {
+	outputProof, err := cmd.Flags().GetString(ProofOutputCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ProofOutputCmdName, err)
+	}
+	proofs = slices.DeleteFunc(proofs, func(p *basetypes.TxRecordProof) bool { return p == nil })
+	if outputProof == "" || len(proofs) == 0 {
+		return nil
+	}
+	filename, err := filepath.Abs(outputProof)
+	if err != nil {
+		return fmt.Errorf("parsing %q flag value as file name: %w", ProofOutputCmdName, err)
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("creating file for transaction proof: %w", err)
+	}
+	defer f.Close()
+	// the same format as the proofs saved by the money and token commands
+	if err := basetypes.Cbor.Encode(f, proofs); err != nil {
+		return fmt.Errorf("encoding transaction proof as CBOR: %w", err)
+	}
+	consoleWriter.Println("Transaction proof(s) saved to file:" + filename)
+	return nil
+}
+
+// parseTxHash decodes the transaction hash given in hexadecimal format with optional 0x prefix.
+func parseTxHash(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+	txHash, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hash: %w", err)
+	}
+	if len(txHash) != txHashLength {
+		return nil, fmt.Errorf("invalid transaction hash: expected %d bytes, got %d", txHashLength, len(txHash))
+	}
+	return txHash, nil
+}
@@ -0,0 +1,80 @@
+package evm
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+)
+
+func Test_evmCmdTxStatus(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	detailBytes, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+	require.NoError(t, err)
+	mockConf := &clientMockConf{
+		round:    3,
+		balance:  "15000000000000000000",
+		gasPrice: "20000000000",
+		serverMeta: &types.ServerMetadata{
+			ActualFee:         4200,
+			SuccessIndicator:  types.TxStatusSuccessful,
+			ProcessingDetails: detailBytes,
+		},
+	}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	apiFlag := " --alphabill-api-uri " + addr.Host
+	unknownHash := "0x" + fmt.Sprintf("%064x", 1)
+
+	stdout, err := execEvmCmd(t, homedir, "evm tx-status "+unknownHash+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Transaction "+unknownHash+" is not confirmed, it is either pending, timed out or unknown")
+
+	// send without waiting for the confirmation
+	stdout, err = execEvmCmd(t, homedir, "evm send --no-wait --amount 1 --to 0x1111111111111111111111111111111111111111"+apiFlag)
+	require.NoError(t, err)
+	require.NotNil(t, mockConf.receivedTx)
+	txHash, err := mockConf.receivedTx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	hashStr := fmt.Sprintf("0x%x", txHash)
+	testutils.VerifyStdout(t, stdout, "Evm transaction submitted, tx hash: "+hashStr)
+	testutils.VerifyStdoutNotExists(t, stdout, "Evm transaction succeeded")
+
+	proofFile := filepath.Join(t.TempDir(), "proof.cbor")
+	stdout, err = execEvmCmd(t, homedir, "evm tx-status "+hashStr+" --proof-output "+proofFile+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Transaction "+hashStr+" confirmed",
+		"Evm transaction hash: "+hashStr,
+		"Evm transaction succeeded",
+		"Evm transaction processing fee: 0.000'042'00",
+		"Transaction proof(s) saved to file:"+proofFile)
+	data, err := os.ReadFile(proofFile)
+	require.NoError(t, err)
+	var proofs []*types.TxRecordProof
+	require.NoError(t, types.Cbor.Unmarshal(data, &proofs))
+	require.Len(t, proofs, 1)
+	require.EqualValues(t, 4200, proofs[0].TxRecord.ServerMetadata.GetActualFee())
+
+	// the hash is printed and the proof is saved when the confirmation is waited for
+	proofFile = filepath.Join(t.TempDir(), "send.cbor")
+	stdout, err = execEvmCmd(t, homedir, "evm send --proof-output "+proofFile+" --amount 1 --to 0x1111111111111111111111111111111111111111"+apiFlag)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout, "Evm transaction hash: 0x", "Transaction proof(s) saved to file:"+proofFile)
+	require.FileExists(t, proofFile)
+
+	// errors
+	_, err = execEvmCmd(t, homedir, "evm tx-status 0x1234"+apiFlag)
+	require.ErrorContains(t, err, "invalid transaction hash: expected 32 bytes, got 2")
+	_, err = execEvmCmd(t, homedir, "evm tx-status zz"+apiFlag)
+	require.ErrorContains(t, err, "invalid transaction hash")
+	_, err = execEvmCmd(t, homedir, "evm send --no-wait --proof-output x --amount 1 --to-key 1"+apiFlag)
+	require.ErrorContains(t, err, "if any flags in the group [no-wait proof-output] are set none of the others can be")
+}
@@ -0,0 +1,130 @@
+package evm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+// verifyContextLen is the number of bytes shown before and after the first difference of the codes.
+const verifyContextLen = 8
+
+func evmCmdVerify(config *types.EvmConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "verifies that the deployed smart contract code matches the compiler artifact",
+		Long: "Verifies that the runtime code of the deployed smart contract matches the runtime bytecode in the compiler " +
+			"artifact (Hardhat, Truffle or Foundry JSON or solc --combined-json output). The metadata appended to the code by " +
+			"the compiler is compared separately, when only the metadata differs the executable code is the same but the " +
+			"contract was compiled from different sources (eg comments) or with different settings. " +
+			"Libraries are linked into the artifact code with the \"" + LinkCmdName + "\" flags the same way as for the deployment. " +
+			"The values of the immutable variables are ignored when the artifact contains their positions (Foundry output), " +
+			"otherwise these are reported as differences.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execEvmCmdVerify(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for from address in evm call")
+	cmd.Flags().String(args.AddressCmdName, "", "smart contract address in hexadecimal format")
+	cmd.Flags().String(ArtifactCmdName, "", "compiler artifact file containing the contract runtime bytecode")
+	cmd.Flags().String(ContractCmdName, "", "name of the contract in the artifact, required when the artifact contains multiple contracts")
+	cmd.Flags().StringArray(LinkCmdName, nil, "library address to link into the bytecode in form name=0x<address>, "+
+		"name is either library name or fully qualified name (path/File.sol:Name), can be repeated")
+	if err := cmd.MarkFlagRequired(args.AddressCmdName); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkFlagRequired(ArtifactCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func execEvmCmdVerify(cmd *cobra.Command, config *types.EvmConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	addrStr, err := cmd.Flags().GetString(args.AddressCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", args.AddressCmdName, err)
+	}
+	if !common.IsHexAddress(addrStr) {
+		return fmt.Errorf("invalid address %q, address must be 20 bytes in hex", addrStr)
+	}
+	artifactFile, err := cmd.Flags().GetString(ArtifactCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ArtifactCmdName, err)
+	}
+	contractName, err := cmd.Flags().GetString(ContractCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ContractCmdName, err)
+	}
+	artifact, err := evmwallet.LoadArtifact(artifactFile, contractName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", ArtifactCmdName, err)
+	}
+	libraries, err := readLinkFlag(cmd)
+	if err != nil {
+		return err
+	}
+	w, err := initEvmWallet(cmd, config)
+	if err != nil {
+		return fmt.Errorf("evm wallet init failed: %w", err)
+	}
+	defer w.Shutdown()
+	addr := common.HexToAddress(addrStr)
+	code, err := w.GetCode(cmd.Context(), accountNumber, addr)
+	if err != nil {
+		return err
+	}
+	res, err := evmwallet.VerifyCode(code, artifact, libraries)
+	if err != nil {
+		return fmt.Errorf("verification failed: %w", err)
+	}
+
+	consoleWriter := config.WalletConfig.Base.ConsoleWriter
+	consoleWriter.Println(fmt.Sprintf("Contract address: 0x%x", addr))
+	consoleWriter.Println(fmt.Sprintf("Deployed code: %d bytes, metadata %d bytes", len(res.Deployed), len(res.DeployedMetadata)))
+	consoleWriter.Println(fmt.Sprintf("Artifact code: %d bytes, metadata %d bytes", len(res.Expected), len(res.ExpectedMetadata)))
+	if !res.Match {
+		consoleWriter.Println("Runtime code does not match the artifact")
+		printCodeMismatch(consoleWriter, res)
+		return errors.New("runtime code does not match the artifact")
+	}
+	consoleWriter.Println("Runtime code matches the artifact")
+	if res.MetadataMatch {
+		consoleWriter.Println("Metadata matches the artifact")
+	} else {
+		consoleWriter.Println("Metadata does not match the artifact, the contract was compiled from different sources or with different settings")
+		consoleWriter.Println(fmt.Sprintf("Deployed metadata: %x", res.DeployedMetadata))
+		consoleWriter.Println(fmt.Sprintf("Artifact metadata: %x", res.ExpectedMetadata))
+	}
+	return nil
+}
+
+// printCodeMismatch prints the first difference of the codes with the surrounding bytes.
+func printCodeMismatch(consoleWriter types.ConsoleWrapper, res *evmwallet.CodeVerification) {
+	if len(res.Deployed) != len(res.Expected) {
+		consoleWriter.Println(fmt.Sprintf("Code length differs: deployed %d bytes, artifact %d bytes", len(res.Deployed), len(res.Expected)))
+	}
+	if res.MismatchCount > 0 {
+		consoleWriter.Println(fmt.Sprintf("%d byte(s) differ in the first %d bytes", res.MismatchCount, min(len(res.Deployed), len(res.Expected))))
+	}
+	start := max(0, res.MismatchOffset-verifyContextLen)
+	consoleWriter.Println(fmt.Sprintf("First difference at byte offset %d (0x%x), code from offset %d:", res.MismatchOffset, res.MismatchOffset, start))
+	consoleWriter.Println(fmt.Sprintf("Deployed: %x", codeWindow(res.Deployed, start)))
+	consoleWriter.Println(fmt.Sprintf("Artifact: %x", codeWindow(res.Expected, start)))
+}
+
+func codeWindow(code []byte, start int) []byte {
+	if start >= len(code) {
+		return nil
+	}
+	return code[start:min(len(code), start+2*verifyContextLen+1)]
+}
@@ -0,0 +1,74 @@
+package evm
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+)
+
+func Test_evmCmdVerify(t *testing.T) {
+	homedir := testutils.CreateNewTestWallet(t)
+	// CBOR encoded metadata {"solc": 0x000818} followed by its length
+	metadata := "a164736f6c6343000818000a"
+	runtimeCode := "6080604052348015600e575f80fd5b50"
+	artifactFile := filepath.Join(t.TempDir(), "Token.json")
+	require.NoError(t, os.WriteFile(artifactFile, []byte(`{"contractName":"Token","abi":[],"bytecode":"0x6080",
+		"deployedBytecode":"0x`+runtimeCode+metadata+`"}`), 0600))
+	deployed := func(code string) *evm.CallEVMResponse {
+		b, err := hex.DecodeString(code)
+		require.NoError(t, err)
+		return &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ReturnData: b, ErrorDetails: "evm runtime error: execution reverted"}}
+	}
+	mockConf := &clientMockConf{balance: "15000000000000000000", gasPrice: "10000", callResp: deployed(runtimeCode + metadata)}
+	mockServer, addr := mockClientCalls(t, mockConf)
+	defer mockServer.Close()
+	cmdArgs := "evm verify --address 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521 --artifact " + artifactFile + " --alphabill-api-uri " + addr.Host
+
+	stdout, err := execEvmCmd(t, homedir, cmdArgs)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Contract address: 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521",
+		"Deployed code: 16 bytes, metadata 12 bytes",
+		"Artifact code: 16 bytes, metadata 12 bytes",
+		"Runtime code matches the artifact",
+		"Metadata matches the artifact")
+	require.Nil(t, mockConf.callReq.To)
+
+	// only metadata differs
+	mockConf.callResp = deployed(runtimeCode + "a164736f6c6343000819000a")
+	stdout, err = execEvmCmd(t, homedir, cmdArgs)
+	require.NoError(t, err)
+	testutils.VerifyStdout(t, stdout,
+		"Runtime code matches the artifact",
+		"Metadata does not match the artifact, the contract was compiled from different sources or with different settings",
+		"Deployed metadata: a164736f6c6343000819000a",
+		"Artifact metadata: "+metadata)
+
+	// code differs
+	mockConf.callResp = deployed("6080604052348015600f575f80fd5b5000" + metadata)
+	stdout, err = execEvmCmd(t, homedir, cmdArgs)
+	require.EqualError(t, err, "runtime code does not match the artifact")
+	testutils.VerifyStdout(t, stdout,
+		"Runtime code does not match the artifact",
+		"Code length differs: deployed 17 bytes, artifact 16 bytes",
+		"1 byte(s) differ in the first 16 bytes",
+		"First difference at byte offset 9 (0x9), code from offset 1:",
+		"Deployed: 80604052348015600f575f80fd5b5000",
+		"Artifact: 80604052348015600e575f80fd5b50")
+
+	// no contract
+	mockConf.callResp = deployed("")
+	_, err = execEvmCmd(t, homedir, cmdArgs)
+	require.EqualError(t, err, "verification failed: there is no contract code at the address")
+
+	_, err = execEvmCmd(t, homedir, "evm verify --address 0x1234 --artifact "+artifactFile+" --alphabill-api-uri "+addr.Host)
+	require.EqualError(t, err, `invalid address "0x1234", address must be 20 bytes in hex`)
+	_, err = execEvmCmd(t, homedir, "evm verify --address 0x3443919fcbc4476b4f332fd5df6a82fe88dbf521 --alphabill-api-uri "+addr.Host)
+	require.ErrorContains(t, err, `required flag(s) "artifact" not set`)
+}
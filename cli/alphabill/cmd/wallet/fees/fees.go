@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
@@ -18,9 +19,15 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
 	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
 	"github.com/spf13/cobra"
 )
 
+const (
+	transferProofCmdName = "transfer-proof"
+	closeProofCmdName    = "close-proof"
+)
+
 // NewFeesCmd creates a new cobra command for the wallet fees component.
 func NewFeesCmd(walletConfig *clitypes.WalletConfig) *cobra.Command {
 	var config = &feesConfig{
@@ -58,6 +65,10 @@ func addFeeCreditCmd(config *feesConfig) *cobra.Command {
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies to which account to add the fee credit")
 	cmd.Flags().StringP(args.AmountCmdName, "v", "1", "specifies how much fee credit to create in ALPHA")
 	args.AddMaxFeeFlag(cmd, cmd.Flags())
+	args.AddUnsignedOutFlags(cmd, cmd.Flags())
+	cmd.Flags().String(transferProofCmdName, "", "file of the transferFC transaction proof saved by the \"tx submit\" command, "+
+		"builds the unsigned addFC transaction of the fee credit addition started with the \""+args.UnsignedOutFlagName+"\" flag")
+	cmd.MarkFlagsMutuallyExclusive(transferProofCmdName, args.AmountCmdName)
 	return cmd
 }
 
@@ -78,6 +89,10 @@ func addFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	if err != nil {
 		return err
 	}
+	unsignedOut, unsignedTimeout, transferProofFile, err := unsignedFeeTxArgs(cmd, config, transferProofCmdName)
+	if err != nil {
+		return err
+	}
 
 	walletConfig := config.walletConfig
 	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
@@ -97,6 +112,32 @@ func addFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	}
 	defer fm.Close()
 
+	if unsignedOut != "" {
+		var envelope *offline.Envelope
+		if transferProofFile != "" {
+			proof, err := readTxProof(transferProofFile)
+			if err != nil {
+				return err
+			}
+			envelope, err = fm.BuildUnsignedAddFC(cmd.Context(), accountNumber-1, proof, unsignedTimeout)
+			if err != nil {
+				return err
+			}
+		} else {
+			amount, err := util.StringToAmount(amountString, 8)
+			if err != nil {
+				return err
+			}
+			envelope, err = fm.BuildUnsignedTransferFC(cmd.Context(), fees.AddFeeCmd{Amount: amount, AccountIndex: accountNumber - 1, DisableLocking: true}, unsignedTimeout)
+			if err != nil {
+				if errors.Is(err, fees.ErrMinimumFeeAmount) {
+					return fmt.Errorf("minimum fee credit amount to add is %s", util.AmountToString(fm.MinAddFeeAmount(), 8))
+				}
+				return err
+			}
+		}
+		return writeUnsignedFeeTx(unsignedOut, envelope, transferProofCmdName, transferProofFile == "", walletConfig.Base.ConsoleWriter)
+	}
 	return addFees(cmd.Context(), accountNumber, amountString, config, fm, walletConfig.Base.ConsoleWriter)
 }
 
@@ -154,6 +195,9 @@ func reclaimFeeCreditCmd(config *feesConfig) *cobra.Command {
 	}
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "specifies to which account to reclaim the fee credit")
 	args.AddMaxFeeFlag(cmd, cmd.Flags())
+	args.AddUnsignedOutFlags(cmd, cmd.Flags())
+	cmd.Flags().String(closeProofCmdName, "", "file of the closeFC transaction proof saved by the \"tx submit\" command, "+
+		"builds the unsigned reclaimFC transaction of the fee credit reclaim started with the \""+args.UnsignedOutFlagName+"\" flag")
 	return cmd
 }
 
@@ -169,6 +213,10 @@ func reclaimFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	if err != nil {
 		return err
 	}
+	unsignedOut, unsignedTimeout, closeProofFile, err := unsignedFeeTxArgs(cmd, config, closeProofCmdName)
+	if err != nil {
+		return err
+	}
 
 	walletConfig := config.walletConfig
 	am, err := cliaccount.LoadExistingAccountManager(walletConfig)
@@ -189,6 +237,28 @@ func reclaimFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	}
 	defer fm.Close()
 
+	if unsignedOut != "" {
+		var envelope *offline.Envelope
+		if closeProofFile != "" {
+			proof, err := readTxProof(closeProofFile)
+			if err != nil {
+				return err
+			}
+			envelope, err = fm.BuildUnsignedReclaimFC(cmd.Context(), accountNumber-1, proof, unsignedTimeout)
+			if err != nil {
+				return err
+			}
+		} else {
+			envelope, err = fm.BuildUnsignedCloseFC(cmd.Context(), fees.ReclaimFeeCmd{AccountIndex: accountNumber - 1, DisableLocking: true}, unsignedTimeout)
+			if err != nil {
+				if errors.Is(err, fees.ErrMinimumFeeAmount) {
+					return fmt.Errorf("insufficient fee credit balance. Minimum amount is %s", util.AmountToString(fm.MinReclaimFeeAmount(), 8))
+				}
+				return err
+			}
+		}
+		return writeUnsignedFeeTx(unsignedOut, envelope, closeProofCmdName, closeProofFile == "", walletConfig.Base.ConsoleWriter)
+	}
 	return reclaimFees(cmd.Context(), accountNumber, config, fm, walletConfig.Base.ConsoleWriter)
 }
 
@@ -307,6 +377,58 @@ func unlockFeeCreditCmdExec(cmd *cobra.Command, config *feesConfig) error {
 	return nil
 }
 
+/*
+unsignedFeeTxArgs returns values of the "unsigned-out", "unsigned-timeout" and the proof file flags,
+the proof file of the first transaction of the fee credit process can only be used together with
+the "unsigned-out" flag.
+*/
+func unsignedFeeTxArgs(cmd *cobra.Command, config *feesConfig, proofFlag string) (unsignedOut string, timeout uint64, proofFile string, _ error) {
+	unsignedOut, timeout, err := args.UnsignedOutArg(cmd)
+	if err != nil {
+		return "", 0, "", err
+	}
+	proofFile, err = cmd.Flags().GetString(proofFlag)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to read '%s' parameter: %w", proofFlag, err)
+	}
+	if proofFile != "" && unsignedOut == "" {
+		return "", 0, "", fmt.Errorf("'%s' parameter can only be used together with '%s' parameter", proofFlag, args.UnsignedOutFlagName)
+	}
+	// the transactions are submitted with the "tx submit" command which supports money and tokens partitions
+	if unsignedOut != "" && config.targetPartitionType == clitypes.EvmType {
+		return "", 0, "", fmt.Errorf("unsigned fee credit transactions are not supported for %s partition", config.targetPartitionType.String())
+	}
+	return unsignedOut, timeout, proofFile, nil
+}
+
+// readTxProof reads the transaction proof from the file saved by the "tx submit" command.
+func readTxProof(filename string) (*basetypes.TxRecordProof, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction proof file: %w", err)
+	}
+	var proofs []*basetypes.TxRecordProof
+	if err := basetypes.Cbor.Unmarshal(data, &proofs); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction proof file: %w", err)
+	}
+	if len(proofs) != 1 {
+		return nil, fmt.Errorf("expected single transaction proof in file, got %d", len(proofs))
+	}
+	return proofs[0], nil
+}
+
+func writeUnsignedFeeTx(filename string, envelope *offline.Envelope, proofFlag string, firstTx bool, consoleWriter clitypes.ConsoleWrapper) error {
+	if err := offline.WriteEnvelope(filename, envelope); err != nil {
+		return err
+	}
+	consoleWriter.Println(fmt.Sprintf("Unsigned transaction(s) saved to file: %s", filename))
+	if firstTx {
+		consoleWriter.Println(fmt.Sprintf("Sign and submit the transaction with the \"tx sign\" and \"tx submit --%s\" commands, "+
+			"then run the command again with the \"%s\" flag to build the second transaction.", args.ProofOutputFlagName, proofFlag))
+	}
+	return nil
+}
+
 type FeeCreditManager interface {
 	GetFeeCredit(ctx context.Context, cmd fees.GetFeeCreditCmd) (*types.FeeCreditRecord, error)
 	AddFeeCredit(ctx context.Context, cmd fees.AddFeeCmd) (*fees.AddFeeCmdResponse, error)
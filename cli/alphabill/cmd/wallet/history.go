@@ -0,0 +1,300 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	cliaccount "github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/history"
+)
+
+const (
+	historyFromRoundCmdName  = "from-round"
+	historyStartRoundCmdName = "start-round"
+	historyToRoundCmdName    = "to-round"
+	historyTypeCmdName       = "type"
+)
+
+// moneyTxTypeNames are the names of the transaction types of the money partition accepted by the "type" flag.
+var moneyTxTypeNames = map[string]uint16{
+	"transfer":    money.TransactionTypeTransfer,
+	"split":       money.TransactionTypeSplit,
+	"transfer-dc": money.TransactionTypeTransDC,
+	"swap-dc":     money.TransactionTypeSwapDC,
+	"transfer-fc": fc.TransactionTypeTransferFeeCredit,
+	"reclaim-fc":  fc.TransactionTypeReclaimFeeCredit,
+	"add-fc":      fc.TransactionTypeAddFeeCredit,
+	"close-fc":    fc.TransactionTypeCloseFeeCredit,
+}
+
+func HistoryCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "lists the transactions sent and received by the wallet",
+		Long: "Lists the transactions which are signed by the keys of the wallet or which pay to the keys of the wallet. " +
+			"The history is indexed from the blocks of the partition and stored in the wallet, every run indexes the " +
+			"blocks created after the previous run. The first run indexes the blocks from the round given with the '" + historyStartRoundCmdName +
+			"' parameter, a later run with an earlier start round indexes the missing blocks. When keys are added to the wallet " +
+			"the already indexed blocks are indexed again for the new keys only.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execHistoryCmd(cmd, config)
+		},
+	}
+	cmd.Flags().StringP(args.RpcUrl, "r", args.DefaultMoneyRpcUrl, "rpc node url")
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 0, "which key to list the history of, 0 for all keys")
+	cmd.Flags().Uint64(historyFromRoundCmdName, 0, "first round to list")
+	cmd.Flags().Uint64(historyToRoundCmdName, 0, "last round to list (default latest round)")
+	cmd.Flags().Uint64(historyStartRoundCmdName, 0, "first round to index, the transactions of the earlier rounds are "+
+		"not listed (default the start round of the previous runs or the first round)")
+	cmd.Flags().StringSlice(historyTypeCmdName, nil, "transaction type(s) to list, number of the type or one of "+
+		strings.Join(slices.Sorted(maps.Keys(moneyTxTypeNames)), ", ")+" for the money partition")
+	cmd.Flags().String(args.ReferenceNumber, "", `list only the transactions with the "reference number", `+
+		`prefix the value with "0x" to pass hex encoded binary data`)
+	return cmd
+}
+
+func execHistoryCmd(cmd *cobra.Command, config *types.WalletConfig) error {
+	filter, err := readHistoryFilter(cmd)
+	if err != nil {
+		return err
+	}
+	rpcUrl, err := cmd.Flags().GetString(args.RpcUrl)
+	if err != nil {
+		return err
+	}
+	rpcClient, err := rpc.NewClient(cmd.Context(), args.BuildRpcUrl(rpcUrl))
+	if err != nil {
+		return fmt.Errorf("failed to dial rpc url: %w", err)
+	}
+	defer rpcClient.Close()
+	stateAPI, err := rpc.NewStateAPIClient(cmd.Context(), rpcClient)
+	if err != nil {
+		return err
+	}
+	adminAPI, err := rpc.NewAdminAPIClient(cmd.Context(), rpcClient)
+	if err != nil {
+		return err
+	}
+	info, err := adminAPI.GetNodeInfo(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("requesting node info: %w", err)
+	}
+	// the same lengths of the identifiers as the partition clients use
+	pdr := &abtypes.PartitionDescriptionRecord{
+		NetworkID:       info.NetworkID,
+		PartitionID:     info.PartitionID,
+		PartitionTypeID: info.PartitionTypeID,
+		UnitIDLen:       256,
+		TypeIDLen:       8,
+	}
+	if filter.TxTypes, err = parseHistoryTxTypes(cmd, pdr.PartitionTypeID); err != nil {
+		return err
+	}
+
+	am, err := cliaccount.LoadExistingAccountManager(config)
+	if err != nil {
+		return err
+	}
+	defer am.Close()
+	owners, err := historyOwners(am)
+	if err != nil {
+		return err
+	}
+
+	historyDB, err := history.NewHistoryDB(config.WalletHomeDir)
+	if err != nil {
+		return err
+	}
+	defer historyDB.Close()
+
+	roundInfo, err := stateAPI.GetRoundInfo(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to read latest round number: %w", err)
+	}
+	startRound, err := cmd.Flags().GetUint64(historyStartRoundCmdName)
+	if err != nil {
+		return err
+	}
+	indexer := history.NewIndexer(historyDB, stateAPI, pdr, owners, config.Base.Logger)
+	if err := indexer.Sync(cmd.Context(), startRound, roundInfo.RoundNumber); err != nil {
+		return fmt.Errorf("failed to sync transaction history: %w", err)
+	}
+
+	entries, err := historyDB.GetEntries(pdr, filter)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		config.Base.ConsoleWriter.Println("No transactions")
+		return nil
+	}
+	for _, e := range entries {
+		config.Base.ConsoleWriter.Println(formatHistoryEntry(e, pdr.PartitionTypeID))
+	}
+	return nil
+}
+
+/*
+billLastModifiedRounds returns the rounds in which the bills of the account were last modified, by
+bill ID. The rounds are found from the transaction history of the wallet, the history is indexed
+up to the latest round first (from the first round if the history command has not been run).
+*/
+func billLastModifiedRounds(ctx context.Context, config *types.WalletConfig, am account.Manager, moneyClient sdktypes.MoneyPartitionClient, rpcUrl string, accountIndex uint64) (map[string]uint64, error) {
+	owners, err := historyOwners(am)
+	if err != nil {
+		return nil, err
+	}
+	pdr, err := moneyClient.PartitionDescription(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partition description: %w", err)
+	}
+	roundInfo, err := moneyClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read latest round number: %w", err)
+	}
+	rpcClient, err := rpc.NewClient(ctx, args.BuildRpcUrl(rpcUrl))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc url: %w", err)
+	}
+	defer rpcClient.Close()
+	stateAPI, err := rpc.NewStateAPIClient(ctx, rpcClient)
+	if err != nil {
+		return nil, err
+	}
+	historyDB, err := history.NewHistoryDB(config.WalletHomeDir)
+	if err != nil {
+		return nil, err
+	}
+	defer historyDB.Close()
+
+	indexer := history.NewIndexer(historyDB, stateAPI, pdr, owners, config.Base.Logger)
+	if err := indexer.Sync(ctx, 0, roundInfo.RoundNumber); err != nil {
+		return nil, fmt.Errorf("failed to sync transaction history: %w", err)
+	}
+	entries, err := historyDB.GetEntries(pdr, &history.Filter{AccountIndexes: []uint64{accountIndex}})
+	if err != nil {
+		return nil, err
+	}
+	return history.LastModifiedRounds(entries), nil
+}
+
+// historyOwners returns the owners of the transaction history, all the keys of the wallet.
+func historyOwners(am account.Manager) ([]*history.Owner, error) {
+	accountKeys, err := am.GetAccountKeys()
+	if err != nil {
+		return nil, err
+	}
+	var owners []*history.Owner
+	for i, key := range accountKeys {
+		if key.PubKey == nil {
+			owners = append(owners, history.NewOwnerFromKeyHash(uint64(i), key.PubKeyHash.Sha256))
+		} else {
+			owners = append(owners, history.NewOwner(uint64(i), key.PubKey))
+		}
+	}
+	return owners, nil
+}
+
+func readHistoryFilter(cmd *cobra.Command) (*history.Filter, error) {
+	filter := &history.Filter{}
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return nil, err
+	}
+	if accountNumber > 0 {
+		filter.AccountIndexes = []uint64{accountNumber - 1}
+	}
+	if filter.FromRound, err = cmd.Flags().GetUint64(historyFromRoundCmdName); err != nil {
+		return nil, err
+	}
+	if filter.ToRound, err = cmd.Flags().GetUint64(historyToRoundCmdName); err != nil {
+		return nil, err
+	}
+	if filter.ToRound != 0 && filter.FromRound > filter.ToRound {
+		return nil, fmt.Errorf("invalid round range %d - %d", filter.FromRound, filter.ToRound)
+	}
+	if cmd.Flags().Changed(args.ReferenceNumber) {
+		refNumber, err := parseReferenceNumberArg(cmd)
+		if err != nil {
+			return nil, err
+		}
+		// empty flag value lists the transactions without reference number
+		filter.ReferenceNumber = append([]byte{}, refNumber...)
+	}
+	return filter, nil
+}
+
+func parseHistoryTxTypes(cmd *cobra.Command, partitionTypeID abtypes.PartitionTypeID) ([]uint16, error) {
+	typeNames, err := cmd.Flags().GetStringSlice(historyTypeCmdName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s' parameter: %w", historyTypeCmdName, err)
+	}
+	var txTypes []uint16
+	for _, name := range typeNames {
+		if txType, ok := moneyTxTypeNames[name]; ok && partitionTypeID == money.PartitionTypeID {
+			txTypes = append(txTypes, txType)
+			continue
+		}
+		txType, err := strconv.ParseUint(name, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction type %q", name)
+		}
+		txTypes = append(txTypes, uint16(txType))
+	}
+	return txTypes, nil
+}
+
+func formatHistoryEntry(e *history.Entry, partitionTypeID abtypes.PartitionTypeID) string {
+	txType := strconv.FormatUint(uint64(e.TxType), 10)
+	if partitionTypeID == money.PartitionTypeID {
+		for name, t := range moneyTxTypeNames {
+			if t == e.TxType {
+				txType = name
+			}
+		}
+	}
+	line := fmt.Sprintf("#%d round %d %s %s", e.AccountIndex+1, e.RoundNumber, e.Direction, txType)
+	if e.Amount > 0 {
+		line += " " + util.AmountToString(e.Amount, 8)
+	}
+	line += fmt.Sprintf(" unit %s tx %s", hexutil.Encode(e.UnitID), hexutil.Encode(e.TxHash))
+	if e.Direction == history.DirectionSent {
+		line += " fee " + util.AmountToString(e.Fee, 8)
+	}
+	if len(e.ReferenceNumber) > 0 {
+		line += " ref " + formatReferenceNumber(e.ReferenceNumber)
+	}
+	if !e.Success {
+		line += " (failed)"
+	}
+	return line
+}
+
+// formatReferenceNumber returns the reference number as string if it is printable
+// UTF-8 text, otherwise hex encoded, i.e. the way it is given to the send command.
+func formatReferenceNumber(refNumber []byte) string {
+	s := string(refNumber)
+	if utf8.ValidString(s) && !strings.HasPrefix(s, "0x") && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsPrint(r) }) < 0 {
+		return s
+	}
+	return hexutil.Encode(refNumber)
+}
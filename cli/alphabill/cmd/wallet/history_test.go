@@ -0,0 +1,131 @@
+package wallet
+
+import (
+	"crypto"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/history"
+)
+
+func TestHistoryCmd(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic(), testutils.WithNumberOfAccounts(2))
+	walletKeys, err := account.NewKeys(testutils.TestMnemonic)
+	require.NoError(t, err)
+	otherKeys, err := account.NewKeys("")
+	require.NoError(t, err)
+	pubKey0, _ := hex.DecodeString(testutils.TestPubKey0Hex)
+	pubKey1, _ := hex.DecodeString(testutils.TestPubKey1Hex)
+
+	// round 2: another wallet sends to key #1
+	received := newHistoryTestTx(t, &pdr, otherKeys.AccountKey, func(bill *sdktypes.Bill) (*abtypes.TransactionOrder, error) {
+		return bill.Transfer(templates.NewP2pkh256BytesFromKey(pubKey0), sdktypes.WithReferenceNumber([]byte("invoice-1")))
+	})
+	// round 4: key #1 sends to key #2 and to another wallet
+	sent := newHistoryTestTx(t, &pdr, walletKeys.AccountKey, func(bill *sdktypes.Bill) (*abtypes.TransactionOrder, error) {
+		return bill.Split([]*money.TargetUnit{
+			{Amount: 50_000_000, OwnerPredicate: templates.NewP2pkh256BytesFromKey(pubKey1)},
+			{Amount: 20_000_000, OwnerPredicate: templates.NewP2pkh256BytesFromKey(otherKeys.AccountKey.PubKey)},
+		})
+	})
+	stateMock := mocksrv.NewStateServiceMock(
+		mocksrv.WithRoundNumber(5),
+		mocksrv.WithBlock(2, newHistoryTestBlock(t, received)),
+		mocksrv.WithBlock(4, newHistoryTestBlock(t, sent)),
+	)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, stateMock)
+	historyCmd := newWalletCmdExecutor("history", "--rpc-url", rpcUrl).WithHome(homedir)
+
+	receivedLine := fmt.Sprintf("#1 round 2 received transfer 1.000'000'00 unit %s tx %s ref invoice-1",
+		hexutil.Encode(received.UnitID), hexutil.Encode(txHash(t, received)))
+	sentLine := fmt.Sprintf("#1 round 4 sent split 0.700'000'00 unit %s tx %s fee 0.000'000'01",
+		hexutil.Encode(sent.UnitID), hexutil.Encode(txHash(t, sent)))
+	receivedByKey2Line := fmt.Sprintf("#2 round 4 received split 0.500'000'00 unit %s", hexutil.Encode(sent.UnitID))
+
+	stdout := historyCmd.Exec(t)
+	require.Len(t, stdout.Lines, 3)
+	testutils.VerifyStdout(t, stdout, receivedLine, sentLine, receivedByKey2Line)
+	_, err = os.Stat(filepath.Join(homedir, "wallet", history.HistoryDBFileName))
+	require.NoError(t, err)
+
+	// filters
+	stdout = historyCmd.Exec(t, "--key", "2")
+	require.Len(t, stdout.Lines, 1)
+	testutils.VerifyStdout(t, stdout, receivedByKey2Line)
+
+	stdout = historyCmd.Exec(t, "--type", "transfer")
+	require.Len(t, stdout.Lines, 1)
+	testutils.VerifyStdout(t, stdout, receivedLine)
+
+	stdout = historyCmd.Exec(t, "--reference-number", "invoice-1")
+	require.Len(t, stdout.Lines, 1)
+	testutils.VerifyStdout(t, stdout, receivedLine)
+
+	stdout = historyCmd.Exec(t, "--from-round", "3", "--to-round", "4", "--key", "1")
+	require.Len(t, stdout.Lines, 1)
+	testutils.VerifyStdout(t, stdout, sentLine)
+
+	stdout = historyCmd.Exec(t, "--type", "swap-dc")
+	testutils.VerifyStdout(t, stdout, "No transactions")
+
+	historyCmd.ExecWithError(t, `invalid transaction type "foo"`, "--type", "foo")
+	historyCmd.ExecWithError(t, "invalid round range 4 - 3", "--from-round", "4", "--to-round", "3")
+
+	// the rounds before the start round are not indexed
+	homedir = testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic(), testutils.WithNumberOfAccounts(2))
+	historyCmd = newWalletCmdExecutor("history", "--rpc-url", rpcUrl).WithHome(homedir)
+	stdout = historyCmd.Exec(t, "--start-round", "3")
+	require.Len(t, stdout.Lines, 2)
+	testutils.VerifyStdout(t, stdout, sentLine, receivedByKey2Line)
+
+	stdout = historyCmd.Exec(t, "--start-round", "1")
+	require.Len(t, stdout.Lines, 3)
+	testutils.VerifyStdout(t, stdout, receivedLine, sentLine, receivedByKey2Line)
+}
+
+func newHistoryTestTx(t *testing.T, pdr *abtypes.PartitionDescriptionRecord, signer *account.AccountKey, newTx func(*sdktypes.Bill) (*abtypes.TransactionOrder, error)) *abtypes.TransactionOrder {
+	bill := &sdktypes.Bill{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: moneyid.NewBillID(t), Value: 1e8, Counter: 1}
+	tx, err := newTx(bill)
+	require.NoError(t, err)
+	txSigner, err := sdktypes.NewMoneyTxSignerFromKey(signer.PrivKey)
+	require.NoError(t, err)
+	require.NoError(t, txSigner.SignTx(tx))
+	return tx
+}
+
+func newHistoryTestBlock(t *testing.T, tx *abtypes.TransactionOrder) []byte {
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	block, err := abtypes.Cbor.Marshal(&abtypes.Block{
+		Header: &abtypes.Header{Version: 1},
+		Transactions: []*abtypes.TransactionRecord{{
+			Version:          1,
+			TransactionOrder: txBytes,
+			ServerMetadata:   &abtypes.ServerMetadata{SuccessIndicator: abtypes.TxStatusSuccessful, ActualFee: 1},
+		}},
+	})
+	require.NoError(t, err)
+	return block
+}
+
+func txHash(t *testing.T, tx *abtypes.TransactionOrder) []byte {
+	h, err := tx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	return h
+}
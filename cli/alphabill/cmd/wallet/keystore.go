@@ -0,0 +1,180 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	cliaccount "github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+const (
+	keystoreFileCmdName     = "file"
+	keystorePasswordCmdName = "keystore-password"
+	lightKDFCmdName         = "light-kdf"
+)
+
+func ExportKeyCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-key",
+		Short: "exports account key as Web3 Secret Storage (keystore v3) JSON file",
+		Long: "Exports the private key of the account as Web3 Secret Storage (keystore v3) JSON file, " +
+			"the file can be imported into Ethereum wallets (eg MetaMask, geth). The key is encrypted with the " +
+			"keystore passphrase, which is prompted when the \"" + keystorePasswordCmdName + "\" flag is not set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecExportKeyCmd(cmd, config)
+		},
+	}
+	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to export")
+	cmd.Flags().String(keystoreFileCmdName, "", "keystore file to create")
+	cmd.Flags().String(keystorePasswordCmdName, "", "passphrase of the keystore file")
+	cmd.Flags().Bool(lightKDFCmdName, false, "use weaker key derivation parameters, makes the keystore faster to decrypt but less secure")
+	if err := cmd.MarkFlagRequired(keystoreFileCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func ExecExportKeyCmd(cmd *cobra.Command, config *types.WalletConfig) error {
+	accountNumber, err := cmd.Flags().GetUint64(args.KeyCmdName)
+	if err != nil {
+		return fmt.Errorf("key parameter read failed: %w", err)
+	}
+	if accountNumber < 1 {
+		return fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	filename, err := cmd.Flags().GetString(keystoreFileCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", keystoreFileCmdName, err)
+	}
+	lightKDF, err := cmd.Flags().GetBool(lightKDFCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", lightKDFCmdName, err)
+	}
+	am, err := cliaccount.LoadExistingAccountManager(config)
+	if err != nil {
+		return err
+	}
+	defer am.Close()
+
+	accountKey, err := am.GetAccountKey(accountNumber - 1)
+	if err != nil {
+		return fmt.Errorf("account key read failed: %w", err)
+	}
+	if accountKey.IsWatchOnly() {
+		return fmt.Errorf("key #%d is watch-only account, it has no private key to export", accountNumber)
+	}
+	passphrase, err := readKeystorePassphrase(cmd, config, true)
+	if err != nil {
+		return err
+	}
+	keyJSON, err := account.EncryptKeystore(accountKey, passphrase, lightKDF)
+	if err != nil {
+		return err
+	}
+	// never overwrite an existing file, it might be the only copy of some other key
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // -rw-------
+	if err != nil {
+		return fmt.Errorf("failed to create keystore file: %w", err)
+	}
+	if _, err := f.Write(keyJSON); err != nil {
+		return errors.Join(fmt.Errorf("failed to write keystore file: %w", err), f.Close())
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write keystore file: %w", err)
+	}
+	addr, err := evmwallet.AddressFromPublicKey(accountKey.PubKey)
+	if err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Exported key #%d (EVM address %s) to %s", accountNumber, addr, filename))
+	return nil
+}
+
+func ImportKeyCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-key",
+		Short: "imports account key from Web3 Secret Storage (keystore v3) JSON file",
+		Long: "Imports the private key from Web3 Secret Storage (keystore v3) JSON file as the next account of the wallet. " +
+			"The imported key is not derived from the wallet mnemonic, it can not be restored from the mnemonic and " +
+			"must be backed up separately. The keystore passphrase is prompted when the \"" + keystorePasswordCmdName + "\" flag is not set.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecImportKeyCmd(cmd, config)
+		},
+	}
+	cmd.Flags().String(keystoreFileCmdName, "", "keystore file to import")
+	cmd.Flags().String(keystorePasswordCmdName, "", "passphrase of the keystore file")
+	if err := cmd.MarkFlagRequired(keystoreFileCmdName); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func ExecImportKeyCmd(cmd *cobra.Command, config *types.WalletConfig) error {
+	filename, err := cmd.Flags().GetString(keystoreFileCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", keystoreFileCmdName, err)
+	}
+	keyJSON, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read keystore file: %w", err)
+	}
+	passphrase, err := readKeystorePassphrase(cmd, config, false)
+	if err != nil {
+		return err
+	}
+	accountKey, err := account.DecryptKeystore(keyJSON, passphrase)
+	if err != nil {
+		return err
+	}
+	am, err := cliaccount.LoadExistingAccountManager(config)
+	if err != nil {
+		return err
+	}
+	defer am.Close()
+
+	accIdx, accPubKey, err := am.ImportAccount(accountKey)
+	if err != nil {
+		return fmt.Errorf("failed to import key: %w", err)
+	}
+	addr, err := evmwallet.AddressFromPublicKey(accPubKey)
+	if err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Imported key #%d %s (EVM address %s)", accIdx+1, hexutil.Encode(accPubKey), addr))
+	return nil
+}
+
+// readKeystorePassphrase returns the keystore passphrase from the flag or prompts it, when "confirm" is true
+// the prompted passphrase must be entered twice.
+func readKeystorePassphrase(cmd *cobra.Command, config *types.WalletConfig, confirm bool) (string, error) {
+	passphrase, err := cmd.Flags().GetString(keystorePasswordCmdName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read '%s' parameter: %w", keystorePasswordCmdName, err)
+	}
+	if cmd.Flags().Changed(keystorePasswordCmdName) {
+		return passphrase, nil
+	}
+	passphrase, err = cliaccount.ReadPassword(config.Base.ConsoleWriter, "Enter keystore passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+	confirmed, err := cliaccount.ReadPassword(config.Base.ConsoleWriter, "Confirm keystore passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirmed {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
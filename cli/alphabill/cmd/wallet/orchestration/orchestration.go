@@ -71,7 +71,7 @@ func execAddVarCmd(cmd *cobra.Command, config *clitypes.AddVarCmdConfig) error {
 	if err != nil {
 		return fmt.Errorf("failed to load account manager: %w", err)
 	}
-	ac, err := am.GetAccountKey(config.OrchestrationConfig.Key - 1)
+	ac, err := am.GetSigningKey(config.OrchestrationConfig.Key - 1)
 	if err != nil {
 		return fmt.Errorf("failed to load account key: %w", err)
 	}
@@ -105,7 +105,7 @@ func addFeeCreditCmdExec(cmd *cobra.Command, config *config) error {
 	if accountNumber == 0 {
 		return fmt.Errorf("invalid parameter for flag %q: 0 is not a valid account key", args.KeyCmdName)
 	}
-	accountKey, err := am.GetAccountKey(accountNumber - 1)
+	accountKey, err := am.GetSigningKey(accountNumber - 1)
 	if err != nil {
 		return fmt.Errorf("failed to get account key for account %d", accountNumber)
 	}
@@ -207,7 +207,7 @@ func deleteFeeCreditCmdExec(cmd *cobra.Command, config *config) error {
 	if accountNumber == 0 {
 		return fmt.Errorf("invalid parameter for flag %q: 0 is not a valid account key", args.KeyCmdName)
 	}
-	accountKey, err := am.GetAccountKey(accountNumber - 1)
+	accountKey, err := am.GetSigningKey(accountNumber - 1)
 	if err != nil {
 		return fmt.Errorf("failed to get account key for account %d", accountNumber)
 	}
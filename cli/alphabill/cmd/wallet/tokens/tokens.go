@@ -15,6 +15,7 @@ import (
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
 	tokenswallet "github.com/alphabill-org/alphabill-wallet/wallet/tokens"
 	"github.com/spf13/cobra"
 )
@@ -550,6 +551,7 @@ func tokenCmdSendFungible(config *types.WalletConfig) *cobra.Command {
 	if err != nil {
 		return nil
 	}
+	args.AddUnsignedOutFlags(cmd, cmd.Flags())
 	return addCommonAccountFlags(cmd)
 }
 
@@ -577,6 +579,10 @@ func execTokenCmdSendFungible(cmd *cobra.Command, config *types.WalletConfig) er
 	if err != nil {
 		return err
 	}
+	unsignedOut, unsignedTimeout, err := args.UnsignedOutArg(cmd)
+	if err != nil {
+		return err
+	}
 	tw, err := initTokensWallet(cmd, config)
 	if err != nil {
 		return err
@@ -621,6 +627,13 @@ func execTokenCmdSendFungible(cmd *cobra.Command, config *types.WalletConfig) er
 	if targetValue == 0 {
 		return fmt.Errorf("invalid parameter \"%s\" for \"--amount\": 0 is not valid amount", amountStr)
 	}
+	if unsignedOut != "" {
+		envelope, err := tw.BuildUnsignedSendFungible(cmd.Context(), accountNumber, typeId, targetValue, pubKey, ib, unsignedTimeout)
+		if err != nil {
+			return err
+		}
+		return writeUnsignedTxs(unsignedOut, envelope, config.Base.ConsoleWriter)
+	}
 	result, err := tw.SendFungible(cmd.Context(), accountNumber, typeId, targetValue, pubKey, ownerProofInput, ib)
 	if err != nil {
 		return err
@@ -654,6 +667,7 @@ func tokenCmdSendNonFungible(config *types.WalletConfig) *cobra.Command {
 	if err != nil {
 		return nil
 	}
+	args.AddUnsignedOutFlags(cmd, cmd.Flags())
 	return addCommonAccountFlags(cmd)
 }
 
@@ -662,6 +676,10 @@ func execTokenCmdSendNonFungible(cmd *cobra.Command, config *types.WalletConfig)
 	if err != nil {
 		return err
 	}
+	unsignedOut, unsignedTimeout, err := args.UnsignedOutArg(cmd)
+	if err != nil {
+		return err
+	}
 	tw, err := initTokensWallet(cmd, config)
 	if err != nil {
 		return err
@@ -688,6 +706,13 @@ func execTokenCmdSendNonFungible(cmd *cobra.Command, config *types.WalletConfig)
 		return err
 	}
 
+	if unsignedOut != "" {
+		envelope, err := tw.BuildUnsignedTransferNFT(cmd.Context(), accountNumber, tokenID, pubKey, typeOwnerPredicateInputs, unsignedTimeout)
+		if err != nil {
+			return err
+		}
+		return writeUnsignedTxs(unsignedOut, envelope, config.Base.ConsoleWriter)
+	}
 	result, err := tw.TransferNFT(cmd.Context(), accountNumber, tokenID, pubKey, typeOwnerPredicateInputs, ownerPredicateInput)
 	if err != nil {
 		return err
@@ -1353,6 +1378,15 @@ func getFileSize(filepath string) (int64, error) {
 	return fi.Size(), nil
 }
 
+// writeUnsignedTxs saves the unsigned transactions to be signed with the "tx sign" command.
+func writeUnsignedTxs(filename string, envelope *offline.Envelope, out types.ConsoleWrapper) error {
+	if err := offline.WriteEnvelope(filename, envelope); err != nil {
+		return err
+	}
+	out.Println(fmt.Sprintf("Unsigned transaction(s) saved to file: %s", filename))
+	return nil
+}
+
 /*
 saveTxProofs saves the tx proofs into file when the cmd has appropriate flag set.
 */
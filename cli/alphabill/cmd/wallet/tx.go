@@ -0,0 +1,168 @@
+package wallet
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
+	cliaccount "github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/util/account"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/client"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+const txOutputCmdName = "output"
+
+// NewTxCmd creates a new cobra command for signing and submitting the transactions
+// saved to file by the "--unsigned-out" flag of the send command.
+func NewTxCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "signs and submits transactions built by another wallet",
+		Long: "Signs and submits the transactions saved to file by the \"" + args.UnsignedOutFlagName + "\" flag. " +
+			"The transactions can be signed on an offline machine which has the account key, " +
+			"and submitted from an online machine which does not have the key.",
+	}
+	cmd.AddCommand(txSignCmd(config))
+	cmd.AddCommand(txSubmitCmd(config))
+	return cmd
+}
+
+func txSignCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign <transaction file>",
+		Short: "signs the transactions with the account key, does not need network access",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTxSignCmd(cmd, config, args[0])
+		},
+	}
+	cmd.Flags().StringP(txOutputCmdName, "o", "", "file to save the signed transactions to (default overwrites the transaction file)")
+	return cmd
+}
+
+func execTxSignCmd(cmd *cobra.Command, config *types.WalletConfig, filename string) error {
+	output, err := cmd.Flags().GetString(txOutputCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", txOutputCmdName, err)
+	}
+	if output == "" {
+		output = filename
+	}
+	envelope, err := offline.ReadEnvelope(filename)
+	if err != nil {
+		return err
+	}
+
+	am, err := cliaccount.LoadExistingAccountManager(config)
+	if err != nil {
+		return err
+	}
+	defer am.Close()
+	accountKey, err := am.GetSigningKey(envelope.AccountIndex)
+	if err != nil {
+		return fmt.Errorf("failed to load account key #%d: %w", envelope.AccountIndex+1, err)
+	}
+
+	summary, err := envelope.Summary()
+	if err != nil {
+		return err
+	}
+	for _, line := range summary {
+		config.Base.ConsoleWriter.Println(line)
+	}
+	if err := envelope.Sign(accountKey); err != nil {
+		return err
+	}
+	if err := offline.WriteEnvelope(output, envelope); err != nil {
+		return err
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Signed %d transaction(s) with key #%d", len(envelope.Transactions), envelope.AccountIndex+1))
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Signed transaction(s) saved to file: %s", output))
+	return nil
+}
+
+func txSubmitCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit <transaction file>",
+		Short: "sends the signed transactions to the partition",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return execTxSubmitCmd(cmd, config, args[0])
+		},
+	}
+	cmd.Flags().StringP(args.RpcUrl, "r", "", fmt.Sprintf("rpc node url of the partition of the transactions "+
+		"(default %s for money and %s for tokens partition)", args.DefaultMoneyRpcUrl, args.DefaultTokensRpcUrl))
+	args.AddWaitForProofFlags(cmd, cmd.Flags())
+	return cmd
+}
+
+func execTxSubmitCmd(cmd *cobra.Command, config *types.WalletConfig, filename string) error {
+	rpcUrl, err := cmd.Flags().GetString(args.RpcUrl)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", args.RpcUrl, err)
+	}
+	waitForConf, proofFile, err := args.WaitForProofArg(cmd)
+	if err != nil {
+		return err
+	}
+	envelope, err := offline.ReadEnvelope(filename)
+	if err != nil {
+		return err
+	}
+
+	var partitionClient sdktypes.PartitionClient
+	switch envelope.PartitionTypeID {
+	case money.PartitionTypeID:
+		if rpcUrl == "" {
+			rpcUrl = args.DefaultMoneyRpcUrl
+		}
+		partitionClient, err = client.NewMoneyPartitionClient(cmd.Context(), args.BuildRpcUrl(rpcUrl))
+	case tokens.PartitionTypeID:
+		if rpcUrl == "" {
+			rpcUrl = args.DefaultTokensRpcUrl
+		}
+		partitionClient, err = client.NewTokensPartitionClient(cmd.Context(), args.BuildRpcUrl(rpcUrl))
+	default:
+		return fmt.Errorf("unsupported partition type: %d", envelope.PartitionTypeID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to dial rpc url: %w", err)
+	}
+	defer partitionClient.Close()
+
+	proofs, err := envelope.Submit(cmd.Context(), partitionClient, waitForConf, config.Base.Logger)
+	if err != nil {
+		return err
+	}
+	if !waitForConf {
+		config.Base.ConsoleWriter.Println("Successfully sent transaction(s)")
+		return nil
+	}
+	config.Base.ConsoleWriter.Println("Successfully confirmed transaction(s)")
+	var feeSum uint64
+	for _, proof := range proofs {
+		feeSum += proof.TxRecord.ServerMetadata.GetActualFee()
+	}
+	config.Base.ConsoleWriter.Println("Paid", util.AmountToString(feeSum, 8), "fees for transaction(s).")
+	if proofFile != "" {
+		w, err := os.Create(proofFile)
+		if err != nil {
+			return fmt.Errorf("creating file for transaction proof: %w", err)
+		}
+		defer w.Close()
+		if err := abtypes.Cbor.Encode(w, proofs); err != nil {
+			return fmt.Errorf("encoding transaction proofs as CBOR: %w", err)
+		}
+		config.Base.ConsoleWriter.Println("Transaction proof(s) saved to file:" + proofFile)
+	}
+	return nil
+}
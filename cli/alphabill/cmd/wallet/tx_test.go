@@ -0,0 +1,198 @@
+package wallet
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	abtypes "github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/testutils"
+	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/wallet/args"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+func TestTxCmd_offlineSigning(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	stateMock := mocksrv.NewStateServiceMock(
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID:      moneyid.NewBillID(t),
+				Data:        money.BillData{Value: 5 * 1e8},
+			}),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID: func() abtypes.UnitID {
+					id, err := money.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, abtypes.ShardID{}, testutils.TestPubKey0Hash(t), 1000)
+					require.NoError(t, err)
+					return id
+				}(),
+				Data: fc.FeeCreditRecord{Balance: 1e8},
+			}),
+	)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, stateMock)
+	walletCmd := newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir)
+	txFile := filepath.Join(t.TempDir(), "tx.cbor")
+
+	// build unsigned transaction
+	stdout := walletCmd.Exec(t, "send", "--amount", "1", "--address", "0x"+testutils.TestPubKey1Hex, "--unsigned-out", txFile)
+	testutils.VerifyStdout(t, stdout, "Unsigned transaction(s) saved to file: "+txFile)
+	require.Empty(t, stateMock.SentTxs)
+	envelope, err := offline.ReadEnvelope(txFile)
+	require.NoError(t, err)
+	require.False(t, envelope.Signed)
+	require.Len(t, envelope.Transactions, 1)
+	require.Len(t, envelope.Bills, 1)
+	require.EqualValues(t, args.DefaultUnsignedTimeout, envelope.Transactions[0].Timeout())
+
+	// timeout of the unsigned transactions
+	walletCmd.ExecWithError(t, "'unsigned-timeout' parameter can only be used together with 'unsigned-out' parameter",
+		"send", "--amount", "1", "--address", "0x"+testutils.TestPubKey1Hex, "--unsigned-timeout", "100")
+	walletCmd.Exec(t, "send", "--amount", "1", "--address", "0x"+testutils.TestPubKey1Hex, "--unsigned-out", txFile, "--unsigned-timeout", "100")
+	envelope, err = offline.ReadEnvelope(txFile)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, envelope.Transactions[0].Timeout())
+
+	// unsigned transactions can not be submitted
+	submitCmd := newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir)
+	submitCmd.ExecWithError(t, "transactions are not signed", "tx", "submit", txFile)
+
+	// sign
+	signedFile := filepath.Join(t.TempDir(), "signed.cbor")
+	stdout = newWalletCmdExecutor().WithHome(homedir).Exec(t, "tx", "sign", txFile, "--output", signedFile)
+	testutils.VerifyStdout(t, stdout, "  1.000'000'00 to pubkey hash 0x"+fmt.Sprintf("%X", testutils.TestPubKey1Hash(t)),
+		"Signed 1 transaction(s) with key #1", "Signed transaction(s) saved to file: "+signedFile)
+	envelope, err = offline.ReadEnvelope(signedFile)
+	require.NoError(t, err)
+	require.True(t, envelope.Signed)
+
+	// submit
+	stdout = submitCmd.Exec(t, "tx", "submit", signedFile)
+	testutils.VerifyStdout(t, stdout, "Successfully confirmed transaction(s)")
+	require.Len(t, stateMock.SentTxs, 1)
+}
+
+func TestTxCmd_offlineSigningTokens(t *testing.T) {
+	pdr := tokenid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	stateMock := mocksrv.NewStateServiceMock(
+		mocksrv.WithUnit(&sdktypes.Unit[any]{
+			NetworkID:   pdr.NetworkID,
+			PartitionID: pdr.PartitionID,
+			UnitID:      typeID,
+			Data:        tokens.FungibleTokenTypeData{Symbol: "AB", DecimalPlaces: 2},
+		}),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID:      tokenid.NewFungibleTokenID(t),
+				Data: tokens.FungibleTokenData{
+					TypeID:         typeID,
+					Value:          500,
+					OwnerPredicate: templates.NewP2pkh256BytesFromKeyHash(testutils.TestPubKey0Hash(t)),
+					Counter:        1,
+				},
+			}),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID: func() abtypes.UnitID {
+					id, err := tokens.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, abtypes.ShardID{}, testutils.TestPubKey0Hash(t), 1000)
+					require.NoError(t, err)
+					return id
+				}(),
+				Data: fc.FeeCreditRecord{Balance: 1e8},
+			}),
+	)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, stateMock)
+	txFile := filepath.Join(t.TempDir(), "tx.cbor")
+
+	// build unsigned transaction
+	stdout := newWalletCmdExecutor("token", "send", "fungible", "--rpc-url", rpcUrl).WithHome(homedir).Exec(t,
+		"--type", typeID.String(), "--amount", "1.5", "--address", "0x"+testutils.TestPubKey1Hex, "--unsigned-out", txFile)
+	testutils.VerifyStdout(t, stdout, "Unsigned transaction(s) saved to file: "+txFile)
+	require.Empty(t, stateMock.SentTxs)
+	envelope, err := offline.ReadEnvelope(txFile)
+	require.NoError(t, err)
+	require.EqualValues(t, tokens.PartitionTypeID, envelope.PartitionTypeID)
+	require.Len(t, envelope.Transactions, 1)
+	require.Equal(t, tokens.TransactionTypeSplitFT, envelope.Transactions[0].Type)
+
+	// sign
+	signedFile := filepath.Join(t.TempDir(), "signed.cbor")
+	stdout = newWalletCmdExecutor().WithHome(homedir).Exec(t, "tx", "sign", txFile, "--output", signedFile)
+	testutils.VerifyStdout(t, stdout, fmt.Sprintf("  150 of type 0x%s to pubkey hash 0x%X", typeID, testutils.TestPubKey1Hash(t)),
+		"Signed 1 transaction(s) with key #1")
+
+	// submit, the tokens partition client is chosen by the partition type of the envelope
+	stdout = newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir).Exec(t, "tx", "submit", signedFile)
+	testutils.VerifyStdout(t, stdout, "Successfully confirmed transaction(s)")
+	require.Len(t, stateMock.SentTxs, 1)
+}
+
+func TestTxCmd_offlineSigningFeeCredit(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	billID := moneyid.NewBillID(t)
+	stateMock := mocksrv.NewStateServiceMock(
+		mocksrv.WithRoundNumber(100),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				NetworkID:   pdr.NetworkID,
+				PartitionID: pdr.PartitionID,
+				UnitID:      billID,
+				Data:        money.BillData{Value: 5 * 1e8, Counter: 3},
+			}),
+	)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, stateMock)
+	feesCmd := newWalletCmdExecutor("fees", "--rpc-url", rpcUrl).WithHome(homedir)
+	signCmd := newWalletCmdExecutor("tx", "sign").WithHome(homedir)
+	submitCmd := newWalletCmdExecutor("tx", "submit", "--rpc-url", rpcUrl).WithHome(homedir)
+	dir := t.TempDir()
+	transferFile := filepath.Join(dir, "transfer.cbor")
+	transferProofFile := filepath.Join(dir, "transfer-proof.cbor")
+	addFile := filepath.Join(dir, "add.cbor")
+
+	// the proof of the first transaction requires the unsigned output
+	feesCmd.ExecWithError(t, "'transfer-proof' parameter can only be used together with 'unsigned-out' parameter",
+		"add", "--transfer-proof", transferProofFile)
+
+	// transferFC
+	stdout := feesCmd.Exec(t, "add", "--amount", "1", "--unsigned-out", transferFile)
+	testutils.VerifyStdout(t, stdout, "Unsigned transaction(s) saved to file: "+transferFile)
+	require.Empty(t, stateMock.SentTxs)
+	stdout = signCmd.Exec(t, transferFile)
+	testutils.VerifyStdout(t, stdout, fmt.Sprintf("Transaction #1: transfer to fee credit of bill 0x%s, max fee 0.000'000'10, timeout round %d", billID, 100+args.DefaultUnsignedTimeout))
+	submitCmd.Exec(t, transferFile, "--proof-output", transferProofFile)
+	require.Len(t, stateMock.SentTxs, 1)
+
+	// addFC from the proof of transferFC
+	stdout = feesCmd.Exec(t, "add", "--transfer-proof", transferProofFile, "--unsigned-out", addFile)
+	testutils.VerifyStdout(t, stdout, "Unsigned transaction(s) saved to file: "+addFile)
+	envelope, err := offline.ReadEnvelope(addFile)
+	require.NoError(t, err)
+	require.Len(t, envelope.Transactions, 1)
+	require.Equal(t, fc.TransactionTypeAddFeeCredit, envelope.Transactions[0].Type)
+	signCmd.Exec(t, addFile)
+	stdout = submitCmd.Exec(t, addFile)
+	testutils.VerifyStdout(t, stdout, "Successfully confirmed transaction(s)")
+	require.Len(t, stateMock.SentTxs, 2)
+}
@@ -27,8 +27,18 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/client"
 	"github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
 	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
 	"github.com/alphabill-org/alphabill-wallet/wallet/money"
+	"github.com/alphabill-org/alphabill-wallet/wallet/money/txbuilder"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+const (
+	evmCmdName        = "evm"
+	selectionCmdName  = "selection"
+	pubKeyCmdName     = "pubkey"
+	pubKeyHashCmdName = "pubkey-hash"
 )
 
 // NewWalletCmd creates a new cobra command for the wallet component.
@@ -57,6 +67,11 @@ func NewWalletCmd(baseConfig *types.BaseConfiguration) *cobra.Command {
 	walletCmd.AddCommand(GetBalanceCmd(config))
 	walletCmd.AddCommand(CollectDustCmd(config))
 	walletCmd.AddCommand(AddKeyCmd(config))
+	walletCmd.AddCommand(AddWatchKeyCmd(config))
+	walletCmd.AddCommand(ExportKeyCmd(config))
+	walletCmd.AddCommand(ImportKeyCmd(config))
+	walletCmd.AddCommand(NewTxCmd(config))
+	walletCmd.AddCommand(HistoryCmd(config))
 	walletCmd.AddCommand(tokens.NewTokenCmd(config))
 	walletCmd.AddCommand(evm.NewEvmCmd(config))
 	walletCmd.AddCommand(orchestration.NewCmd(config))
@@ -125,6 +140,10 @@ func ExecCreateCmd(cmd *cobra.Command, config *types.WalletConfig) (err error) {
 func SendCmd(config *types.WalletConfig) *cobra.Command {
 	cmd := &cobra.Command{
 		Use: "send",
+		Long: "Sends the amount(s) to the receiver(s) given by the \"" + args.AddressCmdName + "\" and \"" + args.AmountCmdName + "\" flags, " +
+			"or pays all the payments of the batch file given by the \"" + batchCmdName + "\" flag. " +
+			"The payments of the batch are packed into the fewest split transactions the bills allow, " +
+			"and the report of the payments is saved.\n" + batchFileHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return ExecSendCmd(cmd.Context(), cmd, config)
 		},
@@ -139,14 +158,26 @@ func SendCmd(config *types.WalletConfig) *cobra.Command {
 		"If the command results in more than one transaction all of them use the same reference number")
 	cmd.Flags().StringP(args.RpcUrl, "r", args.DefaultMoneyRpcUrl, "rpc node url")
 	cmd.Flags().Uint64P(args.KeyCmdName, "k", 1, "which key to use for sending the transaction")
+	cmd.Flags().String(selectionCmdName, txbuilder.SelectionLargestFirst, "strategy for choosing the bills to spend when "+
+		"sending to a single receiver, one of "+strings.Join(txbuilder.SelectionStrategies(), ", ")+": "+
+		txbuilder.SelectionLargestFirst+" - a bill with the exact amount or the largest bills first, "+
+		txbuilder.SelectionFewestTxs+" - the least number of transactions, "+
+		txbuilder.SelectionExactMatch+" - bills with the total value equal to the amount so that no bill is split (if possible), "+
+		txbuilder.SelectionSmallestFirst+" - the smallest bills first to consolidate dust, "+
+		txbuilder.SelectionOldestFirst+" - the bills last modified in the earliest round first, the rounds are found "+
+		"from the transaction history of the wallet which is indexed first (see the history command)")
+	args.AddUnsignedOutFlags(cmd, cmd.Flags())
+	cmd.Flags().String(batchCmdName, "", "CSV file of the payments to send as a batch, replaces the \""+
+		args.AddressCmdName+"\" and \""+args.AmountCmdName+"\" flags")
+	cmd.Flags().String(batchReportCmdName, "", "file to save the JSON report of the batch payments to (if the file already "+
+		"exists it will be overwritten), by default the report is saved next to the batch file with \".report.json\" extension")
 	args.AddWaitForProofFlags(cmd, cmd.Flags())
 	args.AddMaxFeeFlag(cmd, cmd.Flags())
 
-	if err := cmd.MarkFlagRequired(args.AddressCmdName); err != nil {
-		panic(err)
-	}
-	if err := cmd.MarkFlagRequired(args.AmountCmdName); err != nil {
-		panic(err)
+	cmd.MarkFlagsOneRequired(args.AddressCmdName, batchCmdName)
+	cmd.MarkFlagsRequiredTogether(args.AddressCmdName, args.AmountCmdName)
+	for _, flag := range []string{args.AddressCmdName, args.AmountCmdName, args.ReferenceNumber, args.UnsignedOutFlagName, args.UnsignedTimeoutFlagName, selectionCmdName, args.ProofOutputFlagName} {
+		cmd.MarkFlagsMutuallyExclusive(batchCmdName, flag)
 	}
 	return cmd
 }
@@ -195,6 +226,13 @@ func ExecSendCmd(ctx context.Context, cmd *cobra.Command, config *types.WalletCo
 	if err != nil {
 		return err
 	}
+	batchFile, err := cmd.Flags().GetString(batchCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", batchCmdName, err)
+	}
+	if batchFile != "" {
+		return execSendBatchCmd(ctx, cmd, config, w, batchFile, money.SendBatchCmd{WaitForConfirmation: waitForConf, AccountIndex: accountNumber - 1, MaxFee: maxFee})
+	}
 	receiverPubKeys, err := cmd.Flags().GetStringSlice(args.AddressCmdName)
 	if err != nil {
 		return err
@@ -211,7 +249,38 @@ func ExecSendCmd(ctx context.Context, cmd *cobra.Command, config *types.WalletCo
 	if err != nil {
 		return err
 	}
-	proofs, err := w.Send(ctx, money.SendCmd{Receivers: receivers, WaitForConfirmation: waitForConf, AccountIndex: accountNumber - 1, ReferenceNumber: refNumber, MaxFee: maxFee})
+	selection, err := cmd.Flags().GetString(selectionCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", selectionCmdName, err)
+	}
+	var lastModifiedRounds map[string]uint64
+	if selection == txbuilder.SelectionOldestFirst {
+		if lastModifiedRounds, err = billLastModifiedRounds(ctx, config, am, moneyClient, rpcUrl, accountNumber-1); err != nil {
+			return err
+		}
+	}
+	billSelector, err := txbuilder.NewBillSelector(selection, lastModifiedRounds)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", selectionCmdName, err)
+	}
+	unsignedOut, unsignedTimeout, err := args.UnsignedOutArg(cmd)
+	if err != nil {
+		return err
+	}
+	sendCmd := money.SendCmd{Receivers: receivers, WaitForConfirmation: waitForConf, AccountIndex: accountNumber - 1, ReferenceNumber: refNumber, MaxFee: maxFee, BillSelector: billSelector}
+	if unsignedOut != "" {
+		sendCmd.Timeout = unsignedTimeout
+		envelope, err := w.BuildUnsignedSend(ctx, sendCmd)
+		if err != nil {
+			return err
+		}
+		if err := offline.WriteEnvelope(unsignedOut, envelope); err != nil {
+			return err
+		}
+		config.Base.ConsoleWriter.Println(fmt.Sprintf("Unsigned transaction(s) saved to file: %s", unsignedOut))
+		return nil
+	}
+	proofs, err := w.Send(ctx, sendCmd)
 	if err != nil {
 		return err
 	}
@@ -345,6 +414,7 @@ func GetPubKeysCmd(config *types.WalletConfig) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolP(args.QuietCmdName, "q", false, "hides info irrelevant for scripting, e.g. account key numbers")
+	cmd.Flags().Bool(evmCmdName, false, "shows also the EVM (Ethereum) address of the keys")
 	return cmd
 }
 
@@ -355,16 +425,37 @@ func ExecGetPubKeysCmd(cmd *cobra.Command, config *types.WalletConfig) error {
 	}
 	defer am.Close()
 
-	pubKeys, err := am.GetPublicKeys()
+	accountKeys, err := am.GetAccountKeys()
 	if err != nil {
 		return err
 	}
 	hideKeyNumber, _ := cmd.Flags().GetBool(args.QuietCmdName)
-	for accIdx, accPubKey := range pubKeys {
+	showEvmAddress, err := cmd.Flags().GetBool(evmCmdName)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s' parameter: %w", evmCmdName, err)
+	}
+	for accIdx, accKey := range accountKeys {
+		var line string
+		if accKey.PubKey == nil {
+			// watch-only account of public key hash has no public key nor EVM address
+			line = "pubkey hash " + hexutil.Encode(accKey.PubKeyHash.Sha256)
+		} else {
+			line = hexutil.Encode(accKey.PubKey)
+			if showEvmAddress {
+				addr, err := evmwallet.AddressFromPublicKey(accKey.PubKey)
+				if err != nil {
+					return fmt.Errorf("failed to generate EVM address of key #%d: %w", accIdx+1, err)
+				}
+				line += " " + addr.Hex()
+			}
+		}
+		if accKey.IsWatchOnly() {
+			line += " (watch-only)"
+		}
 		if hideKeyNumber {
-			config.Base.ConsoleWriter.Println(hexutil.Encode(accPubKey))
+			config.Base.ConsoleWriter.Println(line)
 		} else {
-			config.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s", accIdx+1, hexutil.Encode(accPubKey)))
+			config.Base.ConsoleWriter.Println(fmt.Sprintf("#%d %s", accIdx+1, line))
 		}
 	}
 	return nil
@@ -487,6 +578,39 @@ func ExecAddKeyCmd(cmd *cobra.Command, config *types.WalletConfig) error {
 	return nil
 }
 
+func AddWatchKeyCmd(config *types.WalletConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-watch-key",
+		Short: "adds watch-only key of the public key or public key hash to the wallet",
+		Long: "Adds the public key or the public key hash, without the private key, as the next key of the wallet. " +
+			"The balance, bills, tokens and fee credit of the watch-only key can be listed but the key can not sign transactions.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ExecAddWatchKeyCmd(cmd, config)
+		},
+	}
+	cmd.Flags().Var(&types.BytesHex{}, pubKeyCmdName, "compressed secp256k1 public key to watch")
+	cmd.Flags().Var(&types.BytesHex{}, pubKeyHashCmdName, "sha256 hash of the public key to watch")
+	cmd.MarkFlagsOneRequired(pubKeyCmdName, pubKeyHashCmdName)
+	return cmd
+}
+
+func ExecAddWatchKeyCmd(cmd *cobra.Command, config *types.WalletConfig) error {
+	pubKey := *cmd.Flag(pubKeyCmdName).Value.(*types.BytesHex)
+	pubKeyHash := *cmd.Flag(pubKeyHashCmdName).Value.(*types.BytesHex)
+	am, err := cliaccount.LoadExistingAccountManager(config)
+	if err != nil {
+		return err
+	}
+	defer am.Close()
+
+	accIdx, err := am.AddWatchOnlyAccount(pubKey, pubKeyHash)
+	if err != nil {
+		return fmt.Errorf("failed to add watch-only key: %w", err)
+	}
+	config.Base.ConsoleWriter.Println(fmt.Sprintf("Added watch-only key #%d", accIdx+1))
+	return nil
+}
+
 func InitWalletConfig(cmd *cobra.Command, config *types.WalletConfig) error {
 	walletLocation, err := cmd.Flags().GetString(args.WalletLocationCmdName)
 	if err != nil {
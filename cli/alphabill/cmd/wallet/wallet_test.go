@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"encoding/hex"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -8,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/require"
 
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
 	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
@@ -18,6 +21,9 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/cli/alphabill/cmd/types"
 	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+	"github.com/alphabill-org/alphabill-wallet/wallet/history"
 	moneywallet "github.com/alphabill-org/alphabill-wallet/wallet/money"
 )
 
@@ -181,6 +187,94 @@ func TestPubKeysCmd(t *testing.T) {
 	testutils.VerifyStdout(t, stdout, "#1 "+hexutil.Encode(pk))
 }
 
+func TestPubKeysCmd_evm(t *testing.T) {
+	am, homedir := testutils.CreateNewWallet(t, "")
+	pk, err := am.GetPublicKey(0)
+	require.NoError(t, err)
+	am.Close()
+	addr, err := evmwallet.AddressFromPublicKey(pk)
+	require.NoError(t, err)
+
+	walletCmd := newWalletCmdExecutor().WithHome(homedir)
+	stdout := walletCmd.Exec(t, "get-pubkeys", "--evm")
+	testutils.VerifyStdout(t, stdout, "#1 "+hexutil.Encode(pk)+" "+addr.Hex())
+	stdout = walletCmd.Exec(t, "get-pubkeys", "--evm", "--quiet")
+	testutils.VerifyStdout(t, stdout, hexutil.Encode(pk)+" "+addr.Hex())
+}
+
+func TestExportImportKeyCmd(t *testing.T) {
+	am, homedir := testutils.CreateNewWallet(t, "")
+	pk, err := am.GetPublicKey(0)
+	require.NoError(t, err)
+	am.Close()
+	addr, err := evmwallet.AddressFromPublicKey(pk)
+	require.NoError(t, err)
+	keystoreFile := filepath.Join(t.TempDir(), "key.json")
+
+	walletCmd := newWalletCmdExecutor().WithHome(homedir)
+	stdout := walletCmd.Exec(t, "export-key", "--file", keystoreFile, "--keystore-password", "secret", "--light-kdf")
+	testutils.VerifyStdout(t, stdout, "Exported key #1 (EVM address "+addr.Hex()+") to "+keystoreFile)
+	walletCmd.ExecWithError(t, "failed to create keystore file", "export-key", "--file", keystoreFile, "--keystore-password", "secret", "--light-kdf")
+	walletCmd.ExecWithError(t, "account key read failed", "export-key", "--key", "2", "--file", filepath.Join(t.TempDir(), "key2.json"), "--keystore-password", "secret")
+
+	// the key already exists in the wallet
+	walletCmd.ExecWithError(t, "failed to import key: account already exists: key #1", "import-key", "--file", keystoreFile, "--keystore-password", "secret")
+
+	// import into another wallet
+	am, otherHomedir := testutils.CreateNewWallet(t, "")
+	am.Close()
+	otherWalletCmd := newWalletCmdExecutor().WithHome(otherHomedir)
+	otherWalletCmd.ExecWithError(t, "could not decrypt key with given password", "import-key", "--file", keystoreFile, "--keystore-password", "wrong")
+	stdout = otherWalletCmd.Exec(t, "import-key", "--file", keystoreFile, "--keystore-password", "secret")
+	testutils.VerifyStdout(t, stdout, "Imported key #2 "+hexutil.Encode(pk)+" (EVM address "+addr.Hex()+")")
+	stdout = otherWalletCmd.Exec(t, "get-pubkeys")
+	testutils.VerifyStdout(t, stdout, "#2 "+hexutil.Encode(pk))
+}
+
+func TestAddWatchKeyCmd(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	billID := moneyid.NewBillID(t)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, mocksrv.NewStateServiceMock(
+		mocksrv.WithOwnerUnit(testutils.TestPubKey1Hash(t),
+			&sdktypes.Unit[any]{
+				UnitID: billID,
+				Data:   money.BillData{Value: 15 * 1e8},
+			}),
+	))
+	otherKeys, err := account.NewKeys("")
+	require.NoError(t, err)
+
+	walletCmd := newWalletCmdExecutor().WithHome(homedir)
+	stdout := walletCmd.Exec(t, "add-watch-key", "--pubkey-hash", hexutil.Encode(testutils.TestPubKey1Hash(t)))
+	testutils.VerifyStdout(t, stdout, "Added watch-only key #2")
+	stdout = walletCmd.Exec(t, "add-watch-key", "--pubkey", hexutil.Encode(otherKeys.AccountKey.PubKey))
+	testutils.VerifyStdout(t, stdout, "Added watch-only key #3")
+	walletCmd.ExecWithError(t, "failed to add watch-only key: account already exists: key #2", "add-watch-key", "--pubkey", "0x"+testutils.TestPubKey1Hex)
+	walletCmd.ExecWithError(t, "failed to add watch-only key: invalid public key hash", "add-watch-key", "--pubkey-hash", "0x0102")
+	walletCmd.ExecWithError(t, "at least one of the flags in the group [pubkey pubkey-hash] is required", "add-watch-key")
+
+	stdout = walletCmd.Exec(t, "get-pubkeys")
+	require.Len(t, stdout.Lines, 3)
+	testutils.VerifyStdout(t, stdout,
+		"#1 0x"+testutils.TestPubKey0Hex,
+		"#2 pubkey hash "+hexutil.Encode(testutils.TestPubKey1Hash(t))+" (watch-only)",
+		"#3 "+hexutil.Encode(otherKeys.AccountKey.PubKey)+" (watch-only)")
+
+	// the balance and the bills of the watch-only keys can be listed
+	stdout = walletCmd.Exec(t, "get-balance", "--rpc-url", rpcUrl, "--key", "2")
+	testutils.VerifyStdout(t, stdout, "#2 15")
+	billsCmd := newWalletCmdExecutor("bills").WithHome(homedir)
+	stdout = billsCmd.Exec(t, "list", "--rpc-url", rpcUrl, "--key", "2")
+	testutils.VerifyStdout(t, stdout, "Account #2", "#1 0x"+billID.String()+" 15.000'000'00")
+
+	// but the watch-only keys can not sign
+	walletCmd.ExecWithError(t, "key #2: watch-only account can not sign transactions",
+		"send", "--rpc-url", rpcUrl, "--key", "2", "--amount", "1", "--address", "0x"+testutils.TestPubKey0Hex)
+	walletCmd.ExecWithError(t, "key #3 is watch-only account, it has no private key to export",
+		"export-key", "--key", "3", "--file", filepath.Join(t.TempDir(), "key.json"), "--keystore-password", "secret")
+}
+
 func TestSendingFailsWithInsufficientBalance(t *testing.T) {
 	pdr := moneyid.PDR()
 	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
@@ -206,6 +300,65 @@ func TestSendingFailsWithInsufficientBalance(t *testing.T) {
 		"send", "--amount", "10", "--address", "0x"+testutils.TestPubKey1Hex)
 }
 
+func TestSendCmd_invalidSelection(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, mocksrv.NewStateServiceMock())
+
+	walletCmd := newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir)
+	walletCmd.ExecWithError(t, `failed to read 'selection' parameter: unknown bill selection strategy "random"`,
+		"send", "--amount", "10", "--address", "0x"+testutils.TestPubKey1Hex, "--selection", "random")
+}
+
+func TestSendCmd_oldestFirstSelection(t *testing.T) {
+	pdr := moneyid.PDR()
+	homedir := testutils.CreateNewTestWallet(t, testutils.WithDefaultMnemonic())
+	otherKeys, err := account.NewKeys("")
+	require.NoError(t, err)
+	pubKey0, _ := hex.DecodeString(testutils.TestPubKey0Hex)
+	transferTo0 := func(bill *sdktypes.Bill) (*abtypes.TransactionOrder, error) {
+		return bill.Transfer(templates.NewP2pkh256BytesFromKey(pubKey0))
+	}
+	// the bills are received in rounds 2 and 3, the older bill is smaller
+	older := newHistoryTestTx(t, &pdr, otherKeys.AccountKey, transferTo0)
+	newer := newHistoryTestTx(t, &pdr, otherKeys.AccountKey, transferTo0)
+	stateMock := mocksrv.NewStateServiceMock(
+		mocksrv.WithRoundNumber(5),
+		mocksrv.WithBlock(2, newHistoryTestBlock(t, older)),
+		mocksrv.WithBlock(3, newHistoryTestBlock(t, newer)),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				UnitID: older.UnitID,
+				Data:   money.BillData{Value: 1e8, Counter: 2},
+			}),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				UnitID: newer.UnitID,
+				Data:   money.BillData{Value: 3 * 1e8, Counter: 2},
+			}),
+		mocksrv.WithOwnerUnit(testutils.TestPubKey0Hash(t),
+			&sdktypes.Unit[any]{
+				UnitID: func() abtypes.UnitID {
+					id, err := money.NewFeeCreditRecordIDFromPublicKeyHash(&pdr, abtypes.ShardID{}, testutils.TestPubKey0Hash(t), 1000)
+					require.NoError(t, err)
+					return id
+				}(),
+				Data: fc.FeeCreditRecord{Balance: 1e8},
+			}),
+	)
+	rpcUrl := mocksrv.StartStateApiServer(t, &pdr, stateMock)
+
+	walletCmd := newWalletCmdExecutor("--rpc-url", rpcUrl).WithHome(homedir)
+	walletCmd.Exec(t, "send", "--amount", "0.5", "--address", "0x"+testutils.TestPubKey1Hex, "--selection", "oldest-first")
+	require.Len(t, stateMock.SentTxs, 1)
+	for _, tx := range stateMock.SentTxs {
+		require.EqualValues(t, older.UnitID, tx.UnitID)
+	}
+	// the history has been indexed for the selection
+	_, err = os.Stat(filepath.Join(homedir, "wallet", history.HistoryDBFileName))
+	require.NoError(t, err)
+}
+
 func Test_groupPubKeysAndAmounts(t *testing.T) {
 	t.Run("count of keys and amounts do not match", func(t *testing.T) {
 		data, err := groupPubKeysAndAmounts(nil, []string{"1"})
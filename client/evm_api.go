@@ -0,0 +1,150 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+const (
+	evmApiPathPrefix = "/api/v1/evm"
+
+	userAgentHeader = "User-Agent"
+	clientUserAgent = "EVM API Client/0.1"
+)
+
+// Call executes smart contract call without storing the result in blockchain. Can be used to
+// simulate transaction or to read contract state.
+func (c *evmPartitionClient) Call(ctx context.Context, callAttr *evm.CallEVMRequest) (*evm.ProcessingDetails, error) {
+	b, err := types.Cbor.Marshal(callAttr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode call request: %w", err)
+	}
+	rsp := &struct {
+		_       struct{} `cbor:",toarray"`
+		Details *evm.ProcessingDetails
+	}{}
+	if err = c.post(ctx, c.evmApiPath("call"), bytes.NewReader(b), rsp); err != nil {
+		return nil, fmt.Errorf("evm call failed: %w", err)
+	}
+	return rsp.Details, nil
+}
+
+// GetTransactionCount returns the nonce of the account, ErrNotFound is returned when
+// the account does not exist.
+func (c *evmPartitionClient) GetTransactionCount(ctx context.Context, ethAddr []byte) (uint64, error) {
+	rsp := &struct {
+		_     struct{} `cbor:",toarray"`
+		Nonce uint64
+	}{}
+	if err := c.get(ctx, c.evmApiPath("transactionCount", hex.EncodeToString(ethAddr)), rsp); err != nil {
+		return 0, err
+	}
+	return rsp.Nonce, nil
+}
+
+// GetBalance returns the balance of the account in wei, ErrNotFound is returned when
+// the account does not exist.
+func (c *evmPartitionClient) GetBalance(ctx context.Context, ethAddr []byte) (*big.Int, error) {
+	rsp := &struct {
+		_       struct{} `cbor:",toarray"`
+		Balance string
+		Counter uint64
+	}{}
+	if err := c.get(ctx, c.evmApiPath("balance", hex.EncodeToString(ethAddr)), rsp); err != nil {
+		return nil, err
+	}
+	balance, ok := new(big.Int).SetString(rsp.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("account %x has invalid balance %q", ethAddr, rsp.Balance)
+	}
+	return balance, nil
+}
+
+// GetGasPrice returns the current gas price in wei.
+func (c *evmPartitionClient) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	rsp := &struct {
+		_        struct{} `cbor:",toarray"`
+		GasPrice string
+	}{}
+	if err := c.get(ctx, c.evmApiPath("gasPrice"), rsp); err != nil {
+		return nil, fmt.Errorf("gas price request failed: %w", err)
+	}
+	gasPrice, ok := new(big.Int).SetString(rsp.GasPrice, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid gas price %q", rsp.GasPrice)
+	}
+	return gasPrice, nil
+}
+
+func (c *evmPartitionClient) evmApiPath(pathElements ...string) *url.URL {
+	return c.evmApiURL.JoinPath(pathElements...)
+}
+
+// get executes GET request to "addr" and decodes CBOR response body into "data".
+func (c *evmPartitionClient) get(ctx context.Context, addr *url.URL, data any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build http request: %w", err)
+	}
+	return c.do(req, data)
+}
+
+// post executes POST request to "addr" and decodes CBOR response body into "data".
+func (c *evmPartitionClient) post(ctx context.Context, addr *url.URL, body io.Reader, data any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr.String(), body)
+	if err != nil {
+		return fmt.Errorf("failed to build http request: %w", err)
+	}
+	return c.do(req, data)
+}
+
+func (c *evmPartitionClient) do(req *http.Request, data any) error {
+	req.Header.Set(userAgentHeader, clientUserAgent)
+	rsp, err := c.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to evm node failed: %w", err)
+	}
+	return decodeEvmApiResponse(rsp, data)
+}
+
+/*
+When "rsp" StatusCode is 200 OK response body is decoded into "data". In case of
+some other response status body is expected to contain CBOR encoded error message.
+*/
+func decodeEvmApiResponse(rsp *http.Response, data any) error {
+	defer func() { _ = rsp.Body.Close() }()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+		if err := types.Cbor.Decode(rsp.Body, data); err != nil {
+			return fmt.Errorf("failed to decode response body: %w", err)
+		}
+		return nil
+	case http.StatusNotFound:
+		return sdktypes.ErrNotFound
+	default:
+		errInfo := &struct {
+			_   struct{} `cbor:",toarray"`
+			Err string
+		}{}
+		if err := types.Cbor.Decode(rsp.Body, errInfo); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s", rsp.Status)
+		}
+		if errInfo.Err == "" {
+			return fmt.Errorf("%s", rsp.Status)
+		}
+		return fmt.Errorf("%s, %s", rsp.Status, errInfo.Err)
+	}
+}
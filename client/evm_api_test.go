@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/internal/testutils"
+)
+
+func TestEvmApiURLFromRpcUrl(t *testing.T) {
+	tests := []struct {
+		rpcUrl string
+		want   string
+	}{
+		{rpcUrl: "http://localhost:29866/rpc", want: "http://localhost:29866/api/v1/evm"},
+		{rpcUrl: "http://localhost:29866/rpc/", want: "http://localhost:29866/api/v1/evm"},
+		{rpcUrl: "https://example.com/evm/rpc", want: "https://example.com/evm/api/v1/evm"},
+		{rpcUrl: "http://localhost:29866", want: "http://localhost:29866/api/v1/evm"},
+	}
+	for _, tt := range tests {
+		u, err := evmApiURLFromRpcUrl(tt.rpcUrl)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, u.String())
+	}
+}
+
+func TestEvmPartitionClient_EvmAPI(t *testing.T) {
+	addr := testutils.RandomBytes(20)
+	var callReq *evm.CallEVMRequest
+	client := startEvmNode(t, func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get(userAgentHeader); ua != clientUserAgent {
+			t.Errorf("expected User-Agent header %q, got %q", clientUserAgent, ua)
+		}
+		switch r.URL.Path {
+		case "/api/v1/evm/balance/" + hex.EncodeToString(addr):
+			writeCBORResponse(t, w, &struct {
+				_       struct{} `cbor:",toarray"`
+				Balance string
+				Counter uint64
+			}{Balance: "184467440737095516150000000000", Counter: 5}, http.StatusOK)
+		case "/api/v1/evm/transactionCount/" + hex.EncodeToString(addr):
+			writeCBORResponse(t, w, &struct {
+				_     struct{} `cbor:",toarray"`
+				Nonce uint64
+			}{Nonce: 3}, http.StatusOK)
+		case "/api/v1/evm/gasPrice":
+			writeCBORResponse(t, w, &struct {
+				_        struct{} `cbor:",toarray"`
+				GasPrice string
+			}{GasPrice: "210000000"}, http.StatusOK)
+		case "/api/v1/evm/call":
+			require.Equal(t, http.MethodPost, r.Method)
+			callReq = &evm.CallEVMRequest{}
+			require.NoError(t, types.Cbor.Decode(r.Body, callReq))
+			writeCBORResponse(t, w, &evm.CallEVMResponse{ProcessingDetails: &evm.ProcessingDetails{ErrorDetails: "some error occurred"}}, http.StatusOK)
+		default:
+			writeCBORError(t, w, errors.New("address not found"), http.StatusNotFound)
+		}
+	})
+	ctx := context.Background()
+
+	balance, err := client.GetBalance(ctx, addr)
+	require.NoError(t, err)
+	require.Equal(t, "184467440737095516150000000000", balance.String())
+
+	nonce, err := client.GetTransactionCount(ctx, addr)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, nonce)
+
+	gasPrice, err := client.GetGasPrice(ctx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(210000000), gasPrice)
+
+	details, err := client.Call(ctx, &evm.CallEVMRequest{From: addr, Gas: 100})
+	require.NoError(t, err)
+	require.Equal(t, "some error occurred", details.ErrorDetails)
+	require.Equal(t, addr, callReq.From)
+	require.EqualValues(t, 100, callReq.Gas)
+
+	// unknown account
+	unknown := testutils.RandomBytes(20)
+	_, err = client.GetBalance(ctx, unknown)
+	require.ErrorIs(t, err, sdktypes.ErrNotFound)
+	_, err = client.GetTransactionCount(ctx, unknown)
+	require.ErrorIs(t, err, sdktypes.ErrNotFound)
+}
+
+func TestEvmPartitionClient_EvmAPI_errors(t *testing.T) {
+	client := startEvmNode(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/evm/call":
+			writeCBORError(t, w, errors.New("not a valid transaction"), http.StatusBadRequest)
+		case "/api/v1/evm/gasPrice":
+			writeCBORResponse(t, w, &struct {
+				_        struct{} `cbor:",toarray"`
+				GasPrice string
+			}{GasPrice: "abc"}, http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	ctx := context.Background()
+
+	details, err := client.Call(ctx, &evm.CallEVMRequest{})
+	require.EqualError(t, err, "evm call failed: 400 Bad Request, not a valid transaction")
+	require.Nil(t, details)
+
+	gasPrice, err := client.GetGasPrice(ctx)
+	require.EqualError(t, err, `invalid gas price "abc"`)
+	require.Nil(t, gasPrice)
+
+	balance, err := client.GetBalance(ctx, []byte{1})
+	require.EqualError(t, err, "500 Internal Server Error")
+	require.Nil(t, balance)
+}
+
+// startEvmNode starts the server serving the RPC API at "/rpc" and the EVM API with the handler.
+func startEvmNode(t *testing.T, evmApi http.HandlerFunc) sdktypes.EvmPartitionClient {
+	rpcServer := ethrpc.NewServer()
+	t.Cleanup(rpcServer.Stop)
+	require.NoError(t, rpcServer.RegisterName("admin", mocksrv.NewAdminServiceMock(mocksrv.WithInfoResponse(&sdktypes.NodeInfoResponse{
+		NetworkID:       3,
+		PartitionID:     evm.DefaultPartitionID,
+		PartitionTypeID: evm.PartitionTypeID,
+	}))))
+	mux := http.NewServeMux()
+	mux.Handle("/rpc", rpcServer)
+	mux.Handle("/api/v1/evm/", evmApi)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewEvmPartitionClient(context.Background(), srv.URL+"/rpc")
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func writeCBORResponse(t *testing.T, w http.ResponseWriter, response any, statusCode int) {
+	w.Header().Set("Content-Type", "application/cbor")
+	w.WriteHeader(statusCode)
+	require.NoError(t, types.Cbor.Encode(w, response))
+}
+
+func writeCBORError(t *testing.T, w http.ResponseWriter, e error, statusCode int) {
+	writeCBORResponse(t, w, struct {
+		_   struct{} `cbor:",toarray"`
+		Err string
+	}{Err: e.Error()}, statusCode)
+}
@@ -4,11 +4,16 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
 	"github.com/alphabill-org/alphabill-go-base/types"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 	"github.com/holiman/uint256"
 )
@@ -16,6 +21,10 @@ import (
 type (
 	evmPartitionClient struct {
 		*partitionClient
+		// the EVM specific calls are served by the REST API of the node,
+		// on the same address as the RPC API
+		evmApiURL *url.URL
+		hc        *http.Client
 	}
 
 	// TODO: these structs are also defined in alphabill/txsystem/evm/statedb,
@@ -36,8 +45,17 @@ type (
 	}
 )
 
-// NewEvmPartitionClient creates an evm partition client for the given RPC URL.
-func NewEvmPartitionClient(ctx context.Context, rpcUrl string) (sdktypes.PartitionClient, error) {
+/*
+NewEvmPartitionClient creates an evm partition client for the given RPC URL. The EVM
+specific calls (call, nonce, balance and gas price) are made to the EVM API of the
+node which is served on the same address as the RPC API, ie for the RPC URL
+"http://localhost:29866/rpc" the EVM API is at "http://localhost:29866/api/v1/evm".
+*/
+func NewEvmPartitionClient(ctx context.Context, rpcUrl string) (sdktypes.EvmPartitionClient, error) {
+	evmApiURL, err := evmApiURLFromRpcUrl(rpcUrl)
+	if err != nil {
+		return nil, err
+	}
 	partitionClient, err := newPartitionClient(ctx, rpcUrl, evm.PartitionTypeID)
 	if err != nil {
 		return nil, err
@@ -45,9 +63,22 @@ func NewEvmPartitionClient(ctx context.Context, rpcUrl string) (sdktypes.Partiti
 
 	return &evmPartitionClient{
 		partitionClient: partitionClient,
+		evmApiURL:       evmApiURL,
+		hc:              &http.Client{Timeout: 10 * time.Second},
 	}, nil
 }
 
+// evmApiURLFromRpcUrl returns the URL of the EVM API of the node serving RPC API at "rpcUrl".
+func evmApiURLFromRpcUrl(rpcUrl string) (*url.URL, error) {
+	u, err := url.Parse(rpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rpc url %q: %w", rpcUrl, err)
+	}
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/rpc") + evmApiPathPrefix
+	u.RawPath = ""
+	return u, nil
+}
+
 // GetFeeCreditRecordByOwnerID finds the first fee credit record in evm partition for the given owner ID,
 // returns nil if fee credit record does not exist.
 func (c *evmPartitionClient) GetFeeCreditRecordByOwnerID(ctx context.Context, ownerID []byte) (*sdktypes.FeeCreditRecord, error) {
@@ -66,8 +97,12 @@ func (c *evmPartitionClient) GetFeeCreditRecordByOwnerID(ctx context.Context, ow
 		return nil, nil
 	}
 	stateObj := u.Data
+	balance, err := util.BigToUint64(util.WeiToTemaRounded(stateObj.Account.Balance.ToBig()))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee credit balance %s wei: %w", stateObj.Account.Balance, err)
+	}
 	fcr := &fc.FeeCreditRecord{
-		Balance:     weiToAlpha(stateObj.Account.Balance),
+		Balance:     balance,
 		Counter:     stateObj.AlphaBill.Counter,
 		MinLifetime: stateObj.AlphaBill.MinLifetime,
 	}
@@ -83,16 +118,6 @@ func (c *evmPartitionClient) GetFeeCreditRecordByOwnerID(ctx context.Context, ow
 	}, nil
 }
 
-// TODO: copied from AB repo, move to go-base?
-var alpha2Wei = new(uint256.Int).Exp(uint256.NewInt(10), uint256.NewInt(10))
-var alpha2WeiRoundCorrector = new(uint256.Int).Div(alpha2Wei, uint256.NewInt(2))
-
-// weiToAlpha - converts from wei to alpha, rounding half up.
-// 1 wei = wei * 10^10 / 10^18
-func weiToAlpha(wei *uint256.Int) uint64 {
-	return new(uint256.Int).Div(new(uint256.Int).Add(wei, alpha2WeiRoundCorrector), alpha2Wei).Uint64()
-}
-
 func (c *evmPartitionClient) ConfirmTransaction(ctx context.Context, tx *types.TransactionOrder, log *slog.Logger) (*types.TxRecordProof, error) {
 	sub, err := txsubmitter.New(tx)
 	if err != nil {
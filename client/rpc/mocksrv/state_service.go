@@ -19,6 +19,8 @@ type (
 		OwnerUnitIDs map[string][]types.UnitID
 		TxProofs     map[string]*sdktypes.TransactionRecordAndProof
 		Block        hex.Bytes
+		// Blocks are the blocks of the rounds, Block is returned for the other rounds
+		Blocks       map[uint64]hex.Bytes
 		SentTxs      map[string]*types.TransactionOrder
 		Err          error
 		GetUnitCalls int
@@ -30,6 +32,7 @@ type (
 		TxProofs     map[string]*sdktypes.TransactionRecordAndProof
 		Units        map[string]*sdktypes.Unit[any]
 		OwnerUnits   map[string][]types.UnitID
+		Blocks       map[uint64]hex.Bytes
 		InfoResponse *sdktypes.NodeInfoResponse
 	}
 
@@ -41,6 +44,7 @@ func NewStateServiceMock(opts ...Option) *StateServiceMock {
 		TxProofs:   map[string]*sdktypes.TransactionRecordAndProof{},
 		Units:      map[string]*sdktypes.Unit[any]{},
 		OwnerUnits: map[string][]types.UnitID{},
+		Blocks:     map[uint64]hex.Bytes{},
 	}
 	for _, option := range opts {
 		option(options)
@@ -51,6 +55,7 @@ func NewStateServiceMock(opts ...Option) *StateServiceMock {
 		Units:        options.Units,
 		OwnerUnitIDs: options.OwnerUnits,
 		TxProofs:     options.TxProofs,
+		Blocks:       options.Blocks,
 		SentTxs:      map[string]*types.TransactionOrder{},
 	}
 }
@@ -82,6 +87,12 @@ func WithTxProof(txHash []byte, txProof *sdktypes.TransactionRecordAndProof) Opt
 	}
 }
 
+func WithBlock(roundNumber uint64, block hex.Bytes) Option {
+	return func(o *Options) {
+		o.Blocks[roundNumber] = block
+	}
+}
+
 func WithRoundNumber(roundNumber uint64) Option {
 	return func(o *Options) {
 		o.RoundNumber = roundNumber
@@ -194,6 +205,9 @@ func (s *StateServiceMock) GetBlock(ctx context.Context, roundNumber hex.Uint64)
 	if s.Err != nil {
 		return nil, s.Err
 	}
+	if block, ok := s.Blocks[uint64(roundNumber)]; ok {
+		return block, nil
+	}
 	return s.Block, nil
 }
 
@@ -204,5 +218,6 @@ func (s *StateServiceMock) Reset() {
 	s.TxProofs = nil
 	s.Err = nil
 	s.Block = nil
+	s.Blocks = nil
 	s.GetUnitCalls = 0
 }
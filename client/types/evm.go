@@ -0,0 +1,30 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+)
+
+var (
+	// ErrNotFound is returned by the evm partition client when the account does not exist.
+	ErrNotFound = errors.New("not found")
+)
+
+type (
+	// EvmPartitionClient is the partition client with the EVM specific calls added.
+	EvmPartitionClient interface {
+		PartitionClient
+		// Call executes smart contract call without storing the result in blockchain,
+		// can be used to simulate transaction or to read contract state.
+		Call(ctx context.Context, callAttr *evm.CallEVMRequest) (*evm.ProcessingDetails, error)
+		// GetTransactionCount returns the nonce of the account.
+		GetTransactionCount(ctx context.Context, ethAddr []byte) (uint64, error)
+		// GetBalance returns the balance of the account in wei.
+		GetBalance(ctx context.Context, ethAddr []byte) (*big.Int, error)
+		// GetGasPrice returns the current gas price in wei.
+		GetGasPrice(ctx context.Context) (*big.Int, error)
+	}
+)
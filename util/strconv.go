@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -56,7 +57,47 @@ func StringToAmount(amountIn string, decimals uint32) (uint64, error) {
 // NB! it is assumed that the decimal places value is sane and verified before
 // calling this method.
 func AmountToString(amount uint64, decimals uint32) string {
-	amountStr := strconv.FormatUint(amount, 10)
+	return amountStrToString(strconv.FormatUint(amount, 10), decimals)
+}
+
+// StringToBigAmount converts string and decimals to big.Int amount, unlike StringToAmount
+// the amount is not limited to uint64 range.
+func StringToBigAmount(amountIn string, decimals uint32) (*big.Int, error) {
+	if amountIn == "" {
+		return nil, fmt.Errorf("invalid empty amount string")
+	}
+	amountIn = strings.ReplaceAll(amountIn, "'", "")
+	integerStr, fractionStr, hasFraction := strings.Cut(amountIn, ".")
+	if len(integerStr) == 0 {
+		return nil, fmt.Errorf("invalid amount string %s: missing integer part", amountIn)
+	}
+	if hasFraction {
+		if len(fractionStr) == 0 {
+			return nil, fmt.Errorf("invalid amount string %s: missing fraction part", amountIn)
+		}
+		if uint32(len(fractionStr)) > decimals {
+			return nil, fmt.Errorf("invalid precision: %s", amountIn)
+		}
+	}
+	// pad with 0's so that decimal number of fraction places are present
+	digits := integerStr + fractionStr + strings.Repeat("0", int(decimals)-len(fractionStr))
+	if strings.TrimLeft(digits, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid amount string \"%s\": not a positive decimal number", amountIn)
+	}
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount string \"%s\"", amountIn)
+	}
+	return amount, nil
+}
+
+// BigAmountToString converts big.Int amount to string with specified decimals,
+// see AmountToString.
+func BigAmountToString(amount *big.Int, decimals uint32) string {
+	return amountStrToString(amount.String(), decimals)
+}
+
+func amountStrToString(amountStr string, decimals uint32) string {
 	if decimals == 0 {
 		return InsertSeparator(amountStr, false)
 	}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -227,3 +228,53 @@ func Test_amountToString(t *testing.T) {
 		})
 	}
 }
+
+func Test_stringToBigAmount(t *testing.T) {
+	oneAlphaInWei := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	maxUint256, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	tests := []struct {
+		name       string
+		amount     string
+		decimals   uint32
+		want       *big.Int
+		wantErrStr string
+	}{
+		{name: "integer", amount: "12", decimals: 18, want: new(big.Int).Mul(big.NewInt(12), oneAlphaInWei)},
+		{name: "fraction", amount: "1.5", decimals: 18, want: new(big.Int).Div(new(big.Int).Mul(big.NewInt(15), oneAlphaInWei), big.NewInt(10))},
+		{name: "smallest unit", amount: "0.000000000000000001", decimals: 18, want: big.NewInt(1)},
+		{name: "with separators", amount: "1'000.000'001", decimals: 6, want: big.NewInt(1_000_000_001)},
+		{name: "no decimals", amount: maxUint256.String(), decimals: 0, want: maxUint256},
+		{name: "over uint64", amount: "18446744073709551616", decimals: 0, want: new(big.Int).Lsh(big.NewInt(1), 64)},
+		{name: "empty", amount: "", decimals: 18, wantErrStr: "invalid empty amount string"},
+		{name: "missing integer part", amount: ".5", decimals: 18, wantErrStr: "missing integer part"},
+		{name: "missing fraction part", amount: "5.", decimals: 18, wantErrStr: "missing fraction part"},
+		{name: "too precise", amount: "0.001", decimals: 2, wantErrStr: "invalid precision: 0.001"},
+		{name: "two commas", amount: "1.1.1", decimals: 8, wantErrStr: "not a positive decimal number"},
+		{name: "negative", amount: "-1", decimals: 8, wantErrStr: "not a positive decimal number"},
+		{name: "hex", amount: "0x10", decimals: 0, wantErrStr: "not a positive decimal number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StringToBigAmount(tt.amount, tt.decimals)
+			if tt.wantErrStr != "" {
+				require.ErrorContains(t, err, tt.wantErrStr)
+				require.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_bigAmountToString(t *testing.T) {
+	oneAlphaInWei := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	require.Equal(t, "1.000'000'000'000'000'000", BigAmountToString(oneAlphaInWei, 18))
+	require.Equal(t, "0.000'000'000'000'000'001", BigAmountToString(big.NewInt(1), 18))
+	require.Equal(t, "0", BigAmountToString(big.NewInt(0), 0))
+	require.Equal(t, "18'446'744'073'709'551'616", BigAmountToString(new(big.Int).Lsh(big.NewInt(1), 64), 0))
+	// same result as AmountToString in uint64 range
+	for _, amount := range []uint64{0, 1, 12345, 99999, 18446744073709551615} {
+		require.Equal(t, AmountToString(amount, 8), BigAmountToString(new(big.Int).SetUint64(amount), 8))
+	}
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+)
+
+/*
+Units of ALPHA in the EVM partition. The EVM denominates ALPHA in wei like ETH, the
+smallest unit of ALPHA outside of the EVM is tema:
+
+	1 ALPHA = 10^8 tema = 10^18 wei
+	1 tema  = 10^10 wei
+*/
+const (
+	// AlphaDecimals is the number of decimal places of ALPHA denominated in tema.
+	AlphaDecimals = 8
+	// WeiDecimals is the number of decimal places of ALPHA denominated in wei.
+	WeiDecimals = 18
+)
+
+var (
+	weiPerTema          = new(big.Int).Exp(big.NewInt(10), big.NewInt(WeiDecimals-AlphaDecimals), nil)
+	weiPerTemaHalf      = new(big.Int).Rsh(weiPerTema, 1)
+	errNegativeAmount   = errors.New("amount is negative")
+	errAmountOutOfRange = errors.New("amount does not fit into uint64")
+)
+
+// WeiToTema converts wei to tema, the fraction of tema is truncated.
+func WeiToTema(wei *big.Int) *big.Int {
+	return new(big.Int).Quo(wei, weiPerTema)
+}
+
+// WeiToTemaRounded converts wei to tema rounding half up, the same way the EVM
+// partition converts the account balance to fee credit.
+func WeiToTemaRounded(wei *big.Int) *big.Int {
+	if wei.Sign() < 0 {
+		return new(big.Int).Neg(WeiToTemaRounded(new(big.Int).Neg(wei)))
+	}
+	return new(big.Int).Quo(new(big.Int).Add(wei, weiPerTemaHalf), weiPerTema)
+}
+
+// TemaToWei converts tema to wei.
+func TemaToWei(tema uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tema), weiPerTema)
+}
+
+// BigToUint64 returns the amount as uint64, error is returned when the amount is
+// negative or does not fit into uint64.
+func BigToUint64(amount *big.Int) (uint64, error) {
+	if amount.Sign() < 0 {
+		return 0, errNegativeAmount
+	}
+	if !amount.IsUint64() {
+		return 0, errAmountOutOfRange
+	}
+	return amount.Uint64(), nil
+}
+
+// WeiToAlphaString returns the amount in wei as ALPHA with tema precision,
+// the fraction of tema is truncated. See AmountToString for the format.
+func WeiToAlphaString(wei *big.Int) string {
+	return BigAmountToString(WeiToTema(wei), AlphaDecimals)
+}
+
+// WeiToString returns the amount in wei as ALPHA with full wei precision.
+func WeiToString(wei *big.Int) string {
+	return BigAmountToString(wei, WeiDecimals)
+}
+
+// AlphaStringToWei parses the amount in ALPHA (eg "1.5") with up to 18 decimal places to wei.
+func AlphaStringToWei(amount string) (*big.Int, error) {
+	return StringToBigAmount(amount, WeiDecimals)
+}
@@ -0,0 +1,168 @@
+package util
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bigFromString(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok, "invalid number %q", s)
+	return v
+}
+
+func TestWeiToTema(t *testing.T) {
+	tests := []struct {
+		name   string
+		wei    string
+		tema   string
+		errStr string
+	}{
+		{name: "zero", wei: "0", tema: "0"},
+		{name: "1 wei is 0 tema", wei: "1", tema: "0"},
+		{name: "10^10-1 wei is 0 tema", wei: "9999999999", tema: "0"},
+		{name: "10^10 wei is 1 tema", wei: "10000000000", tema: "1"},
+		{name: "10^10+1 wei is 1 tema", wei: "10000000001", tema: "1"},
+		{name: "2*10^10-1 wei is 1 tema", wei: "19999999999", tema: "1"},
+		{name: "2*10^10 wei is 2 tema", wei: "20000000000", tema: "2"},
+		{name: "max uint64 wei", wei: "18446744073709551615", tema: "1844674407"},
+		{name: "max uint64 wei + 1", wei: "18446744073709551616", tema: "1844674407"},
+		{name: "max uint64 tema", wei: "184467440737095516150000000000", tema: "18446744073709551615"},
+		{name: "max uint64 tema with fraction", wei: "184467440737095516159999999999", tema: "18446744073709551615"},
+		{name: "max uint64 tema + 1", wei: "184467440737095516160000000000", tema: "18446744073709551616", errStr: "amount does not fit into uint64"},
+		{name: "negative", wei: "-10000000000", tema: "-1", errStr: "amount is negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tema := WeiToTema(bigFromString(t, tt.wei))
+			require.Equal(t, tt.tema, tema.String())
+			v, err := BigToUint64(tema)
+			if tt.errStr != "" {
+				require.ErrorContains(t, err, tt.errStr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.tema, new(big.Int).SetUint64(v).String())
+		})
+	}
+}
+
+func TestWeiToTemaRounded(t *testing.T) {
+	tests := []struct {
+		name string
+		wei  string
+		tema string
+	}{
+		{name: "zero", wei: "0", tema: "0"},
+		{name: "less than half is rounded down", wei: "4999999999", tema: "0"},
+		{name: "half is rounded up", wei: "5000000000", tema: "1"},
+		{name: "1.5 tema - 1 wei", wei: "14999999999", tema: "1"},
+		{name: "1.5 tema", wei: "15000000000", tema: "2"},
+		{name: "negative half is rounded away from zero", wei: "-5000000000", tema: "-1"},
+		{name: "max uint64 tema rounded down", wei: "184467440737095516154999999999", tema: "18446744073709551615"},
+		{name: "max uint64 tema rounded up", wei: "184467440737095516145000000000", tema: "18446744073709551615"},
+		{name: "overflows uint64 when rounded up", wei: "184467440737095516155000000000", tema: "18446744073709551616"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.tema, WeiToTemaRounded(bigFromString(t, tt.wei)).String())
+		})
+	}
+}
+
+func TestTemaToWei(t *testing.T) {
+	tests := []struct {
+		tema uint64
+		wei  string
+	}{
+		{tema: 0, wei: "0"},
+		{tema: 1, wei: "10000000000"},
+		{tema: 100_000_000, wei: "1000000000000000000"},
+		{tema: math.MaxUint64, wei: "184467440737095516150000000000"},
+	}
+	for _, tt := range tests {
+		wei := TemaToWei(tt.tema)
+		require.Equal(t, tt.wei, wei.String())
+		// conversion back is exact
+		tema, err := BigToUint64(WeiToTema(wei))
+		require.NoError(t, err)
+		require.Equal(t, tt.tema, tema)
+	}
+}
+
+func TestBigToUint64(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		want   uint64
+		errStr string
+	}{
+		{name: "zero", amount: "0", want: 0},
+		{name: "max uint64", amount: "18446744073709551615", want: math.MaxUint64},
+		{name: "max uint64 + 1", amount: "18446744073709551616", errStr: "amount does not fit into uint64"},
+		{name: "negative", amount: "-1", errStr: "amount is negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := BigToUint64(bigFromString(t, tt.amount))
+			if tt.errStr != "" {
+				require.ErrorContains(t, err, tt.errStr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestWeiToString(t *testing.T) {
+	tests := []struct {
+		name  string
+		wei   string
+		alpha string
+		full  string
+	}{
+		{name: "zero", wei: "0", alpha: "0.000'000'00", full: "0.000'000'000'000'000'000"},
+		{name: "1 wei", wei: "1", alpha: "0.000'000'00", full: "0.000'000'000'000'000'001"},
+		{name: "1 tema", wei: "10000000000", alpha: "0.000'000'01", full: "0.000'000'010'000'000'000"},
+		{name: "1.5 ALPHA", wei: "1500000000000000000", alpha: "1.500'000'00", full: "1.500'000'000'000'000'000"},
+		{name: "max uint64 wei", wei: "18446744073709551615", alpha: "18.446'744'07", full: "18.446'744'073'709'551'615"},
+		{name: "max uint64 wei + 1", wei: "18446744073709551616", alpha: "18.446'744'07", full: "18.446'744'073'709'551'616"},
+		{name: "max uint64 tema", wei: "184467440737095516150000000000", alpha: "184'467'440'737.095'516'15", full: "184'467'440'737.095'516'150'000'000'000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wei := bigFromString(t, tt.wei)
+			require.Equal(t, tt.alpha, WeiToAlphaString(wei))
+			require.Equal(t, tt.full, WeiToString(wei))
+		})
+	}
+}
+
+func TestAlphaStringToWei(t *testing.T) {
+	tests := []struct {
+		amount string
+		wei    string
+		errStr string
+	}{
+		{amount: "1", wei: "1000000000000000000"},
+		{amount: "1.5", wei: "1500000000000000000"},
+		{amount: "0.000000000000000001", wei: "1"},
+		{amount: "18.446744073709551616", wei: "18446744073709551616"},
+		{amount: "184467440737.09551615", wei: "184467440737095516150000000000"},
+		{amount: "0.0000000000000000001", errStr: "invalid precision"},
+	}
+	for _, tt := range tests {
+		wei, err := AlphaStringToWei(tt.amount)
+		if tt.errStr != "" {
+			require.ErrorContains(t, err, tt.errStr)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tt.wei, wei.String())
+	}
+}
@@ -1,6 +1,7 @@
 package account
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -14,9 +15,12 @@ type (
 		GetAll() []Account
 		CreateKeys(mnemonic string) error
 		AddAccount() (uint64, []byte, error)
+		ImportAccount(key *AccountKey) (uint64, []byte, error)
+		AddWatchOnlyAccount(pubKey []byte, pubKeyHash []byte) (uint64, error)
 		GetMnemonic() (string, error)
 		GetAccountKey(uint64) (*AccountKey, error)
 		GetAccountKeys() ([]*AccountKey, error)
+		GetSigningKey(accountIndex uint64) (*AccountKey, error)
 		GetMaxAccountIndex() (uint64, error)
 		GetPublicKey(accountIndex uint64) ([]byte, error)
 		GetPublicKeys() ([][]byte, error)
@@ -33,6 +37,8 @@ type (
 
 var (
 	ErrInvalidPassword = errors.New("invalid password")
+	ErrAccountExists   = errors.New("account already exists")
+	ErrWatchOnly       = errors.New("watch-only account can not sign transactions")
 )
 
 func NewManager(dir string, password string, create bool) (Manager, error) {
@@ -93,7 +99,20 @@ func (m *managerImpl) GetAccountKeys() ([]*AccountKey, error) {
 	return m.db.Do().GetAccountKeys()
 }
 
-// GetPublicKey returns public key of the wallet (compressed secp256k1 key 33 bytes)
+// GetSigningKey returns the account key for signing transactions, returns ErrWatchOnly if the account has no private key.
+func (m *managerImpl) GetSigningKey(accountIndex uint64) (*AccountKey, error) {
+	key, err := m.GetAccountKey(accountIndex)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsWatchOnly() {
+		return nil, fmt.Errorf("key #%d: %w", accountIndex+1, ErrWatchOnly)
+	}
+	return key, nil
+}
+
+// GetPublicKey returns public key of the wallet (compressed secp256k1 key 33 bytes),
+// nil if the account is watch-only account of public key hash.
 func (m *managerImpl) GetPublicKey(accountIndex uint64) ([]byte, error) {
 	key, err := m.GetAccountKey(accountIndex)
 	if err != nil {
@@ -102,7 +121,8 @@ func (m *managerImpl) GetPublicKey(accountIndex uint64) ([]byte, error) {
 	return key.PubKey, nil
 }
 
-// GetPublicKeys returns public keys of the wallet, indexed by account indexes
+// GetPublicKeys returns public keys of the wallet, indexed by account indexes,
+// the public key of watch-only account of public key hash is nil.
 func (m *managerImpl) GetPublicKeys() ([][]byte, error) {
 	accKeys, err := m.GetAccountKeys()
 	if err != nil {
@@ -165,6 +185,53 @@ func (m *managerImpl) AddAccount() (uint64, []byte, error) {
 	return accountIndex, accountKey.PubKey, nil
 }
 
+// ImportAccount adds the account key, which is not derived from the wallet master key, as the next account
+// of the wallet. The account index of the imported key is skipped in the account key series.
+// Returns the created account index and public key.
+func (m *managerImpl) ImportAccount(accountKey *AccountKey) (uint64, []byte, error) {
+	var accountIndex uint64
+	err := m.db.WithTransaction(func(tx TxContext) error {
+		keys, err := tx.GetAccountKeys()
+		if err != nil {
+			return err
+		}
+		for idx, key := range keys {
+			if bytes.Equal(key.PubKeyHash.Sha256, accountKey.PubKeyHash.Sha256) {
+				return fmt.Errorf("%w: key #%d", ErrAccountExists, idx+1)
+			}
+		}
+		maxIndex, err := tx.GetMaxAccountIndex()
+		if err != nil {
+			return err
+		}
+		accountIndex = maxIndex + 1
+		if err := tx.AddAccount(accountIndex, accountKey); err != nil {
+			return err
+		}
+		if err := tx.SetMaxAccountIndex(accountIndex); err != nil {
+			return err
+		}
+		m.accounts.add(NewAccount(accountIndex, *accountKey))
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return accountIndex, accountKey.PubKey, nil
+}
+
+// AddWatchOnlyAccount adds account of the public key or public key hash, which has no private key, as the next
+// account of the wallet (see NewWatchOnlyAccountKey). The units of the account can be listed but the account
+// can not sign transactions. Returns the created account index.
+func (m *managerImpl) AddWatchOnlyAccount(pubKey []byte, pubKeyHash []byte) (uint64, error) {
+	accountKey, err := NewWatchOnlyAccountKey(pubKey, pubKeyHash)
+	if err != nil {
+		return 0, err
+	}
+	accountIndex, _, err := m.ImportAccount(accountKey)
+	return accountIndex, err
+}
+
 func (m *managerImpl) GetAll() []Account {
 	return m.accounts.getAll()
 }
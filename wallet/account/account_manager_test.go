@@ -124,3 +124,146 @@ func copyFile(src string, dst string) error {
 	}
 	return nil
 }
+
+func TestImportAccount(t *testing.T) {
+	am, err := newManager(t.TempDir(), walletPass, true)
+	require.NoError(t, err)
+	defer am.Close()
+	require.NoError(t, am.CreateKeys(testMnemonic))
+
+	privKey, err := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	require.NoError(t, err)
+	key, err := NewAccountKeyFromPrivateKey(privKey)
+	require.NoError(t, err)
+	require.Empty(t, key.DerivationPath)
+
+	accIdx, pubKey, err := am.ImportAccount(key)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, accIdx)
+	require.Equal(t, key.PubKey, pubKey)
+	require.Len(t, am.GetAll(), 2)
+	ac, err := am.GetAccountKey(1)
+	require.NoError(t, err)
+	require.Equal(t, key, ac)
+
+	// the same key can not be imported twice
+	_, _, err = am.ImportAccount(key)
+	require.ErrorIs(t, err, ErrAccountExists)
+	require.ErrorContains(t, err, "key #2")
+	accountKey0, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	_, _, err = am.ImportAccount(accountKey0)
+	require.ErrorIs(t, err, ErrAccountExists)
+
+	// the next derived account follows the imported account
+	accIdx, pubKey, err = am.AddAccount()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, accIdx)
+	require.Equal(t, testPubKey2Hex, hex.EncodeToString(pubKey))
+}
+
+func TestAddWatchOnlyAccount(t *testing.T) {
+	dir := t.TempDir()
+	am, err := newManager(dir, walletPass, true)
+	require.NoError(t, err)
+	require.NoError(t, am.CreateKeys(testMnemonic))
+
+	pubKey1, _ := hex.DecodeString(testPubKey1Hex)
+	pubKey2, _ := hex.DecodeString(testPubKey2Hex)
+	pubKey2Hash := NewKeyHash(pubKey2).Sha256
+
+	// watch-only account of public key
+	accIdx, err := am.AddWatchOnlyAccount(pubKey1, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, accIdx)
+	// watch-only account of public key hash
+	accIdx, err = am.AddWatchOnlyAccount(nil, pubKey2Hash)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, accIdx)
+	require.Len(t, am.GetAll(), 3)
+	require.Equal(t, pubKey2Hash, am.GetAll()[2].AccountKeys.Sha256)
+
+	// the same key can not be added twice, neither by the public key nor by the hash
+	_, err = am.AddWatchOnlyAccount(nil, NewKeyHash(pubKey1).Sha256)
+	require.ErrorIs(t, err, ErrAccountExists)
+	require.ErrorContains(t, err, "key #2")
+	_, err = am.AddWatchOnlyAccount(pubKey2, pubKey2Hash)
+	require.ErrorIs(t, err, ErrAccountExists)
+	require.ErrorContains(t, err, "key #3")
+	pubKey0, _ := hex.DecodeString(testPubKey0Hex)
+	_, err = am.AddWatchOnlyAccount(pubKey0, nil)
+	require.ErrorIs(t, err, ErrAccountExists)
+	require.ErrorContains(t, err, "key #1")
+
+	// the watch-only accounts survive reopening the encrypted wallet
+	am.Close()
+	am, err = newManager(dir, walletPass, false)
+	require.NoError(t, err)
+	defer am.Close()
+	require.Len(t, am.GetAll(), 3)
+
+	pubKeys, err := am.GetPublicKeys()
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{pubKey0, pubKey1, nil}, pubKeys)
+
+	key, err := am.GetAccountKey(1)
+	require.NoError(t, err)
+	require.True(t, key.IsWatchOnly())
+	require.Equal(t, pubKey1, key.PubKey)
+	require.Equal(t, NewKeyHash(pubKey1), key.PubKeyHash)
+	key, err = am.GetAccountKey(2)
+	require.NoError(t, err)
+	require.True(t, key.IsWatchOnly())
+	require.Nil(t, key.PubKey)
+	require.Equal(t, pubKey2Hash, key.PubKeyHash.Sha256)
+
+	// watch-only accounts can not sign
+	key, err = am.GetSigningKey(0)
+	require.NoError(t, err)
+	require.False(t, key.IsWatchOnly())
+	_, err = am.GetSigningKey(1)
+	require.ErrorIs(t, err, ErrWatchOnly)
+	require.EqualError(t, err, "key #2: watch-only account can not sign transactions")
+	_, err = am.GetSigningKey(2)
+	require.ErrorIs(t, err, ErrWatchOnly)
+
+	// the next derived account follows the watch-only accounts
+	accIdx, _, err = am.AddAccount()
+	require.NoError(t, err)
+	require.EqualValues(t, 3, accIdx)
+}
+
+func TestNewWatchOnlyAccountKey(t *testing.T) {
+	pubKey, _ := hex.DecodeString(testPubKey0Hex)
+	pubKeyHash, _ := hex.DecodeString(testPubKey0HashSha256Hex)
+
+	tests := []struct {
+		name       string
+		pubKey     []byte
+		pubKeyHash []byte
+		wantErr    string
+	}{
+		{name: "public key", pubKey: pubKey},
+		{name: "public key hash", pubKeyHash: pubKeyHash},
+		{name: "public key and hash", pubKey: pubKey, pubKeyHash: pubKeyHash},
+		{name: "none", wantErr: "public key or public key hash is required"},
+		{name: "invalid public key length", pubKey: pubKey[1:], wantErr: "invalid public key: pubkey must be 33 bytes long, but is 32"},
+		{name: "invalid public key", pubKey: append([]byte{0x05}, pubKey[1:]...), wantErr: "invalid public key"},
+		{name: "invalid public key hash", pubKeyHash: pubKeyHash[1:], wantErr: "invalid public key hash: expected 32 bytes, got 31 bytes"},
+		{name: "hash mismatch", pubKey: pubKey, pubKeyHash: make([]byte, 32), wantErr: "public key hash does not match the public key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewWatchOnlyAccountKey(tt.pubKey, tt.pubKeyHash)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, key.IsWatchOnly())
+			require.Equal(t, tt.pubKey, key.PubKey)
+			require.Equal(t, pubKeyHash, key.PubKeyHash.Sha256)
+			require.Empty(t, key.DerivationPath)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package account
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/sha256"
 	"errors"
@@ -81,8 +82,17 @@ func NewAccountKey(masterKey *hdkeychain.ExtendedKey, derivationPath string) (*A
 	if err != nil {
 		return nil, err
 	}
-	privateKeyBytes := crypto.FromECDSA(privateKey)
+	accountKey, err := NewAccountKeyFromPrivateKey(crypto.FromECDSA(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	accountKey.DerivationPath = []byte(derivationPath)
+	return accountKey, nil
+}
 
+// NewAccountKeyFromPrivateKey creates account key from given secp256k1 private key (32 bytes),
+// the derivation path of the key is empty i.e. the key is not derived from the wallet master key.
+func NewAccountKeyFromPrivateKey(privateKeyBytes []byte) (*AccountKey, error) {
 	signer, err := abcrypto.NewInMemorySecp256K1SignerFromKey(privateKeyBytes)
 	if err != nil {
 		return nil, err
@@ -96,13 +106,40 @@ func NewAccountKey(masterKey *hdkeychain.ExtendedKey, derivationPath string) (*A
 		return nil, err
 	}
 	return &AccountKey{
-		PubKey:         compressedPubKey,
-		PrivKey:        privateKeyBytes,
-		PubKeyHash:     NewKeyHash(compressedPubKey),
-		DerivationPath: []byte(derivationPath),
+		PubKey:     compressedPubKey,
+		PrivKey:    privateKeyBytes,
+		PubKeyHash: NewKeyHash(compressedPubKey),
 	}, nil
 }
 
+// NewWatchOnlyAccountKey creates account key without private key from given compressed secp256k1 public key
+// or sha256 hash of the public key, if both are given the hash must match the public key. The watch-only key
+// can be used to look up the units of the key but not to sign transactions.
+func NewWatchOnlyAccountKey(pubKey []byte, pubKeyHash []byte) (*AccountKey, error) {
+	if len(pubKey) == 0 && len(pubKeyHash) == 0 {
+		return nil, errors.New("public key or public key hash is required")
+	}
+	if len(pubKey) == 0 {
+		if len(pubKeyHash) != sha256.Size {
+			return nil, fmt.Errorf("invalid public key hash: expected %d bytes, got %d bytes", sha256.Size, len(pubKeyHash))
+		}
+		return &AccountKey{PubKeyHash: &KeyHashes{Sha256: pubKeyHash}}, nil
+	}
+	if _, err := abcrypto.NewVerifierSecp256k1(pubKey); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	keyHash := NewKeyHash(pubKey)
+	if len(pubKeyHash) > 0 && !bytes.Equal(pubKeyHash, keyHash.Sha256) {
+		return nil, errors.New("public key hash does not match the public key")
+	}
+	return &AccountKey{PubKey: pubKey, PubKeyHash: keyHash}, nil
+}
+
+// IsWatchOnly returns true if the account key has no private key i.e. the key can not sign transactions.
+func (k *AccountKey) IsWatchOnly() bool {
+	return len(k.PrivKey) == 0
+}
+
 // NewDerivationPath returns derivation path for given account index
 func NewDerivationPath(accountIndex uint64) string {
 	// https://github.com/bitcoin/bips/blob/master/bip-0044.mediawiki
@@ -0,0 +1,42 @@
+package account
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+// EncryptKeystore encrypts the private key of the account key into Web3 Secret Storage (keystore v3) JSON.
+// When lightKDF is true the scrypt parameters are weaker, the encryption is faster but less secure.
+func EncryptKeystore(accountKey *AccountKey, passphrase string, lightKDF bool) ([]byte, error) {
+	privateKey, err := crypto.ToECDSA(accountKey.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key id: %w", err)
+	}
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if lightKDF {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	key := &keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	return keystore.EncryptKey(key, passphrase, scryptN, scryptP)
+}
+
+// DecryptKeystore decrypts Web3 Secret Storage (keystore v3) JSON and returns the account key of the
+// private key in it, the derivation path of the returned key is empty.
+func DecryptKeystore(keyJSON []byte, passphrase string) (*AccountKey, error) {
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	return NewAccountKeyFromPrivateKey(crypto.FromECDSA(key.PrivateKey))
+}
@@ -0,0 +1,36 @@
+package account
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore(t *testing.T) {
+	privKey, err := hex.DecodeString(testPrivKey0Hex)
+	require.NoError(t, err)
+	key, err := NewAccountKeyFromPrivateKey(privKey)
+	require.NoError(t, err)
+	require.Equal(t, testPubKey0Hex, hex.EncodeToString(key.PubKey))
+
+	keyJSON, err := EncryptKeystore(key, "secret", true)
+	require.NoError(t, err)
+	var ks struct {
+		Version int    `json:"version"`
+		Address string `json:"address"`
+	}
+	require.NoError(t, json.Unmarshal(keyJSON, &ks))
+	require.Equal(t, 3, ks.Version)
+	require.Len(t, ks.Address, 40)
+
+	decrypted, err := DecryptKeystore(keyJSON, "secret")
+	require.NoError(t, err)
+	require.Equal(t, key, decrypted)
+
+	_, err = DecryptKeystore(keyJSON, "wrong")
+	require.ErrorContains(t, err, "could not decrypt key with given password")
+	_, err = DecryptKeystore([]byte("{}"), "secret")
+	require.ErrorContains(t, err, "failed to decrypt keystore")
+}
@@ -0,0 +1,419 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type (
+	// NamedValue is a single ABI decoded value together with its name and type.
+	NamedValue struct {
+		Name  string
+		Type  string
+		Value any
+	}
+
+	// DecodedLog is an EVM log entry decoded with the contract ABI.
+	DecodedLog struct {
+		Address common.Address
+		Event   string
+		Args    []NamedValue
+	}
+)
+
+// LoadABI reads contract ABI from JSON file. Accepts either plain ABI (JSON array)
+// or a compiler artifact (JSON object with "abi" field, eg Hardhat or Foundry output).
+func LoadABI(filename string) (*abi.ABI, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading ABI file: %w", err)
+	}
+	return ParseABI(data)
+}
+
+// ParseABI parses contract ABI from JSON, see LoadABI for supported formats.
+func ParseABI(data []byte) (*abi.ABI, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		artifact := struct {
+			ABI json.RawMessage `json:"abi"`
+		}{}
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return nil, fmt.Errorf("decoding artifact: %w", err)
+		}
+		if len(artifact.ABI) == 0 {
+			return nil, errors.New("artifact does not contain ABI")
+		}
+		data = artifact.ABI
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding ABI: %w", err)
+	}
+	return &contractABI, nil
+}
+
+// FindMethod looks up contract method either by name (eg "transfer") or by
+// signature (eg "transfer(address,uint256)"), the latter is useful for overloaded methods.
+func FindMethod(contractABI *abi.ABI, name string) (*abi.Method, error) {
+	if contractABI == nil {
+		return nil, errors.New("contract ABI is not set")
+	}
+	if m, ok := contractABI.Methods[name]; ok {
+		return &m, nil
+	}
+	for _, m := range contractABI.Methods {
+		if m.Sig == name {
+			return &m, nil
+		}
+	}
+	return nil, fmt.Errorf("method %q not found in ABI", name)
+}
+
+// FindEvent looks up contract event either by name (eg "Transfer") or by
+// signature (eg "Transfer(address,address,uint256)").
+func FindEvent(contractABI *abi.ABI, name string) (*abi.Event, error) {
+	if contractABI == nil {
+		return nil, errors.New("contract ABI is not set")
+	}
+	if e, ok := contractABI.Events[name]; ok {
+		return &e, nil
+	}
+	for _, e := range contractABI.Events {
+		if e.Sig == name {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("event %q not found in ABI", name)
+}
+
+// PackMethodCall returns call data (4 byte method ID followed by ABI encoded arguments)
+// for the method. Arguments are given in their string representation, see ParseABIArgs.
+func PackMethodCall(contractABI *abi.ABI, method string, args []string) ([]byte, error) {
+	m, err := FindMethod(contractABI, method)
+	if err != nil {
+		return nil, err
+	}
+	values, err := ParseABIArgs(m.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %w", m.Sig, err)
+	}
+	packed, err := m.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("encoding arguments of %s: %w", m.Sig, err)
+	}
+	return append(m.ID, packed...), nil
+}
+
+// PackConstructorArgs returns ABI encoded constructor arguments to be appended to the contract
+// creation code. Arguments are given in their string representation, see ParseABIArgs.
+func PackConstructorArgs(contractABI *abi.ABI, args []string) ([]byte, error) {
+	if contractABI == nil {
+		if len(args) > 0 {
+			return nil, errors.New("contract ABI is required to encode constructor arguments")
+		}
+		return nil, nil
+	}
+	inputs := contractABI.Constructor.Inputs
+	values, err := ParseABIArgs(inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("constructor: %w", err)
+	}
+	packed, err := inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("encoding constructor arguments: %w", err)
+	}
+	return packed, nil
+}
+
+/*
+ParseABIArgs converts string arguments into values of the ABI types of "inputs".
+
+Scalar values are given as is: integers in base 10 (or base 16 with "0x" prefix),
+addresses, bytes and fixed size bytes as hex strings, booleans as "true" or "false".
+Arrays, slices and tuples are given as JSON arrays, ie ["0x01..",2].
+*/
+func ParseABIArgs(inputs abi.Arguments, args []string) ([]any, error) {
+	if len(inputs) != len(args) {
+		return nil, fmt.Errorf("expected %d argument(s), got %d", len(inputs), len(args))
+	}
+	values := make([]any, len(inputs))
+	for i, input := range inputs {
+		v, err := parseABIValue(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s %s): %w", i, input.Type.String(), input.Name, err)
+		}
+		values[i] = v.Interface()
+	}
+	return values, nil
+}
+
+// DecodeMethodOutput decodes the return data of the method.
+func DecodeMethodOutput(contractABI *abi.ABI, method string, data []byte) ([]NamedValue, error) {
+	m, err := FindMethod(contractABI, method)
+	if err != nil {
+		return nil, err
+	}
+	values, err := m.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding output of %s: %w", m.Sig, err)
+	}
+	res := make([]NamedValue, len(values))
+	for i, v := range values {
+		res[i] = NamedValue{Name: m.Outputs[i].Name, Type: m.Outputs[i].Type.String(), Value: v}
+	}
+	return res, nil
+}
+
+// DecodeLog decodes the log entry with the contract ABI, the event is identified by the first topic.
+func DecodeLog(contractABI *abi.ABI, log *evm.LogEntry) (*DecodedLog, error) {
+	if contractABI == nil {
+		return nil, errors.New("contract ABI is not set")
+	}
+	if log == nil || len(log.Topics) == 0 {
+		return nil, errors.New("log has no topics")
+	}
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any)
+	if len(log.Data) > 0 {
+		if err := event.Inputs.UnpackIntoMap(values, log.Data); err != nil {
+			return nil, fmt.Errorf("decoding data of event %s: %w", event.Name, err)
+		}
+	}
+	var indexed abi.Arguments
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("decoding topics of event %s: %w", event.Name, err)
+	}
+	res := &DecodedLog{Address: log.Address, Event: event.Name}
+	for _, input := range event.Inputs {
+		res.Args = append(res.Args, NamedValue{Name: input.Name, Type: input.Type.String(), Value: values[input.Name]})
+	}
+	return res, nil
+}
+
+func (v NamedValue) String() string {
+	return fmt.Sprintf("%s (%s): %s", v.Name, v.Type, FormatABIValue(v.Value))
+}
+
+func (l *DecodedLog) String() string {
+	args := make([]string, len(l.Args))
+	for i, a := range l.Args {
+		args[i] = a.Name + ": " + FormatABIValue(a.Value)
+	}
+	return fmt.Sprintf("%s(%s)", l.Event, strings.Join(args, ", "))
+}
+
+// FormatABIValue returns human readable representation of the ABI decoded value.
+func FormatABIValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "<nil>"
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case string:
+		return strconv.Quote(v)
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = FormatABIValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := range fields {
+			fields[i] = rv.Type().Field(i).Name + ": " + FormatABIValue(rv.Field(i).Interface())
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		return FormatABIValue(rv.Elem().Interface())
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// parseABIValue converts argument in string form to the Go type used by the abi package for "t".
+func parseABIValue(t abi.Type, arg string) (reflect.Value, error) {
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		var items []json.RawMessage
+		if err := json.Unmarshal([]byte(arg), &items); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected JSON array: %w", err)
+		}
+		return parseABICompositeValue(t, items)
+	default:
+		return parseABIScalarValue(t, arg)
+	}
+}
+
+func parseABICompositeValue(t abi.Type, items []json.RawMessage) (reflect.Value, error) {
+	itemValue := func(typ abi.Type, raw json.RawMessage) (reflect.Value, error) {
+		// nested composite values are JSON arrays, scalars may be JSON strings, numbers or booleans
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || typ.T == abi.SliceTy || typ.T == abi.ArrayTy || typ.T == abi.TupleTy {
+			s = string(raw)
+		}
+		return parseABIValue(typ, s)
+	}
+
+	switch t.T {
+	case abi.SliceTy, abi.ArrayTy:
+		var res reflect.Value
+		if t.T == abi.SliceTy {
+			res = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		} else {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("expected array of %d items, got %d", t.Size, len(items))
+			}
+			res = reflect.New(t.GetType()).Elem()
+		}
+		for i, item := range items {
+			v, err := itemValue(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %w", i, err)
+			}
+			res.Index(i).Set(v)
+		}
+		return res, nil
+	case abi.TupleTy:
+		if len(items) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("expected tuple of %d items, got %d", len(t.TupleElems), len(items))
+		}
+		res := reflect.New(t.GetType()).Elem()
+		for i, item := range items {
+			v, err := itemValue(*t.TupleElems[i], item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", t.TupleRawNames[i], err)
+			}
+			res.Field(i).Set(v)
+		}
+		return res, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported composite type %s", t.String())
+}
+
+func parseABIScalarValue(t abi.Type, arg string) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, ok := parseBigInt(arg)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid integer %q", arg)
+		}
+		if t.T == abi.UintTy && n.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("negative value %s for unsigned integer", arg)
+		}
+		bitLen := n.BitLen()
+		if t.T == abi.IntTy && n.Sign() < 0 {
+			bitLen = new(big.Int).Add(n, big.NewInt(1)).BitLen()
+		}
+		if (t.T == abi.UintTy && bitLen > t.Size) || (t.T == abi.IntTy && bitLen > t.Size-1) {
+			return reflect.Value{}, fmt.Errorf("value %s overflows %s", arg, t.String())
+		}
+		typ := t.GetType()
+		switch typ.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.ValueOf(n.Int64()).Convert(typ), nil
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return reflect.ValueOf(n.Uint64()).Convert(typ), nil
+		default:
+			return reflect.ValueOf(n), nil
+		}
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(arg)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid boolean %q", arg)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		return reflect.ValueOf(arg), nil
+	case abi.AddressTy:
+		if !common.IsHexAddress(arg) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", arg)
+		}
+		return reflect.ValueOf(common.HexToAddress(arg)), nil
+	case abi.BytesTy:
+		b, err := decodeHexArg(arg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	case abi.FixedBytesTy:
+		b, err := decodeHexArg(arg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		res := reflect.New(t.GetType()).Elem()
+		reflect.Copy(res, reflect.ValueOf(b))
+		return res, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t.String())
+}
+
+func parseBigInt(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s, base = s[2:], 16
+	}
+	n, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return nil, false
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, true
+}
+
+func decodeHexArg(s string) ([]byte, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		if errors.Is(err, hexutil.ErrMissingPrefix) {
+			return nil, fmt.Errorf("invalid hex %q, must start with 0x", s)
+		}
+		return nil, fmt.Errorf("invalid hex %q: %w", s, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,186 @@
+package evm
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"function","name":"set","inputs":[{"name":"a","type":"uint8"},{"name":"b","type":"int16"},{"name":"c","type":"bytes4"},{"name":"d","type":"bool"},{"name":"e","type":"string"},{"name":"f","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"sum","inputs":[{"name":"values","type":"uint256[]"},{"name":"pair","type":"address[2]"}],"outputs":[{"name":"","type":"uint256"},{"name":"","type":"bool"}]},
+	{"type":"function","name":"store","inputs":[{"name":"item","type":"tuple","components":[{"name":"id","type":"uint64"},{"name":"tags","type":"string[]"}]}],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+func TestParseABI(t *testing.T) {
+	t.Run("plain ABI", func(t *testing.T) {
+		contractABI, err := ParseABI([]byte(testABI))
+		require.NoError(t, err)
+		require.Len(t, contractABI.Methods, 4)
+		require.Len(t, contractABI.Events, 1)
+	})
+	t.Run("compiler artifact", func(t *testing.T) {
+		contractABI, err := ParseABI([]byte(`{"contractName":"Token","abi":` + testABI + `,"bytecode":"0x00"}`))
+		require.NoError(t, err)
+		require.Len(t, contractABI.Methods, 4)
+	})
+	t.Run("artifact without ABI", func(t *testing.T) {
+		_, err := ParseABI([]byte(`{"bytecode":"0x00"}`))
+		require.EqualError(t, err, "artifact does not contain ABI")
+	})
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := ParseABI([]byte(`[{`))
+		require.ErrorContains(t, err, "decoding ABI")
+	})
+	t.Run("load from file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "token.abi")
+		require.NoError(t, os.WriteFile(filename, []byte(testABI), 0600))
+		contractABI, err := LoadABI(filename)
+		require.NoError(t, err)
+		require.Contains(t, contractABI.Methods, "balanceOf")
+		_, err = LoadABI(filepath.Join(t.TempDir(), "missing.abi"))
+		require.ErrorContains(t, err, "reading ABI file")
+	})
+}
+
+func TestPackMethodCall(t *testing.T) {
+	contractABI, err := ParseABI([]byte(testABI))
+	require.NoError(t, err)
+	addr1 := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	addr2 := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	t.Run("address", func(t *testing.T) {
+		data, err := PackMethodCall(contractABI, "balanceOf", []string{addr1.Hex()})
+		require.NoError(t, err)
+		expected, err := contractABI.Pack("balanceOf", addr1)
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+	t.Run("method signature", func(t *testing.T) {
+		data, err := PackMethodCall(contractABI, "balanceOf(address)", []string{addr1.Hex()})
+		require.NoError(t, err)
+		expected, err := contractABI.Pack("balanceOf", addr1)
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+	t.Run("scalar types", func(t *testing.T) {
+		data, err := PackMethodCall(contractABI, "set", []string{"0xff", "-32768", "0x01020304", "true", "hello", "0x"})
+		require.NoError(t, err)
+		expected, err := contractABI.Pack("set", uint8(255), int16(-32768), [4]byte{1, 2, 3, 4}, true, "hello", []byte{})
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+	t.Run("slice and array", func(t *testing.T) {
+		data, err := PackMethodCall(contractABI, "sum", []string{`[1,"0x02","3"]`, `["` + addr1.Hex() + `","` + addr2.Hex() + `"]`})
+		require.NoError(t, err)
+		expected, err := contractABI.Pack("sum", []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, [2]common.Address{addr1, addr2})
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+	t.Run("tuple", func(t *testing.T) {
+		data, err := PackMethodCall(contractABI, "store", []string{`[7,["a","b"]]`})
+		require.NoError(t, err)
+		expected, err := contractABI.Pack("store", struct {
+			Id   uint64
+			Tags []string
+		}{Id: 7, Tags: []string{"a", "b"}})
+		require.NoError(t, err)
+		require.Equal(t, expected, data)
+	})
+	t.Run("errors", func(t *testing.T) {
+		var tests = []struct {
+			method string
+			args   []string
+			errMsg string
+		}{
+			{method: "mint", args: nil, errMsg: `method "mint" not found in ABI`},
+			{method: "balanceOf", args: nil, errMsg: "expected 1 argument(s), got 0"},
+			{method: "balanceOf", args: []string{"0x01"}, errMsg: `invalid address "0x01"`},
+			{method: "set", args: []string{"256", "0", "0x01020304", "true", "", "0x"}, errMsg: "value 256 overflows uint8"},
+			{method: "set", args: []string{"-1", "0", "0x01020304", "true", "", "0x"}, errMsg: "negative value -1 for unsigned integer"},
+			{method: "set", args: []string{"1", "32768", "0x01020304", "true", "", "0x"}, errMsg: "value 32768 overflows int16"},
+			{method: "set", args: []string{"1", "-32769", "0x01020304", "true", "", "0x"}, errMsg: "value -32769 overflows int16"},
+			{method: "set", args: []string{"1", "0", "0x010203", "true", "", "0x"}, errMsg: "expected 4 bytes, got 3"},
+			{method: "set", args: []string{"1", "0", "01020304", "true", "", "0x"}, errMsg: `invalid hex "01020304", must start with 0x`},
+			{method: "set", args: []string{"1", "0", "0x01020304", "yes", "", "0x"}, errMsg: `invalid boolean "yes"`},
+			{method: "set", args: []string{"x", "0", "0x01020304", "true", "", "0x"}, errMsg: `invalid integer "x"`},
+			{method: "sum", args: []string{"1", "[]"}, errMsg: "expected JSON array"},
+			{method: "sum", args: []string{"[]", `["` + addr1.Hex() + `"]`}, errMsg: "expected array of 2 items, got 1"},
+			{method: "store", args: []string{`[1]`}, errMsg: "expected tuple of 2 items, got 1"},
+		}
+		for _, tc := range tests {
+			_, err := PackMethodCall(contractABI, tc.method, tc.args)
+			require.ErrorContains(t, err, tc.errMsg, "method %s args %v", tc.method, tc.args)
+		}
+	})
+}
+
+func TestDecodeMethodOutput(t *testing.T) {
+	contractABI, err := ParseABI([]byte(testABI))
+	require.NoError(t, err)
+
+	data, err := contractABI.Methods["sum"].Outputs.Pack(big.NewInt(6), true)
+	require.NoError(t, err)
+	values, err := DecodeMethodOutput(contractABI, "sum", data)
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	require.Equal(t, "uint256", values[0].Type)
+	require.Equal(t, "6", FormatABIValue(values[0].Value))
+	require.Equal(t, "bool", values[1].Type)
+	require.Equal(t, true, values[1].Value)
+
+	data, err = contractABI.Methods["balanceOf"].Outputs.Pack(big.NewInt(100))
+	require.NoError(t, err)
+	values, err = DecodeMethodOutput(contractABI, "balanceOf", data)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Equal(t, "balance (uint256): 100", values[0].String())
+
+	_, err = DecodeMethodOutput(contractABI, "balanceOf", []byte{1, 2})
+	require.Error(t, err)
+}
+
+func TestDecodeLog(t *testing.T) {
+	contractABI, err := ParseABI([]byte(testABI))
+	require.NoError(t, err)
+	from := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	contract := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	event := contractABI.Events["Transfer"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(5))
+	require.NoError(t, err)
+
+	decoded, err := DecodeLog(contractABI, &evm.LogEntry{
+		Address: contract,
+		Topics:  []common.Hash{event.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    data,
+	})
+	require.NoError(t, err)
+	require.Equal(t, contract, decoded.Address)
+	require.Equal(t, "Transfer", decoded.Event)
+	require.Equal(t, "Transfer(from: "+from.Hex()+", to: "+to.Hex()+", value: 5)", decoded.String())
+
+	_, err = DecodeLog(contractABI, &evm.LogEntry{Topics: []common.Hash{common.HexToHash("0x01")}})
+	require.Error(t, err)
+	_, err = DecodeLog(contractABI, &evm.LogEntry{})
+	require.Error(t, err)
+}
+
+func TestFormatABIValue(t *testing.T) {
+	require.Equal(t, "<nil>", FormatABIValue(nil))
+	require.Equal(t, `"abc"`, FormatABIValue("abc"))
+	require.Equal(t, "0x0102", FormatABIValue([]byte{1, 2}))
+	require.Equal(t, "0x01020304", FormatABIValue([4]byte{1, 2, 3, 4}))
+	require.Equal(t, "[1, 2]", FormatABIValue([]*big.Int{big.NewInt(1), big.NewInt(2)}))
+	require.Equal(t, "{Id: 7, Tags: [\"a\"]}", FormatABIValue(struct {
+		Id   uint64
+		Tags []string
+	}{Id: 7, Tags: []string{"a"}}))
+}
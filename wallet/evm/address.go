@@ -0,0 +1,54 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+// DefaultCreate2Factory is the address of the deterministic deployment proxy, the de facto standard
+// CREATE2 factory which is deployed at the same address on most of the EVM chains. The factory
+// expects the call data to be the 32 byte salt followed by the contract creation code and returns
+// the address of the deployed contract.
+var DefaultCreate2Factory = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+
+// CreateAddress returns the address of the contract deployed by the sender with the given nonce (CREATE).
+func CreateAddress(sender common.Address, nonce uint64) common.Address {
+	return ethcrypto.CreateAddress(sender, nonce)
+}
+
+// Create2Address returns the address of the contract deployed by the factory contract with the
+// given salt and hash of the contract creation code (CREATE2, EIP-1014).
+func Create2Address(factory common.Address, salt, initCodeHash common.Hash) common.Address {
+	return ethcrypto.CreateAddress2(factory, salt, initCodeHash.Bytes())
+}
+
+// InitCodeHash returns the hash of the contract creation code used to calculate the CREATE2 address.
+func InitCodeHash(initCode []byte) common.Hash {
+	return ethcrypto.Keccak256Hash(initCode)
+}
+
+// Create2FactoryCallData returns the call data for the DefaultCreate2Factory (or a factory with
+// the same interface) to deploy the contract creation code with the salt.
+func Create2FactoryCallData(salt common.Hash, initCode []byte) []byte {
+	return append(salt.Bytes(), initCode...)
+}
+
+// PredictContractAddress returns the address of the contract the account deploys with its next
+// transaction and the nonce of the account. Account which does not exist yet has nonce 0.
+func (w *Wallet) PredictContractAddress(ctx context.Context, accountNumber uint64) (common.Address, uint64, error) {
+	_, from, err := w.accountKey(accountNumber)
+	if err != nil {
+		return common.Address{}, 0, err
+	}
+	nonce, err := w.client.GetTransactionCount(ctx, from.Bytes())
+	if err != nil && !errors.Is(err, sdktypes.ErrNotFound) {
+		return common.Address{}, 0, fmt.Errorf("account %x transaction count read failed: %w", from.Bytes(), err)
+	}
+	return CreateAddress(from, nonce), nonce, nil
+}
@@ -0,0 +1,83 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAddress(t *testing.T) {
+	sender := common.HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	require.Equal(t, common.HexToAddress("0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"), CreateAddress(sender, 0))
+	require.Equal(t, common.HexToAddress("0x343c43a37d37dff08ae8c4a11544c718abb4fcf8"), CreateAddress(sender, 1))
+}
+
+func TestCreate2Address(t *testing.T) {
+	// examples from EIP-1014
+	tests := []struct {
+		factory  string
+		salt     string
+		initCode []byte
+		want     string
+	}{
+		{
+			factory:  "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: []byte{0x00},
+			want:     "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			factory:  "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x000000000000000000000000feed000000000000000000000000000000000000",
+			initCode: []byte{0x00},
+			want:     "0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+		{
+			factory:  "0x00000000000000000000000000000000deadbeef",
+			salt:     "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			initCode: common.FromHex("0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"),
+			want:     "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+	}
+	for _, tc := range tests {
+		addr := Create2Address(common.HexToAddress(tc.factory), common.HexToHash(tc.salt), InitCodeHash(tc.initCode))
+		require.Equal(t, common.HexToAddress(tc.want), addr)
+	}
+}
+
+func TestCreate2FactoryCallData(t *testing.T) {
+	salt := common.HexToHash("0x01")
+	data := Create2FactoryCallData(salt, []byte{0xAA, 0xBB})
+	require.Len(t, data, 34)
+	require.Equal(t, salt.Bytes(), data[:32])
+	require.Equal(t, []byte{0xAA, 0xBB}, data[32:])
+}
+
+func TestWallet_PredictContractAddress(t *testing.T) {
+	ctx := context.Background()
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	from, err := w.GetAccountAddress(1)
+	require.NoError(t, err)
+
+	addr, nonce, err := w.PredictContractAddress(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce)
+	require.Equal(t, CreateAddress(from, 1), addr)
+
+	// account does not exist yet
+	clientMock.noFcb = true
+	addr, nonce, err = w.PredictContractAddress(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, nonce)
+	require.Equal(t, CreateAddress(from, 0), addr)
+
+	clientMock.SimulateErr = errors.New("some error")
+	_, _, err = w.PredictContractAddress(ctx, 1)
+	require.ErrorContains(t, err, "transaction count read failed: some error")
+	_, _, err = w.PredictContractAddress(ctx, 0)
+	require.EqualError(t, err, "invalid account number: 0")
+}
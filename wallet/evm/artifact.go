@@ -0,0 +1,296 @@
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// placeholderLen is the length of the library link placeholder in hex encoded bytecode (20 bytes).
+const placeholderLen = 2 * common.AddressLength
+
+// linkPlaceholderRe matches library link placeholders in hex encoded bytecode: "__$<hash>$__"
+// used by solc >= 0.5 and "__<library name>___" used by older versions (hex never contains "_").
+var linkPlaceholderRe = regexp.MustCompile(`__.{36}__`)
+
+type (
+	// ContractArtifact is a compiled smart contract read from compiler output.
+	ContractArtifact struct {
+		Name string
+		ABI  *abi.ABI
+		// Bytecode is the hex encoded creation code which may contain library link placeholders.
+		Bytecode       string
+		LinkReferences LinkReferences
+		// DeployedBytecode is the hex encoded runtime code, empty when the artifact does not contain it.
+		DeployedBytecode       string
+		DeployedLinkReferences LinkReferences
+		// ImmutableReferences are the positions of the immutable variables in the runtime code
+		// (AST node ID -> positions), the values are set by the constructor.
+		ImmutableReferences map[string][]LinkReference
+	}
+
+	// LinkReferences describes the library link placeholders in the bytecode,
+	// source file name -> library name -> positions.
+	LinkReferences map[string]map[string][]LinkReference
+
+	// LinkReference is the byte offset and length of the library address in the bytecode.
+	LinkReference struct {
+		Start  int `json:"start"`
+		Length int `json:"length"`
+	}
+
+	// artifactJSON covers the fields of Hardhat, Truffle, Foundry and solc --combined-json outputs.
+	artifactJSON struct {
+		ContractName           string                          `json:"contractName"`
+		ABI                    json.RawMessage                 `json:"abi"`
+		Bytecode               json.RawMessage                 `json:"bytecode"`
+		LinkReferences         LinkReferences                  `json:"linkReferences"`
+		DeployedBytecode       json.RawMessage                 `json:"deployedBytecode"`
+		DeployedLinkReferences LinkReferences                  `json:"deployedLinkReferences"`
+		ImmutableReferences    map[string][]LinkReference      `json:"immutableReferences"`
+		Contracts              map[string]combinedContractJSON `json:"contracts"`
+	}
+
+	foundryBytecodeJSON struct {
+		Object              string                     `json:"object"`
+		LinkReferences      LinkReferences             `json:"linkReferences"`
+		ImmutableReferences map[string][]LinkReference `json:"immutableReferences"`
+	}
+
+	combinedContractJSON struct {
+		ABI        json.RawMessage `json:"abi"`
+		Bin        string          `json:"bin"`
+		BinRuntime string          `json:"bin-runtime"`
+	}
+)
+
+/*
+LoadArtifact reads compiled contract from the compiler artifact file. Supported formats are
+Hardhat and Truffle artifacts, Foundry output and solc --combined-json output. The latter
+may contain multiple contracts, "contractName" selects the contract (either "Name" or
+"path/File.sol:Name"), it can be empty when the file contains single contract.
+*/
+func LoadArtifact(filename, contractName string) (*ContractArtifact, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading artifact file: %w", err)
+	}
+	return ParseArtifact(data, contractName)
+}
+
+// ParseArtifact parses compiled contract from the compiler output, see LoadArtifact for details.
+func ParseArtifact(data []byte, contractName string) (*ContractArtifact, error) {
+	var aj artifactJSON
+	if err := json.Unmarshal(data, &aj); err != nil {
+		return nil, fmt.Errorf("decoding artifact: %w", err)
+	}
+	if len(aj.Contracts) > 0 {
+		return parseCombinedJSON(aj.Contracts, contractName)
+	}
+	if contractName != "" && aj.ContractName != "" && aj.ContractName != contractName {
+		return nil, fmt.Errorf("artifact contains contract %q, not %q", aj.ContractName, contractName)
+	}
+	res := &ContractArtifact{Name: aj.ContractName, ImmutableReferences: aj.ImmutableReferences}
+	var err error
+	if res.Bytecode, res.LinkReferences, _, err = parseBytecodeJSON(aj.Bytecode, aj.LinkReferences); err != nil {
+		return nil, fmt.Errorf("decoding bytecode: %w", err)
+	}
+	deployed, deployedLinkRefs, immutableRefs, err := parseBytecodeJSON(aj.DeployedBytecode, aj.DeployedLinkReferences)
+	if err != nil {
+		return nil, fmt.Errorf("decoding deployed bytecode: %w", err)
+	}
+	res.DeployedBytecode = strings.TrimPrefix(strings.TrimSpace(deployed), "0x")
+	res.DeployedLinkReferences = deployedLinkRefs
+	if immutableRefs != nil {
+		res.ImmutableReferences = immutableRefs
+	}
+	if err := res.setABI(aj.ABI); err != nil {
+		return nil, err
+	}
+	if err := res.verifyBytecode(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseCombinedJSON(contracts map[string]combinedContractJSON, contractName string) (*ContractArtifact, error) {
+	var names []string
+	for name := range contracts {
+		if contractName == "" || name == contractName || strings.HasSuffix(name, ":"+contractName) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	switch {
+	case len(names) == 0:
+		return nil, fmt.Errorf("contract %q not found in artifact", contractName)
+	case len(names) > 1:
+		return nil, fmt.Errorf("artifact contains multiple contracts (%s), contract name must be specified", strings.Join(names, ", "))
+	}
+	c := contracts[names[0]]
+	res := &ContractArtifact{Name: names[0], Bytecode: c.Bin, DeployedBytecode: strings.TrimPrefix(c.BinRuntime, "0x")}
+	if err := res.setABI(c.ABI); err != nil {
+		return nil, err
+	}
+	if err := res.verifyBytecode(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// parseBytecodeJSON parses the bytecode which is either hex string (Hardhat, Truffle) or an object
+// with the link and immutable references (Foundry). The link references of the object override
+// the given ones.
+func parseBytecodeJSON(data json.RawMessage, linkRefs LinkReferences) (string, LinkReferences, map[string][]LinkReference, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '"':
+		var bytecode string
+		if err := json.Unmarshal(data, &bytecode); err != nil {
+			return "", nil, nil, err
+		}
+		return bytecode, linkRefs, nil, nil
+	case len(data) > 0 && data[0] == '{':
+		var fb foundryBytecodeJSON
+		if err := json.Unmarshal(data, &fb); err != nil {
+			return "", nil, nil, err
+		}
+		return fb.Object, fb.LinkReferences, fb.ImmutableReferences, nil
+	}
+	return "", linkRefs, nil, nil
+}
+
+func (a *ContractArtifact) setABI(data json.RawMessage) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("artifact does not contain ABI")
+	}
+	// older solc versions output ABI as JSON encoded string
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("decoding ABI: %w", err)
+		}
+		data = []byte(s)
+	}
+	contractABI, err := ParseABI(data)
+	if err != nil {
+		return err
+	}
+	a.ABI = contractABI
+	return nil
+}
+
+func (a *ContractArtifact) verifyBytecode() error {
+	a.Bytecode = strings.TrimPrefix(strings.TrimSpace(a.Bytecode), "0x")
+	if a.Bytecode == "" {
+		return errors.New("artifact does not contain bytecode, the contract may be abstract or an interface")
+	}
+	return nil
+}
+
+/*
+LinkBytecode replaces library link placeholders in the hex encoded bytecode with the library
+addresses and returns the decoded bytecode. Libraries are keyed either by the library name or
+by the fully qualified name ("path/File.sol:Name"). The placeholders are located using the link
+references (when known) and by matching the placeholder text. Returns error when some placeholder
+remains unresolved or when a library is not referenced by the bytecode.
+*/
+func LinkBytecode(bytecode string, linkRefs LinkReferences, libraries map[string]common.Address) ([]byte, error) {
+	code := []byte(strings.TrimPrefix(strings.TrimSpace(bytecode), "0x"))
+	used := make(map[string]bool)
+	var unresolved []string
+	for _, file := range sortedKeys(linkRefs) {
+		for _, lib := range sortedKeys(linkRefs[file]) {
+			fqName := file + ":" + lib
+			key := fqName
+			addr, ok := libraries[key]
+			if !ok {
+				key = lib
+				addr, ok = libraries[key]
+			}
+			if !ok {
+				unresolved = append(unresolved, fqName)
+				continue
+			}
+			used[key] = true
+			for _, ref := range linkRefs[file][lib] {
+				start, end := 2*ref.Start, 2*(ref.Start+ref.Length)
+				if ref.Length != common.AddressLength || start < 0 || end > len(code) {
+					return nil, fmt.Errorf("invalid link reference of library %s at offset %d", fqName, ref.Start)
+				}
+				copy(code[start:end], hex.EncodeToString(addr.Bytes()))
+			}
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolved library link placeholder(s): %s", strings.Join(unresolved, ", "))
+	}
+	for name, addr := range libraries {
+		addrHex := []byte(hex.EncodeToString(addr.Bytes()))
+		for _, placeholder := range linkPlaceholders(name) {
+			if bytes.Contains(code, placeholder) {
+				code = bytes.ReplaceAll(code, placeholder, addrHex)
+				used[name] = true
+			}
+		}
+	}
+	if placeholders := linkPlaceholderRe.FindAll(code, -1); len(placeholders) > 0 {
+		var names []string
+		for _, p := range placeholders {
+			if s := string(p); !slices.Contains(names, s) {
+				names = append(names, s)
+			}
+		}
+		return nil, fmt.Errorf("unresolved library link placeholder(s): %s", strings.Join(names, ", "))
+	}
+	for _, name := range sortedKeys(libraries) {
+		if !used[name] {
+			return nil, fmt.Errorf("library %q is not referenced by the bytecode", name)
+		}
+	}
+	res, err := hex.DecodeString(string(code))
+	if err != nil {
+		return nil, fmt.Errorf("hex decode error: %w", err)
+	}
+	return res, nil
+}
+
+// linkPlaceholders returns possible placeholder texts of the library in the bytecode.
+func linkPlaceholders(name string) [][]byte {
+	res := [][]byte{legacyLinkPlaceholder(name)}
+	if strings.Contains(name, ":") {
+		hash := hex.EncodeToString(crypto.Keccak256([]byte(name)))
+		res = append(res, []byte("__$"+hash[:34]+"$__"))
+		// legacy placeholder contains library name without the source file
+		res = append(res, legacyLinkPlaceholder(name[strings.LastIndex(name, ":")+1:]))
+	}
+	return res
+}
+
+func legacyLinkPlaceholder(name string) []byte {
+	if len(name) > placeholderLen-4 {
+		name = name[:placeholderLen-4]
+	}
+	return []byte("__" + name + strings.Repeat("_", placeholderLen-2-len(name)))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
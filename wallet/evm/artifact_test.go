@@ -0,0 +1,153 @@
+package evm
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const testArtifactABI = `[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`
+
+func TestParseArtifact(t *testing.T) {
+	t.Run("hardhat", func(t *testing.T) {
+		artifact, err := ParseArtifact([]byte(`{"contractName":"Token","abi":`+testArtifactABI+`,"bytecode":"0x6080",
+			"linkReferences":{"contracts/Math.sol":{"Math":[{"start":1,"length":20}]}}}`), "")
+		require.NoError(t, err)
+		require.Equal(t, "Token", artifact.Name)
+		require.Equal(t, "6080", artifact.Bytecode)
+		require.Len(t, artifact.ABI.Constructor.Inputs, 1)
+		require.Equal(t, LinkReferences{"contracts/Math.sol": {"Math": {{Start: 1, Length: 20}}}}, artifact.LinkReferences)
+		require.Empty(t, artifact.DeployedBytecode)
+
+		artifact, err = ParseArtifact([]byte(`{"contractName":"Token","abi":`+testArtifactABI+`,"bytecode":"0x6080",
+			"deployedBytecode":"0x6040","deployedLinkReferences":{"contracts/Math.sol":{"Math":[{"start":2,"length":20}]}}}`), "")
+		require.NoError(t, err)
+		require.Equal(t, "6040", artifact.DeployedBytecode)
+		require.Equal(t, LinkReferences{"contracts/Math.sol": {"Math": {{Start: 2, Length: 20}}}}, artifact.DeployedLinkReferences)
+
+		_, err = ParseArtifact([]byte(`{"contractName":"Token","abi":`+testArtifactABI+`,"bytecode":"0x6080"}`), "Other")
+		require.EqualError(t, err, `artifact contains contract "Token", not "Other"`)
+	})
+	t.Run("foundry", func(t *testing.T) {
+		artifact, err := ParseArtifact([]byte(`{"abi":`+testArtifactABI+`,"bytecode":{"object":"0x6080",
+			"linkReferences":{"src/Math.sol":{"Math":[{"start":1,"length":20}]}}}}`), "")
+		require.NoError(t, err)
+		require.Equal(t, "6080", artifact.Bytecode)
+		require.Equal(t, LinkReferences{"src/Math.sol": {"Math": {{Start: 1, Length: 20}}}}, artifact.LinkReferences)
+
+		artifact, err = ParseArtifact([]byte(`{"abi":`+testArtifactABI+`,"bytecode":{"object":"0x6080"},
+			"deployedBytecode":{"object":"0x6040","immutableReferences":{"7":[{"start":3,"length":32}]}}}`), "")
+		require.NoError(t, err)
+		require.Equal(t, "6040", artifact.DeployedBytecode)
+		require.Equal(t, map[string][]LinkReference{"7": {{Start: 3, Length: 32}}}, artifact.ImmutableReferences)
+	})
+	t.Run("solc combined json", func(t *testing.T) {
+		data := []byte(`{"contracts":{
+			"Token.sol:Token":{"abi":` + testArtifactABI + `,"bin":"6080","bin-runtime":"6040"},
+			"Token.sol:Other":{"abi":"[]","bin":"6040"}
+		},"version":"0.8.24"}`)
+		artifact, err := ParseArtifact(data, "Token")
+		require.NoError(t, err)
+		require.Equal(t, "Token.sol:Token", artifact.Name)
+		require.Equal(t, "6080", artifact.Bytecode)
+		require.Equal(t, "6040", artifact.DeployedBytecode)
+		require.Len(t, artifact.ABI.Constructor.Inputs, 1)
+		// ABI as JSON encoded string (older solc versions)
+		artifact, err = ParseArtifact(data, "Token.sol:Other")
+		require.NoError(t, err)
+		require.Equal(t, "6040", artifact.Bytecode)
+
+		_, err = ParseArtifact(data, "")
+		require.EqualError(t, err, "artifact contains multiple contracts (Token.sol:Other, Token.sol:Token), contract name must be specified")
+		_, err = ParseArtifact(data, "Missing")
+		require.EqualError(t, err, `contract "Missing" not found in artifact`)
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := ParseArtifact([]byte(`{"abi":[],"bytecode":"0x"}`), "")
+		require.EqualError(t, err, "artifact does not contain bytecode, the contract may be abstract or an interface")
+		_, err = ParseArtifact([]byte(`{"bytecode":"0x6080"}`), "")
+		require.EqualError(t, err, "artifact does not contain ABI")
+		_, err = ParseArtifact([]byte(`[]`), "")
+		require.ErrorContains(t, err, "decoding artifact")
+	})
+	t.Run("load from file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "Token.json")
+		require.NoError(t, os.WriteFile(filename, []byte(`{"abi":[],"bytecode":"0x6080"}`), 0600))
+		artifact, err := LoadArtifact(filename, "")
+		require.NoError(t, err)
+		require.Equal(t, "6080", artifact.Bytecode)
+	})
+}
+
+func TestLinkBytecode(t *testing.T) {
+	libAddr := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	addrHex := hex.EncodeToString(libAddr.Bytes())
+	hash := hex.EncodeToString(crypto.Keccak256([]byte("contracts/Math.sol:Math")))
+	placeholder := "__$" + hash[:34] + "$__"
+	legacyPlaceholder := "__Math__________________________________"
+	require.Len(t, legacyPlaceholder, 40)
+
+	t.Run("no placeholders", func(t *testing.T) {
+		code, err := LinkBytecode("0x6080", nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x60, 0x80}, code)
+	})
+	t.Run("link references", func(t *testing.T) {
+		linkRefs := LinkReferences{"contracts/Math.sol": {"Math": {{Start: 1, Length: 20}, {Start: 23, Length: 20}}}}
+		bytecode := "60" + placeholder + "6040" + placeholder
+		expected := "60" + addrHex + "6040" + addrHex
+		for _, name := range []string{"Math", "contracts/Math.sol:Math"} {
+			code, err := LinkBytecode(bytecode, linkRefs, map[string]common.Address{name: libAddr})
+			require.NoError(t, err)
+			require.Equal(t, expected, hex.EncodeToString(code))
+		}
+		_, err := LinkBytecode(bytecode, linkRefs, nil)
+		require.EqualError(t, err, "unresolved library link placeholder(s): contracts/Math.sol:Math")
+		_, err = LinkBytecode("60"+placeholder, LinkReferences{"contracts/Math.sol": {"Math": {{Start: 10, Length: 20}}}}, map[string]common.Address{"Math": libAddr})
+		require.EqualError(t, err, "invalid link reference of library contracts/Math.sol:Math at offset 10")
+	})
+	t.Run("hashed placeholder", func(t *testing.T) {
+		code, err := LinkBytecode("60"+placeholder+"40", nil, map[string]common.Address{"contracts/Math.sol:Math": libAddr})
+		require.NoError(t, err)
+		require.Equal(t, "60"+addrHex+"40", hex.EncodeToString(code))
+		// library name without source file can't be matched to the hash
+		_, err = LinkBytecode("60"+placeholder+"40", nil, map[string]common.Address{"Math": libAddr})
+		require.EqualError(t, err, "unresolved library link placeholder(s): "+placeholder)
+	})
+	t.Run("legacy placeholder", func(t *testing.T) {
+		for _, name := range []string{"Math", "contracts/Math.sol:Math"} {
+			code, err := LinkBytecode("60"+legacyPlaceholder+"40", nil, map[string]common.Address{name: libAddr})
+			require.NoError(t, err)
+			require.Equal(t, "60"+addrHex+"40", hex.EncodeToString(code))
+		}
+	})
+	t.Run("unused library", func(t *testing.T) {
+		_, err := LinkBytecode("6080", nil, map[string]common.Address{"Math": libAddr})
+		require.EqualError(t, err, `library "Math" is not referenced by the bytecode`)
+	})
+	t.Run("invalid hex", func(t *testing.T) {
+		_, err := LinkBytecode("60x0", nil, nil)
+		require.ErrorContains(t, err, "hex decode error")
+	})
+}
+
+func TestPackConstructorArgs(t *testing.T) {
+	artifact, err := ParseArtifact([]byte(`{"abi":`+testArtifactABI+`,"bytecode":"0x6080"}`), "")
+	require.NoError(t, err)
+	data, err := PackConstructorArgs(artifact.ABI, []string{"0x10"})
+	require.NoError(t, err)
+	require.Equal(t, common.LeftPadBytes([]byte{0x10}, 32), data)
+
+	_, err = PackConstructorArgs(artifact.ABI, nil)
+	require.EqualError(t, err, "constructor: expected 1 argument(s), got 0")
+	data, err = PackConstructorArgs(nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, data)
+	_, err = PackConstructorArgs(nil, []string{"1"})
+	require.EqualError(t, err, "contract ABI is required to encode constructor arguments")
+}
@@ -0,0 +1,248 @@
+package evm
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
+)
+
+type (
+	// TxBatch sends many transactions of one account without waiting for the previous transaction
+	// to be confirmed. The nonces are assigned locally starting from the next nonce of the account
+	// (which follows the pending transactions of the wallet, see PostEvmTx), all the transactions
+	// are posted back-to-back and then confirmed together.
+	//
+	// A transaction which is rejected by the node or which times out does not consume its nonce,
+	// so none of the transactions after it can be executed. These are not sent (or are reported as
+	// not executed) and SendTx can be called again to resend them with the nonces re-assigned.
+	// A transaction which is executed but fails (eg is reverted) consumes its nonce and does not
+	// affect the transactions after it.
+	TxBatch struct {
+		w             *Wallet
+		accountNumber uint64
+		txs           []*BatchTx
+		log           *slog.Logger
+	}
+
+	// BatchTx is a transaction in the TxBatch.
+	BatchTx struct {
+		Attrs *evm.TxAttributes
+		// GasEstimate is the estimate the gas limit of the transaction was taken from (optional),
+		// the simulation of the transaction reuses it
+		GasEstimate *GasEstimate
+		TxHash      []byte
+		Transaction *types.TransactionOrder
+		// Result is the execution result of the confirmed transaction, nil when the
+		// transaction is not confirmed
+		Result *evmclient.Result
+		// Err is the reason why the transaction was not executed, nil when the
+		// transaction is confirmed or not sent yet
+		Err error
+		// cost is the maximum cost of the transaction, reserved while it is pending
+		cost *big.Int
+	}
+)
+
+// NewTxBatch creates an empty transaction batch of the account.
+func (w *Wallet) NewTxBatch(accountNumber uint64, log *slog.Logger) *TxBatch {
+	return &TxBatch{
+		w:             w,
+		accountNumber: accountNumber,
+		log:           log,
+	}
+}
+
+// Add adds the transaction to the batch, the sender and the nonce are assigned when the batch is sent.
+func (b *TxBatch) Add(attrs *evm.TxAttributes) *BatchTx {
+	tx := &BatchTx{Attrs: attrs}
+	b.txs = append(b.txs, tx)
+	return tx
+}
+
+// Transactions returns the transactions of the batch in the order of the nonces.
+func (b *TxBatch) Transactions() []*BatchTx {
+	return b.txs
+}
+
+// Confirmed returns true when the transaction was executed, successfully or not.
+func (tx *BatchTx) Confirmed() bool {
+	return tx.Result != nil
+}
+
+/*
+SendTx signs and sends the transactions of the batch which are not sent yet or were not executed
+(BatchTx.Err is set). When "confirmTx" is true the confirmation of the sent transactions is waited
+for. Error is returned when any of the transactions was not executed, the reason is set in BatchTx.Err.
+Execution failures of the confirmed transactions are not errors, see BatchTx.Result.
+
+With the WithSimulation option all the transactions are simulated against the current state
+before any of them is sent and nothing is sent when a simulation fails, so the option must not
+be used when the transactions depend on the previous transactions of the batch. WithGasEstimate
+option is ignored, see BatchTx.GasEstimate.
+*/
+func (b *TxBatch) SendTx(ctx context.Context, confirmTx bool, opts ...SendOption) error {
+	var pending []*BatchTx
+	for _, tx := range b.txs {
+		if !tx.Confirmed() && (tx.Transaction == nil || tx.Err != nil) {
+			tx.TxHash, tx.Transaction, tx.Err = nil, nil, nil
+			pending = append(pending, tx)
+		}
+	}
+	if len(pending) == 0 {
+		return errors.New("no transactions to send")
+	}
+	o := newSendOptions(opts)
+	for i, tx := range b.txs {
+		// only the pending transactions are simulated, these were reset above
+		if tx.Transaction != nil {
+			continue
+		}
+		if err := o.runSimulation(ctx, b.w, b.accountNumber, tx.Attrs, tx.GasEstimate); err != nil {
+			return fmt.Errorf("transaction #%d: %w", i+1, err)
+		}
+	}
+	if err := b.createTxs(ctx, pending); err != nil {
+		return err
+	}
+	for i, tx := range pending {
+		if _, err := b.w.client.SendTransaction(ctx, tx.Transaction); err != nil {
+			tx.Err = fmt.Errorf("evm post tx failed: %w", err)
+			b.skip(pending[i+1:], tx)
+			break
+		}
+		b.w.setPendingTx(common.BytesToAddress(tx.Attrs.From), tx.Attrs.Nonce, tx.Transaction.Timeout(), tx.cost)
+		b.log.DebugContext(ctx, fmt.Sprintf("Tx sent: hash=%X, nonce=%d", tx.TxHash, tx.Attrs.Nonce))
+	}
+	if confirmTx {
+		if err := b.confirmTxs(ctx, pending); err != nil {
+			return err
+		}
+	}
+	return batchError(pending)
+}
+
+// createTxs assigns the nonces to the transactions and signs them.
+func (b *TxBatch) createTxs(ctx context.Context, txs []*BatchTx) error {
+	acc, from, err := b.w.accountKey(b.accountNumber)
+	if err != nil {
+		return err
+	}
+	roundInfo, err := b.w.client.GetRoundInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("evm current round number read failed: %w", err)
+	}
+	nonce, reserved, err := b.w.accountNonce(ctx, from, roundInfo.RoundNumber)
+	if err != nil {
+		return err
+	}
+	// the fee credit must cover all the transactions
+	attrs := make([]*evm.TxAttributes, len(txs))
+	for i, tx := range txs {
+		attrs[i] = tx.Attrs
+	}
+	costs, err := b.w.verifyFeeCreditBalance(ctx, from, reserved, attrs...)
+	if err != nil {
+		return err
+	}
+	timeout := roundInfo.RoundNumber + txTimeoutBlockCount
+	for i, tx := range txs {
+		tx.cost = costs[i]
+		if tx.Transaction, err = b.w.newSignedTx(acc, from, tx.Attrs, nonce+uint64(i), timeout); err != nil {
+			return err
+		}
+		if tx.TxHash, err = tx.Transaction.Hash(crypto.SHA256); err != nil {
+			return fmt.Errorf("failed to hash transaction: %w", err)
+		}
+	}
+	return nil
+}
+
+// confirmTxs waits until the sent transactions are confirmed or time out.
+func (b *TxBatch) confirmTxs(ctx context.Context, txs []*BatchTx) error {
+	b.log.InfoContext(ctx, "Confirming submitted transactions")
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("confirming transactions interrupted: %w", err)
+		}
+		roundInfo, err := b.w.client.GetRoundInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read latest round from evm node: %w", err)
+		}
+		unconfirmed := false
+		for _, tx := range txs {
+			if tx.Confirmed() || tx.Err != nil {
+				continue
+			}
+			// the proof is fetched also after the timeout round, the transaction may have
+			// been executed in the last rounds before the timeout
+			proof, err := b.w.client.GetTransactionProof(ctx, tx.TxHash)
+			if err != nil {
+				return err
+			}
+			if proof != nil {
+				if tx.Result, err = newTxResult(proof, tx.TxHash); err != nil {
+					return err
+				}
+				b.log.DebugContext(ctx, fmt.Sprintf("Tx confirmed: hash=%X, nonce=%d, success=%t", tx.TxHash, tx.Attrs.Nonce, tx.Result.Success))
+			}
+			unconfirmed = unconfirmed || !tx.Confirmed()
+		}
+		if !unconfirmed {
+			b.log.InfoContext(ctx, "All transactions confirmed")
+			return nil
+		}
+		if roundInfo.RoundNumber > b.maxTimeout(txs) {
+			b.log.InfoContext(ctx, fmt.Sprintf("Tx confirmation timeout is reached: round=%d", roundInfo.RoundNumber))
+			for i, tx := range txs {
+				if !tx.Confirmed() && tx.Err == nil {
+					tx.Err = fmt.Errorf("confirmation timeout evm round %d, tx timeout round %d", roundInfo.RoundNumber, tx.Transaction.Timeout())
+					b.skip(txs[i+1:], tx)
+					break
+				}
+			}
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// skip marks the transactions after the failed one as not executed, they can't be
+// executed because of the nonce gap.
+func (b *TxBatch) skip(txs []*BatchTx, failed *BatchTx) {
+	for _, tx := range txs {
+		if !tx.Confirmed() && tx.Err == nil {
+			tx.Err = fmt.Errorf("not executed, transaction with nonce %d was not executed", failed.Attrs.Nonce)
+		}
+	}
+}
+
+func (b *TxBatch) maxTimeout(txs []*BatchTx) uint64 {
+	var timeout uint64
+	for _, tx := range txs {
+		timeout = max(timeout, tx.Transaction.Timeout())
+	}
+	return timeout
+}
+
+func batchError(txs []*BatchTx) error {
+	failed := 0
+	for _, tx := range txs {
+		if tx.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transaction(s) were not executed", failed, len(txs))
+	}
+	return nil
+}
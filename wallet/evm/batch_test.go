@@ -0,0 +1,261 @@
+package evm
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	test "github.com/alphabill-org/alphabill-wallet/internal/testutils"
+	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
+)
+
+// batchClientMock executes the transactions in the order of the nonces, a transaction
+// with unexpected nonce is not executed
+type batchClientMock struct {
+	*evmClientMock
+	round uint64
+	nonce uint64
+	// sent - the transactions in the order they were sent
+	sent []*types.TransactionOrder
+	// rejectNonce - the transaction with the nonce is rejected when sent
+	rejectNonce map[uint64]bool
+	// dropNonce - the transaction with the nonce is accepted but never executed
+	dropNonce map[uint64]bool
+	// revertNonce - the execution of the transaction with the nonce fails
+	revertNonce map[uint64]bool
+	// lateProofNonce - the proof of the executed transaction with the nonce is available
+	// only after the timeout round of the transaction
+	lateProofNonce map[uint64]bool
+	executed       map[string]*types.TxRecordProof
+}
+
+func newBatchClientMock(nonce uint64) *batchClientMock {
+	return &batchClientMock{
+		evmClientMock:  newClientMock(),
+		round:          3,
+		nonce:          nonce,
+		rejectNonce:    map[uint64]bool{},
+		dropNonce:      map[uint64]bool{},
+		revertNonce:    map[uint64]bool{},
+		lateProofNonce: map[uint64]bool{},
+		executed:       map[string]*types.TxRecordProof{},
+	}
+}
+
+func (m *batchClientMock) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
+	// every call "produces" a new round where the sent transactions are executed
+	m.round++
+	for _, tx := range m.sent {
+		attrs := &evm.TxAttributes{}
+		if err := tx.UnmarshalAttributes(attrs); err != nil {
+			return nil, err
+		}
+		txHash, err := tx.Hash(crypto.SHA256)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := m.executed[string(txHash)]; ok || attrs.Nonce != m.nonce || m.dropNonce[attrs.Nonce] || tx.Timeout() < m.round {
+			continue
+		}
+		status := types.TxStatusSuccessful
+		if m.revertNonce[attrs.Nonce] {
+			status = types.TxStatusFailed
+		}
+		details, err := types.Cbor.Marshal(evm.ProcessingDetails{})
+		if err != nil {
+			return nil, err
+		}
+		txBytes, err := tx.MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		m.executed[string(txHash)] = &types.TxRecordProof{
+			TxRecord: &types.TransactionRecord{
+				TransactionOrder: txBytes,
+				ServerMetadata:   &types.ServerMetadata{ActualFee: 1, SuccessIndicator: status, ProcessingDetails: details},
+			},
+			TxProof: &types.TxProof{},
+		}
+		m.nonce++
+	}
+	return &sdktypes.RoundInfo{RoundNumber: m.round}, nil
+}
+
+func (m *batchClientMock) SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+	attrs := &evm.TxAttributes{}
+	if err := tx.UnmarshalAttributes(attrs); err != nil {
+		return nil, err
+	}
+	if m.rejectNonce[attrs.Nonce] {
+		return nil, errors.New("tx rejected")
+	}
+	m.sent = append(m.sent, tx)
+	return tx.Hash(crypto.SHA256)
+}
+
+func (m *batchClientMock) GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+	proof := m.executed[string(txHash)]
+	if proof == nil {
+		return nil, nil
+	}
+	tx, err := proof.GetTransactionOrderV1()
+	if err != nil {
+		return nil, err
+	}
+	attrs := &evm.TxAttributes{}
+	if err := tx.UnmarshalAttributes(attrs); err != nil {
+		return nil, err
+	}
+	if m.lateProofNonce[attrs.Nonce] && m.round <= tx.Timeout() {
+		return nil, nil
+	}
+	return proof, nil
+}
+
+func (m *batchClientMock) GetTransactionCount(ctx context.Context, ethAddr []byte) (uint64, error) {
+	return m.nonce, nil
+}
+
+func createBatchTestWallet(t *testing.T, clientMock *batchClientMock) *TxBatch {
+	w, _ := createTestWallet(t)
+	w.client = clientMock
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	batch := w.NewTxBatch(1, logger.New(t))
+	for i := 0; i < 3; i++ {
+		batch.Add(&evm.TxAttributes{To: test.RandomBytes(20), Gas: 100})
+	}
+	return batch
+}
+
+func batchNonces(batch *TxBatch) []uint64 {
+	var nonces []uint64
+	for _, tx := range batch.Transactions() {
+		nonces = append(nonces, tx.Attrs.Nonce)
+	}
+	return nonces
+}
+
+func TestTxBatch_SendTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all confirmed", func(t *testing.T) {
+		clientMock := newBatchClientMock(5)
+		clientMock.revertNonce[6] = true
+		batch := createBatchTestWallet(t, clientMock)
+		require.NoError(t, batch.SendTx(ctx, true))
+		require.Equal(t, []uint64{5, 6, 7}, batchNonces(batch))
+		require.Len(t, clientMock.sent, 3)
+		for i, tx := range batch.Transactions() {
+			require.True(t, tx.Confirmed())
+			require.NoError(t, tx.Err)
+			// reverted transaction consumes the nonce
+			require.Equal(t, i != 1, tx.Result.Success)
+		}
+		require.EqualError(t, batch.SendTx(ctx, true), "no transactions to send")
+	})
+
+	t.Run("rejected tx and the txs after it are not sent", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		clientMock.rejectNonce[1] = true
+		batch := createBatchTestWallet(t, clientMock)
+		require.EqualError(t, batch.SendTx(ctx, true), "2 of 3 transaction(s) were not executed")
+		require.Len(t, clientMock.sent, 1)
+		txs := batch.Transactions()
+		require.True(t, txs[0].Confirmed())
+		require.ErrorContains(t, txs[1].Err, "evm post tx failed: tx rejected")
+		require.EqualError(t, txs[2].Err, "not executed, transaction with nonce 1 was not executed")
+
+		// resend, nonces are re-assigned from the current nonce of the account
+		delete(clientMock.rejectNonce, 1)
+		require.NoError(t, batch.SendTx(ctx, true))
+		require.Equal(t, []uint64{0, 1, 2}, batchNonces(batch))
+		require.Len(t, clientMock.sent, 3)
+		for _, tx := range txs {
+			require.True(t, tx.Confirmed())
+		}
+	})
+
+	t.Run("timed out tx blocks the txs after it", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		clientMock.dropNonce[0] = true
+		batch := createBatchTestWallet(t, clientMock)
+		require.EqualError(t, batch.SendTx(ctx, true), "3 of 3 transaction(s) were not executed")
+		txs := batch.Transactions()
+		require.ErrorContains(t, txs[0].Err, "confirmation timeout evm round 15, tx timeout round 14")
+		require.EqualError(t, txs[1].Err, "not executed, transaction with nonce 0 was not executed")
+		require.EqualError(t, txs[2].Err, "not executed, transaction with nonce 0 was not executed")
+	})
+
+	t.Run("tx confirmed after the timeout round", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		clientMock.lateProofNonce[0] = true
+		batch := createBatchTestWallet(t, clientMock)
+		require.NoError(t, batch.SendTx(ctx, true))
+		for _, tx := range batch.Transactions() {
+			require.True(t, tx.Confirmed())
+			require.NoError(t, tx.Err)
+		}
+	})
+
+	t.Run("no wait", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		batch := createBatchTestWallet(t, clientMock)
+		require.NoError(t, batch.SendTx(ctx, false))
+		require.Len(t, clientMock.sent, 3)
+		for _, tx := range batch.Transactions() {
+			require.Len(t, tx.TxHash, 32)
+			require.False(t, tx.Confirmed())
+		}
+		// sent transactions are not sent again
+		require.EqualError(t, batch.SendTx(ctx, false), "no transactions to send")
+	})
+
+	t.Run("nonces follow the pending transactions", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		// the sent transactions stay pending
+		clientMock.dropNonce[0] = true
+		batch := createBatchTestWallet(t, clientMock)
+		require.NoError(t, batch.SendTx(ctx, false))
+		require.Equal(t, []uint64{0, 1, 2}, batchNonces(batch))
+
+		next := batch.w.NewTxBatch(1, logger.New(t))
+		next.Add(&evm.TxAttributes{To: test.RandomBytes(20), Gas: 100})
+		require.NoError(t, next.SendTx(ctx, false))
+		require.Equal(t, []uint64{3}, batchNonces(next))
+
+		attrs := &evm.TxAttributes{To: test.RandomBytes(20), Gas: 100}
+		_, err := batch.w.PostEvmTx(ctx, 1, attrs)
+		require.NoError(t, err)
+		require.EqualValues(t, 4, attrs.Nonce)
+	})
+
+	t.Run("pending transactions reserve fee credit", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		clientMock.dropNonce[0] = true
+		// 3 * 100 gas * 100 wei of the pending transactions + 800 gas * 100 wei > 100000 wei
+		batch := createBatchTestWallet(t, clientMock)
+		require.NoError(t, batch.SendTx(ctx, false))
+		next := batch.w.NewTxBatch(1, logger.New(t))
+		next.Add(&evm.TxAttributes{To: test.RandomBytes(20), Gas: 800})
+		require.EqualError(t, next.SendTx(ctx, false), "insufficient fee credit balance for transaction")
+		next = batch.w.NewTxBatch(1, logger.New(t))
+		next.Add(&evm.TxAttributes{To: test.RandomBytes(20), Gas: 700})
+		require.NoError(t, next.SendTx(ctx, false))
+	})
+
+	t.Run("insufficient fee credit", func(t *testing.T) {
+		clientMock := newBatchClientMock(0)
+		// 3 * 100 gas * 400 wei > 100000 wei
+		clientMock.gasPrice = "400"
+		batch := createBatchTestWallet(t, clientMock)
+		require.EqualError(t, batch.SendTx(ctx, true), "insufficient fee credit balance for transaction")
+		require.Empty(t, clientMock.sent)
+	})
+}
@@ -1,11 +1,45 @@
 package client
 
 import (
+	"math/big"
+
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
 )
 
-type Result struct {
-	Success   bool
-	ActualFee uint64
-	Details   *evm.ProcessingDetails
-}
+type (
+	Result struct {
+		Success   bool
+		ActualFee uint64
+		Details   *evm.ProcessingDetails
+		// TxHash is the hash of the transaction order, empty for calls
+		TxHash []byte
+		// Revert is the decoded revert reason of the failed execution, nil when the
+		// execution succeeded or the return data is not a known error
+		Revert *RevertReason
+		// Proof is the proof of the confirmed transaction, nil for calls
+		Proof *types.TxRecordProof
+	}
+
+	// RevertReason is the decoded return data of the reverted EVM execution.
+	RevertReason struct {
+		// Name is "Error" for revert with a reason string, "Panic" for failed
+		// assertion or the name of the custom error.
+		Name string
+		// Signature is the error signature, e.g. "Error(string)".
+		Signature string
+		// Message is the reason string of Error(string) or the description of the panic code.
+		Message string
+		// PanicCode is the code of Panic(uint256), nil for other errors.
+		PanicCode *big.Int
+		// Args are the decoded arguments of the custom error.
+		Args []RevertArg
+	}
+
+	// RevertArg is a decoded argument of the custom error.
+	RevertArg struct {
+		Name  string
+		Type  string
+		Value any
+	}
+)
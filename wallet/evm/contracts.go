@@ -0,0 +1,149 @@
+package evm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	ContractBookFileName = "evmcontracts.db"
+	maxContractNameLen   = 64
+)
+
+var (
+	bucketContracts = []byte("contracts")
+
+	contractNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.\-]*$`)
+
+	ErrContractNotFound = errors.New("contract not found")
+	ErrContractExists   = errors.New("contract already exists")
+)
+
+type (
+	// Contract is a named smart contract in the ContractBook.
+	Contract struct {
+		Name    string         `json:"name"`
+		Address common.Address `json:"address"`
+		// ABIPath is the path of the contract ABI file (plain ABI JSON or compiler artifact), optional.
+		ABIPath string `json:"abiPath,omitempty"`
+	}
+
+	// ContractBook is the registry of the named smart contracts of the wallet.
+	ContractBook struct {
+		db *bolt.DB
+	}
+)
+
+// NewContractBook opens the contract book in the wallet home directory, the database is created if it does not exist.
+func NewContractBook(dir string) (*ContractBook, error) {
+	dbFile := filepath.Join(dir, ContractBookFileName)
+	if err := os.MkdirAll(dir, 0700); err != nil { // ensure dirs exist
+		return nil, err
+	}
+	db, err := bolt.Open(dbFile, 0600, &bolt.Options{Timeout: 3 * time.Second}) // -rw-------
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt DB %s: %w", dbFile, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketContracts)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create db buckets: %w", err)
+	}
+	return &ContractBook{db: db}, nil
+}
+
+// ValidateContractName verifies that the name can be used as the contract alias, the name must not
+// be confused with the hex encoded address.
+func ValidateContractName(name string) error {
+	if len(name) == 0 || len(name) > maxContractNameLen {
+		return fmt.Errorf("invalid contract name %q, name must be 1 to %d characters long", name, maxContractNameLen)
+	}
+	if common.IsHexAddress(name) {
+		return fmt.Errorf("invalid contract name %q, name must not be an address", name)
+	}
+	if !contractNameRe.MatchString(name) {
+		return fmt.Errorf("invalid contract name %q, name must start with a letter or underscore and contain only letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+// Add adds the contract to the book, existing contract with the same name is replaced only when "replace" is true.
+func (b *ContractBook) Add(c *Contract, replace bool) error {
+	if err := ValidateContractName(c.Name); err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(bucketContracts)
+		if !replace && bkt.Get([]byte(c.Name)) != nil {
+			return fmt.Errorf("%w: %s", ErrContractExists, c.Name)
+		}
+		val, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("failed to serialize contract to json: %w", err)
+		}
+		return bkt.Put([]byte(c.Name), val)
+	})
+}
+
+// Get returns the contract by name, ErrContractNotFound is returned when there is no contract with the name.
+func (b *ContractBook) Get(name string) (*Contract, error) {
+	var c *Contract
+	err := b.db.View(func(tx *bolt.Tx) error {
+		val := tx.Bucket(bucketContracts).Get([]byte(name))
+		if val == nil {
+			return fmt.Errorf("%w: %s", ErrContractNotFound, name)
+		}
+		if err := json.Unmarshal(val, &c); err != nil {
+			return fmt.Errorf("failed to deserialize contract json: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// List returns all the contracts ordered by name.
+func (b *ContractBook) List() ([]*Contract, error) {
+	var res []*Contract
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketContracts).ForEach(func(k, v []byte) error {
+			var c *Contract
+			if err := json.Unmarshal(v, &c); err != nil {
+				return fmt.Errorf("failed to deserialize contract json: %w", err)
+			}
+			res = append(res, c)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Remove removes the contract by name, ErrContractNotFound is returned when there is no contract with the name.
+func (b *ContractBook) Remove(name string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(bucketContracts)
+		if bkt.Get([]byte(name)) == nil {
+			return fmt.Errorf("%w: %s", ErrContractNotFound, name)
+		}
+		return bkt.Delete([]byte(name))
+	})
+}
+
+func (b *ContractBook) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,63 @@
+package evm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractBook(t *testing.T) {
+	dir := t.TempDir()
+	book, err := NewContractBook(dir)
+	require.NoError(t, err)
+
+	contracts, err := book.List()
+	require.NoError(t, err)
+	require.Empty(t, contracts)
+	_, err = book.Get("usdc")
+	require.ErrorIs(t, err, ErrContractNotFound)
+
+	usdc := &Contract{Name: "usdc", Address: common.HexToAddress("0x01"), ABIPath: "/tmp/usdc.json"}
+	require.NoError(t, book.Add(usdc, false))
+	require.NoError(t, book.Add(&Contract{Name: "Token_v2.1-test", Address: common.HexToAddress("0x02")}, false))
+	require.ErrorIs(t, book.Add(&Contract{Name: "usdc", Address: common.HexToAddress("0x03")}, false), ErrContractExists)
+
+	c, err := book.Get("usdc")
+	require.NoError(t, err)
+	require.Equal(t, usdc, c)
+
+	// replace
+	usdc.Address = common.HexToAddress("0x03")
+	require.NoError(t, book.Add(usdc, true))
+	contracts, err = book.List()
+	require.NoError(t, err)
+	require.Len(t, contracts, 2)
+	require.Equal(t, "Token_v2.1-test", contracts[0].Name)
+	require.Equal(t, usdc, contracts[1])
+
+	// the book is persisted
+	require.NoError(t, book.Close())
+	book, err = NewContractBook(dir)
+	require.NoError(t, err)
+	defer book.Close()
+	c, err = book.Get("usdc")
+	require.NoError(t, err)
+	require.Equal(t, usdc, c)
+
+	require.NoError(t, book.Remove("usdc"))
+	require.ErrorIs(t, book.Remove("usdc"), ErrContractNotFound)
+	_, err = book.Get("usdc")
+	require.EqualError(t, err, "contract not found: usdc")
+}
+
+func TestValidateContractName(t *testing.T) {
+	require.NoError(t, ValidateContractName("usdc"))
+	require.NoError(t, ValidateContractName("_my.token-2"))
+	require.NoError(t, ValidateContractName("cafe"))
+	require.EqualError(t, ValidateContractName(""), `invalid contract name "", name must be 1 to 64 characters long`)
+	require.ErrorContains(t, ValidateContractName("1token"), "name must start with a letter or underscore")
+	require.ErrorContains(t, ValidateContractName("my token"), "name must start with a letter or underscore")
+	require.EqualError(t, ValidateContractName("deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"),
+		`invalid contract name "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", name must not be an address`)
+}
@@ -0,0 +1,126 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// erc20ABIJSON is the standard ERC-20 token interface (EIP-20) including the optional metadata methods.
+const erc20ABIJSON = `[
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"totalSupply","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"function","name":"allowance","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"outputs":[{"name":"remaining","type":"uint256"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"success","type":"bool"}]},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"success","type":"bool"}]},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"success","type":"bool"}]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"spender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+// ERC20ABI is the ABI of the standard ERC-20 token interface.
+var ERC20ABI = mustParseABI(erc20ABIJSON)
+
+// ERC20Info is the metadata of an ERC-20 token.
+type ERC20Info struct {
+	Address common.Address
+	// Name and Symbol are optional in the standard, empty when the token does not implement them.
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	TotalSupply *big.Int
+}
+
+// ERC20Info reads the token metadata, the call is made from the address of the account.
+func (w *Wallet) ERC20Info(ctx context.Context, accountNumber uint64, token common.Address) (*ERC20Info, error) {
+	info := &ERC20Info{Address: token}
+	var err error
+	if info.Decimals, err = callMethodOutput[uint8](ctx, w, accountNumber, token, ERC20ABI, "decimals"); err != nil {
+		return nil, err
+	}
+	if info.TotalSupply, err = callMethodOutput[*big.Int](ctx, w, accountNumber, token, ERC20ABI, "totalSupply"); err != nil {
+		return nil, err
+	}
+	// optional metadata, errors are ignored
+	info.Name, _ = callMethodOutput[string](ctx, w, accountNumber, token, ERC20ABI, "name")
+	info.Symbol, _ = callMethodOutput[string](ctx, w, accountNumber, token, ERC20ABI, "symbol")
+	return info, nil
+}
+
+// ERC20BalanceOf returns the token balance of the owner in the smallest token units.
+func (w *Wallet) ERC20BalanceOf(ctx context.Context, accountNumber uint64, token, owner common.Address) (*big.Int, error) {
+	return callMethodOutput[*big.Int](ctx, w, accountNumber, token, ERC20ABI, "balanceOf", owner)
+}
+
+// ERC20Allowance returns the amount of tokens the spender is allowed to transfer on behalf of the owner.
+func (w *Wallet) ERC20Allowance(ctx context.Context, accountNumber uint64, token, owner, spender common.Address) (*big.Int, error) {
+	return callMethodOutput[*big.Int](ctx, w, accountNumber, token, ERC20ABI, "allowance", owner, spender)
+}
+
+/*
+CallMethod executes the read-only method of the contract without creating a transaction and
+returns the decoded output. The call is made from the address of the account. Returns error
+when the execution fails or the contract returns no data (eg the address is not a contract).
+*/
+func (w *Wallet) CallMethod(ctx context.Context, accountNumber uint64, contract common.Address, contractABI *abi.ABI, method string, args ...any) ([]any, error) {
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s call: %w", method, err)
+	}
+	res, err := w.EvmCall(ctx, accountNumber, &evm.CallEVMRequest{
+		To:    contract.Bytes(),
+		Data:  data,
+		Value: big.NewInt(0),
+		Gas:   EstimateGasCap,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s call failed: %w", method, err)
+	}
+	if !res.Success {
+		if revert := DecodeRevertReason(contractABI, res.Details.ReturnData); revert != nil {
+			return nil, fmt.Errorf("%s call failed: %s", method, FormatRevertReason(revert))
+		}
+		return nil, fmt.Errorf("%s call failed: %s", method, res.Details.ErrorDetails)
+	}
+	if len(res.Details.ReturnData) == 0 {
+		return nil, fmt.Errorf("%s call returned no data, %s may not be a contract", method, contract)
+	}
+	values, err := contractABI.Unpack(method, res.Details.ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("decoding output of %s: %w", method, err)
+	}
+	return values, nil
+}
+
+// callMethodOutput calls the method with single output value of type T.
+func callMethodOutput[T any](ctx context.Context, w *Wallet, accountNumber uint64, contract common.Address, contractABI *abi.ABI, method string, args ...any) (T, error) {
+	var res T
+	values, err := w.CallMethod(ctx, accountNumber, contract, contractABI, method, args...)
+	if err != nil {
+		return res, err
+	}
+	if len(values) != 1 {
+		return res, fmt.Errorf("%s returned %d values, expected 1", method, len(values))
+	}
+	res, ok := values[0].(T)
+	if !ok {
+		return res, errors.New("unexpected output type of " + method)
+	}
+	return res, nil
+}
+
+func mustParseABI(data string) *abi.ABI {
+	contractABI, err := ParseABI([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return contractABI
+}
@@ -0,0 +1,99 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testContractCalls returns call handler which simulates contract with the given ABI,
+// methods missing from "outputs" revert.
+func testContractCalls(t *testing.T, contractABI *abi.ABI, outputs map[string][]any) func(*evm.CallEVMRequest) *evm.ProcessingDetails {
+	return func(req *evm.CallEVMRequest) *evm.ProcessingDetails {
+		for name, m := range contractABI.Methods {
+			if !bytes.Equal(req.Data[:4], m.ID) {
+				continue
+			}
+			out, ok := outputs[name]
+			if !ok {
+				return &evm.ProcessingDetails{ErrorDetails: "evm runtime error: execution reverted"}
+			}
+			data, err := m.Outputs.Pack(out...)
+			require.NoError(t, err)
+			return &evm.ProcessingDetails{ReturnData: data}
+		}
+		return &evm.ProcessingDetails{ErrorDetails: "evm runtime error: execution reverted"}
+	}
+}
+
+func TestWallet_ERC20(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	ctx := context.Background()
+	token := common.HexToAddress("0x3443919fcbc4476b4f332fd5df6a82fe88dbf521")
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	spender := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	t.Run("info", func(t *testing.T) {
+		clientMock.callFn = testContractCalls(t, ERC20ABI, map[string][]any{
+			"name":        {"Test Token"},
+			"symbol":      {"TT"},
+			"decimals":    {uint8(6)},
+			"totalSupply": {big.NewInt(1_000_000)},
+		})
+		info, err := w.ERC20Info(ctx, 1, token)
+		require.NoError(t, err)
+		require.Equal(t, &ERC20Info{Address: token, Name: "Test Token", Symbol: "TT", Decimals: 6, TotalSupply: big.NewInt(1_000_000)}, info)
+	})
+	t.Run("info without optional metadata", func(t *testing.T) {
+		clientMock.callFn = testContractCalls(t, ERC20ABI, map[string][]any{
+			"decimals":    {uint8(18)},
+			"totalSupply": {big.NewInt(5)},
+		})
+		info, err := w.ERC20Info(ctx, 1, token)
+		require.NoError(t, err)
+		require.Empty(t, info.Name)
+		require.Empty(t, info.Symbol)
+		require.EqualValues(t, 18, info.Decimals)
+	})
+	t.Run("not a token", func(t *testing.T) {
+		clientMock.callFn = testContractCalls(t, ERC20ABI, nil)
+		_, err := w.ERC20Info(ctx, 1, token)
+		require.EqualError(t, err, "decimals call failed: evm runtime error: execution reverted")
+		// account without code returns nothing
+		clientMock.callFn = func(*evm.CallEVMRequest) *evm.ProcessingDetails { return &evm.ProcessingDetails{} }
+		_, err = w.ERC20BalanceOf(ctx, 1, token, owner)
+		require.EqualError(t, err, "balanceOf call returned no data, "+token.Hex()+" may not be a contract")
+	})
+	t.Run("balance and allowance", func(t *testing.T) {
+		var req *evm.CallEVMRequest
+		tokenFn := testContractCalls(t, ERC20ABI, map[string][]any{
+			"balanceOf": {big.NewInt(100)},
+			"allowance": {big.NewInt(7)},
+		})
+		clientMock.callFn = func(r *evm.CallEVMRequest) *evm.ProcessingDetails {
+			req = r
+			return tokenFn(r)
+		}
+		balance, err := w.ERC20BalanceOf(ctx, 1, token, owner)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, balance.Int64())
+		require.Equal(t, token.Bytes(), req.To)
+		data, err := ERC20ABI.Pack("balanceOf", owner)
+		require.NoError(t, err)
+		require.Equal(t, data, req.Data)
+
+		allowance, err := w.ERC20Allowance(ctx, 1, token, owner, spender)
+		require.NoError(t, err)
+		require.EqualValues(t, 7, allowance.Int64())
+		data, err = ERC20ABI.Pack("allowance", owner, spender)
+		require.NoError(t, err)
+		require.Equal(t, data, req.Data)
+	})
+}
@@ -0,0 +1,551 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+// ClientVersion is returned by web3_clientVersion.
+const ClientVersion = "abwallet-evm-gateway/v0.1.0"
+
+type (
+	// EvmClient is the subset of the EVM partition client used by the API.
+	EvmClient interface {
+		Call(ctx context.Context, callAttr *evm.CallEVMRequest) (*evm.ProcessingDetails, error)
+		GetTransactionCount(ctx context.Context, ethAddr []byte) (uint64, error)
+		GetBalance(ctx context.Context, ethAddr []byte) (*big.Int, error)
+		GetGasPrice(ctx context.Context) (*big.Int, error)
+		GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error)
+		GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error)
+	}
+
+	// Wallet signs and sends the transactions using the wallet account keys.
+	Wallet interface {
+		GetAccountAddresses() ([]common.Address, error)
+		PostEvmTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes, opts ...evmwallet.SendOption) ([]byte, error)
+		EstimateGasFrom(ctx context.Context, from common.Address, attrs *evm.CallEVMRequest, marginPercent uint64) (*evmwallet.GasEstimate, error)
+	}
+
+	// EthAPI implements the "eth" namespace of the Ethereum JSON-RPC API on top of the
+	// Alphabill EVM partition. Alphabill rounds are presented as Ethereum blocks, only
+	// the state of the latest round is available.
+	EthAPI struct {
+		client    EvmClient
+		wallet    Wallet
+		chainID   *big.Int
+		gasMargin uint64
+
+		// accountLocks serialize the transactions of the accounts, the nonce of
+		// the transaction depends on the previous transaction of the account
+		mu           sync.Mutex
+		accountLocks map[common.Address]*sync.Mutex
+	}
+
+	// TransactionArgs are the arguments of eth_call, eth_estimateGas and eth_sendTransaction.
+	// Gas price and nonce are accepted but ignored, the wallet uses the current values.
+	TransactionArgs struct {
+		From     *common.Address `json:"from"`
+		To       *common.Address `json:"to"`
+		Gas      *hexutil.Uint64 `json:"gas"`
+		GasPrice *hexutil.Big    `json:"gasPrice"`
+		Value    *hexutil.Big    `json:"value"`
+		Nonce    *hexutil.Uint64 `json:"nonce"`
+		Data     *hexutil.Bytes  `json:"data"`
+		Input    *hexutil.Bytes  `json:"input"`
+	}
+
+	netAPI struct {
+		chainID *big.Int
+	}
+
+	web3API struct{}
+
+	// revertError is returned by eth_call when the execution fails,
+	// the return data of the failed call is returned as error data.
+	revertError struct {
+		reason string
+		data   []byte
+	}
+)
+
+/*
+NewServer creates JSON-RPC server which serves the "eth", "net" and "web3" namespaces.
+Transactions are signed with the wallet account keys without asking for confirmation,
+"gasMargin" is the safety margin (in percent) added to the estimated gas when the
+transaction does not specify the gas limit.
+*/
+func NewServer(client EvmClient, wallet Wallet, chainID uint64, gasMargin uint64) (*rpc.Server, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", NewEthAPI(client, wallet, chainID, gasMargin)); err != nil {
+		return nil, fmt.Errorf("registering eth API: %w", err)
+	}
+	if err := server.RegisterName("net", &netAPI{chainID: new(big.Int).SetUint64(chainID)}); err != nil {
+		return nil, fmt.Errorf("registering net API: %w", err)
+	}
+	if err := server.RegisterName("web3", &web3API{}); err != nil {
+		return nil, fmt.Errorf("registering web3 API: %w", err)
+	}
+	return server, nil
+}
+
+func NewEthAPI(client EvmClient, wallet Wallet, chainID uint64, gasMargin uint64) *EthAPI {
+	return &EthAPI{
+		client:       client,
+		wallet:       wallet,
+		chainID:      new(big.Int).SetUint64(chainID),
+		gasMargin:    gasMargin,
+		accountLocks: make(map[common.Address]*sync.Mutex),
+	}
+}
+
+// ChainId returns the chain ID used by the EVM, eth_chainId.
+func (api *EthAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.chainID)
+}
+
+// BlockNumber returns the latest round number, eth_blockNumber.
+func (api *EthAPI) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	roundInfo, err := api.client.GetRoundInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(roundInfo.RoundNumber), nil
+}
+
+// Syncing always returns false, the gateway is not a node, eth_syncing.
+func (api *EthAPI) Syncing() bool {
+	return false
+}
+
+// Accounts returns the addresses of the wallet accounts, eth_accounts. The watch-only
+// accounts of public key hash have no address and are not listed.
+func (api *EthAPI) Accounts() ([]common.Address, error) {
+	addresses, err := api.wallet.GetAccountAddresses()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(addresses, func(addr common.Address) bool { return addr == (common.Address{}) }), nil
+}
+
+// GasPrice returns the gas price in wei, eth_gasPrice.
+func (api *EthAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	gasPrice, err := api.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(gasPrice), nil
+}
+
+// MaxPriorityFeePerGas returns 0, the partition does not support priority fees, eth_maxPriorityFeePerGas.
+func (api *EthAPI) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(0))
+}
+
+// GetBalance returns the balance of the address in wei, eth_getBalance.
+func (api *EthAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	if err := checkLatestBlock(blockNrOrHash); err != nil {
+		return nil, err
+	}
+	balance, err := api.client.GetBalance(ctx, address.Bytes())
+	if err != nil {
+		if errors.Is(err, sdktypes.ErrNotFound) {
+			return (*hexutil.Big)(big.NewInt(0)), nil
+		}
+		return nil, err
+	}
+	return (*hexutil.Big)(balance), nil
+}
+
+// GetTransactionCount returns the nonce of the address, eth_getTransactionCount.
+func (api *EthAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if err := checkLatestBlock(blockNrOrHash); err != nil {
+		return 0, err
+	}
+	nonce, err := api.client.GetTransactionCount(ctx, address.Bytes())
+	if err != nil {
+		if errors.Is(err, sdktypes.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return hexutil.Uint64(nonce), nil
+}
+
+// Call executes the call without creating a transaction, eth_call.
+func (api *EthAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if err := checkLatestBlock(blockNrOrHash); err != nil {
+		return nil, err
+	}
+	req := args.callRequest()
+	if req.Gas == 0 {
+		req.Gas = evmwallet.EstimateGasCap
+	}
+	details, err := api.client.Call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if details.ErrorDetails != "" {
+		reason := details.ErrorDetails
+		if r := evmwallet.DecodeRevertReason(nil, details.ReturnData); r != nil {
+			reason = "execution reverted: " + r.Message
+		}
+		return nil, &revertError{reason: reason, data: details.ReturnData}
+	}
+	return details.ReturnData, nil
+}
+
+// EstimateGas returns the lowest gas limit with which the call succeeds, eth_estimateGas.
+func (api *EthAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	if err := checkLatestBlock(blockNrOrHash); err != nil {
+		return 0, err
+	}
+	req := args.callRequest()
+	estimate, err := api.wallet.EstimateGasFrom(ctx, common.BytesToAddress(req.From), req, 0)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(estimate.GasUsed), nil
+}
+
+/*
+SendTransaction signs the transaction with the key of the "from" account and sends it,
+eth_sendTransaction. The call returns the hash of the transaction without waiting for the
+execution, the result is available with eth_getTransactionReceipt. The nonce is assigned by
+the wallet and the gas limit is estimated when not given. The transaction is simulated first
+and it is not sent when the execution fails.
+*/
+func (api *EthAPI) SendTransaction(ctx context.Context, args TransactionArgs) (common.Hash, error) {
+	if args.From == nil {
+		return common.Hash{}, errors.New("from address is required")
+	}
+	accountNumber, err := api.accountNumber(*args.From)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	req := args.callRequest()
+	opts := []evmwallet.SendOption{evmwallet.WithSimulation(nil)}
+	if req.Gas == 0 {
+		estimate, err := api.wallet.EstimateGasFrom(ctx, *args.From, req, api.gasMargin)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		req.Gas = estimate.GasLimit
+		opts = append(opts, evmwallet.WithGasEstimate(estimate))
+	}
+	unlock := api.lockAccount(*args.From)
+	defer unlock()
+	txHash, err := api.wallet.PostEvmTx(ctx, accountNumber, &evm.TxAttributes{
+		To:    req.To,
+		Data:  req.Data,
+		Value: req.Value,
+		Gas:   req.Gas,
+	}, opts...)
+	if err != nil {
+		if errors.Is(err, sdktypes.ErrNotFound) {
+			return common.Hash{}, fmt.Errorf("no evm fee credit for account %s", args.From)
+		}
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(txHash), nil
+}
+
+// lockAccount locks the account until the returned function is called.
+func (api *EthAPI) lockAccount(addr common.Address) func() {
+	api.mu.Lock()
+	l, ok := api.accountLocks[addr]
+	if !ok {
+		l = &sync.Mutex{}
+		api.accountLocks[addr] = l
+	}
+	api.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// SendRawTransaction is not supported, Alphabill transactions are not RLP encoded
+// Ethereum transactions, eth_sendRawTransaction.
+func (api *EthAPI) SendRawTransaction(hexutil.Bytes) (common.Hash, error) {
+	return common.Hash{}, errors.New("raw Ethereum transactions are not supported, use eth_sendTransaction")
+}
+
+// GetTransactionReceipt returns the receipt of the executed transaction or nil when
+// the transaction is not found, eth_getTransactionReceipt.
+func (api *EthAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]any, error) {
+	tx, err := api.loadTx(ctx, hash)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	gasPrice, err := api.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := hexutil.Uint64(0)
+	if tx.proof.TxRecord.IsSuccessful() {
+		status = 1
+	}
+	// the fee is charged for the used gas
+	gasUsed := tx.attrs.Gas
+	if gasPrice.Sign() > 0 {
+		fee := util.TemaToWei(tx.proof.TxRecord.GetActualFee())
+		gasUsed = new(big.Int).Div(fee, gasPrice).Uint64()
+	}
+	var contractAddr *common.Address
+	if len(tx.attrs.To) == 0 && tx.details.ContractAddr != (common.Address{}) {
+		contractAddr = &tx.details.ContractAddr
+	}
+	logs := make([]*ethtypes.Log, len(tx.details.Logs))
+	for i, l := range tx.details.Logs {
+		logs[i] = &ethtypes.Log{
+			Address:     l.Address,
+			Topics:      l.Topics,
+			Data:        l.Data,
+			BlockNumber: tx.round,
+			TxHash:      hash,
+			BlockHash:   tx.blockHash,
+			Index:       uint(i),
+		}
+	}
+	return map[string]any{
+		"transactionHash":   hash,
+		"transactionIndex":  hexutil.Uint64(0),
+		"blockHash":         tx.blockHash,
+		"blockNumber":       hexutil.Uint64(tx.round),
+		"from":              common.BytesToAddress(tx.attrs.From),
+		"to":                tx.to(),
+		"cumulativeGasUsed": hexutil.Uint64(gasUsed),
+		"gasUsed":           hexutil.Uint64(gasUsed),
+		"effectiveGasPrice": (*hexutil.Big)(gasPrice),
+		"contractAddress":   contractAddr,
+		"logs":              logs,
+		"logsBloom":         ethtypes.BytesToBloom(ethtypes.LogsBloom(logs)),
+		"status":            status,
+		"type":              hexutil.Uint64(ethtypes.LegacyTxType),
+	}, nil
+}
+
+// GetTransactionByHash returns the executed transaction or nil when the transaction
+// is not found, eth_getTransactionByHash. Alphabill transactions do not carry an
+// Ethereum signature, the signature fields are zero.
+func (api *EthAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]any, error) {
+	tx, err := api.loadTx(ctx, hash)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	gasPrice, err := api.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value := tx.attrs.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	zero := (*hexutil.Big)(big.NewInt(0))
+	return map[string]any{
+		"hash":             hash,
+		"blockHash":        tx.blockHash,
+		"blockNumber":      hexutil.Uint64(tx.round),
+		"transactionIndex": hexutil.Uint64(0),
+		"from":             common.BytesToAddress(tx.attrs.From),
+		"to":               tx.to(),
+		"nonce":            hexutil.Uint64(tx.attrs.Nonce),
+		"gas":              hexutil.Uint64(tx.attrs.Gas),
+		"gasPrice":         (*hexutil.Big)(gasPrice),
+		"value":            (*hexutil.Big)(value),
+		"input":            hexutil.Bytes(tx.attrs.Data),
+		"chainId":          (*hexutil.Big)(api.chainID),
+		"type":             hexutil.Uint64(ethtypes.LegacyTxType),
+		"v":                zero,
+		"r":                zero,
+		"s":                zero,
+	}, nil
+}
+
+// GetBlockByNumber returns the header fields of the latest block, eth_getBlockByNumber.
+// Rounds are not Ethereum blocks, the hashes are zero and the block never lists transactions.
+func (api *EthAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, _ bool) (map[string]any, error) {
+	roundInfo, err := api.client.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	round := roundInfo.RoundNumber
+	if number >= 0 {
+		if uint64(number) > round {
+			return nil, nil
+		}
+		round = uint64(number)
+	}
+	return map[string]any{
+		"number":           hexutil.Uint64(round),
+		"hash":             common.Hash{},
+		"parentHash":       common.Hash{},
+		"timestamp":        hexutil.Uint64(0),
+		"gasLimit":         hexutil.Uint64(evmwallet.EstimateGasCap),
+		"gasUsed":          hexutil.Uint64(0),
+		"miner":            common.Address{},
+		"difficulty":       (*hexutil.Big)(big.NewInt(0)),
+		"extraData":        hexutil.Bytes{},
+		"logsBloom":        ethtypes.Bloom{},
+		"transactions":     []common.Hash{},
+		"uncles":           []common.Hash{},
+		"sha3Uncles":       ethtypes.EmptyUncleHash,
+		"transactionsRoot": ethtypes.EmptyTxsHash,
+		"receiptsRoot":     ethtypes.EmptyReceiptsHash,
+		"stateRoot":        common.Hash{},
+	}, nil
+}
+
+func (api *EthAPI) gasPrice(ctx context.Context) (*big.Int, error) {
+	return api.client.GetGasPrice(ctx)
+}
+
+// accountNumber returns the number of the wallet account with the address.
+func (api *EthAPI) accountNumber(address common.Address) (uint64, error) {
+	addresses, err := api.wallet.GetAccountAddresses()
+	if err != nil {
+		return 0, err
+	}
+	for i, addr := range addresses {
+		if addr == address {
+			return uint64(i) + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown account %s", address)
+}
+
+type executedTx struct {
+	proof     *types.TxRecordProof
+	attrs     *evm.TxAttributes
+	details   *evm.ProcessingDetails
+	round     uint64
+	blockHash common.Hash
+}
+
+func (tx *executedTx) to() *common.Address {
+	if len(tx.attrs.To) == 0 {
+		return nil
+	}
+	to := common.BytesToAddress(tx.attrs.To)
+	return &to
+}
+
+// loadTx reads the transaction and its execution result from the transaction proof,
+// returns nil when the transaction is not found.
+func (api *EthAPI) loadTx(ctx context.Context, hash common.Hash) (*executedTx, error) {
+	proof, err := api.client.GetTransactionProof(ctx, hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil || proof.TxRecord == nil {
+		return nil, nil
+	}
+	txo, err := proof.TxRecord.GetTransactionOrderV1()
+	if err != nil {
+		return nil, fmt.Errorf("decoding transaction order: %w", err)
+	}
+	if txo.Type != evm.TransactionTypeEVMCall {
+		return nil, fmt.Errorf("transaction %s is not an EVM transaction", hash)
+	}
+	tx := &executedTx{proof: proof, attrs: &evm.TxAttributes{}, details: &evm.ProcessingDetails{}}
+	if err := txo.UnmarshalAttributes(tx.attrs); err != nil {
+		return nil, fmt.Errorf("decoding transaction attributes: %w", err)
+	}
+	if err := proof.TxRecord.UnmarshalProcessingDetails(tx.details); err != nil {
+		return nil, fmt.Errorf("decoding evm execution result: %w", err)
+	}
+	uc, err := proof.TxProof.GetUC()
+	if err != nil {
+		return nil, fmt.Errorf("reading unicity certificate: %w", err)
+	}
+	tx.round = uc.GetRoundNumber()
+	if uc.InputRecord != nil {
+		tx.blockHash = common.BytesToHash(uc.InputRecord.BlockHash)
+	}
+	return tx, nil
+}
+
+// callRequest converts the arguments to EVM call request.
+func (args *TransactionArgs) callRequest() *evm.CallEVMRequest {
+	req := &evm.CallEVMRequest{Value: big.NewInt(0)}
+	if args.From != nil {
+		req.From = args.From.Bytes()
+	} else {
+		req.From = common.Address{}.Bytes()
+	}
+	if args.To != nil {
+		req.To = args.To.Bytes()
+	}
+	if args.Gas != nil {
+		req.Gas = uint64(*args.Gas)
+	}
+	if args.Value != nil {
+		req.Value = args.Value.ToInt()
+	}
+	// "input" is the newer name of the "data" field
+	if args.Input != nil {
+		req.Data = *args.Input
+	} else if args.Data != nil {
+		req.Data = *args.Data
+	}
+	return req
+}
+
+// checkLatestBlock returns error when the block parameter refers to other than latest block.
+func checkLatestBlock(blockNrOrHash *rpc.BlockNumberOrHash) error {
+	if blockNrOrHash == nil {
+		return nil
+	}
+	if number, ok := blockNrOrHash.Number(); ok && number < 0 {
+		// latest, pending, safe and finalized all refer to the latest state
+		return nil
+	}
+	return errors.New("historical state is not supported, only the latest block can be queried")
+}
+
+// Version returns the chain ID as decimal string, net_version.
+func (api *netAPI) Version() string {
+	return api.chainID.String()
+}
+
+// Listening returns true, net_listening.
+func (api *netAPI) Listening() bool {
+	return true
+}
+
+// ClientVersion returns the gateway version, web3_clientVersion.
+func (api *web3API) ClientVersion() string {
+	return ClientVersion
+}
+
+func (e *revertError) Error() string {
+	if strings.Contains(e.reason, "execution reverted") {
+		return e.reason
+	}
+	return "execution reverted: " + e.reason
+}
+
+// ErrorCode returns the error code used by Ethereum nodes for reverted calls.
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData returns the hex encoded return data of the reverted call.
+func (e *revertError) ErrorData() any {
+	return hexutil.Encode(e.data)
+}
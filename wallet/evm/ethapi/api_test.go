@@ -0,0 +1,302 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	evmwallet "github.com/alphabill-org/alphabill-wallet/wallet/evm"
+)
+
+var (
+	testAccount  = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	testContract = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	testTxHash   = common.HexToHash("0x0101010101010101010101010101010101010101010101010101010101010101")
+)
+
+type clientMock struct {
+	balance  string
+	nonce    uint64
+	gasPrice string
+	round    uint64
+	callReq  *evm.CallEVMRequest
+	callResp *evm.ProcessingDetails
+	proofs   map[common.Hash]*types.TxRecordProof
+}
+
+func (c *clientMock) Call(_ context.Context, callAttr *evm.CallEVMRequest) (*evm.ProcessingDetails, error) {
+	c.callReq = callAttr
+	return c.callResp, nil
+}
+
+func (c *clientMock) GetTransactionCount(_ context.Context, ethAddr []byte) (uint64, error) {
+	if common.BytesToAddress(ethAddr) != testAccount {
+		return 0, sdktypes.ErrNotFound
+	}
+	return c.nonce, nil
+}
+
+func (c *clientMock) GetBalance(_ context.Context, ethAddr []byte) (*big.Int, error) {
+	if common.BytesToAddress(ethAddr) != testAccount {
+		return nil, sdktypes.ErrNotFound
+	}
+	balance, _ := new(big.Int).SetString(c.balance, 10)
+	return balance, nil
+}
+
+func (c *clientMock) GetGasPrice(context.Context) (*big.Int, error) {
+	gasPrice, _ := new(big.Int).SetString(c.gasPrice, 10)
+	return gasPrice, nil
+}
+
+func (c *clientMock) GetRoundInfo(context.Context) (*sdktypes.RoundInfo, error) {
+	return &sdktypes.RoundInfo{RoundNumber: c.round}, nil
+}
+
+func (c *clientMock) GetTransactionProof(_ context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
+	return c.proofs[common.BytesToHash(txHash)], nil
+}
+
+type walletMock struct {
+	sentAccount uint64
+	sentAttrs   *evm.TxAttributes
+	sentOpts    []evmwallet.SendOption
+	estimateReq *evm.CallEVMRequest
+	estimateErr error
+	// postDelay - the post takes the time, posting counts the posts in progress
+	postDelay  time.Duration
+	posting    atomic.Int32
+	maxPosting atomic.Int32
+}
+
+func (w *walletMock) GetAccountAddresses() ([]common.Address, error) {
+	return []common.Address{common.HexToAddress("0x01"), testAccount}, nil
+}
+
+func (w *walletMock) PostEvmTx(_ context.Context, accountNumber uint64, attrs *evm.TxAttributes, opts ...evmwallet.SendOption) ([]byte, error) {
+	if n := w.posting.Add(1); n > w.maxPosting.Load() {
+		w.maxPosting.Store(n)
+	}
+	defer w.posting.Add(-1)
+	time.Sleep(w.postDelay)
+	w.sentAccount = accountNumber
+	w.sentAttrs = attrs
+	w.sentOpts = opts
+	return testTxHash.Bytes(), nil
+}
+
+func (w *walletMock) EstimateGasFrom(_ context.Context, _ common.Address, attrs *evm.CallEVMRequest, marginPercent uint64) (*evmwallet.GasEstimate, error) {
+	w.estimateReq = attrs
+	if w.estimateErr != nil {
+		return nil, w.estimateErr
+	}
+	return &evmwallet.GasEstimate{GasUsed: 30000, GasLimit: 30000 + 30000*marginPercent/100, GasPrice: big.NewInt(1)}, nil
+}
+
+func newTestClient(t *testing.T, client *clientMock, wallet *walletMock) *rpc.Client {
+	server, err := NewServer(client, wallet, 3, 20)
+	require.NoError(t, err)
+	rpcClient := rpc.DialInProc(server)
+	t.Cleanup(func() {
+		rpcClient.Close()
+		server.Stop()
+	})
+	return rpcClient
+}
+
+func TestEthAPI_chainInfo(t *testing.T) {
+	c := newTestClient(t, &clientMock{round: 42, gasPrice: "210000000"}, &walletMock{})
+	var res any
+	require.NoError(t, c.Call(&res, "eth_chainId"))
+	require.Equal(t, "0x3", res)
+	require.NoError(t, c.Call(&res, "net_version"))
+	require.Equal(t, "3", res)
+	require.NoError(t, c.Call(&res, "web3_clientVersion"))
+	require.Equal(t, ClientVersion, res)
+	require.NoError(t, c.Call(&res, "eth_blockNumber"))
+	require.Equal(t, "0x2a", res)
+	require.NoError(t, c.Call(&res, "eth_gasPrice"))
+	require.Equal(t, "0xc845880", res)
+	require.NoError(t, c.Call(&res, "eth_syncing"))
+	require.Equal(t, false, res)
+
+	var accounts []common.Address
+	require.NoError(t, c.Call(&accounts, "eth_accounts"))
+	require.Equal(t, []common.Address{common.HexToAddress("0x01"), testAccount}, accounts)
+
+	var block map[string]any
+	require.NoError(t, c.Call(&block, "eth_getBlockByNumber", "latest", false))
+	require.Equal(t, "0x2a", block["number"])
+	require.NoError(t, c.Call(&block, "eth_getBlockByNumber", "0x2b", false))
+	require.Nil(t, block)
+}
+
+func TestEthAPI_accountState(t *testing.T) {
+	c := newTestClient(t, &clientMock{balance: "1000000000000000000", nonce: 5}, &walletMock{})
+	var balance hexutil.Big
+	require.NoError(t, c.Call(&balance, "eth_getBalance", testAccount, "latest"))
+	require.EqualValues(t, "1000000000000000000", balance.ToInt().String())
+	// unknown account has zero balance and nonce
+	require.NoError(t, c.Call(&balance, "eth_getBalance", testContract, "latest"))
+	require.Zero(t, balance.ToInt().Sign())
+
+	var nonce hexutil.Uint64
+	require.NoError(t, c.Call(&nonce, "eth_getTransactionCount", testAccount, "pending"))
+	require.EqualValues(t, 5, nonce)
+	require.NoError(t, c.Call(&nonce, "eth_getTransactionCount", testContract))
+	require.EqualValues(t, 0, nonce)
+
+	err := c.Call(&nonce, "eth_getTransactionCount", testAccount, "0x1")
+	require.ErrorContains(t, err, "historical state is not supported")
+}
+
+func TestEthAPI_Call(t *testing.T) {
+	client := &clientMock{callResp: &evm.ProcessingDetails{ReturnData: []byte{1, 2}}}
+	c := newTestClient(t, client, &walletMock{})
+	args := map[string]any{"to": testContract, "input": "0x70a08231", "value": "0x10"}
+	var res hexutil.Bytes
+	require.NoError(t, c.Call(&res, "eth_call", args, "latest"))
+	require.Equal(t, hexutil.Bytes{1, 2}, res)
+	require.Equal(t, testContract.Bytes(), client.callReq.To)
+	require.Equal(t, common.Address{}.Bytes(), client.callReq.From)
+	require.Equal(t, []byte{0x70, 0xa0, 0x82, 0x31}, client.callReq.Data)
+	require.EqualValues(t, 16, client.callReq.Value.Uint64())
+	require.EqualValues(t, evmwallet.EstimateGasCap, client.callReq.Gas)
+
+	client.callResp = &evm.ProcessingDetails{ErrorDetails: "execution reverted", ReturnData: []byte{8, 0xc3, 0x79, 0xa0}}
+	err := c.Call(&res, "eth_call", args)
+	require.EqualError(t, err, "execution reverted")
+	var dataErr rpc.DataError
+	require.True(t, errors.As(err, &dataErr))
+	require.Equal(t, "0x08c379a0", dataErr.ErrorData())
+}
+
+func TestEthAPI_EstimateGas(t *testing.T) {
+	wallet := &walletMock{}
+	c := newTestClient(t, &clientMock{}, wallet)
+	var gas hexutil.Uint64
+	require.NoError(t, c.Call(&gas, "eth_estimateGas", map[string]any{"from": testAccount, "to": testContract, "data": "0x01"}))
+	require.EqualValues(t, 30000, gas)
+	require.Equal(t, []byte{1}, wallet.estimateReq.Data)
+
+	wallet.estimateErr = errors.New("execution fails with gas limit 50000000: reverted")
+	require.EqualError(t, c.Call(&gas, "eth_estimateGas", map[string]any{"to": testContract}), wallet.estimateErr.Error())
+}
+
+func TestEthAPI_SendTransaction(t *testing.T) {
+	wallet := &walletMock{}
+	c := newTestClient(t, &clientMock{}, wallet)
+	var hash common.Hash
+	require.NoError(t, c.Call(&hash, "eth_sendTransaction", map[string]any{"from": testAccount, "to": testContract, "data": "0x01", "value": "0x5"}))
+	require.Equal(t, testTxHash, hash)
+	require.EqualValues(t, 2, wallet.sentAccount)
+	require.Equal(t, testContract.Bytes(), wallet.sentAttrs.To)
+	require.Equal(t, []byte{1}, wallet.sentAttrs.Data)
+	require.EqualValues(t, 5, wallet.sentAttrs.Value.Uint64())
+	// estimated gas with 20% margin, the simulation reuses the estimate
+	require.EqualValues(t, 36000, wallet.sentAttrs.Gas)
+	require.Len(t, wallet.sentOpts, 2)
+
+	require.NoError(t, c.Call(&hash, "eth_sendTransaction", map[string]any{"from": testAccount, "data": "0x6080", "gas": "0x5208"}))
+	require.Nil(t, wallet.sentAttrs.To)
+	require.EqualValues(t, 21000, wallet.sentAttrs.Gas)
+	require.Len(t, wallet.sentOpts, 1)
+
+	err := c.Call(&hash, "eth_sendTransaction", map[string]any{"to": testContract})
+	require.EqualError(t, err, "from address is required")
+	err = c.Call(&hash, "eth_sendTransaction", map[string]any{"from": testContract})
+	require.EqualError(t, err, "unknown account "+testContract.Hex())
+	err = c.Call(&hash, "eth_sendRawTransaction", "0x01")
+	require.ErrorContains(t, err, "raw Ethereum transactions are not supported")
+}
+
+func TestEthAPI_SendTransaction_serialized(t *testing.T) {
+	wallet := &walletMock{postDelay: 10 * time.Millisecond}
+	c := newTestClient(t, &clientMock{}, wallet)
+	errs := make(chan error, 5)
+	for range cap(errs) {
+		go func() {
+			var hash common.Hash
+			errs <- c.Call(&hash, "eth_sendTransaction", map[string]any{"from": testAccount, "to": testContract, "gas": "0x5208"})
+		}()
+	}
+	for range cap(errs) {
+		require.NoError(t, <-errs)
+	}
+	// the transactions of the account are posted one at a time
+	require.EqualValues(t, 1, wallet.maxPosting.Load())
+}
+
+func TestEthAPI_GetTransactionReceipt(t *testing.T) {
+	attrs := &evm.TxAttributes{From: testAccount.Bytes(), Data: []byte{0x60, 0x80}, Value: big.NewInt(0), Gas: 100000, Nonce: 7}
+	logs := []*evm.LogEntry{{Address: testContract, Topics: []common.Hash{common.HexToHash("0xaa")}, Data: []byte{1}}}
+	client := &clientMock{
+		gasPrice: "210000000",
+		proofs:   map[common.Hash]*types.TxRecordProof{testTxHash: newTestTxProof(t, attrs, &evm.ProcessingDetails{ContractAddr: testContract, Logs: logs}, 10, 12)},
+	}
+	c := newTestClient(t, client, &walletMock{})
+
+	var receipt map[string]any
+	require.NoError(t, c.Call(&receipt, "eth_getTransactionReceipt", testTxHash))
+	require.Equal(t, testTxHash.Hex(), receipt["transactionHash"])
+	require.Equal(t, "0xc", receipt["blockNumber"])
+	require.Equal(t, common.BytesToHash([]byte{0x0b}).Hex(), receipt["blockHash"])
+	require.Equal(t, "0x1", receipt["status"])
+	require.Equal(t, testAccount.Hex(), receipt["from"])
+	require.Nil(t, receipt["to"])
+	require.Equal(t, testContract.Hex(), receipt["contractAddress"])
+	// fee 10 tema = 10^11 wei, gas price 2.1*10^8 wei
+	require.Equal(t, "0x1dc", receipt["gasUsed"])
+	require.Len(t, receipt["logs"], 1)
+	log := receipt["logs"].([]any)[0].(map[string]any)
+	require.Equal(t, testContract.Hex(), log["address"])
+	require.Equal(t, "0xc", log["blockNumber"])
+	require.NotEqual(t, "0x"+common.Bytes2Hex(make([]byte, 256)), receipt["logsBloom"])
+
+	var tx map[string]any
+	require.NoError(t, c.Call(&tx, "eth_getTransactionByHash", testTxHash))
+	require.Equal(t, "0x7", tx["nonce"])
+	require.Equal(t, "0x186a0", tx["gas"])
+	require.Equal(t, "0x6080", tx["input"])
+	require.Equal(t, "0x3", tx["chainId"])
+
+	// unknown transaction
+	require.NoError(t, c.Call(&receipt, "eth_getTransactionReceipt", common.HexToHash("0x02")))
+	require.Nil(t, receipt)
+	require.NoError(t, c.Call(&tx, "eth_getTransactionByHash", common.HexToHash("0x02")))
+	require.Nil(t, tx)
+}
+
+func newTestTxProof(t *testing.T, attrs *evm.TxAttributes, details *evm.ProcessingDetails, fee, round uint64) *types.TxRecordProof {
+	txo, err := sdktypes.NewTransactionOrder(types.NetworkLocal, evm.DefaultPartitionID, attrs.From, evm.TransactionTypeEVMCall, attrs)
+	require.NoError(t, err)
+	txBytes, err := txo.MarshalCBOR()
+	require.NoError(t, err)
+	detailBytes, err := types.Cbor.Marshal(details)
+	require.NoError(t, err)
+	uc, err := types.Cbor.Marshal(&types.UnicityCertificate{
+		Version:     1,
+		InputRecord: &types.InputRecord{Version: 1, RoundNumber: round, BlockHash: []byte{0x0b}},
+	})
+	require.NoError(t, err)
+	return &types.TxRecordProof{
+		TxRecord: &types.TransactionRecord{
+			Version:          1,
+			TransactionOrder: txBytes,
+			ServerMetadata:   &types.ServerMetadata{ActualFee: fee, SuccessIndicator: types.TxStatusSuccessful, ProcessingDetails: detailBytes},
+		},
+		TxProof: &types.TxProof{Version: 1, UnicityCertificate: uc},
+	}
+}
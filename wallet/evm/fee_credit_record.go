@@ -10,7 +10,8 @@ import (
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 )
 
-func generateAddress(pubKeyBytes []byte) (common.Address, error) {
+// AddressFromPublicKey returns the EVM address of the compressed secp256k1 public key.
+func AddressFromPublicKey(pubKeyBytes []byte) (common.Address, error) {
 	if pubKeyBytes == nil {
 		return common.Address{}, fmt.Errorf("public key bytes is nil")
 	}
@@ -32,6 +33,6 @@ func NewFeeCreditRecordIDFromPublicKey(_ types.ShardID, pubKey []byte, _ uint64)
 	if pubKey == nil {
 		return common.Address{}.Bytes(), nil
 	}
-	addr, _ := generateAddress(pubKey)
+	addr, _ := AddressFromPublicKey(pubKey)
 	return addr.Bytes(), nil
 }
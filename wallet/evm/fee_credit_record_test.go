@@ -25,19 +25,19 @@ func TestFeeCreditRecordIDFromPublicKey(t *testing.T) {
 	require.EqualValues(t, make([]byte, 20), fcr)
 }
 
-func Test_generateAddress(t *testing.T) {
+func TestAddressFromPublicKey(t *testing.T) {
 	// nil, returns empty address
-	addr, err := generateAddress(nil)
+	addr, err := AddressFromPublicKey(nil)
 	require.Error(t, err)
 	require.EqualValues(t, make([]byte, 20), addr)
 	// not an actual public key
 	invalidPubKey, err := hex.DecodeString("276B52B4808893d1e2Affd5310898818E8e7699d")
 	require.NoError(t, err)
-	addr, err = generateAddress(invalidPubKey)
+	addr, err = AddressFromPublicKey(invalidPubKey)
 	require.Error(t, err)
 	require.EqualValues(t, make([]byte, 20), addr)
 	// ok
-	addr, err = generateAddress(pubKey[:])
+	addr, err = AddressFromPublicKey(pubKey[:])
 	require.NoError(t, err)
 	expected, err := hex.DecodeString("276B52B4808893d1e2Affd5310898818E8e7699d")
 	require.NoError(t, err)
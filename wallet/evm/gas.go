@@ -0,0 +1,100 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// EstimateGasCap is the highest gas limit tried when estimating gas.
+	EstimateGasCap = 50000000
+	// TransferGas is the gas used by plain value transfer, the minimum amount of gas any transaction uses.
+	TransferGas = 21000
+)
+
+// GasEstimate is the result of gas estimation.
+type GasEstimate struct {
+	// GasUsed is the lowest gas limit with which the call succeeds.
+	GasUsed uint64
+	// GasLimit is GasUsed with the safety margin added.
+	GasLimit uint64
+	// GasPrice is the current gas price in wei.
+	GasPrice *big.Int
+}
+
+// Cost returns the maximum cost of the transaction in wei (GasLimit * GasPrice).
+func (e *GasEstimate) Cost() *big.Int {
+	return new(big.Int).Mul(e.GasPrice, new(big.Int).SetUint64(e.GasLimit))
+}
+
+/*
+EstimateGas finds the lowest gas limit with which the call succeeds by executing the call
+with different gas limits (binary search between TransferGas and EstimateGasCap). The
+safety margin is added to the result as percentage of the used gas. Call with nil "To"
+address estimates contract deployment.
+*/
+func (w *Wallet) EstimateGas(ctx context.Context, accountNumber uint64, attrs *evm.CallEVMRequest, marginPercent uint64) (*GasEstimate, error) {
+	from, err := w.GetAccountAddress(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return w.EstimateGasFrom(ctx, from, attrs, marginPercent)
+}
+
+// EstimateGasFrom estimates gas of the call made from any address, see EstimateGas.
+func (w *Wallet) EstimateGasFrom(ctx context.Context, from common.Address, attrs *evm.CallEVMRequest, marginPercent uint64) (*GasEstimate, error) {
+	call := func(gas uint64) (*evm.ProcessingDetails, error) {
+		req := *attrs
+		req.From = from.Bytes()
+		req.Gas = gas
+		return w.client.Call(ctx, &req)
+	}
+	hi := uint64(EstimateGasCap)
+	details, err := call(hi)
+	if err != nil {
+		return nil, err
+	}
+	if len(details.ErrorDetails) > 0 {
+		return nil, fmt.Errorf("execution fails with gas limit %d: %s", hi, details.ErrorDetails)
+	}
+	if hi, err = lowestGas(call, hi); err != nil {
+		return nil, err
+	}
+	gasPrice, err := w.GetGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &GasEstimate{
+		GasUsed:  hi,
+		GasLimit: min(hi+hi*marginPercent/100, EstimateGasCap),
+		GasPrice: gasPrice,
+	}, nil
+}
+
+// lowestGas finds the lowest gas limit between TransferGas and "hi" with which the call succeeds
+// (binary search), the call must succeed with the gas limit "hi".
+func lowestGas(call func(gas uint64) (*evm.ProcessingDetails, error), hi uint64) (uint64, error) {
+	lo := uint64(TransferGas - 1)
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		details, err := call(mid)
+		if err != nil {
+			return 0, err
+		}
+		if len(details.ErrorDetails) == 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+// GetGasPrice returns the current gas price in wei.
+func (w *Wallet) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	return w.client.GetGasPrice(ctx)
+}
@@ -0,0 +1,71 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWallet_EstimateGas(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	ctx := context.Background()
+	attrs := &evm.CallEVMRequest{To: []byte{1}, Data: []byte{2}}
+	_, err := w.EstimateGas(ctx, 1, attrs, 10)
+	require.ErrorContains(t, err, "account key read failed: account does not exist")
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	_, err = w.EstimateGas(ctx, 0, attrs, 10)
+	require.ErrorContains(t, err, "invalid account number: 0")
+
+	t.Run("binary search", func(t *testing.T) {
+		calls := 0
+		clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+			calls++
+			require.NotNil(t, callAttr.From)
+			require.Equal(t, attrs.To, callAttr.To)
+			require.Equal(t, attrs.Data, callAttr.Data)
+			if callAttr.Gas < 54321 {
+				return &evm.ProcessingDetails{ErrorDetails: "out of gas"}
+			}
+			return &evm.ProcessingDetails{}
+		}
+		estimate, err := w.EstimateGas(ctx, 1, attrs, 10)
+		require.NoError(t, err)
+		require.EqualValues(t, 54321, estimate.GasUsed)
+		require.EqualValues(t, 54321+5432, estimate.GasLimit)
+		require.Equal(t, big.NewInt(100), estimate.GasPrice)
+		require.Equal(t, big.NewInt(100*(54321+5432)), estimate.Cost())
+		require.Less(t, calls, 30)
+		// original request is not modified
+		require.Nil(t, attrs.From)
+		require.Zero(t, attrs.Gas)
+	})
+	t.Run("margin is capped", func(t *testing.T) {
+		clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+			if callAttr.Gas < EstimateGasCap-1 {
+				return &evm.ProcessingDetails{ErrorDetails: "out of gas"}
+			}
+			return &evm.ProcessingDetails{}
+		}
+		estimate, err := w.EstimateGas(ctx, 1, attrs, 10)
+		require.NoError(t, err)
+		require.EqualValues(t, EstimateGasCap-1, estimate.GasUsed)
+		require.EqualValues(t, EstimateGasCap, estimate.GasLimit)
+	})
+	t.Run("execution fails", func(t *testing.T) {
+		clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+			return &evm.ProcessingDetails{ErrorDetails: "execution reverted"}
+		}
+		_, err := w.EstimateGas(ctx, 1, attrs, 10)
+		require.EqualError(t, err, fmt.Sprintf("execution fails with gas limit %d: execution reverted", EstimateGasCap))
+	})
+	t.Run("client error", func(t *testing.T) {
+		clientMock.SimulateErr = fmt.Errorf("something bad happened")
+		defer func() { clientMock.SimulateErr = nil }()
+		_, err := w.EstimateGas(ctx, 1, attrs, 10)
+		require.ErrorContains(t, err, "something bad happened")
+	})
+}
@@ -0,0 +1,109 @@
+package evm
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"slices"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type (
+	// BlockReader reads partition blocks, returns nil block if the round has no block.
+	BlockReader interface {
+		GetBlock(ctx context.Context, roundNumber uint64) (*types.Block, error)
+	}
+
+	// LogFilter selects EVM logs by round range, contract address and topics.
+	LogFilter struct {
+		FromRound uint64
+		ToRound   uint64
+		// Addresses of the contracts which emitted the log, empty matches any address.
+		Addresses []common.Address
+		// Topics by position, log matches when for every position the topic of the log is one
+		// of the given topics, empty position matches any topic.
+		Topics [][]common.Hash
+	}
+
+	// Log is an EVM log entry together with its location in the block chain.
+	Log struct {
+		*evm.LogEntry
+		RoundNumber uint64
+		TxHash      hex.Bytes
+		// TxIndex is the index of the transaction in the block.
+		TxIndex int
+		// LogIndex is the index of the log in the transaction.
+		LogIndex int
+	}
+)
+
+// Match returns true if the log entry matches the address and topic filters.
+func (f *LogFilter) Match(log *evm.LogEntry) bool {
+	if len(f.Addresses) > 0 && !slices.Contains(f.Addresses, log.Address) {
+		return false
+	}
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range f.Topics {
+		if len(topics) > 0 && !slices.Contains(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ScanLogs reads blocks of rounds from filter.FromRound to filter.ToRound (inclusive) and calls
+"fn" for every log which matches the filter. Only logs of successfully executed EVM transactions
+are returned as logs of failed transactions are reverted.
+*/
+func ScanLogs(ctx context.Context, blocks BlockReader, filter *LogFilter, fn func(*Log) error) error {
+	if filter.FromRound > filter.ToRound {
+		return fmt.Errorf("invalid round range %d - %d", filter.FromRound, filter.ToRound)
+	}
+	for round := filter.FromRound; round <= filter.ToRound; round++ {
+		block, err := blocks.GetBlock(ctx, round)
+		if err != nil {
+			return fmt.Errorf("failed to load block of round %d: %w", round, err)
+		}
+		if block == nil {
+			continue
+		}
+		for txIndex, txr := range block.Transactions {
+			if !txr.IsSuccessful() {
+				continue
+			}
+			txo, err := txr.GetTransactionOrderV1()
+			if err != nil {
+				return fmt.Errorf("failed to decode transaction %d of round %d: %w", txIndex, round, err)
+			}
+			if txo.Type != evm.TransactionTypeEVMCall {
+				continue
+			}
+			var details evm.ProcessingDetails
+			if err := txr.UnmarshalProcessingDetails(&details); err != nil {
+				return fmt.Errorf("failed to decode processing details of transaction %d of round %d: %w", txIndex, round, err)
+			}
+			var txHash []byte
+			for logIndex, l := range details.Logs {
+				if !filter.Match(l) {
+					continue
+				}
+				if txHash == nil {
+					if txHash, err = txo.Hash(crypto.SHA256); err != nil {
+						return fmt.Errorf("failed to hash transaction %d of round %d: %w", txIndex, round, err)
+					}
+				}
+				if err := fn(&Log{LogEntry: l, RoundNumber: round, TxHash: txHash, TxIndex: txIndex, LogIndex: logIndex}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
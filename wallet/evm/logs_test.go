@@ -0,0 +1,119 @@
+package evm
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type blockReaderMock map[uint64]*types.Block
+
+func (m blockReaderMock) GetBlock(ctx context.Context, roundNumber uint64) (*types.Block, error) {
+	if roundNumber == 666 {
+		return nil, errors.New("block not available")
+	}
+	return m[roundNumber], nil
+}
+
+func newTestTxRecord(t *testing.T, txType uint16, status types.TxStatus, logs ...*evm.LogEntry) *types.TransactionRecord {
+	t.Helper()
+	txo := &types.TransactionOrder{Version: 1, Payload: types.Payload{PartitionID: evm.DefaultPartitionID, Type: txType}}
+	txBytes, err := txo.MarshalCBOR()
+	require.NoError(t, err)
+	details, err := types.Cbor.Marshal(evm.ProcessingDetails{Logs: logs})
+	require.NoError(t, err)
+	return &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: txBytes,
+		ServerMetadata:   &types.ServerMetadata{SuccessIndicator: status, ProcessingDetails: details},
+	}
+}
+
+func TestLogFilter_Match(t *testing.T) {
+	addr1 := common.HexToAddress("0x01")
+	addr2 := common.HexToAddress("0x02")
+	topicA := common.HexToHash("0x0a")
+	topicB := common.HexToHash("0x0b")
+	log := &evm.LogEntry{Address: addr1, Topics: []common.Hash{topicA, topicB}}
+
+	require.True(t, (&LogFilter{}).Match(log))
+	require.True(t, (&LogFilter{Addresses: []common.Address{addr2, addr1}}).Match(log))
+	require.False(t, (&LogFilter{Addresses: []common.Address{addr2}}).Match(log))
+	require.True(t, (&LogFilter{Topics: [][]common.Hash{{topicA}}}).Match(log))
+	require.True(t, (&LogFilter{Topics: [][]common.Hash{nil, {topicA, topicB}}}).Match(log))
+	require.False(t, (&LogFilter{Topics: [][]common.Hash{{topicB}}}).Match(log))
+	// filter has more topics than the log
+	require.False(t, (&LogFilter{Topics: [][]common.Hash{nil, nil, nil}}).Match(log))
+}
+
+func TestScanLogs(t *testing.T) {
+	addr1 := common.HexToAddress("0x01")
+	addr2 := common.HexToAddress("0x02")
+	log1 := &evm.LogEntry{Address: addr1, Topics: []common.Hash{common.HexToHash("0x0a")}, Data: []byte{1}}
+	log2 := &evm.LogEntry{Address: addr2, Topics: []common.Hash{common.HexToHash("0x0b")}, Data: []byte{2}}
+	log3 := &evm.LogEntry{Address: addr1, Topics: []common.Hash{common.HexToHash("0x0b")}, Data: []byte{3}}
+	failedLog := &evm.LogEntry{Address: addr1, Data: []byte{4}}
+	blocks := blockReaderMock{
+		2: {Transactions: []*types.TransactionRecord{
+			newTestTxRecord(t, evm.TransactionTypeEVMCall, types.TxStatusSuccessful, log1, log2),
+		}},
+		// round 3 has no block
+		4: {Transactions: []*types.TransactionRecord{
+			newTestTxRecord(t, evm.TransactionTypeEVMCall, types.TxStatusFailed, failedLog),
+			newTestTxRecord(t, 123, types.TxStatusSuccessful),
+			newTestTxRecord(t, evm.TransactionTypeEVMCall, types.TxStatusSuccessful, log3),
+		}},
+	}
+	collect := func(filter *LogFilter) ([]*Log, error) {
+		var res []*Log
+		err := ScanLogs(context.Background(), blocks, filter, func(l *Log) error {
+			res = append(res, l)
+			return nil
+		})
+		return res, err
+	}
+
+	logs, err := collect(&LogFilter{FromRound: 1, ToRound: 5})
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	require.Equal(t, log1, logs[0].LogEntry)
+	require.EqualValues(t, 2, logs[0].RoundNumber)
+	require.Equal(t, 0, logs[0].TxIndex)
+	require.Equal(t, 0, logs[0].LogIndex)
+	require.Equal(t, log2, logs[1].LogEntry)
+	require.Equal(t, 1, logs[1].LogIndex)
+	require.Equal(t, log3, logs[2].LogEntry)
+	require.EqualValues(t, 4, logs[2].RoundNumber)
+	require.Equal(t, 2, logs[2].TxIndex)
+	txo, err := blocks[4].Transactions[2].GetTransactionOrderV1()
+	require.NoError(t, err)
+	txHash, err := txo.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	require.EqualValues(t, txHash, logs[2].TxHash)
+
+	logs, err = collect(&LogFilter{FromRound: 1, ToRound: 5, Addresses: []common.Address{addr1}, Topics: [][]common.Hash{{common.HexToHash("0x0b")}}})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, log3, logs[0].LogEntry)
+
+	logs, err = collect(&LogFilter{FromRound: 3, ToRound: 3})
+	require.NoError(t, err)
+	require.Empty(t, logs)
+
+	_, err = collect(&LogFilter{FromRound: 5, ToRound: 4})
+	require.EqualError(t, err, "invalid round range 5 - 4")
+	_, err = collect(&LogFilter{FromRound: 665, ToRound: 667})
+	require.EqualError(t, err, "failed to load block of round 666: block not available")
+
+	// callback error stops the scan
+	err = ScanLogs(context.Background(), blocks, &LogFilter{FromRound: 1, ToRound: 5}, func(l *Log) error {
+		return errors.New("stop")
+	})
+	require.EqualError(t, err, "stop")
+}
@@ -0,0 +1,88 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// erc721ABIJSON is the standard ERC-721 non-fungible token interface (EIP-721) including the
+// optional metadata extension. Only the safeTransferFrom variant without data is included.
+const erc721ABIJSON = `[
+	{"type":"function","name":"name","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"symbol","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"owner","type":"address"}]},
+	{"type":"function","name":"getApproved","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"operator","type":"address"}]},
+	{"type":"function","name":"isApprovedForAll","stateMutability":"view","inputs":[{"name":"owner","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"approve","inputs":[{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"setApprovalForAll","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+	{"type":"event","name":"Approval","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"approved","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+	{"type":"event","name":"ApprovalForAll","inputs":[{"name":"owner","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}]}
+]`
+
+// erc1155ABIJSON is the standard ERC-1155 multi token interface (EIP-1155) including the
+// optional metadata URI extension.
+const erc1155ABIJSON = `[
+	{"type":"function","name":"uri","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"isApprovedForAll","stateMutability":"view","inputs":[{"name":"account","type":"address"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"setApprovalForAll","inputs":[{"name":"operator","type":"address"},{"name":"approved","type":"bool"}],"outputs":[]},
+	{"type":"event","name":"TransferSingle","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"id","type":"uint256","indexed":false},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"TransferBatch","inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}]},
+	{"type":"event","name":"ApprovalForAll","inputs":[{"name":"account","type":"address","indexed":true},{"name":"operator","type":"address","indexed":true},{"name":"approved","type":"bool","indexed":false}]},
+	{"type":"event","name":"URI","inputs":[{"name":"value","type":"string","indexed":false},{"name":"id","type":"uint256","indexed":true}]}
+]`
+
+var (
+	// ERC721ABI is the ABI of the standard ERC-721 non-fungible token interface.
+	ERC721ABI = mustParseABI(erc721ABIJSON)
+	// ERC1155ABI is the ABI of the standard ERC-1155 multi token interface.
+	ERC1155ABI = mustParseABI(erc1155ABIJSON)
+)
+
+// NFTCollection is the metadata of an ERC-721 token contract, name and symbol are
+// optional in the standard and empty when the contract does not implement them.
+type NFTCollection struct {
+	Address common.Address
+	Name    string
+	Symbol  string
+}
+
+// ERC721Collection reads the optional name and symbol of the ERC-721 contract.
+func (w *Wallet) ERC721Collection(ctx context.Context, accountNumber uint64, contract common.Address) *NFTCollection {
+	res := &NFTCollection{Address: contract}
+	res.Name, _ = callMethodOutput[string](ctx, w, accountNumber, contract, ERC721ABI, "name")
+	res.Symbol, _ = callMethodOutput[string](ctx, w, accountNumber, contract, ERC721ABI, "symbol")
+	return res
+}
+
+// ERC721OwnerOf returns the owner of the ERC-721 token.
+func (w *Wallet) ERC721OwnerOf(ctx context.Context, accountNumber uint64, contract common.Address, tokenID *big.Int) (common.Address, error) {
+	return callMethodOutput[common.Address](ctx, w, accountNumber, contract, ERC721ABI, "ownerOf", tokenID)
+}
+
+// ERC721TokenURI returns the metadata URI of the ERC-721 token.
+func (w *Wallet) ERC721TokenURI(ctx context.Context, accountNumber uint64, contract common.Address, tokenID *big.Int) (string, error) {
+	return callMethodOutput[string](ctx, w, accountNumber, contract, ERC721ABI, "tokenURI", tokenID)
+}
+
+// ERC721BalanceOf returns the number of ERC-721 tokens of the owner.
+func (w *Wallet) ERC721BalanceOf(ctx context.Context, accountNumber uint64, contract, owner common.Address) (*big.Int, error) {
+	return callMethodOutput[*big.Int](ctx, w, accountNumber, contract, ERC721ABI, "balanceOf", owner)
+}
+
+// ERC1155BalanceOf returns the amount of ERC-1155 tokens of the given ID the owner has.
+func (w *Wallet) ERC1155BalanceOf(ctx context.Context, accountNumber uint64, contract, owner common.Address, tokenID *big.Int) (*big.Int, error) {
+	return callMethodOutput[*big.Int](ctx, w, accountNumber, contract, ERC1155ABI, "balanceOf", owner, tokenID)
+}
+
+// ERC1155URI returns the metadata URI of the ERC-1155 token, the URI may contain "{id}" placeholder.
+func (w *Wallet) ERC1155URI(ctx context.Context, accountNumber uint64, contract common.Address, tokenID *big.Int) (string, error) {
+	return callMethodOutput[string](ctx, w, accountNumber, contract, ERC1155ABI, "uri", tokenID)
+}
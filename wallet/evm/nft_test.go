@@ -0,0 +1,80 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWallet_ERC721(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	ctx := context.Background()
+	contract := common.HexToAddress("0x3443919fcbc4476b4f332fd5df6a82fe88dbf521")
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tokenID := big.NewInt(42)
+	var req *evm.CallEVMRequest
+	callFn := testContractCalls(t, ERC721ABI, map[string][]any{
+		"name":      {"Test NFT"},
+		"symbol":    {"TNFT"},
+		"ownerOf":   {owner},
+		"tokenURI":  {"ipfs://token/42"},
+		"balanceOf": {big.NewInt(3)},
+	})
+	clientMock.callFn = func(r *evm.CallEVMRequest) *evm.ProcessingDetails {
+		req = r
+		return callFn(r)
+	}
+
+	require.Equal(t, &NFTCollection{Address: contract, Name: "Test NFT", Symbol: "TNFT"}, w.ERC721Collection(ctx, 1, contract))
+	tokenOwner, err := w.ERC721OwnerOf(ctx, 1, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, owner, tokenOwner)
+	data, err := ERC721ABI.Pack("ownerOf", tokenID)
+	require.NoError(t, err)
+	require.Equal(t, data, req.Data)
+	uri, err := w.ERC721TokenURI(ctx, 1, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, "ipfs://token/42", uri)
+	balance, err := w.ERC721BalanceOf(ctx, 1, contract, owner)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, balance.Int64())
+
+	// metadata is optional
+	clientMock.callFn = testContractCalls(t, ERC721ABI, nil)
+	require.Equal(t, &NFTCollection{Address: contract}, w.ERC721Collection(ctx, 1, contract))
+	_, err = w.ERC721OwnerOf(ctx, 1, contract, tokenID)
+	require.EqualError(t, err, "ownerOf call failed: evm runtime error: execution reverted")
+}
+
+func TestWallet_ERC1155(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	ctx := context.Background()
+	contract := common.HexToAddress("0x3443919fcbc4476b4f332fd5df6a82fe88dbf521")
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	tokenID := big.NewInt(7)
+	var req *evm.CallEVMRequest
+	callFn := testContractCalls(t, ERC1155ABI, map[string][]any{
+		"balanceOf": {big.NewInt(25)},
+		"uri":       {"https://example.org/{id}.json"},
+	})
+	clientMock.callFn = func(r *evm.CallEVMRequest) *evm.ProcessingDetails {
+		req = r
+		return callFn(r)
+	}
+
+	balance, err := w.ERC1155BalanceOf(ctx, 1, contract, owner, tokenID)
+	require.NoError(t, err)
+	require.EqualValues(t, 25, balance.Int64())
+	data, err := ERC1155ABI.Pack("balanceOf", owner, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, data, req.Data)
+	uri, err := w.ERC1155URI(ctx, 1, contract, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.org/{id}.json", uri)
+}
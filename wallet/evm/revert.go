@@ -0,0 +1,102 @@
+package evm
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
+)
+
+const (
+	errorSignature = "Error(string)"
+	panicSignature = "Panic(uint256)"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte(errorSignature))[:4]
+	panicSelector = crypto.Keccak256([]byte(panicSignature))[:4]
+
+	// panicReasons describe the Solidity panic codes
+	panicReasons = map[uint64]string{
+		0x00: "generic panic",
+		0x01: "assert(false)",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "enum overflow",
+		0x22: "invalid encoded storage byte array accessed",
+		0x31: "out-of-bounds array access; popping on an empty array",
+		0x32: "out-of-bounds access of an array or bytesN",
+		0x41: "out of memory",
+		0x51: "uninitialized function",
+	}
+)
+
+/*
+DecodeRevertReason decodes the return data of the reverted execution. Standard Error(string)
+and Panic(uint256) payloads are always decoded, custom errors only when the contract ABI
+is given. Returns nil when the data is not a known error.
+*/
+func DecodeRevertReason(contractABI *abi.ABI, data []byte) *evmclient.RevertReason {
+	if len(data) < 4 {
+		return nil
+	}
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		msg, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil
+		}
+		return &evmclient.RevertReason{Name: "Error", Signature: errorSignature, Message: msg}
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) != 4+32 {
+			return nil
+		}
+		code := new(big.Int).SetBytes(data[4:])
+		msg := fmt.Sprintf("unknown panic code: %#x", code)
+		if reason, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			msg = reason
+		}
+		return &evmclient.RevertReason{Name: "Panic", Signature: panicSignature, Message: msg, PanicCode: code}
+	}
+	if contractABI == nil {
+		return nil
+	}
+	customErr, err := contractABI.ErrorByID([4]byte(data[:4]))
+	if err != nil {
+		return nil
+	}
+	values, err := customErr.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil
+	}
+	res := &evmclient.RevertReason{Name: customErr.Name, Signature: customErr.Sig, Args: make([]evmclient.RevertArg, len(values))}
+	for i, v := range values {
+		res.Args[i] = evmclient.RevertArg{Name: customErr.Inputs[i].Name, Type: customErr.Inputs[i].Type.String(), Value: v}
+	}
+	return res
+}
+
+// FormatRevertReason returns human readable representation of the revert reason, e.g.
+// `Error("not owner")`, "Panic(0x11): arithmetic underflow or overflow" or
+// "InsufficientBalance(available: 1, required: 2)".
+func FormatRevertReason(r *evmclient.RevertReason) string {
+	switch {
+	case r.PanicCode != nil:
+		return fmt.Sprintf("%s(%#x): %s", r.Name, r.PanicCode, r.Message)
+	case r.Signature == errorSignature:
+		return fmt.Sprintf("%s(%q)", r.Name, r.Message)
+	}
+	args := make([]string, len(r.Args))
+	for i, a := range r.Args {
+		args[i] = FormatABIValue(a.Value)
+		if a.Name != "" {
+			args[i] = a.Name + ": " + args[i]
+		}
+	}
+	return fmt.Sprintf("%s(%s)", r.Name, strings.Join(args, ", "))
+}
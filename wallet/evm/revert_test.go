@@ -0,0 +1,87 @@
+package evm
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testErrorsABI = `[
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},
+	{"type":"error","name":"Unauthorized","inputs":[{"name":"","type":"address"}]}
+]`
+
+func TestDecodeRevertReason(t *testing.T) {
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	uintType, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	errorData, err := abi.Arguments{{Type: stringType}}.Pack("not owner")
+	require.NoError(t, err)
+	errorData = slices.Concat(errorSelector, errorData)
+	panicData, err := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	require.NoError(t, err)
+	panicData = slices.Concat(panicSelector, panicData)
+	unknownPanicData, err := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x99))
+	require.NoError(t, err)
+	unknownPanicData = slices.Concat(panicSelector, unknownPanicData)
+
+	contractABI, err := ParseABI([]byte(testErrorsABI))
+	require.NoError(t, err)
+	customErr := contractABI.Errors["InsufficientBalance"]
+	customData, err := customErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err)
+	customData = slices.Concat(customErr.ID[:4], customData)
+	account := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	unauthorizedErr := contractABI.Errors["Unauthorized"]
+	unauthorizedData, err := unauthorizedErr.Inputs.Pack(account)
+	require.NoError(t, err)
+	unauthorizedData = slices.Concat(unauthorizedErr.ID[:4], unauthorizedData)
+
+	t.Run("Error(string)", func(t *testing.T) {
+		r := DecodeRevertReason(nil, errorData)
+		require.NotNil(t, r)
+		require.Equal(t, "Error", r.Name)
+		require.Equal(t, "Error(string)", r.Signature)
+		require.Equal(t, "not owner", r.Message)
+		require.Nil(t, r.PanicCode)
+		require.Equal(t, `Error("not owner")`, FormatRevertReason(r))
+	})
+	t.Run("Panic(uint256)", func(t *testing.T) {
+		r := DecodeRevertReason(nil, panicData)
+		require.NotNil(t, r)
+		require.Equal(t, "Panic", r.Name)
+		require.EqualValues(t, 0x11, r.PanicCode.Uint64())
+		require.Equal(t, "Panic(0x11): arithmetic underflow or overflow", FormatRevertReason(r))
+
+		r = DecodeRevertReason(nil, unknownPanicData)
+		require.Equal(t, "Panic(0x99): unknown panic code: 0x99", FormatRevertReason(r))
+	})
+	t.Run("custom error", func(t *testing.T) {
+		require.Nil(t, DecodeRevertReason(nil, customData))
+		r := DecodeRevertReason(contractABI, customData)
+		require.NotNil(t, r)
+		require.Equal(t, "InsufficientBalance", r.Name)
+		require.Equal(t, "InsufficientBalance(uint256,uint256)", r.Signature)
+		require.Len(t, r.Args, 2)
+		require.Equal(t, "required", r.Args[1].Name)
+		require.Equal(t, "uint256", r.Args[1].Type)
+		require.Equal(t, "InsufficientBalance(available: 1, required: 2)", FormatRevertReason(r))
+		// unnamed argument gets generated name
+		r = DecodeRevertReason(contractABI, unauthorizedData)
+		require.Equal(t, "Unauthorized(arg0: "+account.Hex()+")", FormatRevertReason(r))
+	})
+	t.Run("not an error", func(t *testing.T) {
+		require.Nil(t, DecodeRevertReason(contractABI, nil))
+		require.Nil(t, DecodeRevertReason(contractABI, []byte{1, 2, 3}))
+		require.Nil(t, DecodeRevertReason(contractABI, []byte{1, 2, 3, 4, 5}))
+		// truncated payloads
+		require.Nil(t, DecodeRevertReason(nil, errorData[:10]))
+		require.Nil(t, DecodeRevertReason(nil, panicData[:10]))
+		require.Nil(t, DecodeRevertReason(contractABI, customData[:10]))
+	})
+}
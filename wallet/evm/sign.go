@@ -0,0 +1,112 @@
+package evm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+)
+
+// SignatureLen is the length of the Ethereum signature in [R || S || V] format.
+const SignatureLen = ethcrypto.SignatureLength
+
+// TypedData is EIP-712 typed structured data, the JSON format is the same as the
+// parameter of the eth_signTypedData_v4 JSON-RPC method.
+type TypedData = apitypes.TypedData
+
+// ParseTypedData parses EIP-712 typed data JSON (eth_signTypedData_v4 format).
+func ParseTypedData(data []byte) (*TypedData, error) {
+	var typedData TypedData
+	if err := json.Unmarshal(data, &typedData); err != nil {
+		return nil, fmt.Errorf("failed to parse typed data: %w", err)
+	}
+	if _, err := TypedDataHash(&typedData); err != nil {
+		return nil, err
+	}
+	return &typedData, nil
+}
+
+// MessageHash returns the EIP-191 (version 0x45, personal_sign) hash of the message i.e. keccak256 of
+// "\x19Ethereum Signed Message:\n" + len(message) + message.
+func MessageHash(message []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(message))
+}
+
+// TypedDataHash returns the EIP-712 hash of the typed data i.e. keccak256 of
+// "\x19\x01" + hashStruct(domain) + hashStruct(message).
+func TypedDataHash(typedData *TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(*typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid typed data: %w", err)
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// SignMessage signs the message with the account key the same way as the personal_sign JSON-RPC method.
+// Returns 65 byte signature in [R || S || V] format where V is 27 or 28.
+func SignMessage(key *account.AccountKey, message []byte) ([]byte, error) {
+	return signHash(key, MessageHash(message))
+}
+
+// SignTypedData signs the EIP-712 typed data with the account key the same way as the eth_signTypedData_v4
+// JSON-RPC method. Returns 65 byte signature in [R || S || V] format where V is 27 or 28.
+func SignTypedData(key *account.AccountKey, typedData *TypedData) ([]byte, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	return signHash(key, hash)
+}
+
+// RecoverMessageSigner returns the address of the account which signed the message with SignMessage.
+func RecoverMessageSigner(message, signature []byte) (common.Address, error) {
+	return recoverSigner(MessageHash(message), signature)
+}
+
+// RecoverTypedDataSigner returns the address of the account which signed the typed data with SignTypedData.
+func RecoverTypedDataSigner(typedData *TypedData, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return recoverSigner(hash, signature)
+}
+
+func signHash(key *account.AccountKey, hash common.Hash) ([]byte, error) {
+	if key == nil {
+		return nil, errors.New("account key is nil")
+	}
+	privKey, err := ethcrypto.ToECDSA(key.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	sig, err := ethcrypto.Sign(hash.Bytes(), privKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing failed: %w", err)
+	}
+	// Ethereum signatures have the legacy recovery id 27 or 28
+	sig[ethcrypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+func recoverSigner(hash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != SignatureLen {
+		return common.Address{}, fmt.Errorf("invalid signature length %d, expected %d bytes", len(signature), SignatureLen)
+	}
+	sig := common.CopyBytes(signature)
+	// both the legacy (27, 28) and the raw (0, 1) recovery id are accepted
+	if sig[ethcrypto.RecoveryIDOffset] >= 27 {
+		sig[ethcrypto.RecoveryIDOffset] -= 27
+	}
+	pubKey, err := ethcrypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return ethcrypto.PubkeyToAddress(*pubKey), nil
+}
@@ -0,0 +1,109 @@
+package evm
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+)
+
+// example from the EIP-712 specification, signed with keccak256("cow")
+const testMailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func testCowKey(t *testing.T) *account.AccountKey {
+	key, err := account.NewAccountKeyFromPrivateKey(ethcrypto.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+	return key
+}
+
+func TestSignTypedData(t *testing.T) {
+	key := testCowKey(t)
+	typedData, err := ParseTypedData([]byte(testMailTypedData))
+	require.NoError(t, err)
+	hash, err := TypedDataHash(typedData)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"), hash)
+
+	sig, err := SignTypedData(key, typedData)
+	require.NoError(t, err)
+	require.Equal(t, "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"+
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"+"1c", hex.EncodeToString(sig))
+
+	signer, err := RecoverTypedDataSigner(typedData, sig)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	// typed data is validated
+	_, err = ParseTypedData([]byte(`{"types": {}, "primaryType": "Mail", "domain": {}, "message": {}}`))
+	require.ErrorContains(t, err, "invalid typed data")
+	_, err = ParseTypedData([]byte(`[]`))
+	require.ErrorContains(t, err, "failed to parse typed data")
+}
+
+func TestSignMessage(t *testing.T) {
+	key := testCowKey(t)
+	message := []byte("Hello, Alphabill!")
+	sig, err := SignMessage(key, message)
+	require.NoError(t, err)
+	require.Len(t, sig, SignatureLen)
+	require.Contains(t, []byte{27, 28}, sig[64])
+
+	signer, err := RecoverMessageSigner(message, sig)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	// raw recovery id is accepted too
+	sig[64] -= 27
+	signer, err = RecoverMessageSigner(message, sig)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	// different message recovers different signer
+	signer, err = RecoverMessageSigner([]byte("Hello, Bob!"), sig)
+	require.NoError(t, err)
+	require.NotEqual(t, common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), signer)
+
+	_, err = RecoverMessageSigner(message, sig[:64])
+	require.EqualError(t, err, "invalid signature length 64, expected 65 bytes")
+	_, err = SignMessage(nil, message)
+	require.EqualError(t, err, "account key is nil")
+}
+
+func TestMessageHash(t *testing.T) {
+	// hash of the personal_sign message "hello"
+	require.Equal(t, common.HexToHash("0x50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750"), MessageHash([]byte("hello")))
+}
@@ -0,0 +1,151 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+
+	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
+)
+
+type (
+	// TxSimulation is the result of the successful transaction simulation.
+	TxSimulation struct {
+		// Result is the result of the execution with the gas limit of the transaction.
+		Result *evmclient.Result
+		// GasUsed is the lowest gas limit with which the execution succeeds.
+		GasUsed uint64
+	}
+
+	// SimulationError is returned by SimulateEvmTx when the execution of the transaction fails.
+	SimulationError struct {
+		// Result is the result of the failed execution, the revert reason is decoded
+		// when the return data is a standard error.
+		Result *evmclient.Result
+	}
+
+	// SendOption is an option of SendEvmTx, PostEvmTx and TxBatch.SendTx.
+	SendOption func(*sendOptions)
+
+	sendOptions struct {
+		simulate    bool
+		onSimulated func(*TxSimulation) error
+		estimate    *GasEstimate
+	}
+)
+
+/*
+WithSimulation simulates the transaction before sending it (see SimulateEvmTx), the transaction
+is not sent when the simulation fails and SimulationError is returned. The callback is called
+with the result of the successful simulation, the transaction is not sent when it returns an
+error and the error is returned. When the callback is nil the transaction is only executed once
+with its gas limit, the lowest gas limit is not searched for.
+*/
+func WithSimulation(onSuccess func(sim *TxSimulation) error) SendOption {
+	return func(o *sendOptions) {
+		o.simulate = true
+		o.onSimulated = onSuccess
+	}
+}
+
+// WithGasEstimate sets the estimate the gas limit of the transaction was taken from, the
+// simulation reuses the gas used of the estimate instead of searching for it again.
+func WithGasEstimate(estimate *GasEstimate) SendOption {
+	return func(o *sendOptions) {
+		o.estimate = estimate
+	}
+}
+
+func newSendOptions(opts []SendOption) *sendOptions {
+	o := &sendOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (e *SimulationError) Error() string {
+	msg := "transaction simulation failed: " + e.Result.Details.ErrorDetails
+	if e.Result.Revert != nil {
+		msg += ", revert reason: " + FormatRevertReason(e.Result.Revert)
+	}
+	return msg
+}
+
+/*
+SimulateEvmTx executes the transaction as a call from the account without sending it, the
+state is not changed and no fee is charged. Returns SimulationError when the execution fails,
+otherwise the lowest gas limit with which the execution succeeds is found the same way as
+by EstimateGas, but the gas limit of the transaction is used as the upper bound.
+*/
+func (w *Wallet) SimulateEvmTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes) (*TxSimulation, error) {
+	return w.simulateTx(ctx, accountNumber, attrs, nil, true)
+}
+
+// simulateTx simulates the transaction, when the gas estimate is given its gas used is
+// reported instead of searching for the lowest gas limit. The gas used is left zero when
+// "findGasUsed" is false.
+func (w *Wallet) simulateTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes, estimate *GasEstimate, findGasUsed bool) (*TxSimulation, error) {
+	from, err := w.GetAccountAddress(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	value := attrs.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	call := func(gas uint64) (*evm.ProcessingDetails, error) {
+		details, err := w.client.Call(ctx, &evm.CallEVMRequest{
+			From:  from.Bytes(),
+			To:    attrs.To,
+			Data:  attrs.Data,
+			Value: value,
+			Gas:   gas,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("transaction simulation failed: %w", err)
+		}
+		if details == nil {
+			return nil, fmt.Errorf("transaction simulation failed: empty response")
+		}
+		return details, nil
+	}
+	details, err := call(attrs.Gas)
+	if err != nil {
+		return nil, err
+	}
+	result := &evmclient.Result{Success: len(details.ErrorDetails) == 0, Details: details}
+	if !result.Success {
+		result.Revert = DecodeRevertReason(nil, details.ReturnData)
+		return nil, &SimulationError{Result: result}
+	}
+	if !findGasUsed {
+		return &TxSimulation{Result: result}, nil
+	}
+	if estimate != nil {
+		return &TxSimulation{Result: result, GasUsed: estimate.GasUsed}, nil
+	}
+	gasUsed, err := lowestGas(call, attrs.Gas)
+	if err != nil {
+		return nil, err
+	}
+	return &TxSimulation{Result: result, GasUsed: gasUsed}, nil
+}
+
+// runSimulation simulates the transaction when the simulation is enabled by the options, the
+// gas used is only found when there is a callback to report it to.
+func (o *sendOptions) runSimulation(ctx context.Context, w *Wallet, accountNumber uint64, attrs *evm.TxAttributes, estimate *GasEstimate) error {
+	if !o.simulate {
+		return nil
+	}
+	sim, err := w.simulateTx(ctx, accountNumber, attrs, estimate, o.onSimulated != nil)
+	if err != nil {
+		return err
+	}
+	if o.onSimulated != nil {
+		return o.onSimulated(sim)
+	}
+	return nil
+}
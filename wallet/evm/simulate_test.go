@@ -0,0 +1,139 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWallet_SimulateEvmTx(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	ctx := context.Background()
+	attrs := &evm.TxAttributes{To: []byte{1}, Data: []byte{2}, Gas: 100000}
+	_, err := w.SimulateEvmTx(ctx, 1, attrs)
+	require.ErrorContains(t, err, "account key read failed: account does not exist")
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	from, err := w.GetAccountAddress(1)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+			require.Equal(t, from.Bytes(), callAttr.From)
+			require.Equal(t, attrs.To, callAttr.To)
+			require.Equal(t, attrs.Data, callAttr.Data)
+			require.Equal(t, big.NewInt(0), callAttr.Value)
+			require.LessOrEqual(t, callAttr.Gas, attrs.Gas)
+			if callAttr.Gas < 30000 {
+				return &evm.ProcessingDetails{ErrorDetails: "out of gas"}
+			}
+			return &evm.ProcessingDetails{ReturnData: []byte{3}}
+		}
+		sim, err := w.SimulateEvmTx(ctx, 1, attrs)
+		require.NoError(t, err)
+		require.EqualValues(t, 30000, sim.GasUsed)
+		require.True(t, sim.Result.Success)
+		require.Equal(t, []byte{3}, sim.Result.Details.ReturnData)
+		// attributes are not modified
+		require.Nil(t, attrs.From)
+		require.Nil(t, attrs.Value)
+	})
+	t.Run("execution fails", func(t *testing.T) {
+		revertData, err := (abi.Arguments{{Type: mustNewType(t, "string")}}).Pack("not owner")
+		require.NoError(t, err)
+		clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+			return &evm.ProcessingDetails{ErrorDetails: "execution reverted", ReturnData: append(errorSelector, revertData...)}
+		}
+		_, err = w.SimulateEvmTx(ctx, 1, attrs)
+		require.EqualError(t, err, `transaction simulation failed: execution reverted, revert reason: Error("not owner")`)
+		var simErr *SimulationError
+		require.ErrorAs(t, err, &simErr)
+		require.False(t, simErr.Result.Success)
+		require.Equal(t, "not owner", simErr.Result.Revert.Message)
+	})
+	t.Run("client error", func(t *testing.T) {
+		clientMock.SimulateErr = fmt.Errorf("something bad happened")
+		defer func() { clientMock.SimulateErr = nil }()
+		_, err := w.SimulateEvmTx(ctx, 1, attrs)
+		require.ErrorContains(t, err, "transaction simulation failed: something bad happened")
+		var simErr *SimulationError
+		require.False(t, errors.As(err, &simErr))
+	})
+}
+
+func TestWallet_PostEvmTx_simulation(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	ctx := context.Background()
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	clientMock.gasPrice = "1"
+	var calls []uint64
+	clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+		calls = append(calls, callAttr.Gas)
+		if callAttr.Gas < 30000 {
+			return &evm.ProcessingDetails{ErrorDetails: "out of gas"}
+		}
+		return &evm.ProcessingDetails{}
+	}
+
+	t.Run("simulation fails", func(t *testing.T) {
+		// the transactions posted by the previous tests are not pending
+		clientMock.postedTx, calls, w.pending = nil, nil, nil
+		_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{To: []byte{1}, Gas: 25000}, WithSimulation(nil))
+		require.EqualError(t, err, "transaction simulation failed: out of gas")
+		require.Nil(t, clientMock.postedTx)
+	})
+	t.Run("simulation succeeds", func(t *testing.T) {
+		clientMock.postedTx, calls, w.pending = nil, nil, nil
+		var sim *TxSimulation
+		_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{To: []byte{1}, Gas: 40000}, WithSimulation(func(s *TxSimulation) error { sim = s; return nil }))
+		require.NoError(t, err)
+		require.NotNil(t, clientMock.postedTx)
+		require.EqualValues(t, 30000, sim.GasUsed)
+		require.Greater(t, len(calls), 1)
+	})
+	t.Run("no callback", func(t *testing.T) {
+		clientMock.postedTx, calls, w.pending = nil, nil, nil
+		_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{To: []byte{1}, Gas: 40000}, WithSimulation(nil))
+		require.NoError(t, err)
+		require.NotNil(t, clientMock.postedTx)
+		// the lowest gas limit is not searched for
+		require.Equal(t, []uint64{40000}, calls)
+	})
+	t.Run("callback rejects the transaction", func(t *testing.T) {
+		clientMock.postedTx, calls, w.pending = nil, nil, nil
+		_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{To: []byte{1}, Gas: 40000},
+			WithSimulation(func(s *TxSimulation) error { return errors.New("rejected") }))
+		require.EqualError(t, err, "rejected")
+		require.Nil(t, clientMock.postedTx)
+	})
+	t.Run("gas estimate is reused", func(t *testing.T) {
+		clientMock.postedTx, calls, w.pending = nil, nil, nil
+		var sim *TxSimulation
+		estimate := &GasEstimate{GasUsed: 30000, GasLimit: 36000}
+		_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{To: []byte{1}, Gas: estimate.GasLimit},
+			WithSimulation(func(s *TxSimulation) error { sim = s; return nil }), WithGasEstimate(estimate))
+		require.NoError(t, err)
+		require.NotNil(t, clientMock.postedTx)
+		require.EqualValues(t, 30000, sim.GasUsed)
+		// executed once with the gas limit of the transaction
+		require.Equal(t, []uint64{36000}, calls)
+	})
+	t.Run("no simulation", func(t *testing.T) {
+		clientMock.postedTx, calls, w.pending = nil, nil, nil
+		_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{To: []byte{1}, Gas: 25000})
+		require.NoError(t, err)
+		require.NotNil(t, clientMock.postedTx)
+		require.Empty(t, calls)
+	})
+}
+
+func mustNewType(t *testing.T, typ string) abi.Type {
+	abiType, err := abi.NewType(typ, "", nil)
+	require.NoError(t, err)
+	return abiType
+}
@@ -7,16 +7,16 @@ import (
 	"time"
 
 	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
-	"github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
 )
 
 type (
 	Client interface {
-		GetRoundNumber(ctx context.Context) (*client.RoundNumber, error)
-		PostTransaction(ctx context.Context, tx *types.TransactionOrder) error
-		GetTxProof(ctx context.Context, unitID types.UnitID, txHash sdktypes.TxHash) (*types.TxRecordProof, error)
+		GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error)
+		SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error)
+		GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error)
 	}
 
 	TxPublisher struct {
@@ -24,9 +24,9 @@ type (
 	}
 )
 
-func NewTxPublisher(restClient Client) *TxPublisher {
+func NewTxPublisher(client Client) *TxPublisher {
 	return &TxPublisher{
-		cli: restClient,
+		cli: client,
 	}
 }
 
@@ -36,7 +36,7 @@ func (w *TxPublisher) SendTx(ctx context.Context, tx *types.TransactionOrder, _
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash tx: %w", err)
 	}
-	if err := w.cli.PostTransaction(ctx, tx); err != nil {
+	if _, err := w.cli.SendTransaction(ctx, tx); err != nil {
 		return nil, fmt.Errorf("evm post tx failed: %w", err)
 	}
 	// confirm transaction
@@ -45,14 +45,14 @@ func (w *TxPublisher) SendTx(ctx context.Context, tx *types.TransactionOrder, _
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("confirming transaction interrupted: %w", err)
 		}
-		rnr, err := w.cli.GetRoundNumber(ctx)
+		roundInfo, err := w.cli.GetRoundInfo(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read latest round from evm node: %w", err)
 		}
-		if rnr.LastIndexedRoundNumber >= timeout {
-			return nil, fmt.Errorf("confirmation timeout evm round %v, tx timeout round %v", rnr.LastIndexedRoundNumber, timeout)
+		if roundInfo.RoundNumber >= timeout {
+			return nil, fmt.Errorf("confirmation timeout evm round %v, tx timeout round %v", roundInfo.RoundNumber, timeout)
 		}
-		proof, err := w.cli.GetTxProof(ctx, tx.GetUnitID(), txHash)
+		proof, err := w.cli.GetTransactionProof(ctx, txHash)
 		if err != nil {
 			return nil, err
 		}
@@ -2,14 +2,15 @@ package evm
 
 import (
 	"context"
+	"crypto"
 	"testing"
 
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
 	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
 	"github.com/stretchr/testify/require"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
-	"github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
 )
 
 type MockClient struct {
@@ -42,19 +43,21 @@ func NewClientMock(round uint64, proof *types.TxRecordProof) Client {
 	}
 }
 
-func (m *MockClient) GetRoundNumber(ctx context.Context) (*client.RoundNumber, error) {
+func (m *MockClient) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
 	defer func() { m.RoundNr++ }()
-	return &client.RoundNumber{
-		RoundNumber:            m.RoundNr,
-		LastIndexedRoundNumber: m.RoundNr,
+	return &sdktypes.RoundInfo{
+		RoundNumber: m.RoundNr,
 	}, m.RoundNrError
 }
 
-func (m *MockClient) PostTransaction(ctx context.Context, tx *types.TransactionOrder) error {
-	return m.PostError
+func (m *MockClient) SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
+	if m.PostError != nil {
+		return nil, m.PostError
+	}
+	return tx.Hash(crypto.SHA256)
 }
 
-func (m *MockClient) GetTxProof(ctx context.Context, unitID types.UnitID, txHash sdktypes.TxHash) (*types.TxRecordProof, error) {
+func (m *MockClient) GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
 	return m.Proof, m.ProofError
 }
 
@@ -0,0 +1,142 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+type (
+	// CodeVerification is the result of comparing the deployed runtime code with the compiler artifact.
+	CodeVerification struct {
+		// Match is true when the executable code (without the metadata) matches.
+		Match bool
+		// MetadataMatch is true when also the metadata matches, ie the contract was compiled from
+		// exactly the same sources with the same settings.
+		MetadataMatch bool
+		// Deployed and Expected are the deployed code and the runtime code from the artifact
+		// without the metadata, the immutable variables and library address are masked with zeros.
+		Deployed, Expected                 []byte
+		DeployedMetadata, ExpectedMetadata []byte
+		// MismatchOffset is the offset of the first differing byte, -1 when the code matches.
+		MismatchOffset int
+		// MismatchCount is the number of differing bytes in the common length of the codes.
+		MismatchCount int
+	}
+)
+
+// codeLoader returns contract creation code which reverts with the runtime code of the contract
+// at the address as the revert data, ie the Call returns the code without creating a contract:
+//
+//	PUSH20 addr, DUP1, EXTCODESIZE, DUP1, SWAP2, PUSH1 0, DUP1, SWAP2, EXTCODECOPY, PUSH1 0, REVERT
+func codeLoader(addr common.Address) []byte {
+	code := []byte{byte(vm.PUSH20)}
+	code = append(code, addr.Bytes()...)
+	return append(code,
+		byte(vm.DUP1), byte(vm.EXTCODESIZE), byte(vm.DUP1), byte(vm.SWAP2),
+		byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.SWAP2), byte(vm.EXTCODECOPY),
+		byte(vm.PUSH1), 0, byte(vm.REVERT))
+}
+
+// GetCode returns the runtime code of the contract. The node does not provide the code directly,
+// it is read by simulating a contract creation which returns the code of the contract, the call is
+// made from the address of the account. Returns empty code when there is no contract at the address.
+func (w *Wallet) GetCode(ctx context.Context, accountNumber uint64, addr common.Address) ([]byte, error) {
+	from, err := w.GetAccountAddress(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	details, err := w.client.Call(ctx, &evm.CallEVMRequest{From: from.Bytes(), Data: codeLoader(addr)})
+	if err != nil {
+		return nil, fmt.Errorf("reading contract code: %w", err)
+	}
+	return details.ReturnData, nil
+}
+
+// SplitMetadata splits the runtime code generated by solc into the executable code and the CBOR
+// encoded metadata appended to the code (the length of the metadata is in the last two bytes).
+// Returns nil metadata when the code does not end with the metadata.
+func SplitMetadata(code []byte) ([]byte, []byte) {
+	if len(code) < 2 {
+		return code, nil
+	}
+	metadataLen := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - metadataLen
+	// metadata is CBOR map
+	if metadataLen == 0 || start < 0 || code[start]&0xE0 != 0xA0 {
+		return code, nil
+	}
+	return code[:start], code[start:]
+}
+
+/*
+VerifyCode compares the deployed runtime code with the runtime code from the compiler artifact.
+The libraries are linked into the artifact code the same way as for the deployment. The metadata
+appended by the compiler is compared separately, the positions of the immutable variables (when
+known from the artifact) and the address of the library (in case of library contract) are ignored
+as these are set during the deployment.
+*/
+func VerifyCode(deployed []byte, artifact *ContractArtifact, libraries map[string]common.Address) (*CodeVerification, error) {
+	if artifact.DeployedBytecode == "" {
+		return nil, errors.New("artifact does not contain runtime bytecode")
+	}
+	if len(deployed) == 0 {
+		return nil, errors.New("there is no contract code at the address")
+	}
+	expected, err := LinkBytecode(artifact.DeployedBytecode, artifact.DeployedLinkReferences, libraries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link runtime code: %w", err)
+	}
+	deployed = bytes.Clone(deployed)
+	for _, refs := range artifact.ImmutableReferences {
+		for _, ref := range refs {
+			if ref.Start < 0 || ref.Length < 0 || ref.Start+ref.Length > len(expected) {
+				return nil, fmt.Errorf("invalid immutable reference at offset %d", ref.Start)
+			}
+			maskCode(deployed, ref.Start, ref.Length)
+		}
+	}
+	if isLibraryCode(expected) {
+		maskCode(deployed, 1, common.AddressLength)
+	}
+	res := &CodeVerification{MismatchOffset: -1}
+	res.Deployed, res.DeployedMetadata = SplitMetadata(deployed)
+	res.Expected, res.ExpectedMetadata = SplitMetadata(expected)
+	for i := 0; i < min(len(res.Deployed), len(res.Expected)); i++ {
+		if res.Deployed[i] != res.Expected[i] {
+			res.MismatchCount++
+			if res.MismatchOffset < 0 {
+				res.MismatchOffset = i
+			}
+		}
+	}
+	if res.MismatchOffset < 0 && len(res.Deployed) != len(res.Expected) {
+		res.MismatchOffset = min(len(res.Deployed), len(res.Expected))
+	}
+	res.Match = res.MismatchOffset < 0
+	res.MetadataMatch = res.Match && bytes.Equal(res.DeployedMetadata, res.ExpectedMetadata)
+	return res, nil
+}
+
+// maskCode sets the code bytes to zero, the compiler leaves the values set at the deployment zero.
+func maskCode(code []byte, start, length int) {
+	for i := start; i < start+length && i < len(code); i++ {
+		code[i] = 0
+	}
+}
+
+// isLibraryCode returns true when the runtime code starts with the call protection of the library:
+// PUSH20 <library address>, ADDRESS, EQ. The address is set at the deployment and is zero in the artifact.
+func isLibraryCode(code []byte) bool {
+	return len(code) > common.AddressLength+2 &&
+		code[0] == byte(vm.PUSH20) &&
+		bytes.Equal(code[1:common.AddressLength+1], make([]byte, common.AddressLength)) &&
+		code[common.AddressLength+1] == byte(vm.ADDRESS) &&
+		code[common.AddressLength+2] == byte(vm.EQ)
+}
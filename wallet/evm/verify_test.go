@@ -0,0 +1,146 @@
+package evm
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+// testMetadata is CBOR encoded metadata {"solc": 0x000818} followed by its length
+var testMetadata = []byte{0xA1, 0x64, 's', 'o', 'l', 'c', 0x43, 0x00, 0x08, 0x18, 0x00, 0x0A}
+
+func Test_codeLoader(t *testing.T) {
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	code := append([]byte{0x60, 0x80, 0x60, 0x40, 0x52}, testMetadata...)
+	db, err := state.New(ethtypes.EmptyRootHash, state.NewDatabaseForTesting())
+	require.NoError(t, err)
+	db.CreateAccount(target)
+	db.SetCode(target, code)
+
+	ret, _, _, err := runtime.Create(codeLoader(target), &runtime.Config{State: db})
+	require.ErrorIs(t, err, vm.ErrExecutionReverted)
+	require.Equal(t, code, ret)
+	// no contract at the address
+	ret, _, _, err = runtime.Create(codeLoader(common.HexToAddress("0x02")), &runtime.Config{State: db})
+	require.ErrorIs(t, err, vm.ErrExecutionReverted)
+	require.Empty(t, ret)
+}
+
+func TestWallet_GetCode(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	from, err := w.GetAccountAddress(1)
+	require.NoError(t, err)
+	target := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	clientMock.callFn = func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails {
+		require.Nil(t, callAttr.To)
+		require.Equal(t, from.Bytes(), callAttr.From)
+		require.Equal(t, codeLoader(target), callAttr.Data)
+		return &evm.ProcessingDetails{ReturnData: []byte{0x60, 0x80}, ErrorDetails: "evm runtime error: execution reverted"}
+	}
+	code, err := w.GetCode(context.Background(), 1, target)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x60, 0x80}, code)
+
+	clientMock.SimulateErr = errors.New("some error")
+	_, err = w.GetCode(context.Background(), 1, target)
+	require.EqualError(t, err, "reading contract code: some error")
+}
+
+func TestSplitMetadata(t *testing.T) {
+	code, metadata := SplitMetadata(append([]byte{0x60, 0x80}, testMetadata...))
+	require.Equal(t, []byte{0x60, 0x80}, code)
+	require.Equal(t, testMetadata, metadata)
+	// no metadata
+	code, metadata = SplitMetadata([]byte{0x60, 0x80, 0x00, 0x01})
+	require.Equal(t, []byte{0x60, 0x80, 0x00, 0x01}, code)
+	require.Nil(t, metadata)
+	code, metadata = SplitMetadata([]byte{0x00, 0xFF})
+	require.Equal(t, []byte{0x00, 0xFF}, code)
+	require.Nil(t, metadata)
+}
+
+func TestVerifyCode(t *testing.T) {
+	runtimeCode := "608060405260043610"
+	otherMetadata := append([]byte{}, testMetadata...)
+	otherMetadata[9] = 0x19
+	withMetadata := func(code string, metadata []byte) []byte {
+		b, err := hex.DecodeString(code)
+		require.NoError(t, err)
+		return append(b, metadata...)
+	}
+	artifact := &ContractArtifact{DeployedBytecode: hex.EncodeToString(withMetadata(runtimeCode, testMetadata))}
+
+	t.Run("full match", func(t *testing.T) {
+		res, err := VerifyCode(withMetadata(runtimeCode, testMetadata), artifact, nil)
+		require.NoError(t, err)
+		require.True(t, res.Match)
+		require.True(t, res.MetadataMatch)
+		require.Equal(t, -1, res.MismatchOffset)
+	})
+	t.Run("metadata differs", func(t *testing.T) {
+		res, err := VerifyCode(withMetadata(runtimeCode, otherMetadata), artifact, nil)
+		require.NoError(t, err)
+		require.True(t, res.Match)
+		require.False(t, res.MetadataMatch)
+		require.Equal(t, otherMetadata, res.DeployedMetadata)
+		require.Equal(t, testMetadata, res.ExpectedMetadata)
+	})
+	t.Run("code differs", func(t *testing.T) {
+		res, err := VerifyCode(withMetadata("608060405260053611", testMetadata), artifact, nil)
+		require.NoError(t, err)
+		require.False(t, res.Match)
+		require.False(t, res.MetadataMatch)
+		require.Equal(t, 6, res.MismatchOffset)
+		require.Equal(t, 2, res.MismatchCount)
+	})
+	t.Run("length differs", func(t *testing.T) {
+		res, err := VerifyCode(withMetadata(runtimeCode+"00", testMetadata), artifact, nil)
+		require.NoError(t, err)
+		require.False(t, res.Match)
+		require.Equal(t, 9, res.MismatchOffset)
+		require.Equal(t, 0, res.MismatchCount)
+	})
+	t.Run("immutables and libraries", func(t *testing.T) {
+		lib := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		a := &ContractArtifact{
+			DeployedBytecode:       "6080" + "__$" + "0123456789abcdef0123456789abcdef01" + "$__" + "7f" + "0000" + "00",
+			DeployedLinkReferences: LinkReferences{"Math.sol": {"Math": {{Start: 2, Length: 20}}}},
+			ImmutableReferences:    map[string][]LinkReference{"12": {{Start: 23, Length: 2}}},
+		}
+		deployed := withMetadata("6080"+hex.EncodeToString(lib.Bytes())+"7f"+"abcd"+"00", nil)
+		res, err := VerifyCode(deployed, a, map[string]common.Address{"Math": lib})
+		require.NoError(t, err)
+		require.True(t, res.Match)
+		// immutable value is checked when the references are not known
+		a.ImmutableReferences = nil
+		res, err = VerifyCode(deployed, a, map[string]common.Address{"Math": lib})
+		require.NoError(t, err)
+		require.False(t, res.Match)
+		require.Equal(t, 23, res.MismatchOffset)
+		_, err = VerifyCode(deployed, a, nil)
+		require.ErrorContains(t, err, "failed to link runtime code: unresolved library link placeholder(s): Math.sol:Math")
+	})
+	t.Run("library call protection", func(t *testing.T) {
+		a := &ContractArtifact{DeployedBytecode: "73" + hex.EncodeToString(make([]byte, 20)) + "3014" + "6080"}
+		deployed := withMetadata("73"+"00000000000000000000000000000000000000aa"+"3014"+"6080", nil)
+		res, err := VerifyCode(deployed, a, nil)
+		require.NoError(t, err)
+		require.True(t, res.Match)
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := VerifyCode(nil, artifact, nil)
+		require.EqualError(t, err, "there is no contract code at the address")
+		_, err = VerifyCode([]byte{0x60}, &ContractArtifact{}, nil)
+		require.EqualError(t, err, "artifact does not contain runtime bytecode")
+	})
+}
@@ -2,15 +2,16 @@ package evm
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"math/big"
-	"net/url"
-	"strings"
+	"sync"
 
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
-	"github.com/alphabill-org/alphabill-go-base/crypto"
+	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
 	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/ethereum/go-ethereum/common"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
@@ -24,105 +25,273 @@ type (
 		Client
 		Call(ctx context.Context, callAttr *evm.CallEVMRequest) (*evm.ProcessingDetails, error)
 		GetTransactionCount(ctx context.Context, ethAddr []byte) (uint64, error)
-		GetBalance(ctx context.Context, ethAddr []byte) (string, uint64, error)
-		GetFeeCreditBill(ctx context.Context, unitID types.UnitID) (*evmclient.Bill, error)
-		GetGasPrice(ctx context.Context) (string, error)
+		GetBalance(ctx context.Context, ethAddr []byte) (*big.Int, error)
+		GetGasPrice(ctx context.Context) (*big.Int, error)
+		Close()
 	}
 
 	Wallet struct {
 		networkID   types.NetworkID
 		partitionID types.PartitionID
 		am          account.Manager
-		restCli     evmClient
-	}
-)
-
-func ConvertBalanceToAlpha(eth *big.Int) uint64 {
-	return evmclient.WeiToAlpha(eth)
-}
+		client      evmClient
 
-func New(partitionID types.PartitionID, restUrl string, am account.Manager) (*Wallet, error) {
-	if partitionID == 0 {
-		return nil, fmt.Errorf("partition id is unassigned")
+		// pending are the last transactions of the accounts posted without waiting for the
+		// confirmation, the transaction count of the account does not include them until
+		// they are executed
+		pendingMu sync.Mutex
+		pending   map[common.Address]pendingTx
 	}
-	if len(restUrl) == 0 {
-		return nil, fmt.Errorf("rest url is empty")
+
+	pendingTx struct {
+		nonce   uint64
+		timeout uint64
+		// costs are the maximum costs of the pending transactions with consecutive nonces
+		// ending with the nonce, the fee credit they reserve is not available for new transactions
+		costs []*big.Int
 	}
+)
+
+// New creates EVM wallet, the network and partition identifiers are loaded from the partition description.
+func New(ctx context.Context, am account.Manager, evmClient sdktypes.EvmPartitionClient) (*Wallet, error) {
 	if am == nil {
 		return nil, fmt.Errorf("account manager is nil")
 	}
-	if !strings.HasPrefix(restUrl, "http://") && !strings.HasPrefix(restUrl, "https://") {
-		restUrl = "http://" + restUrl
+	if evmClient == nil {
+		return nil, fmt.Errorf("evm client is nil")
 	}
-	addr, err := url.Parse(restUrl)
+	pdr, err := evmClient.PartitionDescription(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("loading partition description: %w", err)
+	}
+	if pdr.PartitionTypeID != evm.PartitionTypeID {
+		return nil, fmt.Errorf("invalid rpc url: expected evm partition (%d) node reports partition type %d", evm.PartitionTypeID, pdr.PartitionTypeID)
 	}
 	return &Wallet{
-		partitionID: partitionID,
+		networkID:   pdr.NetworkID,
+		partitionID: pdr.PartitionID,
 		am:          am,
-		restCli:     evmclient.New(*addr),
+		client:      evmClient,
 	}, nil
 }
 
 func (w *Wallet) Shutdown() {
 	w.am.Close()
+	w.client.Close()
 }
 
-func (w *Wallet) SendEvmTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes) (*evmclient.Result, error) {
-	if accountNumber < 1 {
-		return nil, fmt.Errorf("invalid account number: %d", accountNumber)
+// SendEvmTx signs and sends the transaction and waits until the transaction is confirmed or times out.
+func (w *Wallet) SendEvmTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes, opts ...SendOption) (*evmclient.Result, error) {
+	o := newSendOptions(opts)
+	if err := o.runSimulation(ctx, w, accountNumber, attrs, o.estimate); err != nil {
+		return nil, err
 	}
-	acc, err := w.am.GetAccountKey(accountNumber - 1)
+	txo, _, err := w.createEvmTx(ctx, accountNumber, attrs)
 	if err != nil {
-		return nil, fmt.Errorf("account key read failed: %w", err)
+		return nil, err
 	}
-	from, err := generateAddress(acc.PubKey)
+	txHash, err := txo.Hash(crypto.SHA256)
 	if err != nil {
-		return nil, fmt.Errorf("from address generation failed: %w", err)
+		return nil, fmt.Errorf("failed to hash transaction: %w", err)
 	}
-	rnr, err := w.restCli.GetRoundNumber(ctx)
+	// send transaction and wait for response or timeout
+	txPub := NewTxPublisher(w.client)
+	proof, err := txPub.SendTx(ctx, txo, nil)
 	if err != nil {
-		return nil, fmt.Errorf("evm current round number read failed: %w", err)
+		return nil, fmt.Errorf("evm transaction failed or account does not have enough fee credit: %w", err)
+	}
+	return newTxResult(proof, txHash)
+}
+
+/*
+PostEvmTx signs and sends the transaction without waiting for the confirmation and returns
+the hash of the transaction. The status of the transaction can be queried with GetTxStatus.
+The next transaction of the account gets the nonce following the posted transaction until
+the posted transaction is executed or it times out. The wallet does not serialize the posts,
+the concurrent posts of the account must be serialized by the caller.
+*/
+func (w *Wallet) PostEvmTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes, opts ...SendOption) ([]byte, error) {
+	o := newSendOptions(opts)
+	if err := o.runSimulation(ctx, w, accountNumber, attrs, o.estimate); err != nil {
+		return nil, err
 	}
-	if err := w.verifyFeeCreditBalance(ctx, acc, attrs.Gas); err != nil {
+	txo, cost, err := w.createEvmTx(ctx, accountNumber, attrs)
+	if err != nil {
 		return nil, err
 	}
+	txHash, err := txo.Hash(crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	if _, err := w.client.SendTransaction(ctx, txo); err != nil {
+		return nil, fmt.Errorf("evm post tx failed: %w", err)
+	}
+	w.setPendingTx(common.BytesToAddress(attrs.From), attrs.Nonce, txo.Timeout(), cost)
+	return txHash, nil
+}
+
+/*
+GetTxStatus returns the execution result of the confirmed transaction, the proof of the
+transaction is included in the result. Returns nil when the transaction is not (yet) confirmed,
+ie it is still pending, it has timed out or it was never sent.
+*/
+func (w *Wallet) GetTxStatus(ctx context.Context, txHash []byte) (*evmclient.Result, error) {
+	proof, err := w.client.GetTransactionProof(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction proof: %w", err)
+	}
+	if proof == nil {
+		return nil, nil
+	}
+	return newTxResult(proof, txHash)
+}
+
+// createEvmTx creates and signs the transaction, the sender and the nonce are set in the attributes.
+// The maximum cost of the transaction is returned too.
+func (w *Wallet) createEvmTx(ctx context.Context, accountNumber uint64, attrs *evm.TxAttributes) (*types.TransactionOrder, *big.Int, error) {
+	acc, from, err := w.accountKey(accountNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	roundInfo, err := w.client.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("evm current round number read failed: %w", err)
+	}
+	nonce, reserved, err := w.accountNonce(ctx, from, roundInfo.RoundNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	costs, err := w.verifyFeeCreditBalance(ctx, from, reserved, attrs)
+	if err != nil {
+		return nil, nil, err
+	}
+	txo, err := w.newSignedTx(acc, from, attrs, nonce, roundInfo.RoundNumber+txTimeoutBlockCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txo, costs[0], nil
+}
+
+// accountNonce returns the next nonce of the account and the fee credit reserved by the pending
+// transactions of the account, see nextNonce.
+func (w *Wallet) accountNonce(ctx context.Context, from common.Address, round uint64) (uint64, *big.Int, error) {
 	// verify account exists and get transaction count
-	nonce, err := w.restCli.GetTransactionCount(ctx, from.Bytes())
+	txCount, err := w.client.GetTransactionCount(ctx, from.Bytes())
 	if err != nil {
-		return nil, fmt.Errorf("account %x transaction count read failed: %w", from.Bytes(), err)
+		if errors.Is(err, sdktypes.ErrNotFound) {
+			return 0, nil, fmt.Errorf("no fee credit in evm wallet")
+		}
+		return 0, nil, fmt.Errorf("account %x transaction count read failed: %w", from.Bytes(), err)
 	}
+	nonce, reserved := w.nextNonce(from, txCount, round)
+	return nonce, reserved, nil
+}
+
+// nextNonce returns the nonce following the pending transaction of the account, or the transaction
+// count of the account when there is no pending transaction, ie it has been executed or it has timed out.
+// The fee credit reserved by the pending transactions which are not executed yet is returned too.
+func (w *Wallet) nextNonce(from common.Address, txCount, round uint64) (uint64, *big.Int) {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+	reserved := big.NewInt(0)
+	if p, ok := w.pending[from]; ok {
+		if p.nonce >= txCount && round <= p.timeout {
+			// the transactions with the nonces below the transaction count are executed
+			notExecuted := min(p.nonce-txCount+1, uint64(len(p.costs)))
+			for _, cost := range p.costs[uint64(len(p.costs))-notExecuted:] {
+				reserved.Add(reserved, cost)
+			}
+			return p.nonce + 1, reserved
+		}
+		delete(w.pending, from)
+	}
+	return txCount, reserved
+}
+
+// setPendingTx records the posted transaction as the last pending transaction of the account, the
+// cost is added to the costs of the pending transaction with the previous nonce.
+func (w *Wallet) setPendingTx(from common.Address, nonce, timeout uint64, cost *big.Int) {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+	if w.pending == nil {
+		w.pending = make(map[common.Address]pendingTx)
+	}
+	var costs []*big.Int
+	if p, ok := w.pending[from]; ok && p.nonce+1 == nonce {
+		costs = p.costs
+	}
+	w.pending[from] = pendingTx{nonce: nonce, timeout: timeout, costs: append(costs, cost)}
+}
+
+// newSignedTx creates the transaction with the given nonce and timeout and signs it with the account key.
+func (w *Wallet) newSignedTx(acc *account.AccountKey, from common.Address, attrs *evm.TxAttributes, nonce, timeout uint64) (*types.TransactionOrder, error) {
 	attrs.From = from.Bytes()
 	attrs.Nonce = nonce
 	if attrs.Value == nil {
 		attrs.Value = big.NewInt(0)
 	}
-	txo, err := sdktypes.NewTransactionOrder(w.networkID, w.partitionID, from.Bytes(), evm.TransactionTypeEVMCall, attrs, sdktypes.WithTimeout(rnr.RoundNumber+txTimeoutBlockCount))
+	txo, err := sdktypes.NewTransactionOrder(w.networkID, w.partitionID, from.Bytes(), evm.TransactionTypeEVMCall, attrs, sdktypes.WithTimeout(timeout))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create evm transaction order: %w", err)
 	}
 	if err = signTx(txo, acc); err != nil {
 		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
-	// send transaction and wait for response or timeout
-	txPub := NewTxPublisher(w.restCli)
-	proof, err := txPub.SendTx(ctx, txo, nil)
+	return txo, nil
+}
+
+// accountKey returns the signing key and the EVM address of the account, watch-only account is refused.
+func (w *Wallet) accountKey(accountNumber uint64) (*account.AccountKey, common.Address, error) {
+	if accountNumber < 1 {
+		return nil, common.Address{}, fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	acc, err := w.am.GetSigningKey(accountNumber - 1)
 	if err != nil {
-		return nil, fmt.Errorf("evm transaction failed or account does not have enough fee credit: %w", err)
+		return nil, common.Address{}, fmt.Errorf("account key read failed: %w", err)
 	}
+	from, err := AddressFromPublicKey(acc.PubKey)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("from address generation failed: %w", err)
+	}
+	return acc, from, nil
+}
+
+// newTxResult returns the execution result of the transaction from its proof.
+func newTxResult(proof *types.TxRecordProof, txHash []byte) (*evmclient.Result, error) {
 	if proof == nil || proof.TxRecord == nil {
 		return nil, fmt.Errorf("unexpected result")
 	}
 	var details evm.ProcessingDetails
-	if err = proof.TxRecord.UnmarshalProcessingDetails(&details); err != nil {
+	if err := proof.TxRecord.UnmarshalProcessingDetails(&details); err != nil {
 		return nil, fmt.Errorf("failed to de-serialize evm execution result: %w", err)
 	}
-	return &evmclient.Result{
+	result := &evmclient.Result{
 		Success:   proof.TxRecord.ServerMetadata.SuccessIndicator == types.TxStatusSuccessful,
 		ActualFee: proof.TxRecord.ServerMetadata.GetActualFee(),
 		Details:   &details,
-	}, nil
+		TxHash:    txHash,
+		Proof:     proof,
+	}
+	if !result.Success {
+		result.Revert = DecodeRevertReason(nil, details.ReturnData)
+	}
+	return result, nil
+}
+
+// Transfer sends value (in wei) from the account to the EVM address. When maxGas is 0 the
+// gas needed for a plain value transfer is used.
+func (w *Wallet) Transfer(ctx context.Context, accountNumber uint64, to common.Address, value *big.Int, maxGas uint64) (*evmclient.Result, error) {
+	if value == nil || value.Sign() <= 0 {
+		return nil, fmt.Errorf("transfer value must be positive")
+	}
+	if maxGas == 0 {
+		maxGas = TransferGas
+	}
+	return w.SendEvmTx(ctx, accountNumber, &evm.TxAttributes{
+		To:    to.Bytes(),
+		Value: value,
+		Gas:   maxGas,
+	})
 }
 
 func (w *Wallet) EvmCall(ctx context.Context, accountNumber uint64, attrs *evm.CallEVMRequest) (*evmclient.Result, error) {
@@ -133,20 +302,56 @@ func (w *Wallet) EvmCall(ctx context.Context, accountNumber uint64, attrs *evm.C
 	if err != nil {
 		return nil, fmt.Errorf("account key read failed: %w", err)
 	}
-	from, err := generateAddress(acc.PubKey)
+	from, err := AddressFromPublicKey(acc.PubKey)
 	if err != nil {
 		return nil, fmt.Errorf("generating address: %w", err)
 	}
 	attrs.From = from.Bytes()
-	details, err := w.restCli.Call(ctx, attrs)
+	details, err := w.client.Call(ctx, attrs)
 	if err != nil {
 		return nil, err
 	}
-	return &evmclient.Result{
+	result := &evmclient.Result{
 		Success:   len(details.ErrorDetails) == 0,
 		ActualFee: 0,
 		Details:   details,
-	}, nil
+	}
+	if !result.Success {
+		result.Revert = DecodeRevertReason(nil, details.ReturnData)
+	}
+	return result, nil
+}
+
+// GetAccountAddresses returns the EVM addresses of all accounts, the address of
+// account number N is at index N-1. The address of watch-only account of public
+// key hash is the zero address.
+func (w *Wallet) GetAccountAddresses() ([]common.Address, error) {
+	pubKeys, err := w.am.GetPublicKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public keys: %w", err)
+	}
+	addresses := make([]common.Address, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if pubKey == nil {
+			continue
+		}
+		if addresses[i], err = AddressFromPublicKey(pubKey); err != nil {
+			return nil, fmt.Errorf("generating address: %w", err)
+		}
+	}
+	return addresses, nil
+}
+
+// GetAccountAddress returns the EVM address of the account.
+func (w *Wallet) GetAccountAddress(accountNumber uint64) (common.Address, error) {
+	if accountNumber < 1 {
+		return common.Address{}, fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	acc, err := w.am.GetAccountKey(accountNumber - 1)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("account key read failed: %w", err)
+	}
+	return AddressFromPublicKey(acc.PubKey)
 }
 
 func (w *Wallet) GetBalance(ctx context.Context, accountNumber uint64) (*big.Int, error) {
@@ -157,51 +362,44 @@ func (w *Wallet) GetBalance(ctx context.Context, accountNumber uint64) (*big.Int
 	if err != nil {
 		return nil, fmt.Errorf("account key read failed: %w", err)
 	}
-	from, err := generateAddress(acc.PubKey)
+	from, err := AddressFromPublicKey(acc.PubKey)
 	if err != nil {
 		return nil, fmt.Errorf("generating address: %w", err)
 	}
-	balanceStr, _, err := w.restCli.GetBalance(ctx, from.Bytes())
-	balance, ok := new(big.Int).SetString(balanceStr, 10)
-	if !ok {
-		return nil, fmt.Errorf("balance string %s to base 10 conversion failed: %w", balanceStr, err)
-	}
-	return balance, nil
+	return w.client.GetBalance(ctx, from.Bytes())
 }
 
-// make sure wallet has enough fee credit to perform transaction
-func (w *Wallet) verifyFeeCreditBalance(ctx context.Context, acc *account.AccountKey, maxGas uint64) error {
-	from, err := generateAddress(acc.PubKey)
+// make sure wallet has enough fee credit to perform the transactions and transfer the values on top of
+// the fee credit reserved by the pending transactions, returns the maximum costs of the transactions
+func (w *Wallet) verifyFeeCreditBalance(ctx context.Context, from common.Address, reserved *big.Int, txs ...*evm.TxAttributes) ([]*big.Int, error) {
+	balance, err := w.client.GetBalance(ctx, from.Bytes())
 	if err != nil {
-		return fmt.Errorf("generating address: %w", err)
-	}
-	balanceStr, _, err := w.restCli.GetBalance(ctx, from.Bytes())
-	if err != nil {
-		if errors.Is(err, evmclient.ErrNotFound) {
-			return fmt.Errorf("no fee credit in evm wallet")
+		if errors.Is(err, sdktypes.ErrNotFound) {
+			return nil, fmt.Errorf("no fee credit in evm wallet")
 		}
-		return err
-	}
-	balance, ok := new(big.Int).SetString(balanceStr, 10)
-	if !ok {
-		return fmt.Errorf("balance %s to base 10 conversion failed: %w", balanceStr, err)
+		return nil, err
 	}
-	gasPriceStr, err := w.restCli.GetGasPrice(ctx)
+	gasPrice, err := w.GetGasPrice(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	gasPrice, ok := new(big.Int).SetString(gasPriceStr, 10)
-	if !ok {
-		return fmt.Errorf("gas price string %s to base 10 conversion failed: %w", gasPriceStr, err)
+	required := new(big.Int).Set(reserved)
+	costs := make([]*big.Int, len(txs))
+	for i, attrs := range txs {
+		costs[i] = new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(attrs.Gas))
+		if attrs.Value != nil {
+			costs[i].Add(costs[i], attrs.Value)
+		}
+		required.Add(required, costs[i])
 	}
-	if balance.Cmp(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(maxGas))) == -1 {
-		return fmt.Errorf("insufficient fee credit balance for transaction")
+	if balance.Cmp(required) == -1 {
+		return nil, fmt.Errorf("insufficient fee credit balance for transaction")
 	}
-	return nil
+	return costs, nil
 }
 
 func signTx(tx *types.TransactionOrder, ac *account.AccountKey) error {
-	signer, err := crypto.NewInMemorySecp256K1SignerFromKey(ac.PrivKey)
+	signer, err := abcrypto.NewInMemorySecp256K1SignerFromKey(ac.PrivKey)
 	if err != nil {
 		return err
 	}
@@ -2,18 +2,22 @@ package evm
 
 import (
 	"context"
+	"crypto"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/alphabill-org/alphabill-evm/txsystem/evm"
 	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 
+	"github.com/alphabill-org/alphabill-wallet/client"
+	"github.com/alphabill-org/alphabill-wallet/client/rpc/mocksrv"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	test "github.com/alphabill-org/alphabill-wallet/internal/testutils"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
-	evmclient "github.com/alphabill-org/alphabill-wallet/wallet/evm/client"
 )
 
 const (
@@ -31,30 +35,46 @@ type evmClientMock struct {
 	SimulateErr error
 	noFcb       bool
 	gasPrice    string
+	callFn      func(callAttr *evm.CallEVMRequest) *evm.ProcessingDetails
+	// postedTx is the last transaction posted
+	postedTx *types.TransactionOrder
+	// noProof - GetTxProof returns nil proof, ie the transaction is not confirmed
+	noProof bool
+	// round is the current round, 3 if not set
+	round uint64
+	// txCount is the transaction count of the account, 1 if not set
+	txCount uint64
 }
 
 func newClientMock() *evmClientMock {
 	return &evmClientMock{}
 }
 
-func (e *evmClientMock) GetRoundNumber(ctx context.Context) (*evmclient.RoundNumber, error) {
+func (e *evmClientMock) GetRoundInfo(ctx context.Context) (*sdktypes.RoundInfo, error) {
 	if e.SimulateErr != nil {
 		return nil, e.SimulateErr
 	}
-	return &evmclient.RoundNumber{RoundNumber: 3, LastIndexedRoundNumber: 3}, nil
+	if e.round != 0 {
+		return &sdktypes.RoundInfo{RoundNumber: e.round}, nil
+	}
+	return &sdktypes.RoundInfo{RoundNumber: 3}, nil
 }
 
-func (e *evmClientMock) PostTransaction(ctx context.Context, tx *types.TransactionOrder) error {
+func (e *evmClientMock) SendTransaction(ctx context.Context, tx *types.TransactionOrder) ([]byte, error) {
 	if e.SimulateErr != nil {
-		return e.SimulateErr
+		return nil, e.SimulateErr
 	}
-	return nil
+	e.postedTx = tx
+	return tx.Hash(crypto.SHA256)
 }
 
-func (e *evmClientMock) GetTxProof(ctx context.Context, unitID types.UnitID, txHash sdktypes.TxHash) (*types.TxRecordProof, error) {
+func (e *evmClientMock) GetTransactionProof(ctx context.Context, txHash hex.Bytes) (*types.TxRecordProof, error) {
 	if e.SimulateErr != nil {
 		return nil, e.SimulateErr
 	}
+	if e.noProof {
+		return nil, nil
+	}
 	details := evm.ProcessingDetails{
 		ErrorDetails: "some error string",
 	}
@@ -78,6 +98,9 @@ func (e *evmClientMock) Call(ctx context.Context, callAttr *evm.CallEVMRequest)
 	if e.SimulateErr != nil {
 		return nil, e.SimulateErr
 	}
+	if e.callFn != nil {
+		return e.callFn(callAttr), nil
+	}
 	return &evm.ProcessingDetails{
 		ErrorDetails: "actual execution failed",
 	}, nil
@@ -87,42 +110,38 @@ func (e *evmClientMock) GetTransactionCount(ctx context.Context, ethAddr []byte)
 	if e.SimulateErr != nil {
 		return 0, e.SimulateErr
 	}
-	return uint64(1), nil
-}
-
-func (e *evmClientMock) GetBalance(ctx context.Context, ethAddr []byte) (string, uint64, error) {
-	if e.SimulateErr != nil {
-		return "", 0, e.SimulateErr
-	}
 	if e.noFcb {
-		return "", 0, evmclient.ErrNotFound
+		return 0, sdktypes.ErrNotFound
+	}
+	if e.txCount != 0 {
+		return e.txCount, nil
 	}
-	return "100000", 0, nil
+	return uint64(1), nil
 }
 
-func (e *evmClientMock) GetFeeCreditBill(ctx context.Context, unitID types.UnitID) (*evmclient.Bill, error) {
+func (e *evmClientMock) GetBalance(ctx context.Context, ethAddr []byte) (*big.Int, error) {
 	if e.SimulateErr != nil {
 		return nil, e.SimulateErr
 	}
 	if e.noFcb {
-		return nil, nil
+		return nil, sdktypes.ErrNotFound
 	}
-	return &evmclient.Bill{
-		Id:    unitID,
-		Value: 100 * 1e8,
-	}, nil
+	return big.NewInt(100000), nil
 }
 
-func (e *evmClientMock) GetGasPrice(ctx context.Context) (string, error) {
+func (e *evmClientMock) GetGasPrice(ctx context.Context) (*big.Int, error) {
 	if e.SimulateErr != nil {
-		return "", e.SimulateErr
+		return nil, e.SimulateErr
 	}
 	if e.gasPrice != "" {
-		return e.gasPrice, nil
+		gasPrice, _ := new(big.Int).SetString(e.gasPrice, 10)
+		return gasPrice, nil
 	}
-	return "100", nil
+	return big.NewInt(100), nil
 }
 
+func (e *evmClientMock) Close() {}
+
 func createTestWallet(t *testing.T) (*Wallet, *evmClientMock) {
 	dir := t.TempDir()
 	am, err := account.NewManager(dir, "", true)
@@ -131,83 +150,32 @@ func createTestWallet(t *testing.T) (*Wallet, *evmClientMock) {
 	return &Wallet{
 		partitionID: evm.DefaultPartitionID,
 		am:          am,
-		restCli:     clientMock,
+		client:      clientMock,
 	}, clientMock
 }
 
-func TestConvertBalanceToAlpha(t *testing.T) {
-	type args struct {
-		wei *big.Int
-	}
-	tests := []struct {
-		name string
-		args args
-		want uint64
-	}{
-		{
-			name: "1 wei is 0 alpha",
-			args: args{wei: bigIntFromString(t, "1")},
-			want: 0,
-		},
-		{
-			name: "10^10-1 wei is 0 alpha",
-			args: args{wei: bigIntFromString(t, "9999999999")},
-			want: 0,
-		},
-		{
-			name: "10^10 wei is 1 alpha",
-			args: args{wei: bigIntFromString(t, "10000000000")},
-			want: 1,
-		},
-		{
-			name: "10^10+1 wei is 1 alpha",
-			args: args{wei: bigIntFromString(t, "10000000001")},
-			want: 1,
-		},
-		{
-			name: "(2*10^10)-1 wei is 1 alpha",
-			args: args{wei: bigIntFromString(t, "19999999999")},
-			want: 1,
-		},
-		{
-			name: "2*10^10 wei is 1 alpha",
-			args: args{wei: bigIntFromString(t, "20000000000")},
-			want: 2,
-		},
-		{
-			name: "12*10^10 wei is 1 alpha",
-			args: args{wei: bigIntFromString(t, "120000000000")},
-			want: 12,
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := ConvertBalanceToAlpha(tt.args.wei); got != tt.want {
-				t.Errorf("ConvertBalanceToAlpha() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestNew(t *testing.T) {
 	dir := t.TempDir()
 	am, err := account.NewManager(dir, "", true)
 	require.NoError(t, err)
-	// no partition id
-	w, err := New(0, ":23435", am)
-	require.ErrorContains(t, err, "partition id is unassigned")
-	require.Nil(t, w)
-	// no URL
-	w, err = New(1, "", am)
-	require.ErrorContains(t, err, "rest url is empty")
-	require.Nil(t, w)
+	ctx := context.Background()
+	pdr := &types.PartitionDescriptionRecord{NetworkID: 5, PartitionID: 7, PartitionTypeID: evm.PartitionTypeID}
+	srv := mocksrv.StartStateApiServer(t, pdr, mocksrv.NewStateServiceMock())
+	evmClient, err := client.NewEvmPartitionClient(ctx, "http://"+srv)
+	require.NoError(t, err)
 	// no account manager
-	w, err = New(1, ":23435", nil)
+	w, err := New(ctx, nil, evmClient)
 	require.ErrorContains(t, err, "account manager is nil")
 	require.Nil(t, w)
-	w, err = New(1, ":23435", am)
+	// no client
+	w, err = New(ctx, am, nil)
+	require.ErrorContains(t, err, "evm client is nil")
+	require.Nil(t, w)
+	w, err = New(ctx, am, evmClient)
 	require.NoError(t, err)
 	require.NotNil(t, w)
+	require.EqualValues(t, 5, w.networkID)
+	require.EqualValues(t, 7, w.partitionID)
 	w.Shutdown()
 }
 
@@ -229,6 +197,17 @@ func TestWallet_EvmCall(t *testing.T) {
 	res, err = w.EvmCall(ctx, 0, attrs)
 	require.ErrorContains(t, err, "invalid account number: 0")
 	require.Nil(t, res)
+	// reverted call, Panic(0x01)
+	clientMock.callFn = func(*evm.CallEVMRequest) *evm.ProcessingDetails {
+		return &evm.ProcessingDetails{ErrorDetails: "execution reverted", ReturnData: append(panicSelector[:4:4], common.LeftPadBytes([]byte{1}, 32)...)}
+	}
+	res, err = w.EvmCall(ctx, 1, attrs)
+	require.NoError(t, err)
+	require.False(t, res.Success)
+	require.NotNil(t, res.Revert)
+	require.Equal(t, "Panic", res.Revert.Name)
+	require.Equal(t, "assert(false)", res.Revert.Message)
+	clientMock.callFn = nil
 	// simulate error from client
 	clientMock.SimulateErr = fmt.Errorf("something bad happened")
 	res, err = w.EvmCall(ctx, 1, attrs)
@@ -297,3 +276,137 @@ func TestWallet_SendEvmTx(t *testing.T) {
 	require.ErrorContains(t, err, "insufficient fee credit balance for transaction")
 	require.Nil(t, res)
 }
+
+func TestWallet_PostEvmTx(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	ctx := context.Background()
+	_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{})
+	require.ErrorContains(t, err, "account key read failed: account does not exist")
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	// transaction is posted, confirmation is not waited for
+	clientMock.noProof = true
+	txHash, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 100})
+	require.NoError(t, err)
+	require.NotNil(t, clientMock.postedTx)
+	postedHash, err := clientMock.postedTx.Hash(crypto.SHA256)
+	require.NoError(t, err)
+	require.Equal(t, postedHash, txHash)
+	// simulate error from client
+	clientMock.SimulateErr = fmt.Errorf("something bad happened")
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{})
+	require.ErrorContains(t, err, "something bad happened")
+}
+
+func TestWallet_PostEvmTx_nonce(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	ctx := context.Background()
+	clientMock.noProof = true
+	nonce := func() uint64 {
+		attrs := &evm.TxAttributes{}
+		require.NoError(t, clientMock.postedTx.UnmarshalAttributes(attrs))
+		return attrs.Nonce
+	}
+	// the transaction count of the account is 1
+	_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 100})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce())
+	// the posted transaction is pending
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 100})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, nonce())
+	// the pending transaction has timed out
+	clientMock.round = 3 + txTimeoutBlockCount + 1
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 100})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, nonce())
+}
+
+func TestWallet_PostEvmTx_reservedFeeCredit(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	ctx := context.Background()
+	clientMock.noProof = true
+	// the balance is 100000 and the gas price 100
+	_, err := w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 600})
+	require.NoError(t, err)
+	// the pending transaction reserves 60000
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 500})
+	require.EqualError(t, err, "insufficient fee credit balance for transaction")
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 300, Value: big.NewInt(10000)})
+	require.NoError(t, err)
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 1})
+	require.EqualError(t, err, "insufficient fee credit balance for transaction")
+	// the first transaction is executed, the second one still reserves 40000
+	clientMock.txCount = 2
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 700})
+	require.EqualError(t, err, "insufficient fee credit balance for transaction")
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 600})
+	require.NoError(t, err)
+	// the pending transactions have timed out
+	clientMock.round = 3 + txTimeoutBlockCount + 1
+	_, err = w.PostEvmTx(ctx, 1, &evm.TxAttributes{Gas: 1000})
+	require.NoError(t, err)
+}
+
+func TestWallet_GetTxStatus(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	ctx := context.Background()
+	txHash := test.RandomBytes(32)
+	res, err := w.GetTxStatus(ctx, txHash)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	require.False(t, res.Success)
+	require.EqualValues(t, 1, res.ActualFee)
+	require.Equal(t, "some error string", res.Details.ErrorDetails)
+	require.Equal(t, txHash, res.TxHash)
+	require.NotNil(t, res.Proof)
+	// not confirmed
+	clientMock.noProof = true
+	res, err = w.GetTxStatus(ctx, txHash)
+	require.NoError(t, err)
+	require.Nil(t, res)
+	// simulate error from client
+	clientMock.SimulateErr = fmt.Errorf("something bad happened")
+	_, err = w.GetTxStatus(ctx, txHash)
+	require.ErrorContains(t, err, "failed to read transaction proof: something bad happened")
+}
+
+func TestWallet_Transfer(t *testing.T) {
+	w, clientMock := createTestWallet(t)
+	ctx := context.Background()
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	_, err := w.Transfer(ctx, 1, to, big.NewInt(1000), 0)
+	require.ErrorContains(t, err, "account key read failed: account does not exist")
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	_, err = w.Transfer(ctx, 1, to, big.NewInt(0), 0)
+	require.ErrorContains(t, err, "transfer value must be positive")
+	_, err = w.Transfer(ctx, 1, to, nil, 0)
+	require.ErrorContains(t, err, "transfer value must be positive")
+	// balance 100000 wei, gas price 1 wei, fee 21000 wei
+	clientMock.gasPrice = "1"
+	res, err := w.Transfer(ctx, 1, to, big.NewInt(1000), 0)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	// balance must cover both fee and value
+	_, err = w.Transfer(ctx, 1, to, big.NewInt(80000), 0)
+	require.ErrorContains(t, err, "insufficient fee credit balance for transaction")
+	_, err = w.Transfer(ctx, 1, to, big.NewInt(1000), 100000)
+	require.ErrorContains(t, err, "insufficient fee credit balance for transaction")
+}
+
+func TestWallet_GetAccountAddress(t *testing.T) {
+	w, _ := createTestWallet(t)
+	_, err := w.GetAccountAddress(1)
+	require.ErrorContains(t, err, "account key read failed: account does not exist")
+	require.NoError(t, w.am.CreateKeys(testMnemonic))
+	_, err = w.GetAccountAddress(0)
+	require.ErrorContains(t, err, "invalid account number: 0")
+	addr, err := w.GetAccountAddress(1)
+	require.NoError(t, err)
+	acc, err := w.am.GetAccountKey(0)
+	require.NoError(t, err)
+	expected, err := AddressFromPublicKey(acc.PubKey)
+	require.NoError(t, err)
+	require.Equal(t, expected, addr)
+}
@@ -196,7 +196,7 @@ func (w *FeeManager) AddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeeCm
 	if cmd.Amount < w.MinAddFeeAmount() {
 		return nil, ErrMinimumFeeAmount
 	}
-	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
+	accountKey, err := w.am.GetSigningKey(cmd.AccountIndex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load account key: %w", err)
 	}
@@ -245,7 +245,7 @@ func (w *FeeManager) AddFeeCredit(ctx context.Context, cmd AddFeeCmd) (*AddFeeCm
 // Reclaimed fee credit is added to the largest bill in wallet.
 // Returns transaction proofs that were used to reclaim fee credit.
 func (w *FeeManager) ReclaimFeeCredit(ctx context.Context, cmd ReclaimFeeCmd) (*ReclaimFeeCmdResponse, error) {
-	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
+	accountKey, err := w.am.GetSigningKey(cmd.AccountIndex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load account key: %w", err)
 	}
@@ -302,7 +302,7 @@ func (w *FeeManager) GetFeeCredit(ctx context.Context, cmd GetFeeCreditCmd) (*sd
 // LockFeeCredit locks fee credit record for given account, returns error if fee credit record has not been created yet
 // or is already locked.
 func (w *FeeManager) LockFeeCredit(ctx context.Context, cmd LockFeeCreditCmd) (*types.TxRecordProof, error) {
-	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
+	accountKey, err := w.am.GetSigningKey(cmd.AccountIndex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load account key: %w", err)
 	}
@@ -346,7 +346,7 @@ func (w *FeeManager) LockFeeCredit(ctx context.Context, cmd LockFeeCreditCmd) (*
 // UnlockFeeCredit unlocks fee credit record for given account, returns error if fee credit record has not been created yet
 // or is already unlocked.
 func (w *FeeManager) UnlockFeeCredit(ctx context.Context, cmd UnlockFeeCreditCmd) (*types.TxRecordProof, error) {
-	accountKey, err := w.am.GetAccountKey(cmd.AccountIndex)
+	accountKey, err := w.am.GetSigningKey(cmd.AccountIndex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load account key: %w", err)
 	}
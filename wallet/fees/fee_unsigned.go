@@ -0,0 +1,320 @@
+package fees
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/util"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+/*
+BuildUnsignedTransferFC builds the transferFC transaction of the fee credit addition without
+signing nor sending it, the transaction is returned in the envelope to be signed offline with
+the account key. The transaction is valid for the timeout number of rounds of the money partition.
+
+The amount is transferred from a single bill and the fee credit record is not locked. When the
+signed transaction has been executed, the addFC transaction is built from its proof with
+BuildUnsignedAddFC.
+*/
+func (w *FeeManager) BuildUnsignedTransferFC(ctx context.Context, cmd AddFeeCmd, timeout uint64) (*offline.Envelope, error) {
+	if cmd.Amount < w.MinAddFeeAmount() {
+		return nil, ErrMinimumFeeAmount
+	}
+	accountKey, err := w.getUnsignedAccountKey(cmd.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr != nil && fcr.StateLockTx != nil {
+		return nil, errors.New("fee credit record is locked")
+	}
+	bills, err := w.fetchBills(ctx, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	bills, _ = util.FilterSlice(bills, func(b *sdktypes.Bill) (bool, error) {
+		return b.StateLockTx == nil, nil
+	})
+	if len(bills) == 0 {
+		return nil, errors.New("wallet does not contain any bills")
+	}
+	// bills are sorted by value, largest first
+	sourceBill := bills[0]
+	if sourceBill.Value < cmd.Amount {
+		return nil, fmt.Errorf("%w: unsigned fee credit transfer requires a single bill with at least %s value, largest bill is %s",
+			ErrInsufficientBalance, util.AmountToString(cmd.Amount, 8), util.AmountToString(sourceBill.Value, 8))
+	}
+
+	moneyRoundInfo, err := w.moneyClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch money partition round info: %w", err)
+	}
+	targetRoundInfo, err := w.targetPartitionClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target partition round info: %w", err)
+	}
+	// the addFC transaction is built after the transferFC has been executed, leave it the full
+	// time to add the fee credit counting from the timeout of the transferFC transaction
+	latestAdditionTime := targetRoundInfo.RoundNumber + timeout + transferFCLatestAdditionTime
+	if fcr == nil {
+		fcrID, err := w.targetPartitionFcrIDFn(types.ShardID{}, accountKey.PubKey, latestAdditionTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate fee credit record id: %w", err)
+		}
+		fcr = &sdktypes.FeeCreditRecord{
+			PartitionID: w.targetPartitionID,
+			ID:          fcrID,
+		}
+	}
+	txBill := &sdktypes.Bill{
+		NetworkID:   w.networkID,
+		PartitionID: w.moneyPartitionID,
+		ID:          sourceBill.ID,
+		Counter:     sourceBill.Counter,
+	}
+	tx, err := txBill.TransferToFeeCredit(fcr, cmd.Amount, latestAdditionTime,
+		sdktypes.WithTimeout(moneyRoundInfo.RoundNumber+timeout),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transferFC transaction: %w", err)
+	}
+	envelope, err := newEnvelope(ctx, w.moneyClient, cmd.AccountIndex, accountKey, tx)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Bills = []*sdktypes.Bill{sourceBill}
+	return envelope, nil
+}
+
+/*
+BuildUnsignedAddFC builds the addFC transaction of the fee credit addition from the proof of the
+executed transferFC transaction, the transaction is returned in the envelope to be signed offline
+with the account key. The transaction is valid for the timeout number of rounds of the target partition.
+*/
+func (w *FeeManager) BuildUnsignedAddFC(ctx context.Context, accountIndex uint64, transferFCProof *types.TxRecordProof, timeout uint64) (*offline.Envelope, error) {
+	accountKey, err := w.getUnsignedAccountKey(accountIndex)
+	if err != nil {
+		return nil, err
+	}
+	transferFC, err := provenFeeCreditTx(transferFCProof, fc.TransactionTypeTransferFeeCredit)
+	if err != nil {
+		return nil, err
+	}
+	attr := &fc.TransferFeeCreditAttributes{}
+	if err := transferFC.UnmarshalAttributes(attr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transferFC attributes: %w", err)
+	}
+	if transferFC.NetworkID != w.networkID || attr.TargetPartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("transferFC is for network %d partition %d, expected network %d partition %d",
+			transferFC.NetworkID, attr.TargetPartitionID, w.networkID, w.targetPartitionID)
+	}
+	roundInfo, err := w.targetPartitionClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target partition round info: %w", err)
+	}
+	if roundInfo.RoundNumber >= attr.LatestAdditionTime {
+		return nil, fmt.Errorf("transferFC latest addition time %d exceeded (current round %d)", attr.LatestAdditionTime, roundInfo.RoundNumber)
+	}
+
+	fcr := &sdktypes.FeeCreditRecord{
+		NetworkID:   w.networkID,
+		PartitionID: w.targetPartitionID,
+		ID:          attr.TargetRecordID,
+	}
+	ownerPredicate := templates.NewP2pkh256BytesFromKeyHash(accountKey.PubKeyHash.Sha256)
+	tx, err := fcr.AddFeeCredit(ownerPredicate, transferFCProof,
+		sdktypes.WithTimeout(min(roundInfo.RoundNumber+timeout, attr.LatestAdditionTime)),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create addFC transaction: %w", err)
+	}
+	return newEnvelope(ctx, w.targetPartitionClient, accountIndex, accountKey, tx)
+}
+
+/*
+BuildUnsignedCloseFC builds the closeFC transaction of the fee credit reclaim without signing nor
+sending it, the transaction is returned in the envelope to be signed offline with the account key.
+The transaction is valid for the timeout number of rounds of the target partition.
+
+The fee credit is reclaimed to the largest bill and the bill is not locked. When the signed
+transaction has been executed, the reclaimFC transaction is built from its proof with
+BuildUnsignedReclaimFC.
+*/
+func (w *FeeManager) BuildUnsignedCloseFC(ctx context.Context, cmd ReclaimFeeCmd, timeout uint64) (*offline.Envelope, error) {
+	accountKey, err := w.getUnsignedAccountKey(cmd.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+	fcr, err := w.fetchTargetPartitionFCR(ctx, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return nil, errors.New("fee credit record not found")
+	}
+	if fcr.StateLockTx != nil {
+		return nil, errors.New("fee credit record is locked")
+	}
+	if fcr.Balance < w.MinReclaimFeeAmount() {
+		return nil, ErrMinimumFeeAmount
+	}
+	bills, err := w.fetchBills(ctx, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	bills, _ = util.FilterSlice(bills, func(b *sdktypes.Bill) (bool, error) {
+		return b.StateLockTx == nil, nil
+	})
+	if len(bills) == 0 {
+		return nil, errors.New("wallet must have a source bill to which to add reclaimed fee credits")
+	}
+	targetBill := bills[0]
+
+	roundInfo, err := w.targetPartitionClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch target partition round info: %w", err)
+	}
+	tx, err := fcr.CloseFeeCredit(targetBill.ID, targetBill.Counter,
+		sdktypes.WithTimeout(roundInfo.RoundNumber+timeout),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create closeFC transaction: %w", err)
+	}
+	envelope, err := newEnvelope(ctx, w.targetPartitionClient, cmd.AccountIndex, accountKey, tx)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Bills = []*sdktypes.Bill{targetBill}
+	envelope.FeeCreditRecord = fcr
+	return envelope, nil
+}
+
+/*
+BuildUnsignedReclaimFC builds the reclaimFC transaction of the fee credit reclaim from the proof
+of the executed closeFC transaction, the transaction is returned in the envelope to be signed
+offline with the account key. The transaction is valid for the timeout number of rounds of the
+money partition.
+*/
+func (w *FeeManager) BuildUnsignedReclaimFC(ctx context.Context, accountIndex uint64, closeFCProof *types.TxRecordProof, timeout uint64) (*offline.Envelope, error) {
+	accountKey, err := w.getUnsignedAccountKey(accountIndex)
+	if err != nil {
+		return nil, err
+	}
+	closeFC, err := provenFeeCreditTx(closeFCProof, fc.TransactionTypeCloseFeeCredit)
+	if err != nil {
+		return nil, err
+	}
+	attr := &fc.CloseFeeCreditAttributes{}
+	if err := closeFC.UnmarshalAttributes(attr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal closeFC attributes: %w", err)
+	}
+	if closeFC.NetworkID != w.networkID || closeFC.PartitionID != w.targetPartitionID {
+		return nil, fmt.Errorf("closeFC is for network %d partition %d, expected network %d partition %d",
+			closeFC.NetworkID, closeFC.PartitionID, w.networkID, w.targetPartitionID)
+	}
+	targetBill, err := w.moneyClient.GetBill(ctx, attr.TargetUnitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bill: %w", err)
+	}
+	if targetBill == nil || targetBill.Counter != attr.TargetUnitCounter {
+		return nil, errors.New("closeFC target bill is no longer usable")
+	}
+
+	roundInfo, err := w.moneyClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch money partition round info: %w", err)
+	}
+	txBill := &sdktypes.Bill{
+		NetworkID:   w.networkID,
+		PartitionID: w.moneyPartitionID,
+		ID:          targetBill.ID,
+		Counter:     targetBill.Counter,
+	}
+	tx, err := txBill.ReclaimFromFeeCredit(closeFCProof,
+		sdktypes.WithTimeout(roundInfo.RoundNumber+timeout),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reclaimFC transaction: %w", err)
+	}
+	envelope, err := newEnvelope(ctx, w.moneyClient, accountIndex, accountKey, tx)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Bills = []*sdktypes.Bill{targetBill}
+	return envelope, nil
+}
+
+// getUnsignedAccountKey returns the account key for building unsigned transactions, the
+// public key is required and the account must not have a pending fee credit process.
+func (w *FeeManager) getUnsignedAccountKey(accountIndex uint64) (*account.AccountKey, error) {
+	accountKey, err := w.am.GetAccountKey(accountIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load account key: %w", err)
+	}
+	if len(accountKey.PubKey) == 0 {
+		return nil, fmt.Errorf("key #%d is watch-only account of public key hash, public key is required to build transactions", accountIndex+1)
+	}
+	addFeeCtx, err := w.db.GetAddFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load add fee context: %w", err)
+	}
+	if addFeeCtx != nil {
+		return nil, errors.New("wallet contains unadded fee credit, run the add command to finish the pending fee credit process")
+	}
+	reclaimFeeCtx, err := w.db.GetReclaimFeeContext(accountKey.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reclaim fee context: %w", err)
+	}
+	if reclaimFeeCtx != nil {
+		return nil, errors.New("wallet contains unreclaimed fee credit, run the reclaim command to finish the pending fee credit process")
+	}
+	return accountKey, nil
+}
+
+// provenFeeCreditTx returns the successfully executed transaction of the expected type from the proof.
+func provenFeeCreditTx(proof *types.TxRecordProof, txType uint16) (*types.TransactionOrder, error) {
+	if proof == nil || proof.TxRecord == nil {
+		return nil, errors.New("transaction proof is missing")
+	}
+	tx, err := proof.TxRecord.GetTransactionOrderV1()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction of the proof: %w", err)
+	}
+	if tx.Type != txType {
+		return nil, fmt.Errorf("expected proof of transaction type %d, got %d", txType, tx.Type)
+	}
+	if !proof.TxRecord.IsSuccessful() {
+		return nil, fmt.Errorf("transaction of the proof failed with status %d", proof.TxRecord.TxStatus())
+	}
+	return tx, nil
+}
+
+func newEnvelope(ctx context.Context, partitionClient sdktypes.PartitionClient, accountIndex uint64, accountKey *account.AccountKey, tx *types.TransactionOrder) (*offline.Envelope, error) {
+	pdr, err := partitionClient.PartitionDescription(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partition description: %w", err)
+	}
+	return &offline.Envelope{
+		Version:         offline.EnvelopeVersion,
+		NetworkID:       tx.NetworkID,
+		PartitionID:     tx.PartitionID,
+		PartitionTypeID: pdr.PartitionTypeID,
+		AccountIndex:    accountIndex,
+		OwnerPubKey:     accountKey.PubKey,
+		Transactions:    []*types.TransactionOrder{tx},
+	}, nil
+}
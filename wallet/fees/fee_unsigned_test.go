@@ -0,0 +1,147 @@
+package fees
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
+	testmoney "github.com/alphabill-org/alphabill-wallet/internal/testutils/money"
+)
+
+func TestBuildUnsignedAddFeeCredit(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	bill := testmoney.NewBill(t, 100000000, 20)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(bill),
+		testmoney.WithRoundNumber(100),
+	)
+	feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+	// transferFC
+	envelope, err := feeManager.BuildUnsignedTransferFC(context.Background(), AddFeeCmd{Amount: 50000000}, 10)
+	require.NoError(t, err)
+	require.NoError(t, envelope.IsValid())
+	require.EqualValues(t, money.PartitionTypeID, envelope.PartitionTypeID)
+	require.Equal(t, bill, envelope.Bills[0])
+	transferFC := envelope.Transactions[0]
+	require.Equal(t, fc.TransactionTypeTransferFeeCredit, transferFC.Type)
+	require.EqualValues(t, 110, transferFC.Timeout())
+	transferAttr := &fc.TransferFeeCreditAttributes{}
+	require.NoError(t, transferFC.UnmarshalAttributes(transferAttr))
+	require.EqualValues(t, 50000000, transferAttr.Amount)
+	require.EqualValues(t, bill.Counter, transferAttr.Counter)
+	require.EqualValues(t, 110+transferFCLatestAdditionTime, transferAttr.LatestAdditionTime)
+
+	require.NoError(t, envelope.Sign(accountKey))
+	proofs, err := envelope.Submit(context.Background(), moneyClient, true, logger.New(t))
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+
+	// addFC from the proof of transferFC
+	envelope, err = feeManager.BuildUnsignedAddFC(context.Background(), 0, proofs[0], 10)
+	require.NoError(t, err)
+	require.NoError(t, envelope.IsValid())
+	addFC := envelope.Transactions[0]
+	require.Equal(t, fc.TransactionTypeAddFeeCredit, addFC.Type)
+	require.EqualValues(t, transferAttr.TargetRecordID, addFC.UnitID)
+	require.EqualValues(t, 110, addFC.Timeout())
+	addAttr := &fc.AddFeeCreditAttributes{}
+	require.NoError(t, addFC.UnmarshalAttributes(addAttr))
+	require.Equal(t, proofs[0].TxRecord.TransactionOrder, addAttr.FeeCreditTransferProof.TxRecord.TransactionOrder)
+
+	require.NoError(t, envelope.Sign(accountKey))
+	_, err = envelope.Submit(context.Background(), moneyClient, true, logger.New(t))
+	require.NoError(t, err)
+	require.Len(t, moneyClient.RecordedTxs, 2)
+
+	// the proof of the wrong transaction is rejected
+	_, err = feeManager.BuildUnsignedAddFC(context.Background(), 0, &types.TxRecordProof{TxRecord: successfulTxRecord(t, addFC)}, 10)
+	require.EqualError(t, err, "expected proof of transaction type 14, got 16")
+
+	// the addition time has passed
+	moneyClient.RoundNumber = transferAttr.LatestAdditionTime
+	_, err = feeManager.BuildUnsignedAddFC(context.Background(), 0, proofs[0], 10)
+	require.ErrorContains(t, err, "latest addition time")
+}
+
+func TestBuildUnsignedReclaimFeeCredit(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	bill := testmoney.NewBill(t, 100000000, 20)
+	fcr := newMoneyFCR(t, accountKey, &fc.FeeCreditRecord{Balance: 1000, Counter: 2})
+	fcr.NetworkID = types.NetworkLocal
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(bill),
+		testmoney.WithOwnerFeeCreditRecord(fcr),
+		testmoney.WithRoundNumber(100),
+	)
+	feeManager := newMoneyPartitionFeeManager(am, createFeeManagerDB(t), moneyClient, logger.New(t))
+
+	// closeFC
+	envelope, err := feeManager.BuildUnsignedCloseFC(context.Background(), ReclaimFeeCmd{}, 10)
+	require.NoError(t, err)
+	require.NoError(t, envelope.IsValid())
+	require.Equal(t, fcr, envelope.FeeCreditRecord)
+	closeFC := envelope.Transactions[0]
+	require.Equal(t, fc.TransactionTypeCloseFeeCredit, closeFC.Type)
+	require.EqualValues(t, fcr.ID, closeFC.UnitID)
+	closeAttr := &fc.CloseFeeCreditAttributes{}
+	require.NoError(t, closeFC.UnmarshalAttributes(closeAttr))
+	require.EqualValues(t, 1000, closeAttr.Amount)
+	require.EqualValues(t, bill.ID, closeAttr.TargetUnitID)
+	require.EqualValues(t, bill.Counter, closeAttr.TargetUnitCounter)
+
+	require.NoError(t, envelope.Sign(accountKey))
+	proofs, err := envelope.Submit(context.Background(), moneyClient, true, logger.New(t))
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+
+	// reclaimFC from the proof of closeFC
+	envelope, err = feeManager.BuildUnsignedReclaimFC(context.Background(), 0, proofs[0], 10)
+	require.NoError(t, err)
+	require.NoError(t, envelope.IsValid())
+	reclaimFC := envelope.Transactions[0]
+	require.Equal(t, fc.TransactionTypeReclaimFeeCredit, reclaimFC.Type)
+	require.EqualValues(t, bill.ID, reclaimFC.UnitID)
+	require.EqualValues(t, 110, reclaimFC.Timeout())
+
+	require.NoError(t, envelope.Sign(accountKey))
+	_, err = envelope.Submit(context.Background(), moneyClient, true, logger.New(t))
+	require.NoError(t, err)
+	require.Len(t, moneyClient.RecordedTxs, 2)
+
+	// the target bill has been used after closeFC
+	bill.Counter++
+	_, err = feeManager.BuildUnsignedReclaimFC(context.Background(), 0, proofs[0], 10)
+	require.EqualError(t, err, "closeFC target bill is no longer usable")
+}
+
+func TestBuildUnsignedFeeCredit_PendingFeeContext(t *testing.T) {
+	am := newAccountManager(t)
+	accountKey, err := am.GetAccountKey(0)
+	require.NoError(t, err)
+	moneyClient := testmoney.NewRpcClientMock(testmoney.WithOwnerBill(testmoney.NewBill(t, 100000000, 20)))
+	db := createFeeManagerDB(t)
+	require.NoError(t, db.SetAddFeeContext(accountKey.PubKey, &AddFeeCreditCtx{}))
+	feeManager := newMoneyPartitionFeeManager(am, db, moneyClient, logger.New(t))
+
+	_, err = feeManager.BuildUnsignedTransferFC(context.Background(), AddFeeCmd{Amount: 50000000}, 10)
+	require.ErrorContains(t, err, "wallet contains unadded fee credit")
+	_, err = feeManager.BuildUnsignedCloseFC(context.Background(), ReclaimFeeCmd{}, 10)
+	require.ErrorContains(t, err, "wallet contains unadded fee credit")
+}
+
+func successfulTxRecord(t *testing.T, tx *types.TransactionOrder) *types.TransactionRecord {
+	return &types.TransactionRecord{
+		TransactionOrder: txV1ToBytes(t, tx),
+		ServerMetadata:   &types.ServerMetadata{SuccessIndicator: types.TxStatusSuccessful},
+	}
+}
@@ -0,0 +1,421 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/fxamacker/cbor/v2"
+)
+
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+
+	// syncBatchRounds is the number of rounds indexed in one DB transaction.
+	syncBatchRounds = 100
+)
+
+type (
+	// BlockReader reads partition blocks, returns nil block if the round has no block.
+	BlockReader interface {
+		GetBlock(ctx context.Context, roundNumber uint64) (*types.Block, error)
+	}
+
+	HistoryDB interface {
+		GetIndexState(pdr *types.PartitionDescriptionRecord) (*IndexState, error)
+		AddEntries(pdr *types.PartitionDescriptionRecord, entries []*Entry, state *IndexState) error
+		GetEntries(pdr *types.PartitionDescriptionRecord, filter *Filter) ([]*Entry, error)
+	}
+
+	// IndexState is the range of the rounds indexed for the owners of the partition.
+	IndexState struct {
+		StartRound uint64 `json:"startRound"`
+		LastRound  uint64 `json:"lastRound"`
+		// Owners are the identifiers of the indexed owners, see Owner.id.
+		Owners []hex.Bytes `json:"owners"`
+	}
+
+	// Owner is an account key of the wallet, the transactions signed by the key or
+	// paying to the P2PKH predicate of the key are indexed. The public key of the
+	// owner of public key hash is unknown, only the received transactions are indexed.
+	Owner struct {
+		AccountIndex uint64
+		PubKey       []byte
+		predicate    []byte
+	}
+
+	// Entry is a transaction of the history of an account, a transaction
+	// which touches several accounts has an entry for every account.
+	Entry struct {
+		RoundNumber     uint64    `json:"roundNumber"`
+		TxIndex         int       `json:"txIndex"`
+		TxHash          hex.Bytes `json:"txHash"`
+		TxType          uint16    `json:"txType"`
+		UnitID          hex.Bytes `json:"unitId"`
+		ReferenceNumber hex.Bytes `json:"referenceNumber,omitempty"`
+		AccountIndex    uint64    `json:"accountIndex"`
+		// Direction is "sent" if the account signed the transaction, otherwise "received".
+		Direction string `json:"direction"`
+		// Amount is the value sent to the other owners or received by the account,
+		// zero if the transaction does not move value of the money partition.
+		Amount uint64 `json:"amount"`
+		// Fee is the actual fee of the transaction, set only for the sent transactions.
+		Fee     uint64 `json:"fee"`
+		Success bool   `json:"success"`
+		// NewUnitIDs are the units the transaction creates for the account, e.g. the new bills of a split.
+		NewUnitIDs []hex.Bytes `json:"newUnitIds,omitempty"`
+	}
+
+	// Filter selects the history entries, zero values match all the entries.
+	Filter struct {
+		AccountIndexes  []uint64
+		FromRound       uint64
+		ToRound         uint64
+		TxTypes         []uint16
+		ReferenceNumber []byte
+	}
+
+	// Indexer indexes the transactions of a partition which touch the owners.
+	Indexer struct {
+		db     HistoryDB
+		blocks BlockReader
+		pdr    *types.PartitionDescriptionRecord
+		owners []*Owner
+		log    *slog.Logger
+	}
+)
+
+func NewOwner(accountIndex uint64, pubKey []byte) *Owner {
+	return &Owner{AccountIndex: accountIndex, PubKey: pubKey, predicate: templates.NewP2pkh256BytesFromKey(pubKey)}
+}
+
+// NewOwnerFromKeyHash creates the owner of the watch-only account of public key hash.
+func NewOwnerFromKeyHash(accountIndex uint64, pubKeyHash []byte) *Owner {
+	return &Owner{AccountIndex: accountIndex, predicate: templates.NewP2pkh256BytesFromKeyHash(pubKeyHash)}
+}
+
+func NewIndexer(db HistoryDB, blocks BlockReader, pdr *types.PartitionDescriptionRecord, owners []*Owner, log *slog.Logger) *Indexer {
+	return &Indexer{
+		db:     db,
+		blocks: blocks,
+		pdr:    pdr,
+		owners: owners,
+		log:    log,
+	}
+}
+
+/*
+Sync indexes the blocks from the round after the last indexed round up to toRound (inclusive).
+The first sync starts from fromRound (from the first round if zero), the blocks before the
+start round are indexed when a sync is requested from an earlier round. When owners are added,
+e.g. a key is added to the wallet, the already indexed rounds are indexed again for the new
+owners only. The progress is saved every syncBatchRounds rounds, so an interrupted sync continues
+from the last saved round.
+*/
+func (x *Indexer) Sync(ctx context.Context, fromRound, toRound uint64) error {
+	state, err := x.db.GetIndexState(x.pdr)
+	if err != nil {
+		return fmt.Errorf("failed to load transaction history index state: %w", err)
+	}
+	if state == nil {
+		state = &IndexState{StartRound: max(fromRound, 1)}
+		state.LastRound = state.StartRound - 1
+	}
+
+	// the previous index state is kept until the missing rounds are indexed, the
+	// entries are overwritten when an interrupted sync indexes the rounds again
+	if newOwners := x.newOwners(state); len(newOwners) > 0 && state.LastRound >= state.StartRound {
+		x.log.InfoContext(ctx, "wallet keys have been added, indexing transaction history of the new keys")
+		if err := x.indexRounds(ctx, newOwners, state.StartRound, state.LastRound, nil); err != nil {
+			return err
+		}
+	}
+	if fromRound != 0 && fromRound < state.StartRound {
+		x.log.InfoContext(ctx, fmt.Sprintf("indexing transaction history from round %d", fromRound))
+		if err := x.indexRounds(ctx, x.owners, fromRound, state.StartRound-1, nil); err != nil {
+			return err
+		}
+		state.StartRound = fromRound
+	}
+	state.Owners = nil
+	for _, o := range x.owners {
+		state.Owners = append(state.Owners, o.id())
+	}
+	if err := x.db.AddEntries(x.pdr, nil, state); err != nil {
+		return fmt.Errorf("failed to store transaction history: %w", err)
+	}
+	return x.indexRounds(ctx, x.owners, state.LastRound+1, toRound, state)
+}
+
+// Match returns true if the entry matches the account, type and reference number filters,
+// the round range is applied by the store.
+func (f *Filter) Match(e *Entry) bool {
+	if len(f.AccountIndexes) > 0 && !slices.Contains(f.AccountIndexes, e.AccountIndex) {
+		return false
+	}
+	if len(f.TxTypes) > 0 && !slices.Contains(f.TxTypes, e.TxType) {
+		return false
+	}
+	if f.ReferenceNumber != nil && !bytes.Equal(f.ReferenceNumber, e.ReferenceNumber) {
+		return false
+	}
+	return true
+}
+
+/*
+indexRounds indexes the blocks from fromRound up to toRound (inclusive) for the owners. The
+entries are saved every syncBatchRounds rounds together with the last indexed round of the
+state, the state is not saved if it is nil.
+*/
+func (x *Indexer) indexRounds(ctx context.Context, owners []*Owner, fromRound, toRound uint64, state *IndexState) error {
+	var entries []*Entry
+	flushed := fromRound - 1
+	for round := fromRound; round <= toRound; round++ {
+		if err := ctx.Err(); err != nil {
+			return x.flush(entries, flushed, round-1, state, err)
+		}
+		block, err := x.blocks.GetBlock(ctx, round)
+		if err != nil {
+			return x.flush(entries, flushed, round-1, state, fmt.Errorf("failed to load block of round %d: %w", round, err))
+		}
+		if block != nil {
+			var roundEntries []*Entry
+			for txIndex, txr := range block.Transactions {
+				txEntries, err := x.extractEntries(owners, txr, round, txIndex)
+				if err != nil {
+					return x.flush(entries, flushed, round-1, state, err)
+				}
+				roundEntries = append(roundEntries, txEntries...)
+			}
+			entries = append(entries, roundEntries...)
+		}
+		if round-flushed >= syncBatchRounds || round == toRound {
+			if err := x.flush(entries, flushed, round, state, nil); err != nil {
+				return err
+			}
+			entries = nil
+			flushed = round
+			x.log.DebugContext(ctx, fmt.Sprintf("indexed transaction history up to round %d", round))
+		}
+	}
+	return nil
+}
+
+// flush saves the entries of the fully indexed rounds before returning the error.
+func (x *Indexer) flush(entries []*Entry, flushed, lastRound uint64, state *IndexState, err error) error {
+	if lastRound == flushed {
+		return err
+	}
+	if state != nil {
+		state.LastRound = lastRound
+	}
+	if dbErr := x.db.AddEntries(x.pdr, entries, state); dbErr != nil {
+		if err == nil {
+			return fmt.Errorf("failed to store transaction history: %w", dbErr)
+		}
+		return fmt.Errorf("failed to store transaction history: %w (%w)", dbErr, err)
+	}
+	return err
+}
+
+// newOwners returns the owners which are not indexed yet.
+func (x *Indexer) newOwners(state *IndexState) []*Owner {
+	var owners []*Owner
+	for _, o := range x.owners {
+		if !slices.ContainsFunc(state.Owners, func(id hex.Bytes) bool { return bytes.Equal(id, o.id()) }) {
+			owners = append(owners, o)
+		}
+	}
+	return owners
+}
+
+// id identifies the owner by the public key, or by the predicate if the public key is unknown.
+func (o *Owner) id() []byte {
+	if o.PubKey != nil {
+		return o.PubKey
+	}
+	return o.predicate
+}
+
+// extractEntries returns an entry for every owner the transaction touches, the owner
+// touches the transaction by signing it or by being the new owner of a unit.
+func (x *Indexer) extractEntries(owners []*Owner, txr *types.TransactionRecord, round uint64, txIndex int) ([]*Entry, error) {
+	txo, err := txr.GetTransactionOrderV1()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction %d of round %d: %w", txIndex, round, err)
+	}
+	var attr any
+	if err := types.Cbor.Unmarshal(txo.Attributes, &attr); err != nil {
+		return nil, fmt.Errorf("failed to decode attributes of transaction %d of round %d: %w", txIndex, round, err)
+	}
+	signer := signerPubKey(txo)
+
+	var entries []*Entry
+	var txHash []byte
+	for _, o := range owners {
+		sent := o.PubKey != nil && bytes.Equal(signer, o.PubKey)
+		if !sent && !containsBytes(attr, o.predicate) {
+			continue
+		}
+		if txHash == nil {
+			if txHash, err = txo.Hash(crypto.SHA256); err != nil {
+				return nil, fmt.Errorf("failed to hash transaction %d of round %d: %w", txIndex, round, err)
+			}
+		}
+		e := &Entry{
+			RoundNumber:     round,
+			TxIndex:         txIndex,
+			TxHash:          txHash,
+			TxType:          txo.Type,
+			UnitID:          hex.Bytes(txo.GetUnitID()),
+			ReferenceNumber: txo.ReferenceNumber(),
+			AccountIndex:    o.AccountIndex,
+			Direction:       DirectionReceived,
+			Success:         txr.IsSuccessful(),
+		}
+		if sent {
+			e.Direction = DirectionSent
+			e.Fee = txr.GetActualFee()
+		}
+		if x.pdr.PartitionTypeID == money.PartitionTypeID {
+			if e.Amount, err = moneyAmount(txo, o.predicate, sent); err != nil {
+				return nil, fmt.Errorf("failed to decode attributes of transaction %d of round %d: %w", txIndex, round, err)
+			}
+			if e.NewUnitIDs, err = x.newBillIDs(txo, o.predicate); err != nil {
+				return nil, fmt.Errorf("failed to compose new bill identifiers of transaction %d of round %d: %w", txIndex, round, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// moneyAmount returns the value the transaction sends to the other owners if
+// the owner signed the transaction, otherwise the value the owner receives.
+func moneyAmount(txo *types.TransactionOrder, predicate []byte, sent bool) (uint64, error) {
+	var amount uint64
+	switch txo.Type {
+	case money.TransactionTypeTransfer:
+		attr := &money.TransferAttributes{}
+		if err := txo.UnmarshalAttributes(attr); err != nil {
+			return 0, err
+		}
+		if sent != bytes.Equal(attr.NewOwnerPredicate, predicate) {
+			amount = attr.TargetValue
+		}
+	case money.TransactionTypeSplit:
+		attr := &money.SplitAttributes{}
+		if err := txo.UnmarshalAttributes(attr); err != nil {
+			return 0, err
+		}
+		for _, u := range attr.TargetUnits {
+			if sent != bytes.Equal(u.OwnerPredicate, predicate) {
+				amount += u.Amount
+			}
+		}
+	case fc.TransactionTypeTransferFeeCredit:
+		attr := &fc.TransferFeeCreditAttributes{}
+		if err := txo.UnmarshalAttributes(attr); err != nil {
+			return 0, err
+		}
+		if sent {
+			amount = attr.Amount
+		}
+	}
+	return amount, nil
+}
+
+// newBillIDs returns the identifiers of the bills the split transaction creates for the owner, the
+// identifiers are generated from the transaction in the order of the target units.
+func (x *Indexer) newBillIDs(txo *types.TransactionOrder, predicate []byte) ([]hex.Bytes, error) {
+	if txo.Type != money.TransactionTypeSplit {
+		return nil, nil
+	}
+	attr := &money.SplitAttributes{}
+	if err := txo.UnmarshalAttributes(attr); err != nil {
+		return nil, err
+	}
+	var ids []hex.Bytes
+	prndSh := money.PrndSh(txo)
+	for _, u := range attr.TargetUnits {
+		id, err := x.pdr.ComposeUnitID(types.ShardID{}, money.BillUnitType, prndSh)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(u.OwnerPredicate, predicate) {
+			ids = append(ids, hex.Bytes(id))
+		}
+	}
+	return ids, nil
+}
+
+/*
+LastModifiedRounds returns the rounds in which the units were last modified by the successful
+transactions of the entries, by unit ID. The entries must be in the order of the rounds, as
+returned by HistoryDB.GetEntries. The units which are not modified by the entries were last
+modified before the start round of the index.
+*/
+func LastModifiedRounds(entries []*Entry) map[string]uint64 {
+	rounds := make(map[string]uint64)
+	for _, e := range entries {
+		if !e.Success {
+			continue
+		}
+		rounds[string(e.UnitID)] = e.RoundNumber
+		for _, id := range e.NewUnitIDs {
+			rounds[string(id)] = e.RoundNumber
+		}
+	}
+	return rounds
+}
+
+// signerPubKey returns the public key of the owner proof of the transaction, the owner proof
+// is the first field of the auth proof of all the transaction types. Returns nil if the owner
+// proof is not a P2PKH signature.
+func signerPubKey(txo *types.TransactionOrder) []byte {
+	var authProof []types.RawCBOR
+	if err := types.Cbor.Unmarshal(txo.AuthProof, &authProof); err != nil || len(authProof) == 0 {
+		return nil
+	}
+	var ownerProof []byte
+	if err := types.Cbor.Unmarshal(authProof[0], &ownerProof); err != nil {
+		return nil
+	}
+	sig := templates.P2pkh256Signature{}
+	if err := types.Cbor.Unmarshal(ownerProof, &sig); err != nil {
+		return nil
+	}
+	return sig.PubKey
+}
+
+// containsBytes returns true if the decoded CBOR value contains the byte string b.
+func containsBytes(v any, b []byte) bool {
+	switch v := v.(type) {
+	case []byte:
+		return bytes.Equal(v, b)
+	case []any:
+		for _, item := range v {
+			if containsBytes(item, b) {
+				return true
+			}
+		}
+	case map[any]any:
+		for _, item := range v {
+			if containsBytes(item, b) {
+				return true
+			}
+		}
+	case cbor.Tag:
+		return containsBytes(v.Content, b)
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/alphabill-org/alphabill-wallet/util"
+)
+
+const (
+	HistoryDBFileName = "history.db"
+)
+
+var (
+	bucketPartitions = []byte("partitions")
+	bucketEntries    = []byte("entries")
+	indexStateKey    = []byte("indexState")
+)
+
+type (
+	BoltStore struct {
+		db *bolt.DB
+	}
+)
+
+func NewHistoryDB(dir string) (*BoltStore, error) {
+	dbFile := filepath.Join(dir, HistoryDBFileName)
+	return NewBoltStore(dbFile)
+}
+
+func NewBoltStore(dbFile string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(dbFile), 0700); err != nil { // ensure dirs exist
+		return nil, err
+	}
+	db, err := bolt.Open(dbFile, 0600, &bolt.Options{Timeout: 3 * time.Second}) // -rw-------
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt DB %s: %w", dbFile, err)
+	}
+	s := &BoltStore{db: db}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketPartitions)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create db buckets: %w", err)
+	}
+	return s, nil
+}
+
+// GetIndexState returns the indexed round range and the owners of the partition, nil if the partition is not indexed.
+func (s *BoltStore) GetIndexState(pdr *types.PartitionDescriptionRecord) (*IndexState, error) {
+	var state *IndexState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		partitionBucket := tx.Bucket(bucketPartitions).Bucket(partitionKey(pdr))
+		if partitionBucket == nil {
+			return nil
+		}
+		if b := partitionBucket.Get(indexStateKey); b != nil {
+			if err := json.Unmarshal(b, &state); err != nil {
+				return fmt.Errorf("failed to deserialize index state json: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// AddEntries stores the entries of the partition, the index state is stored in the same
+// transaction if it is not nil.
+func (s *BoltStore) AddEntries(pdr *types.PartitionDescriptionRecord, entries []*Entry, state *IndexState) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		partitionBucket, err := tx.Bucket(bucketPartitions).CreateBucketIfNotExists(partitionKey(pdr))
+		if err != nil {
+			return fmt.Errorf("failed to create partition bucket: %w", err)
+		}
+		entriesBucket, err := partitionBucket.CreateBucketIfNotExists(bucketEntries)
+		if err != nil {
+			return fmt.Errorf("failed to create entries bucket: %w", err)
+		}
+		for _, e := range entries {
+			entryBytes, err := json.Marshal(e)
+			if err != nil {
+				return fmt.Errorf("failed to serialize history entry to json: %w", err)
+			}
+			if err := entriesBucket.Put(entryKey(e), entryBytes); err != nil {
+				return err
+			}
+		}
+		if state == nil {
+			return nil
+		}
+		stateBytes, err := json.Marshal(state)
+		if err != nil {
+			return fmt.Errorf("failed to serialize index state to json: %w", err)
+		}
+		return partitionBucket.Put(indexStateKey, stateBytes)
+	})
+}
+
+// GetEntries returns the entries of the partition which match the filter, in the order of the rounds.
+func (s *BoltStore) GetEntries(pdr *types.PartitionDescriptionRecord, filter *Filter) ([]*Entry, error) {
+	var entries []*Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		partitionBucket := tx.Bucket(bucketPartitions).Bucket(partitionKey(pdr))
+		if partitionBucket == nil {
+			return nil
+		}
+		c := partitionBucket.Bucket(bucketEntries).Cursor()
+		for k, v := c.Seek(util.Uint64ToBytes(filter.FromRound)); k != nil; k, v = c.Next() {
+			if filter.ToRound != 0 && util.BytesToUint64(k[:8]) > filter.ToRound {
+				break
+			}
+			var e *Entry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fmt.Errorf("failed to deserialize history entry json: %w", err)
+			}
+			if filter.Match(e) {
+				entries = append(entries, e)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// partitionKey identifies the partition by the network and partition identifiers.
+func partitionKey(pdr *types.PartitionDescriptionRecord) []byte {
+	key := binary.BigEndian.AppendUint16(nil, uint16(pdr.NetworkID))
+	return binary.BigEndian.AppendUint32(key, uint32(pdr.PartitionID))
+}
+
+// entryKey orders the entries by round, transaction index and account index.
+func entryKey(e *Entry) []byte {
+	key := util.Uint64ToBytes(e.RoundNumber)
+	key = binary.BigEndian.AppendUint32(key, uint32(e.TxIndex))
+	return binary.BigEndian.AppendUint64(key, e.AccountIndex)
+}
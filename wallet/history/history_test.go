@@ -0,0 +1,357 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+type blockReaderMock struct {
+	blocks map[uint64]*types.Block
+	calls  []uint64
+	// errRound is the round of which the block can not be loaded
+	errRound uint64
+}
+
+func (m *blockReaderMock) GetBlock(ctx context.Context, roundNumber uint64) (*types.Block, error) {
+	if roundNumber == m.errRound {
+		return nil, errors.New("block not available")
+	}
+	m.calls = append(m.calls, roundNumber)
+	return m.blocks[roundNumber], nil
+}
+
+func TestIndexer_Sync(t *testing.T) {
+	pdr := moneyid.PDR()
+	keyA, keyB, keyC := newAccountKey(t), newAccountKey(t), newAccountKey(t)
+	blocks := &blockReaderMock{blocks: map[uint64]*types.Block{
+		2: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyA, newTransfer(t, &pdr, keyC, 10, []byte("r1")), types.TxStatusSuccessful, 1),
+			newTxRecord(t, keyC, newSplit(t, &pdr, map[*account.AccountKey]uint64{keyB: 5, keyC: 3}), types.TxStatusSuccessful, 1),
+		}},
+		// round 3 has no block
+		4: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyA, newSplit(t, &pdr, map[*account.AccountKey]uint64{keyB: 4, keyC: 2}), types.TxStatusFailed, 2),
+		}},
+		5: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyA, newTransferFC(t, &pdr, 7), types.TxStatusSuccessful, 1),
+		}},
+	}}
+	db := createHistoryDB(t)
+	owners := []*Owner{NewOwner(0, keyA.PubKey), NewOwner(1, keyB.PubKey)}
+	indexer := NewIndexer(db, blocks, &pdr, owners, logger.New(t))
+
+	require.NoError(t, indexer.Sync(context.Background(), 0, 4))
+	require.Equal(t, []uint64{1, 2, 3, 4}, blocks.calls)
+	entries, err := db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	verifyEntry(t, entries[0], 2, 0, money.TransactionTypeTransfer, DirectionSent, 10, 1, true)
+	require.EqualValues(t, "r1", entries[0].ReferenceNumber)
+	verifyEntry(t, entries[1], 2, 1, money.TransactionTypeSplit, DirectionReceived, 5, 0, true)
+	require.Len(t, entries[1].NewUnitIDs, 1)
+	verifyEntry(t, entries[2], 4, 0, money.TransactionTypeSplit, DirectionSent, 6, 2, false)
+	require.Empty(t, entries[2].NewUnitIDs)
+	verifyEntry(t, entries[3], 4, 1, money.TransactionTypeSplit, DirectionReceived, 4, 0, false)
+
+	// sync continues from the last indexed round
+	blocks.calls = nil
+	require.NoError(t, indexer.Sync(context.Background(), 0, 5))
+	require.Equal(t, []uint64{5}, blocks.calls)
+	entries, err = db.GetEntries(&pdr, &Filter{FromRound: 5})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	verifyEntry(t, entries[0], 5, 0, fc.TransactionTypeTransferFeeCredit, DirectionSent, 7, 1, true)
+
+	// the indexed rounds are indexed again for the added key only
+	blocks.calls = nil
+	indexer = NewIndexer(db, blocks, &pdr, append(owners, NewOwner(2, keyC.PubKey)), logger.New(t))
+	require.NoError(t, indexer.Sync(context.Background(), 0, 6))
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, blocks.calls)
+	entries, err = db.GetEntries(&pdr, &Filter{AccountIndexes: []uint64{2}})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	verifyEntry(t, entries[0], 2, 2, money.TransactionTypeTransfer, DirectionReceived, 10, 0, true)
+	verifyEntry(t, entries[1], 2, 2, money.TransactionTypeSplit, DirectionSent, 5, 1, true)
+	verifyEntry(t, entries[2], 4, 2, money.TransactionTypeSplit, DirectionReceived, 2, 0, false)
+	entries, err = db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 8)
+	state, err := db.GetIndexState(&pdr)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, state.StartRound)
+	require.EqualValues(t, 6, state.LastRound)
+	require.Len(t, state.Owners, 3)
+
+	// nothing to index for the known keys
+	blocks.calls = nil
+	require.NoError(t, indexer.Sync(context.Background(), 0, 6))
+	require.Empty(t, blocks.calls)
+}
+
+func TestIndexer_SyncStartRound(t *testing.T) {
+	pdr := moneyid.PDR()
+	keyA, keyB := newAccountKey(t), newAccountKey(t)
+	blocks := &blockReaderMock{blocks: map[uint64]*types.Block{
+		2: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyA, newTransfer(t, &pdr, keyB, 10, nil), types.TxStatusSuccessful, 1),
+		}},
+		5: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyB, newTransfer(t, &pdr, keyA, 3, nil), types.TxStatusSuccessful, 1),
+		}},
+	}}
+	db := createHistoryDB(t)
+	indexer := NewIndexer(db, blocks, &pdr, []*Owner{NewOwner(0, keyA.PubKey)}, logger.New(t))
+
+	require.NoError(t, indexer.Sync(context.Background(), 4, 6))
+	require.Equal(t, []uint64{4, 5, 6}, blocks.calls)
+	entries, err := db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	verifyEntry(t, entries[0], 5, 0, money.TransactionTypeTransfer, DirectionReceived, 3, 0, true)
+
+	// the start round of the previous sync is kept
+	blocks.calls = nil
+	require.NoError(t, indexer.Sync(context.Background(), 0, 7))
+	require.Equal(t, []uint64{7}, blocks.calls)
+
+	// the rounds before the start round are indexed, the added key is indexed from the new start round
+	blocks.calls = nil
+	indexer = NewIndexer(db, blocks, &pdr, []*Owner{NewOwner(0, keyA.PubKey), NewOwner(1, keyB.PubKey)}, logger.New(t))
+	require.NoError(t, indexer.Sync(context.Background(), 2, 7))
+	require.Equal(t, []uint64{4, 5, 6, 7, 2, 3}, blocks.calls)
+	entries, err = db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	verifyEntry(t, entries[0], 2, 0, money.TransactionTypeTransfer, DirectionSent, 10, 1, true)
+	verifyEntry(t, entries[1], 2, 1, money.TransactionTypeTransfer, DirectionReceived, 10, 0, true)
+	verifyEntry(t, entries[2], 5, 0, money.TransactionTypeTransfer, DirectionReceived, 3, 0, true)
+	verifyEntry(t, entries[3], 5, 1, money.TransactionTypeTransfer, DirectionSent, 3, 1, true)
+	state, err := db.GetIndexState(&pdr)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, state.StartRound)
+	require.EqualValues(t, 7, state.LastRound)
+}
+
+func TestIndexer_SyncInterrupted(t *testing.T) {
+	pdr := moneyid.PDR()
+	keyA := newAccountKey(t)
+	blocks := &blockReaderMock{
+		blocks: map[uint64]*types.Block{
+			2: {Transactions: []*types.TransactionRecord{
+				newTxRecord(t, keyA, newTransfer(t, &pdr, newAccountKey(t), 10, nil), types.TxStatusSuccessful, 1),
+			}},
+		},
+		errRound: 3,
+	}
+	db := createHistoryDB(t)
+	indexer := NewIndexer(db, blocks, &pdr, []*Owner{NewOwner(0, keyA.PubKey)}, logger.New(t))
+
+	require.EqualError(t, indexer.Sync(context.Background(), 0, 10), "failed to load block of round 3: block not available")
+	state, err := db.GetIndexState(&pdr)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, state.LastRound)
+	entries, err := db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// sync continues from the failed round
+	blocks.errRound = 0
+	blocks.calls = nil
+	require.NoError(t, indexer.Sync(context.Background(), 0, 4))
+	require.Equal(t, []uint64{3, 4}, blocks.calls)
+}
+
+func TestIndexer_SyncKeyHashOwner(t *testing.T) {
+	pdr := moneyid.PDR()
+	keyA, keyB := newAccountKey(t), newAccountKey(t)
+	blocks := &blockReaderMock{blocks: map[uint64]*types.Block{
+		2: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyA, newTransfer(t, &pdr, keyB, 10, nil), types.TxStatusSuccessful, 1),
+			newTxRecord(t, keyB, newSplit(t, &pdr, map[*account.AccountKey]uint64{keyA: 5}), types.TxStatusSuccessful, 1),
+		}},
+	}}
+	db := createHistoryDB(t)
+	// the public key of the owner is unknown, only the received transactions are indexed
+	owners := []*Owner{NewOwnerFromKeyHash(0, keyB.PubKeyHash.Sha256)}
+	indexer := NewIndexer(db, blocks, &pdr, owners, logger.New(t))
+
+	require.NoError(t, indexer.Sync(context.Background(), 0, 2))
+	entries, err := db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	verifyEntry(t, entries[0], 2, 0, money.TransactionTypeTransfer, DirectionReceived, 10, 0, true)
+}
+
+func TestLastModifiedRounds(t *testing.T) {
+	pdr := moneyid.PDR()
+	keyA, keyB := newAccountKey(t), newAccountKey(t)
+	transfer := newTransfer(t, &pdr, keyA, 10, nil)
+	split := newSplit(t, &pdr, map[*account.AccountKey]uint64{keyA: 5})
+	failedTransfer := newTransfer(t, &pdr, keyA, 20, nil)
+	blocks := &blockReaderMock{blocks: map[uint64]*types.Block{
+		2: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyB, transfer, types.TxStatusSuccessful, 1),
+			newTxRecord(t, keyB, split, types.TxStatusSuccessful, 1),
+		}},
+		3: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyB, failedTransfer, types.TxStatusFailed, 1),
+		}},
+		4: {Transactions: []*types.TransactionRecord{
+			newTxRecord(t, keyA, newSplit(t, &pdr, map[*account.AccountKey]uint64{keyB: 5}), types.TxStatusSuccessful, 1),
+		}},
+	}}
+	db := createHistoryDB(t)
+	indexer := NewIndexer(db, blocks, &pdr, []*Owner{NewOwner(0, keyA.PubKey)}, logger.New(t))
+	require.NoError(t, indexer.Sync(context.Background(), 0, 4))
+	entries, err := db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	// the new bill of the split is identified the same way the partition creates it
+	splitBillID, err := pdr.ComposeUnitID(types.ShardID{}, money.BillUnitType, money.PrndSh(split))
+	require.NoError(t, err)
+	rounds := LastModifiedRounds(entries)
+	require.EqualValues(t, 2, rounds[string(transfer.UnitID)])
+	require.EqualValues(t, 2, rounds[string(splitBillID)])
+	require.NotContains(t, rounds, string(failedTransfer.UnitID))
+	require.EqualValues(t, 4, rounds[string(entries[3].UnitID)])
+}
+
+func TestFilter_Match(t *testing.T) {
+	e := &Entry{AccountIndex: 1, TxType: money.TransactionTypeSplit, ReferenceNumber: []byte("ref")}
+	require.True(t, (&Filter{}).Match(e))
+	require.True(t, (&Filter{AccountIndexes: []uint64{0, 1}}).Match(e))
+	require.False(t, (&Filter{AccountIndexes: []uint64{0}}).Match(e))
+	require.True(t, (&Filter{TxTypes: []uint16{money.TransactionTypeTransfer, money.TransactionTypeSplit}}).Match(e))
+	require.False(t, (&Filter{TxTypes: []uint16{money.TransactionTypeTransfer}}).Match(e))
+	require.True(t, (&Filter{ReferenceNumber: []byte("ref")}).Match(e))
+	require.False(t, (&Filter{ReferenceNumber: []byte("other")}).Match(e))
+	// empty reference number matches the entries without reference number
+	require.False(t, (&Filter{ReferenceNumber: []byte{}}).Match(e))
+	require.True(t, (&Filter{ReferenceNumber: []byte{}}).Match(&Entry{}))
+}
+
+func TestDB_GetEntries(t *testing.T) {
+	pdr := moneyid.PDR()
+	db := createHistoryDB(t)
+
+	// not indexed partition
+	state, err := db.GetIndexState(&pdr)
+	require.NoError(t, err)
+	require.Nil(t, state)
+	entries, err := db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	require.NoError(t, db.AddEntries(&pdr, []*Entry{
+		{RoundNumber: 10, TxIndex: 1, AccountIndex: 1},
+		{RoundNumber: 10, TxIndex: 1, AccountIndex: 0},
+		{RoundNumber: 2, TxIndex: 0},
+	}, &IndexState{StartRound: 1, LastRound: 20, Owners: []hex.Bytes{{1}}}))
+	// entries without the index state
+	require.NoError(t, db.AddEntries(&pdr, []*Entry{{RoundNumber: 15, TxIndex: 3}}, nil))
+	state, err = db.GetIndexState(&pdr)
+	require.NoError(t, err)
+	require.Equal(t, &IndexState{StartRound: 1, LastRound: 20, Owners: []hex.Bytes{{1}}}, state)
+
+	// entries are ordered by round, transaction and account
+	entries, err = db.GetEntries(&pdr, &Filter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	require.EqualValues(t, 2, entries[0].RoundNumber)
+	require.EqualValues(t, 0, entries[1].AccountIndex)
+	require.EqualValues(t, 1, entries[2].AccountIndex)
+	require.EqualValues(t, 15, entries[3].RoundNumber)
+
+	entries, err = db.GetEntries(&pdr, &Filter{FromRound: 3, ToRound: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// partition of another network
+	otherPDR := pdr
+	otherPDR.NetworkID++
+	entries, err = db.GetEntries(&otherPDR, &Filter{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func verifyEntry(t *testing.T, e *Entry, round uint64, accountIndex uint64, txType uint16, direction string, amount, fee uint64, success bool) {
+	t.Helper()
+	require.Equal(t, round, e.RoundNumber)
+	require.Equal(t, accountIndex, e.AccountIndex)
+	require.Equal(t, txType, e.TxType)
+	require.Equal(t, direction, e.Direction)
+	require.Equal(t, amount, e.Amount)
+	require.Equal(t, fee, e.Fee)
+	require.Equal(t, success, e.Success)
+	require.NotEmpty(t, e.TxHash)
+	require.NotEmpty(t, e.UnitID)
+}
+
+func createHistoryDB(t *testing.T) *BoltStore {
+	db, err := NewHistoryDB(filepath.Join(t.TempDir(), "wallet"))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	return db
+}
+
+func newAccountKey(t *testing.T) *account.AccountKey {
+	keys, err := account.NewKeys("")
+	require.NoError(t, err)
+	return keys.AccountKey
+}
+
+func newBill(t *testing.T, pdr *types.PartitionDescriptionRecord, value uint64) *sdktypes.Bill {
+	return &sdktypes.Bill{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: moneyid.NewBillID(t), Value: value, Counter: 1}
+}
+
+func newTransfer(t *testing.T, pdr *types.PartitionDescriptionRecord, receiver *account.AccountKey, value uint64, refNo []byte) *types.TransactionOrder {
+	tx, err := newBill(t, pdr, value).Transfer(templates.NewP2pkh256BytesFromKey(receiver.PubKey), sdktypes.WithReferenceNumber(refNo))
+	require.NoError(t, err)
+	return tx
+}
+
+func newSplit(t *testing.T, pdr *types.PartitionDescriptionRecord, receivers map[*account.AccountKey]uint64) *types.TransactionOrder {
+	var targetUnits []*money.TargetUnit
+	for key, amount := range receivers {
+		targetUnits = append(targetUnits, &money.TargetUnit{Amount: amount, OwnerPredicate: templates.NewP2pkh256BytesFromKey(key.PubKey)})
+	}
+	tx, err := newBill(t, pdr, 100).Split(targetUnits)
+	require.NoError(t, err)
+	return tx
+}
+
+func newTransferFC(t *testing.T, pdr *types.PartitionDescriptionRecord, amount uint64) *types.TransactionOrder {
+	fcr := &sdktypes.FeeCreditRecord{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: moneyid.NewFeeCreditRecordID(t)}
+	tx, err := newBill(t, pdr, 100).TransferToFeeCredit(fcr, amount, 100)
+	require.NoError(t, err)
+	return tx
+}
+
+func newTxRecord(t *testing.T, signer *account.AccountKey, tx *types.TransactionOrder, status types.TxStatus, fee uint64) *types.TransactionRecord {
+	txSigner, err := offline.NewTxSignerFromKey(signer.PrivKey, money.PartitionTypeID)
+	require.NoError(t, err)
+	require.NoError(t, txSigner.SignTx(tx))
+	txBytes, err := tx.MarshalCBOR()
+	require.NoError(t, err)
+	return &types.TransactionRecord{
+		Version:          1,
+		TransactionOrder: txBytes,
+		ServerMetadata:   &types.ServerMetadata{SuccessIndicator: status, ActualFee: fee},
+	}
+}
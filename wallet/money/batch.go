@@ -0,0 +1,118 @@
+package money
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/money/txbuilder"
+	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
+)
+
+type (
+	SendBatchCmd struct {
+		Payments            []*txbuilder.Payment
+		WaitForConfirmation bool
+		AccountIndex        uint64
+		MaxFee              uint64
+	}
+
+	// BatchPaymentResult is the result of a payment of the batch, the payments
+	// packed into the same split transaction have the same result.
+	BatchPaymentResult struct {
+		TxHash hex.Bytes
+		TxType uint16
+		// Proof is nil if the transaction was not confirmed.
+		Proof *types.TxRecordProof
+	}
+)
+
+/*
+SendBatch pays all the payments of the command, the payments are packed into the fewest split
+transactions the bills of the account allow (see txbuilder.CreateBatchTransactions), all the
+transactions are submitted as one batch.
+
+Returns the results in the order of the payments. If the transactions were created but the
+submission failed then both the results (with the proofs of the confirmed transactions) and
+the error are returned.
+*/
+func (w *Wallet) SendBatch(ctx context.Context, cmd SendBatchCmd) ([]*BatchPaymentResult, error) {
+	if err := cmd.isValid(); err != nil {
+		return nil, err
+	}
+	k, err := w.am.GetSigningKey(cmd.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+	txSigner, err := sdktypes.NewMoneyTxSignerFromKey(k.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create money tx signer: %w", err)
+	}
+	roundInfo, err := w.moneyClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pkh := sha256.Sum256(k.PubKey)
+	fcr, err := w.moneyClient.GetFeeCreditRecordByOwnerID(ctx, pkh[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return nil, errors.New("fee credit record not found")
+	}
+	bills, err := w.getUnlockedBills(ctx, pkh[:])
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := txbuilder.CreateBatchTransactions(cmd.Payments, bills, txSigner, roundInfo.RoundNumber+txTimeoutBlockCount, fcr.ID, cmd.MaxFee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transactions: %w", err)
+	}
+	if txsCost := cmd.MaxFee * uint64(len(txs)); fcr.Balance < txsCost {
+		return nil, errors.New("insufficient fee credit balance for transaction(s)")
+	}
+
+	batch := txsubmitter.NewBatch(w.moneyClient, w.log)
+	subs := make([]*txsubmitter.TxSubmission, len(txs))
+	for i, tx := range txs {
+		if subs[i], err = txsubmitter.New(tx.Tx); err != nil {
+			return nil, fmt.Errorf("failed to create tx submission: %w", err)
+		}
+		batch.Add(subs[i])
+	}
+	err = batch.SendTx(ctx, cmd.WaitForConfirmation)
+
+	results := make([]*BatchPaymentResult, len(cmd.Payments))
+	for i, tx := range txs {
+		for _, p := range tx.Payments {
+			results[p] = &BatchPaymentResult{TxHash: subs[i].TxHash, TxType: tx.Tx.Type, Proof: subs[i].Proof}
+		}
+	}
+	return results, err
+}
+
+func (c *SendBatchCmd) isValid() error {
+	if len(c.Payments) == 0 {
+		return errors.New("payments is empty")
+	}
+	for i, p := range c.Payments {
+		if len(p.PubKey) != abcrypto.CompressedSecp256K1PublicKeySize {
+			return fmt.Errorf("invalid public key of payment #%d: public key must be in compressed secp256k1 format: "+
+				"got %d bytes, expected %d bytes for public key 0x%x", i+1, len(p.PubKey), abcrypto.CompressedSecp256K1PublicKeySize, p.PubKey)
+		}
+		if p.Amount == 0 {
+			return fmt.Errorf("invalid amount of payment #%d: amount must be greater than zero", i+1)
+		}
+		if len(p.ReferenceNumber) > 32 {
+			return fmt.Errorf("invalid reference number of payment #%d: maximum allowed length is 32 bytes, got %d bytes", i+1, len(p.ReferenceNumber))
+		}
+	}
+	return nil
+}
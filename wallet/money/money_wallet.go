@@ -19,6 +19,7 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
 	"github.com/alphabill-org/alphabill-wallet/wallet/money/dc"
 	"github.com/alphabill-org/alphabill-wallet/wallet/money/txbuilder"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 )
 
@@ -44,6 +45,12 @@ type (
 		AccountIndex        uint64
 		ReferenceNumber     []byte
 		MaxFee              uint64
+		// BillSelector chooses the bills to spend when sending to a single receiver,
+		// the default strategy (largest-first) is used if nil.
+		BillSelector txbuilder.BillSelector
+		// Timeout is the number of rounds the transactions are valid for, the default
+		// (10 rounds) is used if 0. Unsigned transactions need a longer timeout.
+		Timeout uint64
 	}
 
 	ReceiverData struct {
@@ -172,41 +179,96 @@ func (w *Wallet) GetRoundNumber(ctx context.Context) (uint64, error) {
 
 // Send creates, signs and broadcasts transactions, in total for the given amount,
 // to the given public key, the public key must be in compressed secp256k1 format.
-// Sends one transaction per bill, prioritizing larger bills.
+// Sends one transaction per bill, the bills are chosen by the bill selector of the command (larger bills first by default).
 // Waits for initial response from the node, returns error if any transaction was not accepted to the mempool.
 // Returns list of tx proofs, if waitForConfirmation=true, otherwise nil.
 func (w *Wallet) Send(ctx context.Context, cmd SendCmd) ([]*types.TxRecordProof, error) {
 	if err := cmd.isValid(); err != nil {
 		return nil, err
 	}
+	k, err := w.am.GetSigningKey(cmd.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+	txSigner, err := sdktypes.NewMoneyTxSignerFromKey(k.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create money tx signer: %w", err)
+	}
+	txs, _, _, err := w.createSendTxs(ctx, cmd, k.PubKey, txSigner)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := txsubmitter.NewBatch(w.moneyClient, w.log)
+	for _, tx := range txs {
+		sub, err := txsubmitter.New(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tx submission: %w", err)
+		}
+		batch.Add(sub)
+	}
+	if err = batch.SendTx(ctx, cmd.WaitForConfirmation); err != nil {
+		return nil, err
+	}
 
+	var proofs []*types.TxRecordProof
+	for _, txSub := range batch.Submissions() {
+		proofs = append(proofs, txSub.Proof)
+	}
+	return proofs, nil
+}
+
+// BuildUnsignedSend creates the same transactions as Send but does not sign nor send them, the
+// transactions are returned in the envelope together with the spent bills and the fee credit record.
+// Only the public key of the account is used, the envelope can be signed on an offline machine.
+func (w *Wallet) BuildUnsignedSend(ctx context.Context, cmd SendCmd) (*offline.Envelope, error) {
+	if err := cmd.isValid(); err != nil {
+		return nil, err
+	}
 	pubKey, err := w.am.GetPublicKey(cmd.AccountIndex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load public key: %w", err)
 	}
-
-	roundInfo, err := w.moneyClient.GetRoundInfo(ctx)
+	if pubKey == nil {
+		return nil, fmt.Errorf("key #%d is watch-only account of public key hash, public key is required to build transactions", cmd.AccountIndex+1)
+	}
+	txs, bills, fcr, err := w.createSendTxs(ctx, cmd, pubKey, nil)
 	if err != nil {
 		return nil, err
 	}
+	return &offline.Envelope{
+		Version:         offline.EnvelopeVersion,
+		NetworkID:       w.pdr.NetworkID,
+		PartitionID:     w.pdr.PartitionID,
+		PartitionTypeID: w.pdr.PartitionTypeID,
+		AccountIndex:    cmd.AccountIndex,
+		OwnerPubKey:     pubKey,
+		Transactions:    txs,
+		Bills:           bills,
+		FeeCreditRecord: fcr,
+	}, nil
+}
 
-	k, err := w.am.GetAccountKey(cmd.AccountIndex)
+// createSendTxs creates the transactions of the send command and returns them together with the
+// spent bills and the fee credit record, the transactions are not signed if txSigner is nil.
+func (w *Wallet) createSendTxs(ctx context.Context, cmd SendCmd, pubKey []byte, txSigner *sdktypes.MoneyTxSigner) ([]*types.TransactionOrder, []*sdktypes.Bill, *sdktypes.FeeCreditRecord, error) {
+	roundInfo, err := w.moneyClient.GetRoundInfo(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	fcr, err := w.moneyClient.GetFeeCreditRecordByOwnerID(ctx, k.PubKeyHash.Sha256)
+	pkh := sha256.Sum256(pubKey)
+	fcr, err := w.moneyClient.GetFeeCreditRecordByOwnerID(ctx, pkh[:])
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
 	}
 	if fcr == nil {
-		return nil, errors.New("fee credit record not found")
+		return nil, nil, nil, errors.New("fee credit record not found")
 	}
 
-	pkh := sha256.Sum256(pubKey)
 	bills, err := w.getUnlockedBills(ctx, pkh[:])
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	var balance uint64
 	for _, b := range bills {
@@ -214,14 +276,11 @@ func (w *Wallet) Send(ctx context.Context, cmd SendCmd) ([]*types.TxRecordProof,
 	}
 	totalAmount := cmd.totalAmount()
 	if totalAmount > balance {
-		return nil, errors.New("insufficient balance for transaction")
+		return nil, nil, nil, errors.New("insufficient balance for transaction")
 	}
 	timeout := roundInfo.RoundNumber + txTimeoutBlockCount
-	batch := txsubmitter.NewBatch(w.moneyClient, w.log)
-
-	txSigner, err := sdktypes.NewMoneyTxSignerFromKey(k.PrivKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create money tx signer: %w", err)
+	if cmd.Timeout > 0 {
+		timeout = roundInfo.RoundNumber + cmd.Timeout
 	}
 
 	var txs []*types.TransactionOrder
@@ -229,14 +288,14 @@ func (w *Wallet) Send(ctx context.Context, cmd SendCmd) ([]*types.TxRecordProof,
 		// if more than one receiver then perform transaction as N-way split and require sufficiently large bill
 		largestBill := bills[0]
 		if largestBill.Value < totalAmount {
-			return nil, fmt.Errorf("sending to multiple addresses is performed using N-way split transaction which "+
+			return nil, nil, nil, fmt.Errorf("sending to multiple addresses is performed using N-way split transaction which "+
 				"requires a single sufficiently large bill, wallet needs a bill with at least %s tema value, "+
 				"largest bill in wallet currently is %s tema",
 				util.AmountToString(totalAmount+1, 8), // +1 because 0 remaining value is not allowed
 				util.AmountToString(largestBill.Value, 8))
 		}
 		if largestBill.Value == totalAmount {
-			return nil, errors.New("sending to multiple addresses is performed using N-way split transaction " +
+			return nil, nil, nil, errors.New("sending to multiple addresses is performed using N-way split transaction " +
 				"which requires a single sufficiently large bill and cannot result in a bill with 0 value after the " +
 				"transaction")
 		}
@@ -255,42 +314,38 @@ func (w *Wallet) Send(ctx context.Context, cmd SendCmd) ([]*types.TxRecordProof,
 			sdktypes.WithReferenceNumber(cmd.ReferenceNumber),
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create N-way split tx: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to create N-way split tx: %w", err)
 		}
-		if err = txSigner.SignTx(tx); err != nil {
-			return nil, fmt.Errorf("failed to sign tx: %w", err)
+		if txSigner != nil {
+			if err = txSigner.SignTx(tx); err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to sign tx: %w", err)
+			}
 		}
 		txs = append(txs, tx)
 	} else {
 		// if single receiver then perform up to N transfers (until target amount is reached)
-		txs, err = txbuilder.CreateTransactions(cmd.Receivers[0].PubKey, cmd.Receivers[0].Amount, bills, txSigner, timeout, fcr.ID, cmd.ReferenceNumber, cmd.MaxFee)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create transactions: %w", err)
-		}
-	}
-
-	for _, tx := range txs {
-		sub, err := txsubmitter.New(tx)
+		txs, err = txbuilder.CreateTransactions(cmd.Receivers[0].PubKey, cmd.Receivers[0].Amount, bills, cmd.BillSelector, txSigner, timeout, fcr.ID, cmd.ReferenceNumber, cmd.MaxFee)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create tx submission: %w", err)
+			return nil, nil, nil, fmt.Errorf("failed to create transactions: %w", err)
 		}
-		batch.Add(sub)
 	}
 
-	txsCost := cmd.MaxFee * uint64(len(batch.Submissions()))
+	txsCost := cmd.MaxFee * uint64(len(txs))
 	if fcr.Balance < txsCost {
-		return nil, errors.New("insufficient fee credit balance for transaction(s)")
-	}
-
-	if err = batch.SendTx(ctx, cmd.WaitForConfirmation); err != nil {
-		return nil, err
+		return nil, nil, nil, errors.New("insufficient fee credit balance for transaction(s)")
 	}
 
-	var proofs []*types.TxRecordProof
-	for _, txSub := range batch.Submissions() {
-		proofs = append(proofs, txSub.Proof)
+	// the bills spent by the transactions
+	var spent []*sdktypes.Bill
+	for _, tx := range txs {
+		for _, b := range bills {
+			if b.ID.Eq(tx.GetUnitID()) {
+				spent = append(spent, b)
+				break
+			}
+		}
 	}
-	return proofs, nil
+	return txs, spent, fcr, nil
 }
 
 // GetFeeCredit returns fee credit record for the given account,
@@ -321,8 +376,9 @@ func (w *Wallet) ReclaimFeeCredit(ctx context.Context, cmd fees.ReclaimFeeCmd) (
 // CollectDust starts the dust collector process for the requested accounts in the wallet.
 // Dust collection process joins up to N units into existing target unit, prioritizing small units first.
 // The largest unit in wallet is selected as the target unit.
-// If accountNumber is equal to 0 then dust collection is run for all accounts, returns list of swap tx proofs
-// together with account numbers, the proof can be nil if swap tx was not sent e.g. if there's not enough bills to swap.
+// If accountNumber is equal to 0 then dust collection is run for all accounts, except the watch-only accounts, returns
+// list of swap tx proofs together with account numbers, the proof can be nil if swap tx was not sent e.g. if there's not
+// enough bills to swap.
 // If accountNumber is greater than 0 then dust collection is run only for the specific account, returns single swap tx
 // proof, the proof can be nil e.g. if there's not enough bills to swap.
 func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64) ([]*DustCollectionResult, error) {
@@ -333,6 +389,9 @@ func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64) ([]*Dust
 			if err != nil {
 				return nil, fmt.Errorf("failed to load account key: %w", err)
 			}
+			if accKey.IsWatchOnly() {
+				continue
+			}
 			dcResult, err := w.dustCollector.CollectDust(ctx, accKey)
 			if err != nil {
 				return nil, fmt.Errorf("dust collection failed for account number %d: %w", acc.AccountIndex+1, err)
@@ -340,7 +399,7 @@ func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64) ([]*Dust
 			res = append(res, &DustCollectionResult{AccountIndex: acc.AccountIndex, DustCollectionResult: dcResult})
 		}
 	} else {
-		accKey, err := w.am.GetAccountKey(accountNumber - 1)
+		accKey, err := w.am.GetSigningKey(accountNumber - 1)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load account key: %w", err)
 		}
@@ -0,0 +1,194 @@
+package txbuilder
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/util"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+type (
+	// Payment is a single payment of a batch.
+	Payment struct {
+		PubKey          []byte
+		Amount          uint64
+		ReferenceNumber []byte
+	}
+
+	// BatchTx is a transaction of a batch and the indexes of the payments it pays.
+	BatchTx struct {
+		Tx       *types.TransactionOrder
+		Payments []int
+	}
+
+	// batchPlan pays the payments from the bill, with a transfer if the
+	// payment is for the value of the bill, otherwise with a split.
+	batchPlan struct {
+		bill     *sdktypes.Bill
+		payments []int
+	}
+)
+
+/*
+CreateBatchTransactions creates the transactions which pay the payments from the bills, each
+bill is spent at most once. The payments are packed into the fewest split transactions: the payments
+with the same reference number are split from the smallest bill which covers all of them, or from
+the largest bill when there is no such bill. As a split must leave a non-zero value in the bill, the
+payments which can not be split from any bill, or do not fit into the splits, are paid by transferring
+a bill of the exact value. The transactions are not signed if txSigner is nil.
+*/
+func CreateBatchTransactions(payments []*Payment, bills []*sdktypes.Bill, txSigner *sdktypes.MoneyTxSigner, timeout uint64, fcrID []byte, maxFee uint64) ([]*BatchTx, error) {
+	plans, err := planBatch(payments, bills)
+	if err != nil {
+		return nil, err
+	}
+	var txs []*BatchTx
+	for _, plan := range plans {
+		first := payments[plan.payments[0]]
+		opts := []sdktypes.Option{
+			sdktypes.WithTimeout(timeout),
+			sdktypes.WithFeeCreditRecordID(fcrID),
+			sdktypes.WithMaxFee(maxFee),
+			sdktypes.WithReferenceNumber(first.ReferenceNumber),
+		}
+		var txo *types.TransactionOrder
+		if len(plan.payments) == 1 && first.Amount == plan.bill.Value {
+			txo, err = plan.bill.Transfer(templates.NewP2pkh256BytesFromKey(first.PubKey), opts...)
+		} else {
+			var targetUnits []*money.TargetUnit
+			for _, i := range plan.payments {
+				targetUnits = append(targetUnits, &money.TargetUnit{
+					Amount:         payments[i].Amount,
+					OwnerPredicate: templates.NewP2pkh256BytesFromKey(payments[i].PubKey),
+				})
+			}
+			txo, err = plan.bill.Split(targetUnits, opts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if txo, err = signTx(txSigner, txo); err != nil {
+			return nil, err
+		}
+		txs = append(txs, &BatchTx{Tx: txo, Payments: plan.payments})
+	}
+	return txs, nil
+}
+
+func planBatch(payments []*Payment, bills []*sdktypes.Bill) ([]*batchPlan, error) {
+	var total uint64
+	for _, p := range payments {
+		var ok bool
+		if total, ok = util.SafeAdd(total, p.Amount); !ok {
+			return nil, errors.New("total amount of the payments overflows")
+		}
+	}
+	if balance := sum(bills); balance < total {
+		return nil, fmt.Errorf("insufficient balance for transaction, trying to send %d have %d", total, balance)
+	}
+
+	unused := sortedBills(bills, byValueDesc)
+	var plans []*batchPlan
+	// the payments which can not be split from any bill are paid by transferring a bill of the exact value
+	paid := make([]bool, len(payments))
+	byAmountDesc := make([]int, len(payments))
+	for i := range byAmountDesc {
+		byAmountDesc[i] = i
+	}
+	slices.SortStableFunc(byAmountDesc, func(a, b int) int { return cmp.Compare(payments[b].Amount, payments[a].Amount) })
+	for _, p := range byAmountDesc {
+		if len(unused) == 0 || unused[0].Value > payments[p].Amount {
+			break
+		}
+		if i := slices.IndexFunc(unused, func(b *sdktypes.Bill) bool { return b.Value == payments[p].Amount }); i >= 0 {
+			plans = append(plans, &batchPlan{bill: unused[i], payments: []int{p}})
+			unused = slices.Delete(unused, i, i+1)
+			paid[p] = true
+		}
+	}
+
+	var unpaid []int
+	for _, group := range groupByReferenceNumber(payments) {
+		group = slices.DeleteFunc(group, func(i int) bool { return paid[i] })
+		// the largest payments first
+		slices.SortStableFunc(group, func(a, b int) int { return cmp.Compare(payments[b].Amount, payments[a].Amount) })
+		for len(group) > 0 && len(unused) > 0 {
+			var groupTotal uint64
+			for _, i := range group {
+				groupTotal += payments[i].Amount
+			}
+			// a bill of the exact value of the last payment is transferred, no change is left
+			if len(group) == 1 {
+				if i := slices.IndexFunc(unused, func(b *sdktypes.Bill) bool { return b.Value == groupTotal }); i >= 0 {
+					plans = append(plans, &batchPlan{bill: unused[i], payments: group})
+					unused = slices.Delete(unused, i, i+1)
+					group = nil
+					break
+				}
+			}
+			// the smallest bill which covers the group, or the largest bill,
+			// the split must leave a non-zero remaining value in the bill
+			billIdx := 0
+			for i := len(unused) - 1; i >= 0; i-- {
+				if unused[i].Value > groupTotal {
+					billIdx = i
+					break
+				}
+			}
+			capacity := unused[billIdx].Value - 1
+			var packed, rest []int
+			for _, i := range group {
+				if payments[i].Amount <= capacity {
+					packed = append(packed, i)
+					capacity -= payments[i].Amount
+				} else {
+					rest = append(rest, i)
+				}
+			}
+			if len(packed) == 0 {
+				break
+			}
+			slices.Sort(packed)
+			plans = append(plans, &batchPlan{bill: unused[billIdx], payments: packed})
+			unused = slices.Delete(unused, billIdx, billIdx+1)
+			group = rest
+		}
+		unpaid = append(unpaid, group...)
+	}
+
+	// the payments which do not fit into the remaining bills are paid by transferring a bill of the exact value
+	slices.Sort(unpaid)
+	for _, p := range unpaid {
+		i := slices.IndexFunc(unused, func(b *sdktypes.Bill) bool { return b.Value == payments[p].Amount })
+		if i < 0 {
+			return nil, fmt.Errorf("payment #%d (amount %d) can not be paid, a split requires a bill larger than "+
+				"the payment and a transfer a bill of the exact value", p+1, payments[p].Amount)
+		}
+		plans = append(plans, &batchPlan{bill: unused[i], payments: []int{p}})
+		unused = slices.Delete(unused, i, i+1)
+	}
+	return plans, nil
+}
+
+// groupByReferenceNumber returns the indexes of the payments grouped by the reference number,
+// in the order of the first payment of the group.
+func groupByReferenceNumber(payments []*Payment) [][]int {
+	var groups [][]int
+	for i, p := range payments {
+		j := slices.IndexFunc(groups, func(g []int) bool { return bytes.Equal(payments[g[0]].ReferenceNumber, p.ReferenceNumber) })
+		if j < 0 {
+			groups = append(groups, []int{i})
+		} else {
+			groups[j] = append(groups[j], i)
+		}
+	}
+	return groups
+}
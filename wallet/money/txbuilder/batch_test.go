@@ -0,0 +1,148 @@
+package txbuilder
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+func TestCreateBatchTransactions(t *testing.T) {
+	// expectedTx is the expected transaction of the batch: the value of the
+	// spent bill, the transaction type and the indexes of the paid payments
+	type expectedTx struct {
+		billValue uint64
+		txType    uint16
+		payments  []int
+	}
+	tests := []struct {
+		name        string
+		bills       []uint64
+		payments    []*Payment
+		expected    []expectedTx
+		expectedErr string
+	}{
+		{
+			name:     "payments are split from the smallest bill which covers them",
+			bills:    []uint64{100, 50, 7, 3},
+			payments: []*Payment{newPayment(10, nil), newPayment(20, nil), newPayment(15, nil)},
+			expected: []expectedTx{{billValue: 50, txType: money.TransactionTypeSplit, payments: []int{0, 1, 2}}},
+		},
+		{
+			name:     "payments are grouped by reference number",
+			bills:    []uint64{100, 50},
+			payments: []*Payment{newPayment(10, []byte("a")), newPayment(20, []byte("b")), newPayment(5, []byte("a"))},
+			expected: []expectedTx{
+				{billValue: 50, txType: money.TransactionTypeSplit, payments: []int{0, 2}},
+				{billValue: 100, txType: money.TransactionTypeSplit, payments: []int{1}},
+			},
+		},
+		{
+			name:     "payments are split from the largest bill when no bill covers them",
+			bills:    []uint64{50, 30},
+			payments: []*Payment{newPayment(25, nil), newPayment(20, nil), newPayment(10, nil)},
+			expected: []expectedTx{
+				{billValue: 50, txType: money.TransactionTypeSplit, payments: []int{0, 1}},
+				{billValue: 30, txType: money.TransactionTypeSplit, payments: []int{2}},
+			},
+		},
+		{
+			name:     "bill of the exact value is transferred",
+			bills:    []uint64{60, 40, 30},
+			payments: []*Payment{newPayment(20, nil), newPayment(60, nil)},
+			expected: []expectedTx{
+				{billValue: 60, txType: money.TransactionTypeTransfer, payments: []int{1}},
+				{billValue: 30, txType: money.TransactionTypeSplit, payments: []int{0}},
+			},
+		},
+		{
+			name:        "insufficient balance",
+			bills:       []uint64{10, 5},
+			payments:    []*Payment{newPayment(10, nil), newPayment(6, nil)},
+			expectedErr: "insufficient balance for transaction, trying to send 16 have 15",
+		},
+		{
+			name:        "total amount overflows",
+			bills:       []uint64{10, 5},
+			payments:    []*Payment{newPayment(math.MaxUint64, nil), newPayment(6, nil)},
+			expectedErr: "total amount of the payments overflows",
+		},
+		{
+			name:        "payment can not be paid",
+			bills:       []uint64{10, 10},
+			payments:    []*Payment{newPayment(15, nil)},
+			expectedErr: "payment #1 (amount 15) can not be paid, a split requires a bill larger than the payment and a transfer a bill of the exact value",
+		},
+	}
+
+	txSigner, err := sdktypes.NewMoneyTxSignerFromKey(accountKey.AccountKey.PrivKey)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bills []*sdktypes.Bill
+			for _, v := range tt.bills {
+				bills = append(bills, createBill(t, v))
+			}
+			txs, err := CreateBatchTransactions(tt.payments, bills, txSigner, 100, nil, 10)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, txs, len(tt.expected))
+			for i, expected := range tt.expected {
+				tx := txs[i].Tx
+				require.Equal(t, expected.txType, tx.Type)
+				require.Equal(t, expected.payments, txs[i].Payments)
+				require.Equal(t, tt.payments[expected.payments[0]].ReferenceNumber, tx.Payload.ClientMetadata.ReferenceNumber)
+				require.NotNil(t, tx.FeeProof)
+
+				bill := bills[findBill(t, bills, tx.UnitID)]
+				require.Equal(t, expected.billValue, bill.Value)
+				if tx.Type == money.TransactionTypeSplit {
+					attr := &money.SplitAttributes{}
+					require.NoError(t, tx.UnmarshalAttributes(attr))
+					require.Len(t, attr.TargetUnits, len(expected.payments))
+					for j, p := range expected.payments {
+						require.Equal(t, tt.payments[p].Amount, attr.TargetUnits[j].Amount)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateBatchTransactions_billSpentOnce(t *testing.T) {
+	bills := []*sdktypes.Bill{createBill(t, 10), createBill(t, 10), createBill(t, 10)}
+	payments := []*Payment{newPayment(9, nil), newPayment(9, []byte("a")), newPayment(9, []byte("b"))}
+
+	txs, err := CreateBatchTransactions(payments, bills, nil, 100, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 3)
+	spent := map[string]bool{}
+	for _, tx := range txs {
+		require.False(t, spent[string(tx.Tx.UnitID)])
+		spent[string(tx.Tx.UnitID)] = true
+		// not signed without signer
+		require.Nil(t, tx.Tx.FeeProof)
+	}
+}
+
+func newPayment(amount uint64, refNo []byte) *Payment {
+	return &Payment{PubKey: receiverPubKey, Amount: amount, ReferenceNumber: refNo}
+}
+
+func findBill(t *testing.T, bills []*sdktypes.Bill, id types.UnitID) int {
+	for i, b := range bills {
+		if b.ID.Eq(id) {
+			return i
+		}
+	}
+	require.FailNow(t, "bill not found", "unit %x", id)
+	return -1
+}
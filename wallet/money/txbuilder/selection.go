@@ -0,0 +1,175 @@
+package txbuilder
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+// Names of the bill selection strategies.
+const (
+	SelectionLargestFirst  = "largest-first"
+	SelectionFewestTxs     = "fewest-txs"
+	SelectionExactMatch    = "exact-match"
+	SelectionSmallestFirst = "smallest-first"
+	SelectionOldestFirst   = "oldest-first"
+
+	// exactMatchMaxTries limits the number of nodes visited by the branch-and-bound search.
+	exactMatchMaxTries = 100000
+)
+
+// BillSelector selects the bills to spend for the given amount.
+type BillSelector interface {
+	// SelectBills returns the bills to spend in the order in which the transactions are created, the
+	// total value of the bills is at least the amount. The last bill is split when the total value
+	// exceeds the amount, the input slice is not modified.
+	SelectBills(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error)
+}
+
+type (
+	largestFirst  struct{}
+	fewestTxs     struct{}
+	exactMatch    struct{ maxTries int }
+	smallestFirst struct{}
+	oldestFirst   struct{ lastModified map[string]uint64 }
+)
+
+// SelectionStrategies returns the names of the bill selection strategies, the default one first.
+func SelectionStrategies() []string {
+	return []string{SelectionLargestFirst, SelectionFewestTxs, SelectionExactMatch, SelectionSmallestFirst, SelectionOldestFirst}
+}
+
+/*
+NewBillSelector returns the bill selector of the strategy, lastModifiedRounds are the rounds in which
+the bills were last modified by bill ID, they are used only by the oldest-first strategy:
+  - largest-first (default) - a bill with the exact value if there is one, otherwise the largest bills first;
+  - fewest-txs - the least number of bills, the last one is the smallest bill which covers the rest of the amount;
+  - exact-match - the fewest bills with the total value equal to the amount found with branch-and-bound search,
+    no bill is split, falls back to fewest-txs when there is no exact match;
+  - smallest-first - the smallest bills first, consolidates dust;
+  - oldest-first - the bills which were last modified in the earliest round first, the bills missing from
+    lastModifiedRounds are considered older than the others (e.g. modified before the history was indexed).
+*/
+func NewBillSelector(strategy string, lastModifiedRounds map[string]uint64) (BillSelector, error) {
+	switch strategy {
+	case SelectionLargestFirst, "":
+		return largestFirst{}, nil
+	case SelectionFewestTxs:
+		return fewestTxs{}, nil
+	case SelectionExactMatch:
+		return exactMatch{maxTries: exactMatchMaxTries}, nil
+	case SelectionSmallestFirst:
+		return smallestFirst{}, nil
+	case SelectionOldestFirst:
+		return oldestFirst{lastModified: lastModifiedRounds}, nil
+	default:
+		return nil, fmt.Errorf("unknown bill selection strategy %q, expected one of %s", strategy, strings.Join(SelectionStrategies(), ", "))
+	}
+}
+
+func (largestFirst) SelectBills(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error) {
+	if i := slices.IndexFunc(bills, func(b *sdktypes.Bill) bool { return b.Value == amount }); i >= 0 {
+		return []*sdktypes.Bill{bills[i]}, nil
+	}
+	return takeUntil(sortedBills(bills, byValueDesc), amount)
+}
+
+func (fewestTxs) SelectBills(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error) {
+	sorted := sortedBills(bills, byValueDesc)
+	selected, err := takeUntil(sorted, amount)
+	if err != nil {
+		return nil, err
+	}
+	// the same number of bills is needed with the smallest last bill which covers the rest of the amount
+	n := len(selected) - 1
+	remaining := amount - sum(selected[:n])
+	for i := len(sorted) - 1; i >= n; i-- {
+		if sorted[i].Value >= remaining {
+			selected[n] = sorted[i]
+			break
+		}
+	}
+	return selected, nil
+}
+
+func (s exactMatch) SelectBills(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error) {
+	sorted := sortedBills(bills, byValueDesc)
+	// suffix sums for pruning the branches which can not reach the amount
+	rest := make([]uint64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		rest[i] = rest[i+1] + sorted[i].Value
+	}
+	var best, current []*sdktypes.Bill
+	tries := 0
+	var search func(i int, remaining uint64)
+	search = func(i int, remaining uint64) {
+		tries++
+		switch {
+		case remaining == 0:
+			if best == nil || len(current) < len(best) {
+				best = slices.Clone(current)
+			}
+			return
+		case tries > s.maxTries || i == len(sorted) || rest[i] < remaining:
+			return
+		case best != nil && len(current)+1 >= len(best):
+			// can not find a match with fewer bills than the best one
+			return
+		}
+		if sorted[i].Value <= remaining {
+			current = append(current, sorted[i])
+			search(i+1, remaining-sorted[i].Value)
+			current = current[:len(current)-1]
+		}
+		search(i+1, remaining)
+	}
+	search(0, amount)
+	if best != nil {
+		return best, nil
+	}
+	return fewestTxs{}.SelectBills(bills, amount)
+}
+
+func (smallestFirst) SelectBills(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error) {
+	return takeUntil(sortedBills(bills, func(a, b *sdktypes.Bill) int { return cmp.Compare(a.Value, b.Value) }), amount)
+}
+
+func (s oldestFirst) SelectBills(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error) {
+	return takeUntil(sortedBills(bills, func(a, b *sdktypes.Bill) int {
+		return cmp.Compare(s.lastModified[string(a.ID)], s.lastModified[string(b.ID)])
+	}), amount)
+}
+
+// takeUntil returns the bills from the start of the slice until their total value reaches the amount.
+func takeUntil(bills []*sdktypes.Bill, amount uint64) ([]*sdktypes.Bill, error) {
+	var accumulatedSum uint64
+	for i, b := range bills {
+		accumulatedSum += b.Value
+		if accumulatedSum >= amount {
+			return bills[:i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("insufficient balance for transaction, trying to send %d have %d", amount, accumulatedSum)
+}
+
+// sortedBills returns a sorted copy of the bills, the bills which compare equal keep their order.
+func sortedBills(bills []*sdktypes.Bill, compare func(a, b *sdktypes.Bill) int) []*sdktypes.Bill {
+	sorted := slices.Clone(bills)
+	slices.SortStableFunc(sorted, compare)
+	return sorted
+}
+
+func byValueDesc(a, b *sdktypes.Bill) int {
+	return cmp.Compare(b.Value, a.Value)
+}
+
+func sum(bills []*sdktypes.Bill) uint64 {
+	var total uint64
+	for _, b := range bills {
+		total += b.Value
+	}
+	return total
+}
@@ -0,0 +1,143 @@
+package txbuilder
+
+import (
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	testmoney "github.com/alphabill-org/alphabill-wallet/internal/testutils/money"
+)
+
+func TestNewBillSelector(t *testing.T) {
+	for _, strategy := range SelectionStrategies() {
+		selector, err := NewBillSelector(strategy, nil)
+		require.NoError(t, err)
+		require.NotNil(t, selector)
+	}
+	selector, err := NewBillSelector("", nil)
+	require.NoError(t, err)
+	require.Equal(t, largestFirst{}, selector)
+
+	selector, err = NewBillSelector("random", nil)
+	require.EqualError(t, err, `unknown bill selection strategy "random", expected one of largest-first, fewest-txs, exact-match, smallest-first, oldest-first`)
+	require.Nil(t, selector)
+}
+
+func TestBillSelectors(t *testing.T) {
+	// bills with value and counter
+	bills := []*sdktypes.Bill{
+		testmoney.NewBill(t, 3, 5),
+		testmoney.NewBill(t, 50, 2),
+		testmoney.NewBill(t, 1, 9),
+		testmoney.NewBill(t, 20, 0),
+		testmoney.NewBill(t, 7, 1),
+		testmoney.NewBill(t, 30, 3),
+	}
+	// rounds in which the bills were last modified, the bill of value 20 was modified before the indexed rounds
+	lastModifiedRounds := map[string]uint64{
+		string(bills[0].ID): 50,
+		string(bills[1].ID): 20,
+		string(bills[2].ID): 90,
+		string(bills[4].ID): 10,
+		string(bills[5].ID): 30,
+	}
+	values := func(bills []*sdktypes.Bill) []uint64 {
+		var res []uint64
+		for _, b := range bills {
+			res = append(res, b.Value)
+		}
+		return res
+	}
+
+	tests := []struct {
+		strategy string
+		amount   uint64
+		expected []uint64
+	}{
+		// exact value bill
+		{strategy: SelectionLargestFirst, amount: 7, expected: []uint64{7}},
+		{strategy: SelectionLargestFirst, amount: 60, expected: []uint64{50, 30}},
+		{strategy: SelectionLargestFirst, amount: 111, expected: []uint64{50, 30, 20, 7, 3, 1}},
+		// the smallest bill which covers the rest of the amount
+		{strategy: SelectionFewestTxs, amount: 8, expected: []uint64{20}},
+		{strategy: SelectionFewestTxs, amount: 60, expected: []uint64{50, 20}},
+		{strategy: SelectionFewestTxs, amount: 7, expected: []uint64{7}},
+		// the fewest bills with the exact total value
+		{strategy: SelectionExactMatch, amount: 60, expected: []uint64{50, 7, 3}},
+		{strategy: SelectionExactMatch, amount: 111, expected: []uint64{50, 30, 20, 7, 3, 1}},
+		{strategy: SelectionExactMatch, amount: 51, expected: []uint64{50, 1}},
+		{strategy: SelectionExactMatch, amount: 27, expected: []uint64{20, 7}},
+		{strategy: SelectionExactMatch, amount: 50, expected: []uint64{50}},
+		// no exact match, falls back to the fewest transactions
+		{strategy: SelectionExactMatch, amount: 2, expected: []uint64{3}},
+		{strategy: SelectionExactMatch, amount: 49, expected: []uint64{50}},
+		{strategy: SelectionSmallestFirst, amount: 10, expected: []uint64{1, 3, 7}},
+		{strategy: SelectionSmallestFirst, amount: 12, expected: []uint64{1, 3, 7, 20}},
+		// the earliest last modified round first
+		{strategy: SelectionOldestFirst, amount: 25, expected: []uint64{20, 7}},
+		{strategy: SelectionOldestFirst, amount: 80, expected: []uint64{20, 7, 50, 30}},
+		{strategy: SelectionOldestFirst, amount: 108, expected: []uint64{20, 7, 50, 30, 3}},
+	}
+	for _, tt := range tests {
+		selector, err := NewBillSelector(tt.strategy, lastModifiedRounds)
+		require.NoError(t, err)
+		selected, err := selector.SelectBills(bills, tt.amount)
+		require.NoError(t, err, "%s %d", tt.strategy, tt.amount)
+		require.Equal(t, tt.expected, values(selected), "%s %d", tt.strategy, tt.amount)
+	}
+
+	// the input is not modified
+	require.Equal(t, []uint64{3, 50, 1, 20, 7, 30}, values(bills))
+
+	for _, strategy := range SelectionStrategies() {
+		selector, err := NewBillSelector(strategy, lastModifiedRounds)
+		require.NoError(t, err)
+		_, err = selector.SelectBills(bills, 112)
+		require.EqualError(t, err, "insufficient balance for transaction, trying to send 112 have 111", strategy)
+	}
+}
+
+func TestBillSelectors_exactMatchTriesLimit(t *testing.T) {
+	var bills []*sdktypes.Bill
+	for range 40 {
+		bills = append(bills, testmoney.NewBill(t, 2, 0))
+	}
+	// odd amount can not be matched, the search is aborted and the fewest bills are selected
+	selected, err := exactMatch{maxTries: 1000}.SelectBills(bills, 41)
+	require.NoError(t, err)
+	require.Len(t, selected, 21)
+}
+
+func TestCreateTransactions_billSelector(t *testing.T) {
+	txSigner, err := sdktypes.NewMoneyTxSignerFromKey(accountKey.AccountKey.PrivKey)
+	require.NoError(t, err)
+	bills := []*sdktypes.Bill{createBill(t, 50), createBill(t, 30), createBill(t, 20), createBill(t, 7), createBill(t, 3)}
+
+	// the default selection spends the largest bill and splits the second one
+	txs, err := CreateTransactions(receiverPubKey, 60, bills, nil, txSigner, 100, nil, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.Equal(t, money.TransactionTypeTransfer, txs[0].Type)
+	require.Equal(t, money.TransactionTypeSplit, txs[1].Type)
+
+	// exact match does not split any bill
+	txs, err = CreateTransactions(receiverPubKey, 60, bills, exactMatch{maxTries: exactMatchMaxTries}, txSigner, 100, nil, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 3)
+	for i, bill := range []*sdktypes.Bill{bills[0], bills[3], bills[4]} {
+		require.Equal(t, money.TransactionTypeTransfer, txs[i].Type)
+		require.EqualValues(t, bill.ID, txs[i].GetUnitID())
+	}
+
+	// smallest first splits the last bill
+	txs, err = CreateTransactions(receiverPubKey, 5, bills, smallestFirst{}, txSigner, 100, nil, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.EqualValues(t, bills[4].ID, txs[0].GetUnitID())
+	require.Equal(t, money.TransactionTypeSplit, txs[1].Type)
+	splitAttr := &money.SplitAttributes{}
+	require.NoError(t, txs[1].UnmarshalAttributes(splitAttr))
+	require.EqualValues(t, 2, splitAttr.TargetUnits[0].Amount)
+}
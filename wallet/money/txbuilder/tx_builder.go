@@ -2,7 +2,6 @@ package txbuilder
 
 import (
 	"fmt"
-	"slices"
 
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
@@ -11,30 +10,20 @@ import (
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 )
 
-// CreateTransactions creates 1 to N P2PKH transactions from given bills until target amount is reached.
-// If there exists a bill with value equal to the given amount then transfer transaction is created using that bill,
-// otherwise bills are selected in the given order.
-func CreateTransactions(pubKey []byte, amount uint64, bills []*sdktypes.Bill, txSigner *sdktypes.MoneyTxSigner, timeout uint64, fcrID, refNo []byte, maxFee uint64) ([]*types.TransactionOrder, error) {
-	billIndex := slices.IndexFunc(bills, func(b *sdktypes.Bill) bool { return b.Value == amount })
-	if billIndex >= 0 {
-		ownerPredicate := templates.NewP2pkh256BytesFromKey(pubKey)
-		txo, err := bills[billIndex].Transfer(ownerPredicate,
-			sdktypes.WithTimeout(timeout),
-			sdktypes.WithFeeCreditRecordID(fcrID),
-			sdktypes.WithMaxFee(maxFee),
-			sdktypes.WithReferenceNumber(refNo),
-		)
-		if err != nil {
-			return nil, err
-		}
-		if err = txSigner.SignTx(txo); err != nil {
-			return nil, fmt.Errorf("failed to sign tx: %w", err)
-		}
-		return []*types.TransactionOrder{txo}, nil
+// CreateTransactions creates 1 to N P2PKH transactions from the bills chosen by the bill selector until target
+// amount is reached, the last bill is split if needed. The default bill selector (largest-first) is used if nil.
+// The transactions are not signed if txSigner is nil.
+func CreateTransactions(pubKey []byte, amount uint64, bills []*sdktypes.Bill, selector BillSelector, txSigner *sdktypes.MoneyTxSigner, timeout uint64, fcrID, refNo []byte, maxFee uint64) ([]*types.TransactionOrder, error) {
+	if selector == nil {
+		selector = largestFirst{}
+	}
+	selected, err := selector.SelectBills(bills, amount)
+	if err != nil {
+		return nil, err
 	}
 	var txs []*types.TransactionOrder
 	var accumulatedSum uint64
-	for _, b := range bills {
+	for _, b := range selected {
 		remainingAmount := amount - accumulatedSum
 		tx, err := createTransaction(pubKey, txSigner, remainingAmount, b, timeout, fcrID, refNo, maxFee)
 		if err != nil {
@@ -62,10 +51,7 @@ func createTransaction(receiverPubKey []byte, txSigner *sdktypes.MoneyTxSigner,
 		if err != nil {
 			return nil, err
 		}
-		if err = txSigner.SignTx(txo); err != nil {
-			return nil, fmt.Errorf("failed to sign tx: %w", err)
-		}
-		return txo, nil
+		return signTx(txSigner, txo)
 	}
 	targetUnits := []*money.TargetUnit{
 		{
@@ -82,7 +68,14 @@ func createTransaction(receiverPubKey []byte, txSigner *sdktypes.MoneyTxSigner,
 	if err != nil {
 		return nil, err
 	}
-	if err = txSigner.SignTx(txo); err != nil {
+	return signTx(txSigner, txo)
+}
+
+func signTx(txSigner *sdktypes.MoneyTxSigner, txo *types.TransactionOrder) (*types.TransactionOrder, error) {
+	if txSigner == nil {
+		return txo, nil
+	}
+	if err := txSigner.SignTx(txo); err != nil {
 		return nil, fmt.Errorf("failed to sign tx: %w", err)
 	}
 	return txo, nil
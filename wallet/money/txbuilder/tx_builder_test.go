@@ -106,7 +106,7 @@ func TestCreateTransactions(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			txs, err := CreateTransactions(receiverPubKey, tt.amount, tt.bills, txSigner, 100, nil, nil, 10)
+			txs, err := CreateTransactions(receiverPubKey, tt.amount, tt.bills, nil, txSigner, 100, nil, nil, 10)
 			if tt.expectedErr != "" {
 				require.ErrorContains(t, err, tt.expectedErr)
 			} else {
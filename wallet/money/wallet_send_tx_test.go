@@ -10,6 +10,7 @@ import (
 	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	testmoney "github.com/alphabill-org/alphabill-wallet/internal/testutils/money"
+	"github.com/alphabill-org/alphabill-wallet/wallet/money/txbuilder"
 	"github.com/stretchr/testify/require"
 )
 
@@ -177,6 +178,34 @@ func TestWalletSendFunction_BillWithExactAmount(t *testing.T) {
 	require.EqualValues(t, exactBill.ID, txo.GetUnitID())
 }
 
+func TestWalletSendFunction_BillSelector(t *testing.T) {
+	// create test wallet with bills of different values
+	dust1 := testmoney.NewBill(t, 1, 1)
+	dust2 := testmoney.NewBill(t, 2, 1)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 100, 1)),
+		testmoney.WithOwnerBill(dust1),
+		testmoney.WithOwnerBill(dust2),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+	)
+	w := createTestWallet(t, moneyClient)
+	selector, err := txbuilder.NewBillSelector(txbuilder.SelectionSmallestFirst, nil)
+	require.NoError(t, err)
+
+	// smallest bills are spent first and the large bill is split
+	_, err = w.Send(context.Background(), SendCmd{
+		Receivers:    []ReceiverData{{PubKey: make([]byte, 33), Amount: 10}},
+		BillSelector: selector,
+	})
+	require.NoError(t, err)
+	require.Len(t, moneyClient.RecordedTxs, 3)
+	require.Equal(t, money.TransactionTypeTransfer, moneyClient.RecordedTxs[0].Type)
+	require.EqualValues(t, dust1.ID, moneyClient.RecordedTxs[0].GetUnitID())
+	require.Equal(t, money.TransactionTypeTransfer, moneyClient.RecordedTxs[1].Type)
+	require.EqualValues(t, dust2.ID, moneyClient.RecordedTxs[1].GetUnitID())
+	require.Equal(t, money.TransactionTypeSplit, moneyClient.RecordedTxs[2].Type)
+}
+
 func TestWalletSendFunction_NWaySplit(t *testing.T) {
 	// create test wallet with a single bill
 	pubKey := make([]byte, 33)
@@ -221,3 +250,96 @@ func newMoneyFCR(t *testing.T, pubKeyHashHex string, balance, counter uint64) *s
 	require.NoError(t, err)
 	return testmoney.NewMoneyFCR(t, pubKeyHash, balance, nil, counter)
 }
+
+func TestWalletBuildUnsignedSend(t *testing.T) {
+	bill1 := testmoney.NewBill(t, 30, 1)
+	bill2 := testmoney.NewBill(t, 20, 1)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(bill1),
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 5, 1)),
+		testmoney.WithOwnerBill(bill2),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+	)
+	w := createTestWallet(t, moneyClient)
+	cmd := SendCmd{Receivers: []ReceiverData{{PubKey: make([]byte, 33), Amount: 40}}, MaxFee: 10}
+
+	envelope, err := w.BuildUnsignedSend(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Empty(t, moneyClient.RecordedTxs)
+	require.Equal(t, money.PartitionTypeID, envelope.PartitionTypeID)
+	require.EqualValues(t, 0, envelope.AccountIndex)
+	require.Equal(t, []*sdktypes.Bill{bill1, bill2}, envelope.Bills)
+	require.EqualValues(t, 100, envelope.FeeCreditRecord.Balance)
+	require.Len(t, envelope.Transactions, 2)
+	for _, tx := range envelope.Transactions {
+		require.Empty(t, tx.AuthProof)
+		require.Empty(t, tx.FeeProof)
+	}
+
+	// signed transactions are the same as sent by Send
+	accountKey, err := w.am.GetAccountKey(0)
+	require.NoError(t, err)
+	require.NoError(t, envelope.Sign(accountKey))
+	_, err = w.Send(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Equal(t, moneyClient.RecordedTxs, envelope.Transactions)
+
+	// timeout of the unsigned transactions
+	moneyClient.RoundNumber = 100
+	cmd.Timeout = 5000
+	envelope, err = w.BuildUnsignedSend(context.Background(), cmd)
+	require.NoError(t, err)
+	for _, tx := range envelope.Transactions {
+		require.EqualValues(t, 5100, tx.Timeout())
+	}
+
+	// fee credit is checked when building the transactions
+	cmd.MaxFee = 51
+	_, err = w.BuildUnsignedSend(context.Background(), cmd)
+	require.EqualError(t, err, "insufficient fee credit balance for transaction(s)")
+}
+
+func TestWalletSendBatch(t *testing.T) {
+	bill1 := testmoney.NewBill(t, 100, 1)
+	bill2 := testmoney.NewBill(t, 20, 1)
+	moneyClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(bill1),
+		testmoney.WithOwnerBill(bill2),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+	)
+	w := createTestWallet(t, moneyClient)
+	pubKey := make([]byte, 33)
+	cmd := SendBatchCmd{
+		Payments: []*txbuilder.Payment{
+			{PubKey: pubKey, Amount: 10, ReferenceNumber: []byte("a")},
+			{PubKey: pubKey, Amount: 20},
+			{PubKey: pubKey, Amount: 30, ReferenceNumber: []byte("a")},
+		},
+		WaitForConfirmation: true,
+		MaxFee:              10,
+	}
+
+	results, err := w.SendBatch(context.Background(), cmd)
+	require.NoError(t, err)
+	require.Len(t, moneyClient.RecordedTxs, 2)
+	require.Len(t, results, 3)
+	// the payments with the same reference number are paid with the same split
+	require.Equal(t, results[0], results[2])
+	require.Equal(t, money.TransactionTypeSplit, results[0].TxType)
+	require.NotNil(t, results[0].Proof)
+	// the payment is paid by transferring the bill of the exact value
+	require.Equal(t, money.TransactionTypeTransfer, results[1].TxType)
+	require.NotNil(t, results[1].Proof)
+	txo, err := results[1].Proof.GetTransactionOrderV1()
+	require.NoError(t, err)
+	require.EqualValues(t, bill2.ID, txo.GetUnitID())
+
+	// fee credit must cover the fees of all the transactions
+	cmd.MaxFee = 51
+	_, err = w.SendBatch(context.Background(), cmd)
+	require.EqualError(t, err, "insufficient fee credit balance for transaction(s)")
+
+	cmd.Payments[1].Amount = 0
+	_, err = w.SendBatch(context.Background(), cmd)
+	require.EqualError(t, err, "invalid amount of payment #2: amount must be greater than zero")
+}
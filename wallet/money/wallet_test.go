@@ -12,6 +12,7 @@ import (
 	testmoney "github.com/alphabill-org/alphabill-wallet/internal/testutils/money"
 	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 	"github.com/alphabill-org/alphabill-wallet/wallet/fees"
+	"github.com/alphabill-org/alphabill-wallet/wallet/money/txbuilder"
 )
 
 const (
@@ -114,3 +115,42 @@ func createTestWallet(t *testing.T, moneyClient sdktypes.MoneyPartitionClient) *
 
 	return w
 }
+
+func TestWallet_WatchOnlyAccount(t *testing.T) {
+	rpcClient := testmoney.NewRpcClientMock(
+		testmoney.WithOwnerBill(testmoney.NewBill(t, 10, 1)),
+		testmoney.WithOwnerFeeCreditRecord(newMoneyFCR(t, testPubKey0Hash, 100, 200)),
+	)
+	w := createTestWallet(t, rpcClient)
+	pubKey1, err := hexutil.Decode("0x" + testPubKey1Hex)
+	require.NoError(t, err)
+	accIdx, err := w.am.AddWatchOnlyAccount(nil, account.NewKeyHash(pubKey1).Sha256)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, accIdx)
+
+	// balance of the watch-only account can be queried
+	balances, sum, err := w.GetBalances(context.Background(), GetBalanceCmd{})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 10}, balances)
+	require.EqualValues(t, 20, sum)
+
+	// but the account can not sign
+	sendCmd := SendCmd{Receivers: []ReceiverData{{PubKey: make([]byte, 33), Amount: 5}}, AccountIndex: 1}
+	_, err = w.Send(context.Background(), sendCmd)
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+	_, err = w.SendBatch(context.Background(), SendBatchCmd{Payments: []*txbuilder.Payment{{PubKey: make([]byte, 33), Amount: 5}}, AccountIndex: 1})
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+	_, err = w.BuildUnsignedSend(context.Background(), sendCmd)
+	require.EqualError(t, err, "key #2 is watch-only account of public key hash, public key is required to build transactions")
+	_, err = w.AddFeeCredit(context.Background(), fees.AddFeeCmd{Amount: 1e8, AccountIndex: 1})
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+	_, err = w.CollectDust(context.Background(), 2)
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+	require.Empty(t, rpcClient.RecordedTxs)
+
+	// dust collection of all accounts skips the watch-only account
+	res, err := w.CollectDust(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.EqualValues(t, 0, res[0].AccountIndex)
+}
@@ -0,0 +1,154 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/types/hex"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
+)
+
+// EnvelopeVersion is the version of the envelope format written by this wallet.
+const EnvelopeVersion = 1
+
+/*
+Envelope carries transactions from the online machine which builds them to the offline
+machine which signs them and back to the online machine which submits them.
+
+Bills and FeeCreditRecord are the state the transactions were built from, they are
+informational for the signer and are not needed for the submission.
+*/
+type Envelope struct {
+	_               struct{} `cbor:",toarray"`
+	Version         uint32
+	NetworkID       types.NetworkID
+	PartitionID     types.PartitionID
+	PartitionTypeID types.PartitionTypeID
+	// AccountIndex and OwnerPubKey identify the account key which must sign the transactions.
+	AccountIndex    uint64
+	OwnerPubKey     hex.Bytes
+	Transactions    []*types.TransactionOrder
+	Bills           []*sdktypes.Bill
+	FeeCreditRecord *sdktypes.FeeCreditRecord
+	Signed          bool
+}
+
+// ReadEnvelope reads the CBOR encoded envelope from the file.
+func ReadEnvelope(filename string) (*Envelope, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction file: %w", err)
+	}
+	envelope := &Envelope{}
+	if err := types.Cbor.Unmarshal(data, envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction file: %w", err)
+	}
+	if err := envelope.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid transaction file: %w", err)
+	}
+	return envelope, nil
+}
+
+// WriteEnvelope writes the envelope to the file in CBOR encoding, existing file is overwritten.
+func WriteEnvelope(filename string, envelope *Envelope) error {
+	data, err := types.Cbor.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction file: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		return fmt.Errorf("failed to write transaction file: %w", err)
+	}
+	return nil
+}
+
+func (e *Envelope) IsValid() error {
+	if e.Version != EnvelopeVersion {
+		return fmt.Errorf("unsupported version %d, expected %d", e.Version, EnvelopeVersion)
+	}
+	if len(e.Transactions) == 0 {
+		return errors.New("no transactions")
+	}
+	for i, tx := range e.Transactions {
+		if tx == nil {
+			return fmt.Errorf("transaction #%d is nil", i+1)
+		}
+		if tx.NetworkID != e.NetworkID || tx.PartitionID != e.PartitionID {
+			return fmt.Errorf("transaction #%d is for network %d partition %d, expected network %d partition %d",
+				i+1, tx.NetworkID, tx.PartitionID, e.NetworkID, e.PartitionID)
+		}
+	}
+	return nil
+}
+
+// Sign signs all the transactions with the account key, the key must be the
+// owner key of the envelope. Signing an already signed envelope replaces the signatures.
+func (e *Envelope) Sign(key *account.AccountKey) error {
+	if len(e.OwnerPubKey) != 0 && !bytes.Equal(e.OwnerPubKey, key.PubKey) {
+		return fmt.Errorf("transactions must be signed with the key 0x%x", []byte(e.OwnerPubKey))
+	}
+	txSigner, err := NewTxSignerFromKey(key.PrivKey, e.PartitionTypeID)
+	if err != nil {
+		return err
+	}
+	for i, tx := range e.Transactions {
+		if err := txSigner.SignTx(tx); err != nil {
+			return fmt.Errorf("failed to sign transaction #%d: %w", i+1, err)
+		}
+	}
+	e.Signed = true
+	return nil
+}
+
+// Submit sends the signed transactions to the partition and optionally waits for the confirmations.
+// Fails without sending anything if any of the transactions has already timed out.
+// Returns the transaction proofs if confirmTx=true, otherwise nil.
+func (e *Envelope) Submit(ctx context.Context, partitionClient sdktypes.PartitionClient, confirmTx bool, log *slog.Logger) ([]*types.TxRecordProof, error) {
+	if !e.Signed {
+		return nil, errors.New("transactions are not signed")
+	}
+	pdr, err := partitionClient.PartitionDescription(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load partition description: %w", err)
+	}
+	if pdr.NetworkID != e.NetworkID || pdr.PartitionID != e.PartitionID {
+		return nil, fmt.Errorf("transactions are for network %d partition %d, node is in network %d partition %d",
+			e.NetworkID, e.PartitionID, pdr.NetworkID, pdr.PartitionID)
+	}
+	roundInfo, err := partitionClient.GetRoundInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch current round: %w", err)
+	}
+	for i, tx := range e.Transactions {
+		if tx.Timeout() <= roundInfo.RoundNumber {
+			return nil, fmt.Errorf("transaction #%d expired in round %d (current round %d), the transactions must be built and signed again",
+				i+1, tx.Timeout(), roundInfo.RoundNumber)
+		}
+	}
+	batch := txsubmitter.NewBatch(partitionClient, log)
+	for _, tx := range e.Transactions {
+		sub, err := txsubmitter.New(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tx submission: %w", err)
+		}
+		batch.Add(sub)
+	}
+	if err := batch.SendTx(ctx, confirmTx); err != nil {
+		return nil, err
+	}
+	if !confirmTx {
+		return nil, nil
+	}
+	var proofs []*types.TxRecordProof
+	for _, sub := range batch.Submissions() {
+		proofs = append(proofs, sub.Proof)
+	}
+	return proofs, nil
+}
@@ -0,0 +1,242 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	moneyid "github.com/alphabill-org/alphabill-go-base/testutils/money"
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/internal/testutils/logger"
+	testmoney "github.com/alphabill-org/alphabill-wallet/internal/testutils/money"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
+)
+
+func TestEnvelope_readWrite(t *testing.T) {
+	key := newAccountKey(t)
+	envelope := newMoneyEnvelope(t, key)
+	filename := filepath.Join(t.TempDir(), "tx.cbor")
+	require.NoError(t, WriteEnvelope(filename, envelope))
+
+	res, err := ReadEnvelope(filename)
+	require.NoError(t, err)
+	require.Equal(t, envelope.PartitionTypeID, res.PartitionTypeID)
+	require.Equal(t, envelope.OwnerPubKey, res.OwnerPubKey)
+	require.Equal(t, envelope.Bills, res.Bills)
+	require.Equal(t, envelope.FeeCreditRecord, res.FeeCreditRecord)
+	require.Len(t, res.Transactions, 1)
+	require.Equal(t, envelope.Transactions[0].Payload, res.Transactions[0].Payload)
+	require.False(t, res.Signed)
+
+	_, err = ReadEnvelope(filepath.Join(t.TempDir(), "missing.cbor"))
+	require.ErrorContains(t, err, "failed to read transaction file")
+
+	envelope.Version = 2
+	require.NoError(t, WriteEnvelope(filename, envelope))
+	_, err = ReadEnvelope(filename)
+	require.EqualError(t, err, "invalid transaction file: unsupported version 2, expected 1")
+}
+
+func TestEnvelope_IsValid(t *testing.T) {
+	key := newAccountKey(t)
+	envelope := newMoneyEnvelope(t, key)
+	require.NoError(t, envelope.IsValid())
+
+	envelope.PartitionID = 5
+	require.EqualError(t, envelope.IsValid(), "transaction #1 is for network 3 partition 1, expected network 3 partition 5")
+
+	envelope.Transactions = nil
+	require.EqualError(t, envelope.IsValid(), "no transactions")
+}
+
+func TestEnvelope_Sign(t *testing.T) {
+	key := newAccountKey(t)
+
+	t.Run("money", func(t *testing.T) {
+		envelope := newMoneyEnvelope(t, key)
+		require.NoError(t, envelope.Sign(key))
+		require.True(t, envelope.Signed)
+		tx := envelope.Transactions[0]
+		authProof := money.TransferAuthProof{}
+		require.NoError(t, tx.UnmarshalAuthProof(&authProof))
+		verifySignature(t, key, authProof.OwnerProof, tx.AuthProofSigBytes)
+		verifySignature(t, key, tx.FeeProof, tx.FeeProofSigBytes)
+	})
+
+	t.Run("fee credit", func(t *testing.T) {
+		envelope := newMoneyEnvelope(t, key)
+		fcr := &sdktypes.FeeCreditRecord{NetworkID: envelope.NetworkID, PartitionID: envelope.PartitionID, ID: moneyid.NewFeeCreditRecordID(t)}
+		tx, err := envelope.Bills[0].TransferToFeeCredit(fcr, 10, 100)
+		require.NoError(t, err)
+		envelope.Transactions = []*types.TransactionOrder{tx}
+		require.NoError(t, envelope.Sign(key))
+		authProof := fc.TransferFeeCreditAuthProof{}
+		require.NoError(t, tx.UnmarshalAuthProof(&authProof))
+		verifySignature(t, key, authProof.OwnerProof, tx.AuthProofSigBytes)
+		require.Nil(t, tx.FeeProof)
+	})
+
+	t.Run("tokens", func(t *testing.T) {
+		pdr := tokenid.PDR()
+		token := &sdktypes.FungibleToken{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: tokenid.NewFungibleTokenID(t), TypeID: tokenid.NewFungibleTokenTypeID(t), Amount: 5}
+		tx, err := token.Transfer(templates.AlwaysTrueBytes())
+		require.NoError(t, err)
+		// the builder of the transaction sets the inputs of the inherited predicates
+		typeProofs := [][]byte{{1}, {2}}
+		require.NoError(t, tx.SetAuthProof(tokens.TransferFungibleTokenAuthProof{TokenTypeOwnerProofs: typeProofs}))
+		envelope := &Envelope{
+			Version:         EnvelopeVersion,
+			NetworkID:       pdr.NetworkID,
+			PartitionID:     pdr.PartitionID,
+			PartitionTypeID: tokens.PartitionTypeID,
+			Transactions:    []*types.TransactionOrder{tx},
+		}
+		require.NoError(t, envelope.Sign(key))
+		authProof := tokens.TransferFungibleTokenAuthProof{}
+		require.NoError(t, tx.UnmarshalAuthProof(&authProof))
+		verifySignature(t, key, authProof.OwnerProof, tx.AuthProofSigBytes)
+		require.Equal(t, typeProofs, authProof.TokenTypeOwnerProofs)
+		verifySignature(t, key, tx.FeeProof, tx.FeeProofSigBytes)
+
+		tx.Type = tokens.TransactionTypeMintFT
+		require.EqualError(t, envelope.Sign(key), "failed to sign transaction #1: unsupported transaction type: 3")
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		envelope := newMoneyEnvelope(t, key)
+		require.ErrorContains(t, envelope.Sign(newAccountKey(t)), "transactions must be signed with the key 0x")
+		require.False(t, envelope.Signed)
+	})
+}
+
+func TestEnvelope_Summary(t *testing.T) {
+	key := newAccountKey(t)
+	envelope := newMoneyEnvelope(t, key)
+	bill := envelope.Bills[0]
+	receiverPredicate := templates.AlwaysTrueBytes()
+	split, err := bill.Split([]*money.TargetUnit{{Amount: 3, OwnerPredicate: receiverPredicate}},
+		sdktypes.WithTimeout(20),
+		sdktypes.WithMaxFee(2),
+	)
+	require.NoError(t, err)
+	envelope.Transactions = append(envelope.Transactions, split)
+
+	summary, err := envelope.Summary()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		fmt.Sprintf("Transaction #1: transfer of bill 0x%s, max fee 0.000'000'10, timeout round 10", bill.ID),
+		fmt.Sprintf("  0.000'000'10 to pubkey hash 0x%X", key.PubKeyHash.Sha256),
+		fmt.Sprintf("Transaction #2: split of bill 0x%s, max fee 0.000'000'02, timeout round 20", bill.ID),
+		fmt.Sprintf("  0.000'000'03 to predicate 0x%X", receiverPredicate),
+		fmt.Sprintf("Spent bill 0x%s value 0.000'000'10", bill.ID),
+		fmt.Sprintf("Fee credit record 0x%s balance 0.000'001'00", envelope.FeeCreditRecord.ID),
+	}, summary)
+
+	// fee credit transactions
+	fcr := &sdktypes.FeeCreditRecord{NetworkID: envelope.NetworkID, PartitionID: envelope.PartitionID, ID: moneyid.NewFeeCreditRecordID(t)}
+	transferFC, err := bill.TransferToFeeCredit(fcr, 7, 100, sdktypes.WithTimeout(30))
+	require.NoError(t, err)
+	envelope.Transactions = []*types.TransactionOrder{transferFC}
+	envelope.FeeCreditRecord = nil
+	summary, err = envelope.Summary()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		fmt.Sprintf("Transaction #1: transfer to fee credit of bill 0x%s, max fee 0.000'000'10, timeout round 30", bill.ID),
+		fmt.Sprintf("  0.000'000'07 to fee credit record 0x%s of partition %s", fcr.ID, fcr.PartitionID),
+		fmt.Sprintf("Spent bill 0x%s value 0.000'000'10", bill.ID),
+	}, summary)
+
+	// tokens
+	pdr := tokenid.PDR()
+	token := &sdktypes.FungibleToken{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: tokenid.NewFungibleTokenID(t), TypeID: tokenid.NewFungibleTokenTypeID(t), Amount: 5}
+	tokenSplit, err := token.Split(2, templates.NewP2pkh256BytesFromKey(key.PubKey), sdktypes.WithTimeout(40), sdktypes.WithMaxFee(3))
+	require.NoError(t, err)
+	tokensEnvelope := &Envelope{
+		Version:         EnvelopeVersion,
+		NetworkID:       pdr.NetworkID,
+		PartitionID:     pdr.PartitionID,
+		PartitionTypeID: tokens.PartitionTypeID,
+		Transactions:    []*types.TransactionOrder{tokenSplit},
+	}
+	summary, err = tokensEnvelope.Summary()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		fmt.Sprintf("Transaction #1: split of token 0x%s, max fee 0.000'000'03, timeout round 40", token.ID),
+		fmt.Sprintf("  2 of type 0x%s to pubkey hash 0x%X", token.TypeID, key.PubKeyHash.Sha256),
+	}, summary)
+}
+
+func TestEnvelope_Submit(t *testing.T) {
+	key := newAccountKey(t)
+	envelope := newMoneyEnvelope(t, key)
+	moneyClient := testmoney.NewRpcClientMock()
+
+	_, err := envelope.Submit(context.Background(), moneyClient, true, logger.New(t))
+	require.EqualError(t, err, "transactions are not signed")
+
+	require.NoError(t, envelope.Sign(key))
+	proofs, err := envelope.Submit(context.Background(), moneyClient, true, logger.New(t))
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+	require.Len(t, moneyClient.RecordedTxs, 1)
+	require.Equal(t, envelope.Transactions[0], moneyClient.RecordedTxs[0])
+
+	// expired transactions are not sent
+	expiredClient := testmoney.NewRpcClientMock(testmoney.WithRoundNumber(10))
+	_, err = envelope.Submit(context.Background(), expiredClient, true, logger.New(t))
+	require.EqualError(t, err, "transaction #1 expired in round 10 (current round 10), the transactions must be built and signed again")
+	require.Empty(t, expiredClient.RecordedTxs)
+
+	// node of another partition
+	tokensPDR := tokenid.PDR()
+	_, err = envelope.Submit(context.Background(), testmoney.NewRpcClientMock(testmoney.WithPartition(&tokensPDR)), true, logger.New(t))
+	require.EqualError(t, err, "transactions are for network 3 partition 1, node is in network 2 partition 2")
+}
+
+func newAccountKey(t *testing.T) *account.AccountKey {
+	keys, err := account.NewKeys("")
+	require.NoError(t, err)
+	return keys.AccountKey
+}
+
+func newMoneyEnvelope(t *testing.T, key *account.AccountKey) *Envelope {
+	pdr := moneyid.PDR()
+	bill := &sdktypes.Bill{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: moneyid.NewBillID(t), Value: 10, Counter: 1}
+	fcr := &sdktypes.FeeCreditRecord{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: moneyid.NewFeeCreditRecordID(t), Balance: 100}
+	tx, err := bill.Transfer(templates.NewP2pkh256BytesFromKey(key.PubKey),
+		sdktypes.WithTimeout(10),
+		sdktypes.WithFeeCreditRecordID(fcr.ID),
+	)
+	require.NoError(t, err)
+	return &Envelope{
+		Version:         EnvelopeVersion,
+		NetworkID:       pdr.NetworkID,
+		PartitionID:     pdr.PartitionID,
+		PartitionTypeID: pdr.PartitionTypeID,
+		OwnerPubKey:     key.PubKey,
+		Transactions:    []*types.TransactionOrder{tx},
+		Bills:           []*sdktypes.Bill{bill},
+		FeeCreditRecord: fcr,
+	}
+}
+
+func verifySignature(t *testing.T, key *account.AccountKey, proof []byte, sigBytes func() ([]byte, error)) {
+	signature := templates.P2pkh256Signature{}
+	require.NoError(t, types.Cbor.Unmarshal(proof, &signature))
+	require.EqualValues(t, key.PubKey, signature.PubKey)
+	verifier, err := abcrypto.NewVerifierSecp256k1(key.PubKey)
+	require.NoError(t, err)
+	data, err := sigBytes()
+	require.NoError(t, err)
+	require.NoError(t, verifier.VerifyBytes(signature.Sig, data))
+}
@@ -0,0 +1,113 @@
+package offline
+
+import (
+	"fmt"
+
+	abcrypto "github.com/alphabill-org/alphabill-go-base/crypto"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+)
+
+// TxSigner generates the P2PKH signatures of money, token and fee credit transactions.
+type TxSigner struct {
+	partitionTypeID types.PartitionTypeID
+	signer          abcrypto.Signer
+	moneyTxSigner   *sdktypes.MoneyTxSigner
+}
+
+func NewTxSignerFromKey(privKey []byte, partitionTypeID types.PartitionTypeID) (*TxSigner, error) {
+	moneyTxSigner, err := sdktypes.NewMoneyTxSignerFromKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return &TxSigner{
+		partitionTypeID: partitionTypeID,
+		signer:          moneyTxSigner.Signer(),
+		moneyTxSigner:   moneyTxSigner,
+	}, nil
+}
+
+/*
+SignTx sets the owner proof of the transaction and the fee proof, fee credit transactions
+do not have the fee proof. Money transactions are signed by MoneyTxSigner.
+
+The token type owner proofs of a token transaction are preserved, the builder of the
+transaction may set them in the auth proof of the unsigned transaction.
+*/
+func (s *TxSigner) SignTx(tx *types.TransactionOrder) error {
+	switch tx.Type {
+	case fc.TransactionTypeTransferFeeCredit, fc.TransactionTypeAddFeeCredit, fc.TransactionTypeCloseFeeCredit, fc.TransactionTypeReclaimFeeCredit:
+		return s.signFeeCreditTx(tx)
+	}
+	switch s.partitionTypeID {
+	case money.PartitionTypeID:
+		return s.moneyTxSigner.SignTx(tx)
+	case tokens.PartitionTypeID:
+		return s.signTokensTx(tx)
+	default:
+		return fmt.Errorf("unsupported partition type: %d", s.partitionTypeID)
+	}
+}
+
+func (s *TxSigner) signFeeCreditTx(tx *types.TransactionOrder) error {
+	ownerProof, err := sdktypes.NewP2pkhAuthProofSignature(tx, s.signer)
+	if err != nil {
+		return fmt.Errorf("failed to create owner proof: %w", err)
+	}
+	var authProof any
+	switch tx.Type {
+	case fc.TransactionTypeTransferFeeCredit:
+		authProof = fc.TransferFeeCreditAuthProof{OwnerProof: ownerProof}
+	case fc.TransactionTypeAddFeeCredit:
+		authProof = fc.AddFeeCreditAuthProof{OwnerProof: ownerProof}
+	case fc.TransactionTypeCloseFeeCredit:
+		authProof = fc.CloseFeeCreditAuthProof{OwnerProof: ownerProof}
+	case fc.TransactionTypeReclaimFeeCredit:
+		authProof = fc.ReclaimFeeCreditAuthProof{OwnerProof: ownerProof}
+	}
+	if err = tx.SetAuthProof(authProof); err != nil {
+		return fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	return nil
+}
+
+func (s *TxSigner) signTokensTx(tx *types.TransactionOrder) error {
+	// the auth proofs of the supported token transactions have the same encoding
+	current := tokens.TransferFungibleTokenAuthProof{}
+	if len(tx.AuthProof) != 0 {
+		if err := tx.UnmarshalAuthProof(&current); err != nil {
+			return fmt.Errorf("failed to decode auth proof: %w", err)
+		}
+	}
+	ownerProof, err := sdktypes.NewP2pkhAuthProofSignature(tx, s.signer)
+	if err != nil {
+		return fmt.Errorf("failed to create owner proof: %w", err)
+	}
+	typeProofs := current.TokenTypeOwnerProofs
+	var authProof any
+	switch tx.Type {
+	case tokens.TransactionTypeTransferFT:
+		authProof = tokens.TransferFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeProofs}
+	case tokens.TransactionTypeSplitFT:
+		authProof = tokens.SplitFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeProofs}
+	case tokens.TransactionTypeBurnFT:
+		authProof = tokens.BurnFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeProofs}
+	case tokens.TransactionTypeJoinFT:
+		authProof = tokens.JoinFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeProofs}
+	case tokens.TransactionTypeTransferNFT:
+		authProof = tokens.TransferNonFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeProofs}
+	default:
+		return fmt.Errorf("unsupported transaction type: %d", tx.Type)
+	}
+	if err = tx.SetAuthProof(authProof); err != nil {
+		return fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	if err = s.moneyTxSigner.AddFeeProof(tx); err != nil {
+		return fmt.Errorf("failed to add fee proof: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package offline
+
+import (
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/fc"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/money"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	"github.com/alphabill-org/alphabill-wallet/util"
+)
+
+/*
+Summary describes the transactions of the envelope in human readable form, one line per
+item, so that the signer can check what is going to be signed: the type and the unit of
+every transaction, the receivers and the amounts, the max fee and the timeout round.
+The bills and the fee credit record the transactions were built from are listed last.
+*/
+func (e *Envelope) Summary() ([]string, error) {
+	var lines []string
+	for i, tx := range e.Transactions {
+		typeName, unitName := e.txTypeName(tx.Type)
+		lines = append(lines, fmt.Sprintf("Transaction #%d: %s of %s 0x%s, max fee %s, timeout round %d",
+			i+1, typeName, unitName, tx.UnitID, util.AmountToString(tx.MaxFee(), 8), tx.Timeout()))
+		receivers, err := e.receiverSummary(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transaction #%d attributes: %w", i+1, err)
+		}
+		lines = append(lines, receivers...)
+	}
+	for _, bill := range e.Bills {
+		lines = append(lines, fmt.Sprintf("Spent bill 0x%s value %s", bill.ID, util.AmountToString(bill.Value, 8)))
+	}
+	if e.FeeCreditRecord != nil {
+		lines = append(lines, fmt.Sprintf("Fee credit record 0x%s balance %s", e.FeeCreditRecord.ID, util.AmountToString(e.FeeCreditRecord.Balance, 8)))
+	}
+	return lines, nil
+}
+
+func (e *Envelope) receiverSummary(tx *types.TransactionOrder) ([]string, error) {
+	switch {
+	case tx.Type == fc.TransactionTypeTransferFeeCredit:
+		attr := &fc.TransferFeeCreditAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("  %s to fee credit record 0x%X of partition %s",
+			util.AmountToString(attr.Amount, 8), attr.TargetRecordID, attr.TargetPartitionID)}, nil
+	case tx.Type == fc.TransactionTypeCloseFeeCredit:
+		attr := &fc.CloseFeeCreditAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		return []string{fmt.Sprintf("  %s to bill 0x%X", util.AmountToString(attr.Amount, 8), attr.TargetUnitID)}, nil
+	case e.PartitionTypeID == money.PartitionTypeID && tx.Type == money.TransactionTypeTransfer:
+		attr := &money.TransferAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		return []string{receiverLine(attr.NewOwnerPredicate, util.AmountToString(attr.TargetValue, 8))}, nil
+	case e.PartitionTypeID == money.PartitionTypeID && tx.Type == money.TransactionTypeSplit:
+		attr := &money.SplitAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		var lines []string
+		for _, unit := range attr.TargetUnits {
+			lines = append(lines, receiverLine(unit.OwnerPredicate, util.AmountToString(unit.Amount, 8)))
+		}
+		return lines, nil
+	case e.PartitionTypeID == tokens.PartitionTypeID && tx.Type == tokens.TransactionTypeTransferFT:
+		attr := &tokens.TransferFungibleTokenAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		return []string{receiverLine(attr.NewOwnerPredicate, fmt.Sprintf("%d of type 0x%s", attr.Value, attr.TypeID))}, nil
+	case e.PartitionTypeID == tokens.PartitionTypeID && tx.Type == tokens.TransactionTypeSplitFT:
+		attr := &tokens.SplitFungibleTokenAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		return []string{receiverLine(attr.NewOwnerPredicate, fmt.Sprintf("%d of type 0x%s", attr.TargetValue, attr.TypeID))}, nil
+	case e.PartitionTypeID == tokens.PartitionTypeID && tx.Type == tokens.TransactionTypeTransferNFT:
+		attr := &tokens.TransferNonFungibleTokenAttributes{}
+		if err := tx.UnmarshalAttributes(attr); err != nil {
+			return nil, err
+		}
+		return []string{receiverLine(attr.NewOwnerPredicate, fmt.Sprintf("token of type 0x%s", attr.TypeID))}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func receiverLine(ownerPredicate []byte, what string) string {
+	receiver := fmt.Sprintf("predicate 0x%X", ownerPredicate)
+	if pubKeyHash, err := templates.ExtractPubKeyHashFromP2pkhPredicate(ownerPredicate); err == nil {
+		receiver = fmt.Sprintf("pubkey hash 0x%X", pubKeyHash)
+	}
+	return fmt.Sprintf("  %s to %s", what, receiver)
+}
+
+// txTypeName returns the name of the transaction type and the name of the unit the
+// transaction is spending.
+func (e *Envelope) txTypeName(txType uint16) (string, string) {
+	switch txType {
+	case fc.TransactionTypeTransferFeeCredit:
+		return "transfer to fee credit", "bill"
+	case fc.TransactionTypeReclaimFeeCredit:
+		return "reclaim fee credit", "bill"
+	case fc.TransactionTypeAddFeeCredit:
+		return "add fee credit", "fee credit record"
+	case fc.TransactionTypeCloseFeeCredit:
+		return "close fee credit", "fee credit record"
+	}
+	switch e.PartitionTypeID {
+	case money.PartitionTypeID:
+		switch txType {
+		case money.TransactionTypeTransfer:
+			return "transfer", "bill"
+		case money.TransactionTypeSplit:
+			return "split", "bill"
+		case money.TransactionTypeTransDC:
+			return "transfer to dust collector", "bill"
+		case money.TransactionTypeSwapDC:
+			return "swap", "bill"
+		}
+	case tokens.PartitionTypeID:
+		switch txType {
+		case tokens.TransactionTypeTransferFT:
+			return "transfer", "token"
+		case tokens.TransactionTypeSplitFT:
+			return "split", "token"
+		case tokens.TransactionTypeBurnFT:
+			return "burn", "token"
+		case tokens.TransactionTypeJoinFT:
+			return "join", "token"
+		case tokens.TransactionTypeTransferNFT:
+			return "transfer", "token"
+		}
+	}
+	return fmt.Sprintf("transaction type %d", txType), "unit"
+}
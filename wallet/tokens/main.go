@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"math"
 
 	"github.com/alphabill-org/alphabill-go-base/predicates"
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
@@ -155,7 +154,7 @@ func (w *Wallet) NewFungibleType(ctx context.Context, accountNumber uint64, ft *
 		}
 	}
 
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +218,7 @@ func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, n
 		}
 	}
 
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -274,7 +273,7 @@ func (w *Wallet) NewNonFungibleType(ctx context.Context, accountNumber uint64, n
 func (w *Wallet) NewFungibleToken(ctx context.Context, accountNumber uint64, ft *sdktypes.FungibleToken, mintPredicateInput *PredicateInput) (*SubmissionResult, error) {
 	w.log.Info("Minting new fungible token")
 
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -333,7 +332,7 @@ func (w *Wallet) NewNFT(ctx context.Context, accountNumber uint64, nft *sdktypes
 		return nil, errInvalidDataLength
 	}
 
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -390,6 +389,10 @@ func (w *Wallet) ListFungibleTokenTypes(ctx context.Context, accountNumber uint6
 		return typez, nil
 	}
 	for _, key := range keys {
+		if key.PubKey == nil {
+			// watch-only account of public key hash can not be the creator of a type
+			continue
+		}
 		typez, err := fetchForPubKey(key.PubKey)
 		if err != nil {
 			return nil, err
@@ -414,6 +417,10 @@ func (w *Wallet) ListNonFungibleTokenTypes(ctx context.Context, accountNumber ui
 		return typez, nil
 	}
 	for _, key := range keys {
+		if key.PubKey == nil {
+			// watch-only account of public key hash can not be the creator of a type
+			continue
+		}
 		typez, err := fetchForPubKey(key.PubKey)
 		if err != nil {
 			return nil, err
@@ -492,6 +499,18 @@ func (w *Wallet) getAccount(accountNumber uint64) (*accountKey, error) {
 	return &accountKey{AccountKey: key, idx: accountNumber - 1}, nil
 }
 
+// getSigningAccount returns the account key for signing transactions, watch-only account is refused.
+func (w *Wallet) getSigningAccount(accountNumber uint64) (*accountKey, error) {
+	if accountNumber < 1 {
+		return nil, fmt.Errorf("invalid account number: %d", accountNumber)
+	}
+	key, err := w.am.GetSigningKey(accountNumber - 1)
+	if err != nil {
+		return nil, err
+	}
+	return &accountKey{AccountKey: key, idx: accountNumber - 1}, nil
+}
+
 func (w *Wallet) getAccounts(accountNumber uint64) ([]*accountKey, error) {
 	if accountNumber > AllAccounts {
 		key, err := w.getAccount(accountNumber)
@@ -534,7 +553,7 @@ func (w *Wallet) GetNonFungibleToken(ctx context.Context, tokenID sdktypes.Token
 }
 
 func (w *Wallet) TransferNFT(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, receiverPubKey sdktypes.PubKey, typeOwnerPredicateInputs []*PredicateInput, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -600,7 +619,7 @@ func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId
 	if accountNumber < 1 {
 		return nil, fmt.Errorf("invalid account number: %d", accountNumber)
 	}
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -615,56 +634,29 @@ func (w *Wallet) SendFungible(ctx context.Context, accountNumber uint64, typeId
 	if len(tokenz) == 0 {
 		return nil, fmt.Errorf("account %d has no tokens", accountNumber)
 	}
-	var matchingTokens []*sdktypes.FungibleToken
-	var totalBalance uint64
-	// find the best unit candidate for transfer or split, value must be equal or larger than the target amount
-	var closestMatch *sdktypes.FungibleToken
-	for _, token := range tokenz {
-		if !typeId.Eq(token.TypeID) {
-			continue
-		}
-		if token.StateLockTx != nil {
-			continue
-		}
-		matchingTokens = append(matchingTokens, token)
-		var ok bool
-		if totalBalance, ok = util.SafeAdd(totalBalance, token.Amount); !ok {
-			// capping the total balance to maxUint64 should be enough to perform the transfer
-			totalBalance = math.MaxUint64
-		}
-		if closestMatch == nil {
-			closestMatch = token
-		} else {
-			prevDiff := closestMatch.Amount - targetAmount
-			currDiff := token.Amount - targetAmount
-			// this should work with overflow nicely
-			if prevDiff > currDiff {
-				closestMatch = token
-			}
-		}
-	}
-	if targetAmount > totalBalance {
-		return nil, fmt.Errorf("insufficient tokens of type %s: got %v, need %v", typeId, totalBalance, targetAmount)
+	selected, err := selectFungibleTokens(tokenz, typeId, targetAmount)
+	if err != nil {
+		return nil, err
 	}
 	// optimization: first try to make a single operation instead of iterating through all tokens in doSendMultiple
-	if closestMatch.Amount >= targetAmount {
+	if len(selected) == 1 {
 		roundNumber, err := w.GetRoundNumber(ctx)
 		if err != nil {
 			return nil, err
 		}
-		sub, err := w.prepareSplitOrTransferTx(acc, targetAmount, closestMatch, fcrID, receiverPubKey, roundNumber+txTimeoutRoundCount, ownerPredicateInput, typeOwnerPredicateInputs)
+		sub, err := w.prepareSplitOrTransferTx(acc, targetAmount, selected[0], fcrID, receiverPubKey, roundNumber+txTimeoutRoundCount, ownerPredicateInput, typeOwnerPredicateInputs)
 		if err != nil {
 			return nil, err
 		}
 		err = sub.ToBatch(w.tokensClient, w.log).SendTx(ctx, w.confirmTx)
 		return newSingleResult(sub, accountNumber), err
 	} else {
-		return w.doSendMultiple(ctx, targetAmount, matchingTokens, acc, fcrID, receiverPubKey, ownerPredicateInput, typeOwnerPredicateInputs)
+		return w.doSendMultiple(ctx, targetAmount, selected, acc, fcrID, receiverPubKey, ownerPredicateInput, typeOwnerPredicateInputs)
 	}
 }
 
 func (w *Wallet) UpdateNFTData(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, data []byte, tokenDataUpdatePredicateInput *PredicateInput, tokenTypeDataUpdatePredicateInputs []*PredicateInput) (*SubmissionResult, error) {
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -722,7 +714,7 @@ func (w *Wallet) UpdateNFTData(ctx context.Context, accountNumber uint64, tokenI
 
 // SendFungibleByID sends fungible tokens by given unit ID, if amount matches, does the transfer, otherwise splits the token
 func (w *Wallet) SendFungibleByID(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, targetAmount uint64, receiverPubKey []byte, typeOwnerPredicateInputs []*PredicateInput) (*SubmissionResult, error) {
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -796,7 +788,7 @@ func (w *Wallet) ensureFeeCredit(ctx context.Context, accountKey *account.Accoun
 }
 
 func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID types.UnitID, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
-	key, err := w.getAccount(accountNumber)
+	key, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -804,7 +796,7 @@ func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID ty
 	if err != nil {
 		return nil, err
 	}
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -869,7 +861,7 @@ func (w *Wallet) LockToken(ctx context.Context, accountNumber uint64, tokenID ty
 }
 
 func (w *Wallet) UnlockToken(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, ownerPredicateInput *PredicateInput) (*SubmissionResult, error) {
-	key, err := w.getAccount(accountNumber)
+	key, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -877,7 +869,7 @@ func (w *Wallet) UnlockToken(ctx context.Context, accountNumber uint64, tokenID
 	if err != nil {
 		return nil, err
 	}
-	acc, err := w.getAccount(accountNumber)
+	acc, err := w.getSigningAccount(accountNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +106,49 @@ func Test_ListTokenTypes(t *testing.T) {
 	require.ErrorContains(t, err, "account does not exist")
 }
 
+func Test_WatchOnlyAccount(t *testing.T) {
+	watchedKeyHash := test.RandomBytes(32)
+	var typeCreators []sdktypes.PubKey
+	rpcClient := &mockTokensPartitionClient{
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			if !bytes.Equal(ownerID, watchedKeyHash) {
+				return nil, nil
+			}
+			return []*sdktypes.FungibleToken{{ID: test.RandomBytes(33), Amount: 10}}, nil
+		},
+		getFungibleTokenTypes: func(ctx context.Context, creator sdktypes.PubKey) ([]*sdktypes.FungibleTokenType, error) {
+			typeCreators = append(typeCreators, creator)
+			return nil, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	accIdx, err := tw.GetAccountManager().AddWatchOnlyAccount(nil, watchedKeyHash)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, accIdx)
+
+	// tokens of the watch-only account can be listed
+	fts, err := tw.ListFungibleTokens(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, fts, 1)
+
+	// watch-only account of public key hash is not queried as type creator
+	_, err = tw.ListFungibleTokenTypes(context.Background(), 0)
+	require.NoError(t, err)
+	pubKey0, err := tw.GetAccountManager().GetPublicKey(0)
+	require.NoError(t, err)
+	require.Equal(t, []sdktypes.PubKey{pubKey0}, typeCreators)
+
+	// but the account can not sign
+	_, err = tw.SendFungible(context.Background(), 2, test.RandomBytes(33), 5, test.RandomBytes(33), nil, nil)
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+	_, err = tw.CollectDust(context.Background(), 2, nil, nil, nil)
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+	watchOnlyKey, err := tw.GetAccountManager().GetAccountKey(1)
+	require.NoError(t, err)
+	_, err = (&PredicateInput{AccountKey: watchOnlyKey}).Proof([]byte{1})
+	require.ErrorIs(t, err, account.ErrWatchOnly)
+}
+
 func TestNewTypes(t *testing.T) {
 	t.Parallel()
 
@@ -12,6 +12,7 @@ import (
 	"github.com/alphabill-org/alphabill-wallet/wallet"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/account"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
 )
 
@@ -25,6 +26,12 @@ func (w *Wallet) CollectDust(ctx context.Context, accountNumber uint64, allowedT
 	results := make(map[uint64][]*SubmissionResult, len(keys))
 
 	for _, key := range keys {
+		if key.IsWatchOnly() {
+			if accountNumber > AllAccounts {
+				return nil, fmt.Errorf("key #%d: %w", accountNumber, account.ErrWatchOnly)
+			}
+			continue
+		}
 		tokensByTypes, err := w.getTokensForDC(ctx, key.PubKey, allowedTokenTypes)
 		if err != nil {
 			return nil, err
@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
 	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+	"github.com/alphabill-org/alphabill-go-base/util"
 
 	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
 	"github.com/alphabill-org/alphabill-wallet/wallet/txsubmitter"
@@ -72,72 +75,110 @@ func (w *Wallet) doSendMultiple(ctx context.Context, amount uint64, tokens []*sd
 }
 
 func (w *Wallet) prepareSplitOrTransferTx(acc *accountKey, amount uint64, ft *sdktypes.FungibleToken, fcrID, receiverPubKey []byte, timeout uint64, ownerPredicateInput *PredicateInput, typeOwnerPredicateInputs []*PredicateInput) (*txsubmitter.TxSubmission, error) {
+	tx, err := w.newSplitOrTransferTx(amount, ft, fcrID, receiverPubKey, timeout)
+	if err != nil {
+		return nil, err
+	}
+	payloadBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = setSplitOrTransferAuthProof(tx, ownerProof, typeOwnerProofs); err != nil {
+		return nil, err
+	}
+	tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	return txsubmitter.New(tx)
+}
+
+// newSplitOrTransferTx creates unsigned transfer transaction of the token if the amount
+// covers the whole token, otherwise split transaction.
+func (w *Wallet) newSplitOrTransferTx(amount uint64, ft *sdktypes.FungibleToken, fcrID, receiverPubKey []byte, timeout uint64) (*types.TransactionOrder, error) {
 	if amount >= ft.Amount {
-		tx, err := ft.Transfer(OwnerPredicateFromPubKey(receiverPubKey),
+		return ft.Transfer(OwnerPredicateFromPubKey(receiverPubKey),
 			sdktypes.WithTimeout(timeout),
 			sdktypes.WithFeeCreditRecordID(fcrID),
 			sdktypes.WithMaxFee(w.maxFee),
 		)
-		if err != nil {
-			return nil, err
-		}
+	}
+	return ft.Split(amount, OwnerPredicateFromPubKey(receiverPubKey),
+		sdktypes.WithTimeout(timeout),
+		sdktypes.WithFeeCreditRecordID(fcrID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+}
 
-		payloadBytes, err := tx.AuthProofSigBytes()
-		if err != nil {
-			return nil, err
-		}
-		typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
-		if err != nil {
-			return nil, err
-		}
-		ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
-		if err != nil {
-			return nil, err
-		}
-		err = tx.SetAuthProof(tokens.TransferFungibleTokenAuthProof{
-			OwnerProof:           ownerProof,
-			TokenTypeOwnerProofs: typeOwnerProofs,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to set auth proof: %w", err)
-		}
-		tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
-		}
-		return txsubmitter.New(tx)
+func setSplitOrTransferAuthProof(tx *types.TransactionOrder, ownerProof []byte, typeOwnerProofs [][]byte) error {
+	var authProof any
+	if tx.Type == tokens.TransactionTypeTransferFT {
+		authProof = tokens.TransferFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeOwnerProofs}
 	} else {
-		tx, err := ft.Split(amount, OwnerPredicateFromPubKey(receiverPubKey),
-			sdktypes.WithTimeout(timeout),
-			sdktypes.WithFeeCreditRecordID(fcrID),
-			sdktypes.WithMaxFee(w.maxFee),
-		)
-		if err != nil {
-			return nil, err
-		}
-		payloadBytes, err := tx.AuthProofSigBytes()
-		if err != nil {
-			return nil, err
+		authProof = tokens.SplitFungibleTokenAuthProof{OwnerProof: ownerProof, TokenTypeOwnerProofs: typeOwnerProofs}
+	}
+	if err := tx.SetAuthProof(authProof); err != nil {
+		return fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	return nil
+}
+
+/*
+selectFungibleTokens returns the unlocked tokens of the type to spend for the target amount:
+the token closest to the amount if it alone is sufficient, otherwise the largest tokens first.
+*/
+func selectFungibleTokens(tokenz []*sdktypes.FungibleToken, typeID sdktypes.TokenTypeID, targetAmount uint64) ([]*sdktypes.FungibleToken, error) {
+	var matchingTokens []*sdktypes.FungibleToken
+	var totalBalance uint64
+	// find the best unit candidate for transfer or split, value must be equal or larger than the target amount
+	var closestMatch *sdktypes.FungibleToken
+	for _, token := range tokenz {
+		if !typeID.Eq(token.TypeID) {
+			continue
 		}
-		typeOwnerProofs, err := newProofs(payloadBytes, typeOwnerPredicateInputs)
-		if err != nil {
-			return nil, err
+		if token.StateLockTx != nil {
+			continue
 		}
-		ownerProof, err := ownerPredicateInput.Proof(payloadBytes)
-		if err != nil {
-			return nil, err
+		matchingTokens = append(matchingTokens, token)
+		var ok bool
+		if totalBalance, ok = util.SafeAdd(totalBalance, token.Amount); !ok {
+			// capping the total balance to maxUint64 should be enough to perform the transfer
+			totalBalance = math.MaxUint64
 		}
-		err = tx.SetAuthProof(tokens.SplitFungibleTokenAuthProof{
-			OwnerProof:           ownerProof,
-			TokenTypeOwnerProofs: typeOwnerProofs,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to set auth proof: %w", err)
+		if closestMatch == nil {
+			closestMatch = token
+		} else {
+			prevDiff := closestMatch.Amount - targetAmount
+			currDiff := token.Amount - targetAmount
+			// this should work with overflow nicely
+			if prevDiff > currDiff {
+				closestMatch = token
+			}
 		}
-		tx.FeeProof, err = sdktypes.NewP2pkhFeeSignatureFromKey(tx, acc.PrivKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to sign tx fee proof: %w", err)
+	}
+	if targetAmount > totalBalance {
+		return nil, fmt.Errorf("insufficient tokens of type %s: got %v, need %v", typeID, totalBalance, targetAmount)
+	}
+	if closestMatch.Amount >= targetAmount {
+		return []*sdktypes.FungibleToken{closestMatch}, nil
+	}
+	sort.Slice(matchingTokens, func(i, j int) bool {
+		return matchingTokens[i].Amount > matchingTokens[j].Amount
+	})
+	var accumulatedSum uint64
+	for i, t := range matchingTokens {
+		accumulatedSum += t.Amount
+		if accumulatedSum >= targetAmount {
+			return matchingTokens[:i+1], nil
 		}
-		return txsubmitter.New(tx)
 	}
+	return matchingTokens, nil
 }
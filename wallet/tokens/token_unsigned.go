@@ -0,0 +1,171 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/alphabill-org/alphabill-go-base/types"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+/*
+BuildUnsignedSendFungible builds the transactions of SendFungible without signing nor sending
+them, the transactions are returned in the envelope to be signed offline with the account key.
+The transactions are valid for the timeout number of rounds.
+
+The inputs of the inherited owner predicates are set to the transactions as-is, signatures
+can not be used as inputs because the account key is not needed to build the transactions.
+*/
+func (w *Wallet) BuildUnsignedSendFungible(ctx context.Context, accountNumber uint64, typeID sdktypes.TokenTypeID, targetAmount uint64, receiverPubKey []byte, typeOwnerPredicateInputs []*PredicateInput, timeout uint64) (*offline.Envelope, error) {
+	if targetAmount == 0 {
+		return nil, fmt.Errorf("invalid amount: 0")
+	}
+	acc, err := w.getUnsignedAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	fcr, err := w.tokensClient.GetFeeCreditRecordByOwnerID(ctx, acc.PubKeyHash.Sha256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return nil, ErrNoFeeCredit
+	}
+	tokenz, err := w.ListFungibleTokens(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokenz) == 0 {
+		return nil, fmt.Errorf("account %d has no tokens", accountNumber)
+	}
+	selected, err := selectFungibleTokens(tokenz, typeID, targetAmount)
+	if err != nil {
+		return nil, err
+	}
+	if fcr.Balance < uint64(len(selected))*w.maxFee {
+		return nil, ErrInsufficientFeeCredit
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []*types.TransactionOrder
+	var accumulatedSum uint64
+	for _, token := range selected {
+		tx, err := w.newSplitOrTransferTx(targetAmount-accumulatedSum, token, fcr.ID, receiverPubKey, roundNumber+timeout)
+		if err != nil {
+			return nil, err
+		}
+		typeOwnerProofs, err := unsignedProofs(tx, typeOwnerPredicateInputs)
+		if err != nil {
+			return nil, err
+		}
+		if err = setSplitOrTransferAuthProof(tx, nil, typeOwnerProofs); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+		accumulatedSum += token.Amount
+	}
+	return w.newEnvelope(acc, txs, fcr), nil
+}
+
+/*
+BuildUnsignedTransferNFT builds the transaction of TransferNFT without signing nor sending it,
+the transaction is returned in the envelope to be signed offline with the account key.
+The transaction is valid for the timeout number of rounds.
+
+The inputs of the inherited owner predicates are set to the transaction as-is, signatures
+can not be used as inputs because the account key is not needed to build the transaction.
+*/
+func (w *Wallet) BuildUnsignedTransferNFT(ctx context.Context, accountNumber uint64, tokenID sdktypes.TokenID, receiverPubKey sdktypes.PubKey, typeOwnerPredicateInputs []*PredicateInput, timeout uint64) (*offline.Envelope, error) {
+	acc, err := w.getUnsignedAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	fcr, err := w.tokensClient.GetFeeCreditRecordByOwnerID(ctx, acc.PubKeyHash.Sha256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee credit record: %w", err)
+	}
+	if fcr == nil {
+		return nil, ErrNoFeeCredit
+	}
+	if fcr.Balance < w.maxFee {
+		return nil, ErrInsufficientFeeCredit
+	}
+	token, err := w.GetNonFungibleToken(ctx, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	// the offline signer creates the P2PKH owner proof
+	if err = ensureTokenOwnership(acc, token, nil); err != nil {
+		return nil, err
+	}
+	if token.GetStateLockTx() != nil {
+		return nil, errors.New("token is locked")
+	}
+	roundNumber, err := w.GetRoundNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := token.Transfer(OwnerPredicateFromPubKey(receiverPubKey),
+		sdktypes.WithTimeout(roundNumber+timeout),
+		sdktypes.WithFeeCreditRecordID(fcr.ID),
+		sdktypes.WithMaxFee(w.maxFee),
+	)
+	if err != nil {
+		return nil, err
+	}
+	typeOwnerProofs, err := unsignedProofs(tx, typeOwnerPredicateInputs)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.SetAuthProof(tokens.TransferNonFungibleTokenAuthProof{TokenTypeOwnerProofs: typeOwnerProofs}); err != nil {
+		return nil, fmt.Errorf("failed to set auth proof: %w", err)
+	}
+	return w.newEnvelope(acc, []*types.TransactionOrder{tx}, fcr), nil
+}
+
+// getUnsignedAccount returns the account key for building unsigned transactions, the public key is required.
+func (w *Wallet) getUnsignedAccount(accountNumber uint64) (*accountKey, error) {
+	acc, err := w.getAccount(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(acc.PubKey) == 0 {
+		return nil, fmt.Errorf("key #%d is watch-only account of public key hash, public key is required to build transactions", accountNumber)
+	}
+	return acc, nil
+}
+
+func (w *Wallet) newEnvelope(acc *accountKey, txs []*types.TransactionOrder, fcr *sdktypes.FeeCreditRecord) *offline.Envelope {
+	return &offline.Envelope{
+		Version:         offline.EnvelopeVersion,
+		NetworkID:       w.pdr.NetworkID,
+		PartitionID:     w.pdr.PartitionID,
+		PartitionTypeID: w.pdr.PartitionTypeID,
+		AccountIndex:    acc.idx,
+		OwnerPubKey:     acc.PubKey,
+		Transactions:    txs,
+		FeeCreditRecord: fcr,
+	}
+}
+
+// unsignedProofs returns the proofs of the predicate inputs which do not need the account key.
+func unsignedProofs(tx *types.TransactionOrder, predicateInputs []*PredicateInput) ([][]byte, error) {
+	for _, input := range predicateInputs {
+		if input != nil && input.AccountKey != nil {
+			return nil, errors.New("signatures of account keys can not be used as inherited predicate inputs of unsigned transactions")
+		}
+	}
+	sigBytes, err := tx.AuthProofSigBytes()
+	if err != nil {
+		return nil, err
+	}
+	return newProofs(sigBytes, predicateInputs)
+}
@@ -0,0 +1,144 @@
+package tokens
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/alphabill-org/alphabill-go-base/predicates/templates"
+	tokenid "github.com/alphabill-org/alphabill-go-base/testutils/tokens"
+	"github.com/alphabill-org/alphabill-go-base/txsystem/tokens"
+	"github.com/stretchr/testify/require"
+
+	sdktypes "github.com/alphabill-org/alphabill-wallet/client/types"
+	"github.com/alphabill-org/alphabill-wallet/wallet/offline"
+)
+
+func TestBuildUnsignedSendFungible(t *testing.T) {
+	pdr := tokenid.PDR()
+	typeID := tokenid.NewFungibleTokenTypeID(t)
+	newToken := func(amount uint64) *sdktypes.FungibleToken {
+		return &sdktypes.FungibleToken{NetworkID: pdr.NetworkID, PartitionID: pdr.PartitionID, ID: tokenid.NewFungibleTokenID(t), TypeID: typeID, Amount: amount, Counter: 1}
+	}
+	tokenz := []*sdktypes.FungibleToken{newToken(3), newToken(5)}
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getFungibleTokens: func(ctx context.Context, ownerID []byte) ([]*sdktypes.FungibleToken, error) {
+			return tokenz, nil
+		},
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: 100}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+	receiverPubKey := make([]byte, 33)
+
+	t.Run("split", func(t *testing.T) {
+		envelope, err := tw.BuildUnsignedSendFungible(context.Background(), 1, typeID, 4, receiverPubKey, nil, 10)
+		require.NoError(t, err)
+		require.NoError(t, envelope.IsValid())
+		require.EqualValues(t, tokens.PartitionTypeID, envelope.PartitionTypeID)
+		require.EqualValues(t, ak.PubKey, envelope.OwnerPubKey)
+		require.NotNil(t, envelope.FeeCreditRecord)
+		require.Len(t, envelope.Transactions, 1)
+		tx := envelope.Transactions[0]
+		require.Equal(t, tokens.TransactionTypeSplitFT, tx.Type)
+		require.EqualValues(t, 110, tx.Timeout())
+		attr := &tokens.SplitFungibleTokenAttributes{}
+		require.NoError(t, tx.UnmarshalAttributes(attr))
+		require.EqualValues(t, 4, attr.TargetValue)
+		require.Equal(t, OwnerPredicateFromPubKey(receiverPubKey), sdktypes.Predicate(attr.NewOwnerPredicate))
+
+		// signed offline with the account key
+		require.NoError(t, envelope.Sign(ak))
+		authProof := &tokens.SplitFungibleTokenAuthProof{}
+		require.NoError(t, tx.UnmarshalAuthProof(authProof))
+		require.NotEmpty(t, authProof.OwnerProof)
+		require.NotEmpty(t, tx.FeeProof)
+	})
+
+	t.Run("multiple tokens", func(t *testing.T) {
+		typeInputs := []*PredicateInput{{Argument: templates.EmptyArgument()}}
+		envelope, err := tw.BuildUnsignedSendFungible(context.Background(), 1, typeID, 7, receiverPubKey, typeInputs, 10)
+		require.NoError(t, err)
+		require.Len(t, envelope.Transactions, 2)
+		require.Equal(t, tokens.TransactionTypeTransferFT, envelope.Transactions[0].Type)
+		require.Equal(t, tokens.TransactionTypeSplitFT, envelope.Transactions[1].Type)
+
+		require.NoError(t, envelope.Sign(ak))
+		authProof := &tokens.TransferFungibleTokenAuthProof{}
+		require.NoError(t, envelope.Transactions[0].UnmarshalAuthProof(authProof))
+		require.NotEmpty(t, authProof.OwnerProof)
+		require.Equal(t, [][]byte{templates.EmptyArgument()}, authProof.TokenTypeOwnerProofs)
+	})
+
+	t.Run("account key as predicate input", func(t *testing.T) {
+		_, err := tw.BuildUnsignedSendFungible(context.Background(), 1, typeID, 4, receiverPubKey, []*PredicateInput{defaultProof(ak)}, 10)
+		require.EqualError(t, err, "signatures of account keys can not be used as inherited predicate inputs of unsigned transactions")
+	})
+
+	t.Run("insufficient tokens", func(t *testing.T) {
+		_, err := tw.BuildUnsignedSendFungible(context.Background(), 1, typeID, 9, receiverPubKey, nil, 10)
+		require.ErrorContains(t, err, "insufficient tokens of type")
+	})
+}
+
+func TestBuildUnsignedTransferNFT(t *testing.T) {
+	pdr := tokenid.PDR()
+	tokenz := make(map[string]*sdktypes.NonFungibleToken)
+	rpcClient := &mockTokensPartitionClient{
+		pdr: &pdr,
+		getNonFungibleToken: func(ctx context.Context, id sdktypes.TokenID) (*sdktypes.NonFungibleToken, error) {
+			return tokenz[string(id)], nil
+		},
+		getRoundInfo: func(ctx context.Context) (*sdktypes.RoundInfo, error) {
+			return &sdktypes.RoundInfo{RoundNumber: 100}, nil
+		},
+	}
+	tw := initTestWallet(t, rpcClient)
+	ak, err := tw.am.GetAccountKey(0)
+	require.NoError(t, err)
+
+	token := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), nil, 1)
+	token.NetworkID = pdr.NetworkID
+	token.PartitionID = pdr.PartitionID
+	tokenz[string(token.ID)] = token
+
+	envelope, err := tw.BuildUnsignedTransferNFT(context.Background(), 1, token.ID, nil, nil, 10)
+	require.NoError(t, err)
+	require.NoError(t, envelope.IsValid())
+	require.Len(t, envelope.Transactions, 1)
+	tx := envelope.Transactions[0]
+	require.Equal(t, tokens.TransactionTypeTransferNFT, tx.Type)
+	require.EqualValues(t, 110, tx.Timeout())
+
+	// the envelope survives the round trip through the file
+	filename := filepath.Join(t.TempDir(), "tx.cbor")
+	require.NoError(t, offline.WriteEnvelope(filename, envelope))
+	envelope, err = offline.ReadEnvelope(filename)
+	require.NoError(t, err)
+	require.NoError(t, envelope.Sign(ak))
+	authProof := &tokens.TransferNonFungibleTokenAuthProof{}
+	require.NoError(t, envelope.Transactions[0].UnmarshalAuthProof(authProof))
+	require.NotEmpty(t, authProof.OwnerProof)
+
+	// tokens of other owners and locked tokens are not sent
+	other := newNonFungibleToken(t, "AB", templates.AlwaysTrueBytes(), nil, 1)
+	tokenz[string(other.ID)] = other
+	_, err = tw.BuildUnsignedTransferNFT(context.Background(), 1, other.ID, nil, nil, 10)
+	require.Error(t, err)
+
+	locked := newNonFungibleToken(t, "AB", templates.NewP2pkh256BytesFromKey(ak.PubKey), []byte{1}, 1)
+	tokenz[string(locked.ID)] = locked
+	_, err = tw.BuildUnsignedTransferNFT(context.Background(), 1, locked.ID, nil, nil, 10)
+	require.EqualError(t, err, "token is locked")
+
+	// no fee credit
+	rpcClient.getFeeCreditRecordByOwnerID = func(ctx context.Context, ownerID []byte) (*sdktypes.FeeCreditRecord, error) {
+		return nil, nil
+	}
+	_, err = tw.BuildUnsignedTransferNFT(context.Background(), 1, token.ID, nil, nil, 10)
+	require.ErrorIs(t, err, ErrNoFeeCredit)
+}
@@ -255,6 +255,9 @@ func (p *PredicateInput) Proof(sigBytes []byte) ([]byte, error) {
 		return nil, nil
 	}
 	if p.AccountKey != nil {
+		if p.AccountKey.IsWatchOnly() {
+			return nil, account.ErrWatchOnly
+		}
 		signer, err := abcrypto.NewInMemorySecp256K1SignerFromKey(p.AccountKey.PrivKey)
 		if err != nil {
 			return nil, err
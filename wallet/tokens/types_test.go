@@ -311,6 +311,14 @@ func (a *accountManagerMock) AddAccount() (uint64, []byte, error) {
 	return 0, nil, nil
 }
 
+func (a *accountManagerMock) ImportAccount(*account.AccountKey) (uint64, []byte, error) {
+	return 0, nil, nil
+}
+
+func (a *accountManagerMock) AddWatchOnlyAccount([]byte, []byte) (uint64, error) {
+	return 0, nil
+}
+
 func (a *accountManagerMock) GetMnemonic() (string, error) {
 	return "", nil
 }
@@ -319,6 +327,10 @@ func (a *accountManagerMock) GetAccountKeys() ([]*account.AccountKey, error) {
 	return nil, nil
 }
 
+func (a *accountManagerMock) GetSigningKey(accountIndex uint64) (*account.AccountKey, error) {
+	return a.GetAccountKey(accountIndex)
+}
+
 func (a *accountManagerMock) GetMaxAccountIndex() (uint64, error) {
 	return 0, nil
 }